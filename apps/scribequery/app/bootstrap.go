@@ -1,32 +1,838 @@
 package app
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/account"
 	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/chat"
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/conversation"
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/credential"
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/document"
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/eval"
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/feedback"
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/health"
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/idempotency"
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/outbox"
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/ratelimit"
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/retrieval"
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/share"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/auth"
 	"github.com/Joepolymath/DaVinci/libs/shared-go/config"
 	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/ai"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/bus"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/db/mongo"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/db/postgres"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/db/sqlite"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/db/vector"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/db/vector/milvus"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/db/vector/qdrant"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/db/vector/weaviate"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/redis"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/web"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/secrets"
 	"go.uber.org/zap"
 )
 
 type Services struct {
-	ChatService chat.Service
+	AccountService      account.Service
+	ChatService         chat.Service
+	ConversationService conversation.Service
+	CredentialService   credential.Service
+	FeedbackService     feedback.Service
+	ShareService        share.Service
+	DocumentService     document.Service
+	RetrievalService    retrieval.Service
+	EvalService         eval.Service
+	IdempotencyService  idempotency.Service
+	RateLimiter         ratelimit.Service
+	OutboxService       outbox.Service
+	HealthService       health.Service
+	ChatProviders       *ai.ChatProviderRegistry
+	ProviderHealth      *ai.HealthSupervisor
+	Embeddings          ai.EmbeddingsProvider
+	Reranker            ai.Reranker
+	VectorStore         vector.Store
+	Database            *sql.DB
+	Mongo               *mongo.Client
+	Redis               *redis.Client
+	APIKeys             auth.KeyStore     // nil when /api does not require an api key
+	JWTVerifier         *auth.JWTVerifier // nil when /api does not require a user token
 }
 
 func InitServices(cfg *config.Config, logger *zap.Logger) *Services {
+	if err := refreshSecrets(cfg, logger); err != nil {
+		logger.Error("Failed to create secrets source", zap.Error(err))
+		return nil
+	}
+
+	chatProviders, err := initChatProviders(cfg, logger)
+	if err != nil {
+		logger.Error("Failed to create chat providers", zap.Error(err))
+		return nil
+	}
+
+	providerHealth := ai.NewHealthSupervisor(chatProviders.All(), ai.HealthSupervisorConfig{}, logger)
+	providerHealth.Start(context.Background())
+
+	// Embeddings are optional for chat, so a misconfiguration is not fatal.
+	embeddings, err := initEmbeddingsProvider(cfg, logger)
+	if err != nil {
+		logger.Warn("Embeddings provider disabled", zap.Error(err))
+	}
+
+	vectorStore, err := initVectorStore(cfg, logger)
+	if err != nil {
+		logger.Warn("Vector store disabled", zap.Error(err))
+	}
+
+	reranker, err := initReranker(cfg, logger)
+	if err != nil {
+		logger.Warn("Reranker disabled", zap.Error(err))
+	}
+
+	var apiKeys auth.KeyStore
+	if cfg.APIKeysFile != "" {
+		if apiKeys, err = auth.NewFileKeyStore(cfg.APIKeysFile); err != nil {
+			logger.Error("Failed to load api keys", zap.Error(err))
+			return nil
+		}
+	}
+
+	var jwtVerifier *auth.JWTVerifier
+	if cfg.JWTSecret != "" || cfg.JWTJWKSURL != "" {
+		if jwtVerifier, err = auth.NewJWTVerifier(auth.JWTConfig{
+			Secret:   cfg.JWTSecret,
+			JWKSURL:  cfg.JWTJWKSURL,
+			Issuer:   cfg.JWTIssuer,
+			Audience: cfg.JWTAudience,
+		}, logger); err != nil {
+			logger.Error("Failed to create jwt verifier", zap.Error(err))
+			return nil
+		}
+	}
+
+	crawler := web.NewCrawler(web.CrawlerConfig{
+		UserAgent:    cfg.CrawlerUserAgent,
+		AllowedHosts: strings.Split(cfg.CrawlerAllowedHosts, ","),
+	}, logger)
+
+	// Without a vector store the repositories stay nil, which disables
+	// ingestion and retrieval.
+	var documentRepo document.Repository
+	var chunkRepo retrieval.Repository
+	if vectorStore != nil {
+		documentRepo = document.NewVectorRepository(vectorStore, chunkCollection)
+		chunkRepo = retrieval.NewVectorRepository(vectorStore, chunkCollection)
+	}
+
+	retrievalService := retrieval.NewService(embeddings, chunkRepo, reranker, chatProviders.Default(), retrieval.Config{
+		ExpandQueries: cfg.QueryExpansion == "true",
+	}, logger)
+
+	database, err := initDatabase(cfg, logger)
+	if err != nil {
+		logger.Error("Failed to open database", zap.Error(err))
+		return nil
+	}
+
+	mongoClient, err := initMongo(cfg, logger)
+	if err != nil {
+		logger.Error("Failed to connect to MongoDB", zap.Error(err))
+		return nil
+	}
+
+	redisClient, err := initRedis(cfg, logger)
+	if err != nil {
+		logger.Error("Failed to connect to Redis", zap.Error(err))
+		return nil
+	}
+
+	conversationRepo, err := initConversationRepository(cfg, database, mongoClient)
+	if err != nil {
+		logger.Error("Failed to open conversation store", zap.Error(err))
+		return nil
+	}
+	publisher, err := initPublisher(cfg, redisClient, logger)
+	if err != nil {
+		logger.Error("Failed to create event publisher", zap.Error(err))
+		return nil
+	}
+	outboxService := outbox.NewService(conversationRepo, publisher, outbox.Config{}, logger)
+	go relayOutbox(context.Background(), outboxService, logger)
+
+	var generationState chat.GenerationState
+	if redisClient != nil {
+		cacheMinutes, _ := strconv.Atoi(cfg.ConversationCacheMins)
+		conversationRepo = conversation.NewRedisCache(conversationRepo, redisClient, conversation.CacheConfig{
+			TTL: time.Duration(cacheMinutes) * time.Minute,
+		}, logger)
+		generationState = chat.NewRedisGenerationState(redisClient)
+	}
+	purgeDays, _ := strconv.Atoi(cfg.ConversationPurgeDays)
+	retentionDays, _ := strconv.Atoi(cfg.RetentionDays)
+	tenantRetention, err := conversation.ParseTenantRetention(cfg.RetentionTenantDays)
+	if err != nil {
+		logger.Error("Invalid RETENTION_TENANT_DAYS", zap.Error(err))
+		return nil
+	}
+	conversationService := conversation.NewService(conversationRepo, chatProviders, conversation.Config{
+		PurgeAfter:      time.Duration(purgeDays) * 24 * time.Hour,
+		RetainFor:       time.Duration(retentionDays) * 24 * time.Hour,
+		TenantRetention: tenantRetention,
+	}, logger)
+	go purgeConversations(context.Background(), conversationService, logger)
+	if retentionDays > 0 || len(tenantRetention) > 0 {
+		dryRun, _ := strconv.ParseBool(cfg.RetentionDryRun)
+		go applyRetention(context.Background(), conversationService, dryRun, logger)
+	}
+
+	feedbackRepo, err := initFeedbackRepository(cfg, database, mongoClient)
+	if err != nil {
+		logger.Error("Failed to open feedback store", zap.Error(err))
+		return nil
+	}
+
+	shareRepo, err := initShareRepository(cfg, database, mongoClient)
+	if err != nil {
+		logger.Error("Failed to open share store", zap.Error(err))
+		return nil
+	}
+
+	userRepo, err := initUserRepository(cfg, database)
+	if err != nil {
+		logger.Error("Failed to open user store", zap.Error(err))
+		return nil
+	}
+
+	idempotencyService := initIdempotency(cfg, redisClient, logger)
+	go purgeIdempotencyKeys(context.Background(), idempotencyService, logger)
+
+	rateLimiter, err := initRateLimiter(cfg, redisClient, logger)
+	if err != nil {
+		logger.Error("Failed to create rate limiter", zap.Error(err))
+		return nil
+	}
+
+	credentialService, err := initCredentialService(cfg, database, logger)
+	if err != nil {
+		logger.Error("Failed to open credential store", zap.Error(err))
+		return nil
+	}
+
+	chatService := chat.NewService(chatProviders, retrievalService, conversationService, embeddings, generationState, credentialService, chatConfig(cfg), logger)
+
+	return &Services{
+		AccountService:      account.NewService(userRepo, accountConfig(cfg), logger),
+		ChatService:         chatService,
+		ConversationService: conversationService,
+		CredentialService:   credentialService,
+		FeedbackService:     feedback.NewService(feedbackRepo, conversationService, logger),
+		ShareService:        share.NewService(shareRepo, conversationService, cfg.ShareSecret, logger),
+		DocumentService:     document.NewService(embeddings, documentRepo, crawler, logger),
+		RetrievalService:    retrievalService,
+		EvalService:         eval.NewService(retrievalService, chatService, chatProviders.Default(), embeddings, logger),
+		IdempotencyService:  idempotencyService,
+		RateLimiter:         rateLimiter,
+		OutboxService:       outboxService,
+		HealthService:       health.NewService(healthChecks(chatProviders, vectorStore, database, mongoClient, redisClient), health.Config{}, logger),
+		ChatProviders:       chatProviders,
+		ProviderHealth:      providerHealth,
+		Embeddings:          embeddings,
+		Reranker:            reranker,
+		VectorStore:         vectorStore,
+		Database:            database,
+		Mongo:               mongoClient,
+		Redis:               redisClient,
+		APIKeys:             apiKeys,
+		JWTVerifier:         jwtVerifier,
+	}
+}
+
+// InitAccountService opens only the user store, for commands that manage
+// users outside the server.
+func InitAccountService(cfg *config.Config, logger *zap.Logger) (account.Service, error) {
+	database, err := initDatabase(cfg, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	userRepo, err := initUserRepository(cfg, database)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open user store: %w", err)
+	}
+	return account.NewService(userRepo, accountConfig(cfg), logger), nil
+}
+
+// initDatabase opens the DATABASE_TYPE SQL database (default postgres) at
+// DATABASE_URL and applies its migrations. It returns nil without error
+// when DATABASE_URL is unset or the database is MongoDB.
+func initDatabase(cfg *config.Config, logger *zap.Logger) (*sql.DB, error) {
+	if cfg.DatabaseURL == "" || cfg.DatabaseType == "mongo" {
+		return nil, nil
+	}
+
+	ctx := context.Background()
+	switch cfg.DatabaseType {
+	case "", "postgres":
+		db, err := postgres.NewPostgresDB(ctx, postgresConfig(cfg), logger)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := db.Migrate(ctx, postgresMigrations); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("failed to migrate database: %w", err)
+		}
+		go postgres.MonitorPool(ctx, db.DB, time.Minute, logger)
+		return db.DB, nil
+	case "sqlite":
+		db, err := sqlite.NewSQLiteDB(ctx, sqlite.SQLiteConfig{
+			Path:   cfg.DatabaseURL,
+			Driver: cfg.DatabaseDriver,
+		}, logger)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := db.Migrate(ctx, sqliteMigrations); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("failed to migrate database: %w", err)
+		}
+		return db.DB, nil
+	default:
+		return nil, fmt.Errorf("unsupported database: %q (supported: %q, %q, %q)", cfg.DatabaseType, "postgres", "sqlite", "mongo")
+	}
+}
+
+// postgresConfig reads the connection pool settings; unset or invalid
+// values use the postgres package defaults.
+func postgresConfig(cfg *config.Config) postgres.PostgresConfig {
+	maxOpen, _ := strconv.Atoi(cfg.DatabaseMaxOpenConns)
+	maxIdle, _ := strconv.Atoi(cfg.DatabaseMaxIdleConns)
+	idleSecs, _ := strconv.Atoi(cfg.DatabaseIdleTimeoutSecs)
+	ageMins, _ := strconv.Atoi(cfg.DatabaseMaxConnAgeMins)
+	statementMs, _ := strconv.Atoi(cfg.DatabaseStmtTimeoutMs)
+	return postgres.PostgresConfig{
+		URL:              cfg.DatabaseURL,
+		Driver:           cfg.DatabaseDriver,
+		MaxOpenConns:     maxOpen,
+		MaxIdleConns:     maxIdle,
+		ConnMaxIdleTime:  time.Duration(idleSecs) * time.Second,
+		ConnMaxLifetime:  time.Duration(ageMins) * time.Minute,
+		StatementTimeout: time.Duration(statementMs) * time.Millisecond,
+	}
+}
+
+// initMongo connects to DATABASE_URL when DATABASE_TYPE is mongo, and
+// returns nil without error otherwise.
+func initMongo(cfg *config.Config, logger *zap.Logger) (*mongo.Client, error) {
+	if cfg.DatabaseURL == "" || cfg.DatabaseType != "mongo" {
+		return nil, nil
+	}
+	return mongo.NewMongoClient(context.Background(), mongo.MongoConfig{URI: cfg.DatabaseURL}, logger)
+}
+
+// initRedis connects to REDIS_URL. It returns nil without error when it is
+// unset.
+func initRedis(cfg *config.Config, logger *zap.Logger) (*redis.Client, error) {
+	if cfg.RedisURL == "" {
+		return nil, nil
+	}
+	return redis.NewRedisClient(context.Background(), redis.RedisConfig{URL: cfg.RedisURL, Mode: cfg.RedisMode}, logger)
+}
+
+// healthChecks lists the dependencies GET /health probes. Only the default
+// chat provider and the conversation database are critical: without the
+// others chats are still answered, if less well.
+func healthChecks(providers *ai.ChatProviderRegistry, store vector.Store, db *sql.DB, mongoClient *mongo.Client, redisClient *redis.Client) []health.Check {
+	var checks []health.Check
+	for _, name := range providers.Names() {
+		provider, _ := providers.Get(name)
+		checks = append(checks, health.Check{
+			Name:     name,
+			Kind:     "provider",
+			Critical: name == providers.DefaultName(),
+			Probe:    provider.Health,
+		})
+	}
+	if store != nil {
+		checks = append(checks, health.Check{
+			Name: "vector_store",
+			Kind: "vector_store",
+			Probe: func(ctx context.Context) error {
+				_, err := store.Collections(ctx)
+				return err
+			},
+		})
+	}
+	if db != nil {
+		checks = append(checks, health.Check{
+			Name:     "database",
+			Kind:     "database",
+			Critical: true,
+			Probe:    db.PingContext,
+			Details:  func() any { return postgres.ReadPoolStats(db) },
+		})
+	}
+	if mongoClient != nil {
+		checks = append(checks, health.Check{Name: "database", Kind: "database", Critical: true, Probe: mongoClient.Health})
+	}
+	if redisClient != nil {
+		checks = append(checks, health.Check{Name: "redis", Kind: "cache", Probe: redisClient.Health})
+	}
+	return checks
+}
+
+// initPublisher selects the bus for outbox events from EVENT_BUS.
+func initPublisher(cfg *config.Config, redisClient *redis.Client, logger *zap.Logger) (bus.Publisher, error) {
+	switch cfg.EventBus {
+	case "":
+		return bus.NewLogPublisher(logger), nil
+	case "redis":
+		if redisClient == nil {
+			return nil, fmt.Errorf("EVENT_BUS=redis requires REDIS_URL")
+		}
+		return bus.NewRedisStreamPublisher(redisClient, bus.RedisStreamConfig{Prefix: "events:"}), nil
+	case "webhook":
+		return bus.NewWebhookPublisher(bus.WebhookConfig{URL: cfg.EventWebhookURL})
+	default:
+		return nil, fmt.Errorf("unsupported event bus: %q (supported: %q, %q)", cfg.EventBus, "redis", "webhook")
+	}
+}
+
+// initConversationRepository stores conversations in the database when
+// there is one, and otherwise in memory (and CONVERSATIONS_FILE).
+func initConversationRepository(cfg *config.Config, db *sql.DB, mongoClient *mongo.Client) (conversation.Repository, error) {
+	switch {
+	case mongoClient != nil:
+		return conversation.NewMongoRepository(context.Background(), mongoClient)
+	case db == nil:
+		return conversation.NewMemoryRepository(cfg.ConversationsFile)
+	case cfg.DatabaseType == "sqlite":
+		return conversation.NewSQLiteRepository(db), nil
+	default:
+		return conversation.NewPostgresRepository(db), nil
+	}
+}
+
+// initFeedbackRepository stores message feedback in the database when
+// there is one, and otherwise in memory (and FEEDBACK_FILE).
+func initFeedbackRepository(cfg *config.Config, db *sql.DB, mongoClient *mongo.Client) (feedback.Repository, error) {
+	switch {
+	case mongoClient != nil:
+		return feedback.NewMongoRepository(context.Background(), mongoClient)
+	case db == nil:
+		return feedback.NewMemoryRepository(cfg.FeedbackFile)
+	case cfg.DatabaseType == "sqlite":
+		return feedback.NewSQLiteRepository(db), nil
+	default:
+		return feedback.NewPostgresRepository(db), nil
+	}
+}
+
+// initShareRepository stores conversation share links in the database
+// when there is one, and otherwise in memory (and SHARES_FILE).
+func initShareRepository(cfg *config.Config, db *sql.DB, mongoClient *mongo.Client) (share.Repository, error) {
+	switch {
+	case mongoClient != nil:
+		return share.NewMongoRepository(context.Background(), mongoClient)
+	case db == nil:
+		return share.NewMemoryRepository(cfg.SharesFile)
+	case cfg.DatabaseType == "sqlite":
+		return share.NewSQLiteRepository(db), nil
+	default:
+		return share.NewPostgresRepository(db), nil
+	}
+}
+
+// initUserRepository stores users in the SQL database when there is one,
+// and otherwise in memory (and USERS_FILE), including with MongoDB.
+func initUserRepository(cfg *config.Config, db *sql.DB) (account.Repository, error) {
+	switch {
+	case db == nil:
+		return account.NewMemoryRepository(cfg.UsersFile)
+	case cfg.DatabaseType == "sqlite":
+		return account.NewSQLiteRepository(db), nil
+	default:
+		return account.NewPostgresRepository(db), nil
+	}
+}
+
+// initCredentialService stores users' provider keys, sealed with
+// ENCRYPTION_KEYS, in the SQL database when there is one, and otherwise in
+// memory (and CREDENTIALS_FILE). Without ENCRYPTION_KEYS users cannot store
+// keys.
+func initCredentialService(cfg *config.Config, db *sql.DB, logger *zap.Logger) (credential.Service, error) {
+	var keyring *secrets.Keyring
+	if cfg.EncryptionKeys != "" {
+		var err error
+		if keyring, err = secrets.ParseKeyring(cfg.EncryptionKeys); err != nil {
+			return nil, err
+		}
+	}
+
+	var repo credential.Repository
+	switch {
+	case db == nil:
+		var err error
+		if repo, err = credential.NewMemoryRepository(cfg.CredentialsFile); err != nil {
+			return nil, err
+		}
+	case cfg.DatabaseType == "sqlite":
+		repo = credential.NewSQLiteRepository(db)
+	default:
+		repo = credential.NewPostgresRepository(db)
+	}
+	return credential.NewService(repo, credential.Config{Keyring: keyring}, logger), nil
+}
+
+// accountConfig signs access tokens with the JWT_SECRET the verifier
+// checks them with; unset or invalid lifetimes use the account defaults.
+func accountConfig(cfg *config.Config) account.Config {
+	accessMinutes, _ := strconv.Atoi(cfg.AccessTokenMinutes)
+	refreshDays, _ := strconv.Atoi(cfg.RefreshTokenDays)
+	return account.Config{
+		Secret:     cfg.JWTSecret,
+		Issuer:     cfg.JWTIssuer,
+		Audience:   cfg.JWTAudience,
+		AccessTTL:  time.Duration(accessMinutes) * time.Minute,
+		RefreshTTL: time.Duration(refreshDays) * 24 * time.Hour,
+	}
+}
+
+// initIdempotency keeps idempotency keys for IDEMPOTENCY_TTL_HOURS, shared
+// between instances through Redis when it is configured, so that a retry
+// routed to another instance is still recognised.
+func initIdempotency(cfg *config.Config, redisClient *redis.Client, logger *zap.Logger) idempotency.Service {
+	ttlHours, _ := strconv.Atoi(cfg.IdempotencyTTLHours)
+	repo := idempotency.NewMemoryRepository()
+	if redisClient != nil {
+		repo = idempotency.NewRedisRepository(redisClient)
+	}
+	return idempotency.NewService(repo, idempotency.Config{
+		TTL: time.Duration(ttlHours) * time.Hour,
+	}, logger)
+}
+
+// initRateLimiter limits callers to RATE_LIMITS, sharing the buckets
+// between instances through Redis when it is configured.
+func initRateLimiter(cfg *config.Config, redisClient *redis.Client, logger *zap.Logger) (ratelimit.Service, error) {
+	limits, err := ratelimit.ParseLimits(cfg.RateLimits)
+	if err != nil {
+		return nil, err
+	}
+	store := ratelimit.NewMemoryStore()
+	if redisClient != nil {
+		store = ratelimit.NewRedisStore(redisClient)
+	}
+	return ratelimit.NewService(store, ratelimit.Config{Limits: limits}, logger), nil
+}
+
+// initVectorStore connects to the backend selected by VECTOR_STORE (default
+// weaviate). It returns nil without error when that backend's host is unset.
+func initVectorStore(cfg *config.Config, logger *zap.Logger) (vector.Store, error) {
+	switch cfg.VectorStore {
+	case "", "weaviate":
+		if cfg.WeaviateHost == "" {
+			return nil, nil
+		}
+		client, err := weaviate.NewWeaviateClient(weaviate.WeaviateConfig{
+			Host:     cfg.WeaviateHost,
+			Scheme:   cfg.WeaviateScheme,
+			APIKey:   cfg.WeaviateAPIKey,
+			GrpcHost: cfg.WeaviateGrpcHost,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		dryRun, _ := strconv.ParseBool(cfg.WeaviateDryRun)
+		migrator := weaviate.NewMigrator(client, logger, dryRun)
+		if _, err := migrator.Migrate(context.Background(), weaviateSchema); err != nil {
+			return nil, fmt.Errorf("failed to migrate weaviate schema: %w", err)
+		}
+
+		return weaviate.NewStore(client, weaviate.NewService(client, logger)), nil
+	case "qdrant":
+		if cfg.QdrantURL == "" {
+			return nil, nil
+		}
+		client, err := qdrant.NewQdrantClient(qdrant.QdrantConfig{
+			URL:    cfg.QdrantURL,
+			APIKey: cfg.QdrantAPIKey,
+			Wait:   true,
+		}, logger)
+		if err != nil {
+			return nil, err
+		}
+		return qdrant.NewStore(client), nil
+	case "milvus":
+		if cfg.MilvusURL == "" {
+			return nil, nil
+		}
+		client, err := milvus.NewMilvusClient(milvus.MilvusConfig{
+			URL:              cfg.MilvusURL,
+			Token:            cfg.MilvusToken,
+			DBName:           cfg.MilvusDB,
+			Partition:        cfg.MilvusPartition,
+			ConsistencyLevel: cfg.MilvusConsistency,
+		}, logger)
+		if err != nil {
+			return nil, err
+		}
+		return milvus.NewStore(client), nil
+	default:
+		return nil, fmt.Errorf("unsupported vector store: %q (supported: %q, %q, %q)", cfg.VectorStore, "weaviate", "qdrant", "milvus")
+	}
+}
+
+// initEmbeddingsProvider builds the embeddings provider from EMBEDDING_PROVIDER
+// (falling back to PROVIDER, then OpenAI) and EMBEDDING_MODEL.
+func initEmbeddingsProvider(cfg *config.Config, logger *zap.Logger) (ai.EmbeddingsProvider, error) {
+	provider := ai.ProviderType(cfg.EmbeddingProvider)
+	if provider == "" {
+		provider = ai.ProviderType(cfg.Provider)
+	}
+	if provider == "" {
+		provider = ai.ProviderOpenAI
+	}
+
+	embeddingsConfig := &ai.EmbeddingsProviderConfig{
+		Provider:     provider,
+		OpenAIAPIKey: cfg.OpenAIAPIKey,
+		LocalHost:    cfg.LocalHost,
+	}
+	if provider == ai.ProviderLocal {
+		embeddingsConfig.LocalModel = cfg.EmbeddingModel
+	} else {
+		embeddingsConfig.OpenAIModel = cfg.EmbeddingModel
+	}
+
+	return ai.NewEmbeddingsProvider(embeddingsConfig, logger)
+}
+
+// chatConfig reads the system prompt, history window and context packing
+// settings; unset or invalid limits use the chat defaults.
+func chatConfig(cfg *config.Config) chat.Config {
+	maxMessages, _ := strconv.Atoi(cfg.HistoryMaxMessages)
+	tokenBudget, _ := strconv.Atoi(cfg.HistoryTokenBudget)
+	messageTokens, _ := strconv.Atoi(cfg.HistoryMaxMessageTokens)
+	attachmentTokens, _ := strconv.Atoi(cfg.AttachmentTokenBudget)
+	return chat.Config{
+		SystemPrompt: cfg.SystemPrompt,
+		History: chat.HistoryConfig{
+			MaxMessages:      maxMessages,
+			TokenBudget:      tokenBudget,
+			MaxMessageTokens: messageTokens,
+		},
+		Packing: packConfig(cfg),
+		Attachments: chat.AttachmentConfig{
+			TokenBudget: attachmentTokens,
+		},
+	}
+}
+
+// purgeConversations removes conversations past their restore window,
+// at startup and then hourly, until ctx is done.
+func purgeConversations(ctx context.Context, conversations conversation.Service, logger *zap.Logger) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		purged, err := conversations.Purge(ctx)
+		if err != nil {
+			logger.Error("Failed to purge deleted conversations", zap.Error(err))
+		} else if purged > 0 {
+			logger.Info("Purged deleted conversations", zap.Int("count", purged))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// applyRetention deletes conversations past the retention period, or in a
+// dry run logs them, at startup and then hourly, until ctx is done.
+func applyRetention(ctx context.Context, conversations conversation.Service, dryRun bool, logger *zap.Logger) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		report, err := conversations.ApplyRetention(ctx, dryRun)
+		if errors.Is(err, conversation.ErrRetentionDisabled) {
+			// Every period listed keeps conversations.
+			return
+		}
+		if err != nil {
+			logger.Error("Failed to apply conversation retention", zap.Error(err))
+		}
+		if report != nil && len(report.Deletions) > 0 {
+			if dryRun {
+				for _, d := range report.Deletions {
+					logger.Info("Retention dry run: conversation would be deleted",
+						zap.String("conversation_id", d.ConversationID),
+						zap.String("tenant_id", d.TenantID),
+						zap.Time("updated_at", d.ConversationUpdatedAt))
+				}
+			} else {
+				logger.Info("Deleted conversations past retention",
+					zap.Int("count", len(report.Deletions)),
+					zap.Time("cutoff", report.Cutoff),
+					zap.Any("tenant_cutoffs", report.TenantCutoffs))
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// relayOutbox publishes outbox events every few seconds and purges
+// published ones hourly.
+func relayOutbox(ctx context.Context, events outbox.Service, logger *zap.Logger) {
+	relay := time.NewTicker(2 * time.Second)
+	defer relay.Stop()
+	purge := time.NewTicker(time.Hour)
+	defer purge.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-relay.C:
+			if _, err := events.Relay(ctx); err != nil {
+				logger.Error("Failed to relay outbox events", zap.Error(err))
+			}
+		case <-purge.C:
+			if _, err := events.Purge(ctx); err != nil {
+				logger.Error("Failed to purge outbox events", zap.Error(err))
+			}
+		}
+	}
+}
+
+// purgeIdempotencyKeys removes expired idempotency keys, at startup and
+// then hourly, until ctx is done.
+func purgeIdempotencyKeys(ctx context.Context, keys idempotency.Service, logger *zap.Logger) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		if _, err := keys.Purge(ctx); err != nil {
+			logger.Error("Failed to purge idempotency keys", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// packConfig reads the retrieved-context limits; unset or invalid values
+// use the retrieval defaults.
+func packConfig(cfg *config.Config) retrieval.PackConfig {
+	budget, _ := strconv.Atoi(cfg.ContextTokenBudget)
+	perDocument, _ := strconv.Atoi(cfg.ContextMaxPerDocument)
+	return retrieval.PackConfig{
+		TokenBudget:    budget,
+		MaxPerDocument: perDocument,
+	}
+}
+
+// initReranker creates the reranker selected by RERANK_PROVIDER ("cohere" or
+// "local"). It returns nil without error when reranking is not configured.
+func initReranker(cfg *config.Config, logger *zap.Logger) (ai.Reranker, error) {
+	if cfg.RerankProvider == "" {
+		return nil, nil
+	}
+	return ai.NewReranker(&ai.RerankerConfig{
+		Provider:     ai.ProviderType(cfg.RerankProvider),
+		CohereAPIKey: cfg.CohereAPIKey,
+		CohereModel:  cfg.RerankModel,
+		LocalHost:    cfg.RerankHost,
+		LocalModel:   cfg.RerankModel,
+	}, logger)
+}
+
+// secretsRefreshInterval is how often the SECRETS_PROVIDER secret is
+// fetched again, or zero when it is not.
+func secretsRefreshInterval(cfg *config.Config) time.Duration {
+	minutes, _ := strconv.Atoi(cfg.SecretsRefreshMins)
+	if cfg.SecretsProvider == "" || minutes <= 0 {
+		return 0
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// refreshSecrets keeps the secret config.LoadConfig applied current, so
+// that chat providers follow rotated API keys: OPENAI_API_KEY for the
+// default provider, and the variables AI_PROVIDERS keys reference. Other
+// secrets are read once at startup.
+func refreshSecrets(cfg *config.Config, logger *zap.Logger) error {
+	interval := secretsRefreshInterval(cfg)
+	if interval == 0 {
+		return nil
+	}
+	source, err := config.NewSecretsSource(cfg)
+	if err != nil {
+		return err
+	}
+	go secrets.Refresh(context.Background(), source, config.SecretNames(cfg), interval, logger)
+	return nil
+}
+
+// initChatProviders builds the named providers from AI_PROVIDERS or
+// AI_PROVIDERS_FILE, falling back to a single "default" provider configured
+// from the OpenAI/local environment variables.
+func initChatProviders(cfg *config.Config, logger *zap.Logger) (*ai.ChatProviderRegistry, error) {
+	switch {
+	case cfg.AIProviders != "":
+		specs, err := ai.ParseProviderSpecs([]byte(cfg.AIProviders))
+		if err != nil {
+			return nil, err
+		}
+		return ai.NewChatProviders(specs, logger)
+	case cfg.AIProvidersFile != "":
+		specs, err := ai.LoadProviderSpecs(cfg.AIProvidersFile)
+		if err != nil {
+			return nil, err
+		}
+		return ai.NewChatProviders(specs, logger)
+	}
+
+	aliases, err := ai.ParseModelAliases(cfg.ModelAliases)
+	if err != nil {
+		return nil, err
+	}
+
 	chatProviderConfig := &ai.ChatProviderConfig{
 		Provider:     ai.ProviderOpenAI,
 		OpenAIAPIKey: cfg.OpenAIAPIKey,
 		OpenAIModel:  cfg.OpenAIModel,
 		LocalHost:    cfg.LocalHost,
 		LocalModel:   cfg.LocalModel,
+		ModelAliases: aliases,
+	}
+	if secretsRefreshInterval(cfg) > 0 {
+		chatProviderConfig.OpenAIAPIKeyFunc = func() string { return os.Getenv("OPENAI_API_KEY") }
 	}
 
 	chatProvider, err := ai.NewChatProvider(chatProviderConfig, logger)
 	if err != nil {
-		logger.Error("Failed to create chat provider", zap.Error(err))
-		return nil
+		return nil, err
 	}
 
-	return &Services{
-		ChatService: chat.NewService(chatProvider),
-	}
+	return ai.NewChatProviderRegistry(map[string]ai.ChatProvider{"default": chatProvider}, "default")
 }
@@ -0,0 +1,16 @@
+package app
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed migrations
+var migrationFiles embed.FS
+
+// postgresMigrations and sqliteMigrations are the database schema for
+// each backend, applied on startup; see postgres.DB.Migrate.
+var (
+	postgresMigrations, _ = fs.Sub(migrationFiles, "migrations/postgres")
+	sqliteMigrations, _   = fs.Sub(migrationFiles, "migrations/sqlite")
+)
@@ -0,0 +1,16 @@
+-- Events written in the same transaction as the messages they describe,
+-- published by the outbox relay. seq is the publish order.
+CREATE TABLE outbox_events (
+    seq          BIGSERIAL PRIMARY KEY,
+    id           TEXT NOT NULL UNIQUE,
+    topic        TEXT NOT NULL,
+    event_key    TEXT NOT NULL,
+    payload      JSONB NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL,
+    attempts     INTEGER NOT NULL DEFAULT 0,
+    last_error   TEXT NOT NULL DEFAULT '',
+    published_at TIMESTAMPTZ
+);
+
+CREATE INDEX outbox_events_pending_idx ON outbox_events (seq) WHERE published_at IS NULL;
+CREATE INDEX outbox_events_published_idx ON outbox_events (published_at) WHERE published_at IS NOT NULL;
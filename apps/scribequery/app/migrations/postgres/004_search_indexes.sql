@@ -0,0 +1,27 @@
+-- Trigram indexes serve the conversation search, whose conditions are
+-- case-insensitive substring matches (ILIKE '%term%') on message content
+-- and titles. Terms shorter than three characters cannot use them and are
+-- checked on the rows the other terms select.
+--
+-- Creating the pg_trgm extension takes the CREATE privilege on the
+-- database (PostgreSQL 13+, where pg_trgm is a trusted extension) or a
+-- superuser. Without it this migration only warns, so that later ones
+-- still apply, and search scans instead; to add the indexes afterwards,
+-- run CREATE EXTENSION pg_trgm as a privileged role and then the CREATE
+-- INDEX statements below.
+DO $$
+BEGIN
+    CREATE EXTENSION IF NOT EXISTS pg_trgm;
+EXCEPTION WHEN insufficient_privilege THEN
+    RAISE WARNING 'pg_trgm is not installed and this role cannot install it; conversation search will not be indexed';
+END
+$$;
+
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'pg_trgm') THEN
+        CREATE INDEX IF NOT EXISTS messages_content_trgm_idx ON messages USING GIN (content gin_trgm_ops);
+        CREATE INDEX IF NOT EXISTS conversations_title_trgm_idx ON conversations USING GIN (title gin_trgm_ops);
+    END IF;
+END
+$$;
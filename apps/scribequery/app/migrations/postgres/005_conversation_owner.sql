@@ -0,0 +1,5 @@
+-- owner_id is the subject of the token that created the conversation;
+-- empty for conversations created without user authentication.
+ALTER TABLE conversations ADD COLUMN owner_id TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX conversations_owner_idx ON conversations (owner_id, updated_at DESC, id);
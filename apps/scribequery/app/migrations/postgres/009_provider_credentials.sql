@@ -0,0 +1,13 @@
+-- Users' own provider API keys. sealed_key is encrypted with the
+-- application keyring (ENCRYPTION_KEYS); hint is the key's last characters.
+CREATE TABLE provider_credentials (
+    id         TEXT PRIMARY KEY,
+    tenant_id  TEXT NOT NULL DEFAULT '',
+    owner_id   TEXT NOT NULL,
+    provider   TEXT NOT NULL,
+    hint       TEXT NOT NULL DEFAULT '',
+    sealed_key TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    UNIQUE (owner_id, provider)
+);
@@ -0,0 +1,31 @@
+-- Feedback on assistant answers, one per message, with snapshots of the
+-- question and answer so rated pairs can become eval cases.
+CREATE TABLE feedback (
+    id              TEXT NOT NULL,
+    message_id      TEXT PRIMARY KEY,
+    conversation_id TEXT NOT NULL,
+    tenant_id       TEXT NOT NULL,
+    rating          TEXT NOT NULL,
+    category        TEXT NOT NULL DEFAULT '',
+    comment         TEXT NOT NULL DEFAULT '',
+    question        TEXT NOT NULL DEFAULT '',
+    answer          TEXT NOT NULL DEFAULT '',
+    provider        TEXT NOT NULL DEFAULT '',
+    model           TEXT NOT NULL DEFAULT '',
+    created_at      TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX feedback_tenant_idx ON feedback (tenant_id, created_at DESC);
+
+-- Read-only links to conversations; the link's signed token names the
+-- share, so revoking it disables the link.
+CREATE TABLE shares (
+    id              TEXT PRIMARY KEY,
+    conversation_id TEXT NOT NULL,
+    tenant_id       TEXT NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL,
+    expires_at      TIMESTAMPTZ,
+    revoked_at      TIMESTAMPTZ
+);
+
+CREATE INDEX shares_conversation_idx ON shares (conversation_id);
@@ -0,0 +1,9 @@
+-- owner_id and tenant_id are the user (empty without one) and tenant of
+-- the request that stored the message, so usage is attributed to who
+-- generated it. Earlier messages take their conversation's.
+ALTER TABLE messages ADD COLUMN owner_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE messages ADD COLUMN tenant_id TEXT NOT NULL DEFAULT '';
+
+UPDATE messages SET
+    owner_id = COALESCE((SELECT c.owner_id FROM conversations c WHERE c.id = messages.conversation_id), ''),
+    tenant_id = COALESCE((SELECT c.tenant_id FROM conversations c WHERE c.id = messages.conversation_id), '');
@@ -0,0 +1,34 @@
+CREATE TABLE conversations (
+    id            TEXT PRIMARY KEY,
+    title         TEXT NOT NULL DEFAULT '',
+    system_prompt TEXT NOT NULL DEFAULT '',
+    provider      TEXT NOT NULL DEFAULT '',
+    model         TEXT NOT NULL DEFAULT '',
+    message_count INTEGER NOT NULL DEFAULT 0,
+    created_at    TIMESTAMP NOT NULL,
+    updated_at    TIMESTAMP NOT NULL,
+    archived_at   TIMESTAMP,
+    deleted_at    TIMESTAMP
+);
+
+CREATE INDEX conversations_updated_at_idx ON conversations (updated_at DESC, id);
+
+-- seq keeps messages in the order they were appended.
+CREATE TABLE messages (
+    seq              INTEGER PRIMARY KEY AUTOINCREMENT,
+    id               TEXT NOT NULL UNIQUE,
+    conversation_id  TEXT NOT NULL REFERENCES conversations (id) ON DELETE CASCADE,
+    role             TEXT NOT NULL,
+    content          TEXT NOT NULL,
+    provider         TEXT NOT NULL DEFAULT '',
+    model            TEXT NOT NULL DEFAULT '',
+    attachments      TEXT,
+    usage            TEXT,
+    latency_ms       INTEGER NOT NULL DEFAULT 0,
+    finish_reason    TEXT NOT NULL DEFAULT '',
+    superseded       BOOLEAN NOT NULL DEFAULT FALSE,
+    regenerated_from TEXT NOT NULL DEFAULT '',
+    created_at       TIMESTAMP NOT NULL
+);
+
+CREATE INDEX messages_conversation_idx ON messages (conversation_id, seq);
@@ -0,0 +1,13 @@
+-- Audit records of permanently deleted conversations, written in the same
+-- transaction as the deletion. They keep no conversation content.
+CREATE TABLE conversation_deletions (
+    id                      TEXT PRIMARY KEY,
+    conversation_id         TEXT NOT NULL,
+    reason                  TEXT NOT NULL,
+    message_count           INTEGER NOT NULL DEFAULT 0,
+    conversation_created_at TIMESTAMP NOT NULL,
+    conversation_updated_at TIMESTAMP NOT NULL,
+    deleted_at              TIMESTAMP NOT NULL
+);
+
+CREATE INDEX conversation_deletions_deleted_at_idx ON conversation_deletions (deleted_at);
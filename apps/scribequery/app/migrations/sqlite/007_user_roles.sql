@@ -0,0 +1,2 @@
+-- role is "admin", "member" or "viewer"; see auth.Role.
+ALTER TABLE users ADD COLUMN role TEXT NOT NULL DEFAULT 'member';
@@ -0,0 +1,6 @@
+-- tenant_id is the customer a user or conversation belongs to, from the
+-- api key or token it was created with; empty in single-tenant deployments.
+ALTER TABLE users ADD COLUMN tenant_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE conversations ADD COLUMN tenant_id TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX conversations_tenant_idx ON conversations (tenant_id, updated_at DESC, id);
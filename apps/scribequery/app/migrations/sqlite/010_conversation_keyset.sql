@@ -0,0 +1,10 @@
+-- Listings page with (updated_at, id) < (cursor) ORDER BY updated_at DESC,
+-- id DESC, so the indexes order conversations updated at the same time by
+-- descending id too.
+DROP INDEX conversations_updated_at_idx;
+DROP INDEX conversations_owner_idx;
+DROP INDEX conversations_tenant_idx;
+
+CREATE INDEX conversations_updated_at_idx ON conversations (updated_at DESC, id DESC);
+CREATE INDEX conversations_owner_idx ON conversations (owner_id, updated_at DESC, id DESC);
+CREATE INDEX conversations_tenant_idx ON conversations (tenant_id, updated_at DESC, id DESC);
@@ -0,0 +1,5 @@
+-- Every request now acts for a tenant, DefaultTenant ("default") when its
+-- key or token names none, so rows saved without one move to it.
+UPDATE users SET tenant_id = 'default' WHERE tenant_id = '';
+UPDATE conversations SET tenant_id = 'default' WHERE tenant_id = '';
+UPDATE provider_credentials SET tenant_id = 'default' WHERE tenant_id = '';
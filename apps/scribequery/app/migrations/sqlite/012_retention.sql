@@ -0,0 +1,5 @@
+-- Deletion audit records name the tenant the conversation belonged to, and
+-- Purge finds conversations deleted before its cutoff through an index.
+ALTER TABLE conversation_deletions ADD COLUMN tenant_id TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX conversations_deleted_at_idx ON conversations (deleted_at) WHERE deleted_at IS NOT NULL;
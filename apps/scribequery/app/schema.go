@@ -0,0 +1,29 @@
+package app
+
+import "github.com/Joepolymath/DaVinci/libs/shared-go/infra/db/vector/weaviate"
+
+// chunkCollection holds embedded document chunks.
+const chunkCollection = "DocumentChunk"
+
+// weaviateSchema is the Weaviate schema scribequery expects. It is migrated
+// additively on startup; see weaviate.Migrator.
+var weaviateSchema = []weaviate.ClassSchema{
+	{
+		Name:        chunkCollection,
+		Description: "A chunk of an ingested document",
+		Distance:    "cosine",
+		Properties: []weaviate.Property{
+			{Name: "document_id", DataType: "text"},
+			{Name: "content", DataType: "text"},
+			{Name: "title", DataType: "text"},
+			{Name: "heading", DataType: "text"},
+			{Name: "source", DataType: "text"},
+			{Name: "chunk_index", DataType: "int"},
+			{Name: "page", DataType: "int"},
+			{Name: "start_offset", DataType: "int"},
+			{Name: "end_offset", DataType: "int"},
+			{Name: "owner_id", DataType: "text"},
+			{Name: "tenant_id", DataType: "text"},
+		},
+	},
+}
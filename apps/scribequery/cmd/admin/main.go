@@ -0,0 +1,54 @@
+// Command admin makes a registered user an admin. Anyone can register with
+// any email, so admins are granted here, by an operator with access to the
+// user store, rather than by the server.
+//
+//	go run ./apps/scribequery/cmd/admin -email you@example.com
+//
+// Without DATABASE_URL users live in USERS_FILE; stop the server first, or
+// it will overwrite the change with its own copy.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/Joepolymath/DaVinci/apps/scribequery/app"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/config"
+	"go.uber.org/zap"
+
+	// database/sql drivers for DATABASE_TYPE=postgres ("pgx") and
+	// DATABASE_TYPE=sqlite ("sqlite").
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
+)
+
+func main() {
+	email := flag.String("email", "", "email of the registered user to make an admin (required)")
+	flag.Parse()
+
+	if *email == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+
+	accounts, err := app.InitAccountService(cfg, logger)
+	if err != nil {
+		log.Fatalf("Failed to initialize accounts: %v", err)
+	}
+
+	user, err := accounts.Promote(context.Background(), *email)
+	if err != nil {
+		log.Fatalf("Failed to promote %s: %v", *email, err)
+	}
+	log.Printf("%s (%s) is an admin", user.Email, user.ID)
+}
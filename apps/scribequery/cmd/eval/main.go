@@ -0,0 +1,82 @@
+// Command eval runs a RAG evaluation dataset through retrieval and chat
+// and writes a JSON or CSV report.
+//
+//	go run ./apps/scribequery/cmd/eval -dataset cases.jsonl -format csv -out report.csv
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/Joepolymath/DaVinci/apps/scribequery/app"
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/eval"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/config"
+	"go.uber.org/zap"
+)
+
+func main() {
+	datasetPath := flag.String("dataset", "", "path to a JSON or JSONL dataset of cases (required)")
+	outPath := flag.String("out", "", "report path (default stdout)")
+	format := flag.String("format", eval.FormatJSON, "report format: json or csv")
+	k := flag.Int("k", 5, "number of retrieved chunks scored for recall@k")
+	flag.Parse()
+
+	if *datasetPath == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	f, err := os.Open(*datasetPath)
+	if err != nil {
+		log.Fatalf("Failed to open dataset: %v", err)
+	}
+	cases, err := eval.LoadDataset(f)
+	f.Close()
+	if err != nil {
+		log.Fatalf("Failed to load dataset: %v", err)
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+
+	services := app.InitServices(cfg, logger)
+	if services == nil {
+		log.Fatal("Failed to initialize services")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	report, err := services.EvalService.Run(ctx, cases, eval.RunOptions{
+		K:       *k,
+		Dataset: filepath.Base(*datasetPath),
+	})
+	if err != nil {
+		log.Fatalf("Eval failed: %v", err)
+	}
+
+	var out io.Writer = os.Stdout
+	if *outPath != "" {
+		file, err := os.Create(*outPath)
+		if err != nil {
+			log.Fatalf("Failed to create report: %v", err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	if err := eval.WriteReport(out, report, *format); err != nil {
+		log.Fatalf("Failed to write report: %v", err)
+	}
+}
@@ -11,12 +11,26 @@ import (
 
 	"github.com/Joepolymath/DaVinci/apps/scribequery/app"
 	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/handlers"
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/handlers/account"
 	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/handlers/chat"
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/handlers/conversation"
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/handlers/credential"
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/handlers/document"
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/handlers/feedback"
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/handlers/health"
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/handlers/provider"
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/handlers/retrieval"
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/handlers/share"
 	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/router"
 	sharedgo "github.com/Joepolymath/DaVinci/libs/shared-go"
 	"github.com/Joepolymath/DaVinci/libs/shared-go/config"
 	vector "github.com/Joepolymath/DaVinci/libs/shared-go/infra/db/vector/pinecone"
 	"go.uber.org/zap"
+
+	// database/sql drivers for DATABASE_TYPE=postgres ("pgx") and
+	// DATABASE_TYPE=sqlite ("sqlite").
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
 )
 
 func main() {
@@ -26,7 +40,7 @@ func main() {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
-	log.Println("Config loaded successfully", cfg)
+	log.Println("Config loaded successfully")
 
 	dimension := 0 // Will use default (1536) if not set
 	if dimStr := os.Getenv("PINECONE_DIMENSION"); dimStr != "" {
@@ -74,7 +88,16 @@ func main() {
 	env := handlers.NewEnvironment(cfg, appEnv, logger, services)
 
 	if err := router.InitHandlers(env, []handlers.IHandler{
+		&account.Handler{},
 		&chat.Handler{},
+		&conversation.Handler{},
+		&credential.Handler{},
+		&document.Handler{},
+		&feedback.Handler{},
+		&health.Handler{},
+		&provider.Handler{},
+		&retrieval.Handler{},
+		&share.Handler{},
 	}); err != nil {
 		logger.Error("Failed to initialize handlers", zap.Error(err))
 		return
@@ -0,0 +1,21 @@
+package account
+
+import "errors"
+
+var (
+	ErrAccountsDisabled = errors.New("user accounts are not enabled")
+
+	ErrUserNotFound         = errors.New("user not found")
+	ErrEmailTaken           = errors.New("email is already registered")
+	ErrInvalidCredentials   = errors.New("invalid email or password")
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+	ErrRefreshTokenRevoked  = errors.New("refresh token has been revoked")
+	ErrInvalidRefreshToken  = errors.New("invalid or expired refresh token")
+
+	ErrInvalidEmail    = errors.New("email is invalid")
+	ErrPasswordLength  = errors.New("password must be between 8 and 1024 characters")
+	ErrNameTooLong     = errors.New("name must be at most 200 characters")
+	ErrPasswordMissing = errors.New("password is required")
+	ErrInvalidRole     = errors.New("role must be admin, member or viewer")
+	ErrOwnRole         = errors.New("admins cannot change their own role")
+)
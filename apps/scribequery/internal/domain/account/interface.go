@@ -0,0 +1,52 @@
+package account
+
+import (
+	"context"
+	"time"
+)
+
+type Service interface {
+	// Register creates a user and signs them in.
+	Register(ctx context.Context, req *RegisterRequest) (*Session, error)
+	Login(ctx context.Context, req *LoginRequest) (*Session, error)
+	// Refresh exchanges a refresh token for a new session. The token is
+	// single-use: presenting it again revokes every session of its user.
+	Refresh(ctx context.Context, refreshToken string) (*Session, error)
+	// Logout revokes a refresh token; revoking an unknown or already
+	// revoked token is not an error.
+	Logout(ctx context.Context, refreshToken string) error
+
+	Get(ctx context.Context, id string) (*User, error)
+	// List returns the users of the current tenant, oldest first.
+	List(ctx context.Context) ([]User, error)
+	// SetRole changes a user's role. It takes effect when their access
+	// token is next refreshed.
+	SetRole(ctx context.Context, req *RoleRequest) (*User, error)
+	// Promote makes the user registered with email an admin. Registration
+	// does not prove that someone owns an email, so only operators call
+	// it, from the admin command, to seed the first admins; it needs no
+	// signed-in user and works with or without JWT_SECRET.
+	Promote(ctx context.Context, email string) (*User, error)
+}
+
+// Repository stores users and refresh tokens. Lookups return
+// ErrUserNotFound or ErrRefreshTokenNotFound for unknown keys.
+type Repository interface {
+	// CreateUser returns ErrEmailTaken when the email is registered.
+	CreateUser(ctx context.Context, u *User) error
+	GetUser(ctx context.Context, id string) (*User, error)
+	GetUserByEmail(ctx context.Context, email string) (*User, error)
+	// ListUsers returns the users of tenantID, or every user when it is
+	// empty, oldest first.
+	ListUsers(ctx context.Context, tenantID string) ([]User, error)
+	// UpdateUser returns ErrUserNotFound for an unknown user.
+	UpdateUser(ctx context.Context, u *User) error
+
+	CreateRefreshToken(ctx context.Context, t *RefreshToken) error
+	GetRefreshToken(ctx context.Context, hash string) (*RefreshToken, error)
+	// RevokeRefreshToken revokes a token unless it is already revoked, in
+	// which case it returns ErrRefreshTokenRevoked.
+	RevokeRefreshToken(ctx context.Context, id string, at time.Time) error
+	// RevokeUserTokens revokes every unrevoked token of a user.
+	RevokeUserTokens(ctx context.Context, userID string, at time.Time) error
+}
@@ -0,0 +1,231 @@
+package account
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Joepolymath/DaVinci/libs/shared-go/auth"
+)
+
+type memoryRepository struct {
+	mu     sync.RWMutex
+	users  map[string]User
+	tokens map[string]RefreshToken // by ID
+
+	// path, when set, is a JSON file rewritten after every change.
+	path string
+}
+
+type memorySnapshot struct {
+	Users         []memoryUser   `json:"users"`
+	RefreshTokens []RefreshToken `json:"refresh_tokens,omitempty"`
+}
+
+// memoryUser saves the password hash, which User leaves out of its JSON.
+type memoryUser struct {
+	User
+	PasswordHash string `json:"password_hash"`
+}
+
+// NewMemoryRepository creates an in-process repository, optionally saved
+// to a JSON file at path. The file holds password hashes, so it is
+// written readable by the owner only.
+func NewMemoryRepository(path string) (Repository, error) {
+	r := &memoryRepository{
+		users:  make(map[string]User),
+		tokens: make(map[string]RefreshToken),
+		path:   path,
+	}
+	if path == "" {
+		return r, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read users file: %w", err)
+	}
+
+	var snapshot memorySnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse users file: %w", err)
+	}
+	for _, u := range snapshot.Users {
+		u.User.PasswordHash = u.PasswordHash
+		if u.Role == "" {
+			// Saved before users had roles.
+			u.Role = auth.RoleMember
+		}
+		if u.TenantID == "" {
+			// Saved before every user had a tenant.
+			u.TenantID = auth.DefaultTenant
+		}
+		r.users[u.ID] = u.User
+	}
+	for _, t := range snapshot.RefreshTokens {
+		r.tokens[t.ID] = t
+	}
+	return r, nil
+}
+
+func (r *memoryRepository) CreateUser(ctx context.Context, u *User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.users {
+		if existing.Email == u.Email {
+			return ErrEmailTaken
+		}
+	}
+	r.users[u.ID] = *u
+	return r.save()
+}
+
+func (r *memoryRepository) GetUser(ctx context.Context, id string) (*User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	u, ok := r.users[id]
+	if !ok {
+		return nil, ErrUserNotFound
+	}
+	return &u, nil
+}
+
+func (r *memoryRepository) GetUserByEmail(ctx context.Context, email string) (*User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, u := range r.users {
+		if u.Email == email {
+			return &u, nil
+		}
+	}
+	return nil, ErrUserNotFound
+}
+
+func (r *memoryRepository) ListUsers(ctx context.Context, tenantID string) ([]User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	users := []User{}
+	for _, u := range r.users {
+		if tenantID == "" || u.TenantID == tenantID {
+			users = append(users, u)
+		}
+	}
+	slices.SortFunc(users, func(a, b User) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return users, nil
+}
+
+func (r *memoryRepository) UpdateUser(ctx context.Context, u *User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.users[u.ID]; !ok {
+		return ErrUserNotFound
+	}
+	r.users[u.ID] = *u
+	return r.save()
+}
+
+func (r *memoryRepository) CreateRefreshToken(ctx context.Context, t *RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.tokens[t.ID] = *t
+	return r.save()
+}
+
+func (r *memoryRepository) GetRefreshToken(ctx context.Context, hash string) (*RefreshToken, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, t := range r.tokens {
+		if t.Hash == hash {
+			return &t, nil
+		}
+	}
+	return nil, ErrRefreshTokenNotFound
+}
+
+func (r *memoryRepository) RevokeRefreshToken(ctx context.Context, id string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t, ok := r.tokens[id]
+	switch {
+	case !ok:
+		return ErrRefreshTokenNotFound
+	case t.RevokedAt != nil:
+		return ErrRefreshTokenRevoked
+	}
+	t.RevokedAt = &at
+	r.tokens[id] = t
+	return r.save()
+}
+
+func (r *memoryRepository) RevokeUserTokens(ctx context.Context, userID string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, t := range r.tokens {
+		if t.UserID == userID && t.RevokedAt == nil {
+			t.RevokedAt = &at
+			r.tokens[id] = t
+		}
+	}
+	return r.save()
+}
+
+// save rewrites the file through a temporary file and rename, dropping
+// expired refresh tokens. Callers hold the write lock.
+func (r *memoryRepository) save() error {
+	if r.path == "" {
+		return nil
+	}
+
+	snapshot := memorySnapshot{
+		Users:         make([]memoryUser, 0, len(r.users)),
+		RefreshTokens: make([]RefreshToken, 0, len(r.tokens)),
+	}
+	for _, u := range r.users {
+		snapshot.Users = append(snapshot.Users, memoryUser{User: u, PasswordHash: u.PasswordHash})
+	}
+	now := time.Now()
+	for id, t := range r.tokens {
+		if now.After(t.ExpiresAt) {
+			delete(r.tokens, id)
+			continue
+		}
+		snapshot.RefreshTokens = append(snapshot.RefreshTokens, t)
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to encode users: %w", err)
+	}
+
+	tmp := r.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to save users: %w", err)
+	}
+	if err := os.Rename(tmp, r.path); err != nil {
+		return fmt.Errorf("failed to save users: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package account
+
+import (
+	"net/mail"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Joepolymath/DaVinci/libs/shared-go/auth"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/validate"
+)
+
+const (
+	minPasswordLength = 8
+	maxPasswordLength = 1024
+	maxNameLength     = 200
+)
+
+// User is a registered account. Its ID is the subject of the user's
+// access tokens and the owner ID of their conversations and documents, and
+// its Role and TenantID are their "role" and "tenant" claims.
+type User struct {
+	ID           string    `json:"id"`
+	Email        string    `json:"email"` // lower-cased
+	Name         string    `json:"name,omitempty"`
+	Role         auth.Role `json:"role"`
+	TenantID     string    `json:"tenant_id,omitempty"`
+	PasswordHash string    `json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// RefreshToken is an issued refresh token. Only the SHA-256 of the token
+// is stored, so a leaked store cannot be used to sign in.
+type RefreshToken struct {
+	ID        string     `json:"id"`
+	UserID    string     `json:"user_id"`
+	Hash      string     `json:"hash"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+type RegisterRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	Name     string `json:"name,omitempty"`
+}
+
+// Validate checks the request and normalizes its email.
+func (r *RegisterRequest) Validate() error {
+	var errs validate.Errors
+	email, ok := normalizeEmail(r.Email)
+	if !ok {
+		errs.Add("email", ErrInvalidEmail)
+	}
+	r.Email = email
+	if n := utf8.RuneCountInString(r.Password); n < minPasswordLength || n > maxPasswordLength {
+		errs.Add("password", ErrPasswordLength)
+	}
+	r.Name = strings.TrimSpace(r.Name)
+	if utf8.RuneCountInString(r.Name) > maxNameLength {
+		errs.Add("name", ErrNameTooLong)
+	}
+	return errs.Err()
+}
+
+type LoginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// Validate checks the request and normalizes its email.
+func (r *LoginRequest) Validate() error {
+	var errs validate.Errors
+	email, ok := normalizeEmail(r.Email)
+	if !ok {
+		errs.Add("email", ErrInvalidEmail)
+	}
+	r.Email = email
+	if r.Password == "" {
+		errs.Add("password", ErrPasswordMissing)
+	}
+	return errs.Err()
+}
+
+type RoleRequest struct {
+	UserID string    `json:"-"`
+	Role   auth.Role `json:"role"`
+}
+
+func (r *RoleRequest) Validate() error {
+	var errs validate.Errors
+	if !r.Role.Valid() {
+		errs.Add("role", ErrInvalidRole)
+	}
+	return errs.Err()
+}
+
+// Session is the result of signing in: a short-lived access token for the
+// Authorization header and a refresh token to get the next one.
+type Session struct {
+	User             User      `json:"user"`
+	AccessToken      string    `json:"access_token"`
+	TokenType        string    `json:"token_type"`
+	ExpiresIn        int       `json:"expires_in"` // seconds
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
+
+// normalizeEmail trims and lower-cases a bare address such as
+// "Ada@example.com", rejecting display names and malformed addresses.
+func normalizeEmail(email string) (string, bool) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return email, false
+	}
+	return email, true
+}
@@ -0,0 +1,322 @@
+package account
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Joepolymath/DaVinci/libs/shared-go/auth"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultAccessTTL  = 15 * time.Minute
+	defaultRefreshTTL = 30 * 24 * time.Hour
+)
+
+type Config struct {
+	// Secret signs access tokens (HS256), so that the JWT_SECRET verifier
+	// accepts them. Without it every method returns ErrAccountsDisabled.
+	Secret   string
+	Issuer   string // "iss" of access tokens, if set
+	Audience string // "aud" of access tokens, if set
+
+	AccessTTL  time.Duration // default: 15m
+	RefreshTTL time.Duration // default: 30 days
+}
+
+type service struct {
+	repo   Repository
+	config Config
+	logger *zap.Logger
+
+	// dummyHash is checked against for unknown emails, so that a login
+	// takes as long whether or not the email is registered.
+	dummyHash     string
+	dummyHashOnce sync.Once
+}
+
+func NewService(repo Repository, cfg Config, logger *zap.Logger) Service {
+	if cfg.AccessTTL <= 0 {
+		cfg.AccessTTL = defaultAccessTTL
+	}
+	if cfg.RefreshTTL <= 0 {
+		cfg.RefreshTTL = defaultRefreshTTL
+	}
+	return &service{repo: repo, config: cfg, logger: logger}
+}
+
+func (s *service) enabled() bool {
+	return s.repo != nil && s.config.Secret != ""
+}
+
+func (s *service) Register(ctx context.Context, req *RegisterRequest) (*Session, error) {
+	if !s.enabled() {
+		return nil, ErrAccountsDisabled
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	hash, err := hashPassword(req.Password)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	user := &User{
+		ID:           uuid.NewString(),
+		Email:        req.Email,
+		Name:         req.Name,
+		Role:         auth.RoleMember,
+		TenantID:     auth.Tenant(ctx),
+		PasswordHash: hash,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if err := s.repo.CreateUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("User registered", zap.String("user_id", user.ID))
+	return s.newSession(ctx, user)
+}
+
+func (s *service) Login(ctx context.Context, req *LoginRequest) (*Session, error) {
+	if !s.enabled() {
+		return nil, ErrAccountsDisabled
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	user, err := s.repo.GetUserByEmail(ctx, req.Email)
+	if errors.Is(err, ErrUserNotFound) {
+		s.dummyHashOnce.Do(func() { s.dummyHash, _ = hashPassword(rand.Text()) })
+		checkPassword(s.dummyHash, req.Password)
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := checkPassword(user.PasswordHash, req.Password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check password of user %s: %w", user.ID, err)
+	}
+	if !ok || !inTenant(ctx, user) {
+		return nil, ErrInvalidCredentials
+	}
+	return s.newSession(ctx, user)
+}
+
+func (s *service) Refresh(ctx context.Context, refreshToken string) (*Session, error) {
+	if !s.enabled() {
+		return nil, ErrAccountsDisabled
+	}
+
+	token, err := s.repo.GetRefreshToken(ctx, hashToken(refreshToken))
+	if errors.Is(err, ErrRefreshTokenNotFound) {
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	if time.Now().After(token.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	// Revoking is conditional, so of two requests racing with the same
+	// token only one gets a session.
+	now := time.Now().UTC()
+	err = s.repo.RevokeRefreshToken(ctx, token.ID, now)
+	if errors.Is(err, ErrRefreshTokenRevoked) {
+		// A rotated token was used again: either it was stolen or the
+		// thief already used it. Sign the user out everywhere.
+		if err := s.repo.RevokeUserTokens(ctx, token.UserID, now); err != nil {
+			return nil, err
+		}
+		s.logger.Warn("Refresh token reused; revoked all sessions",
+			zap.String("user_id", token.UserID), zap.String("token_id", token.ID))
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.repo.GetUser(ctx, token.UserID)
+	if errors.Is(err, ErrUserNotFound) || err == nil && !inTenant(ctx, user) {
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.newSession(ctx, user)
+}
+
+func (s *service) Logout(ctx context.Context, refreshToken string) error {
+	if !s.enabled() {
+		return ErrAccountsDisabled
+	}
+
+	token, err := s.repo.GetRefreshToken(ctx, hashToken(refreshToken))
+	if errors.Is(err, ErrRefreshTokenNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	err = s.repo.RevokeRefreshToken(ctx, token.ID, time.Now().UTC())
+	if err != nil && !errors.Is(err, ErrRefreshTokenRevoked) {
+		return err
+	}
+	return nil
+}
+
+func (s *service) Get(ctx context.Context, id string) (*User, error) {
+	if !s.enabled() {
+		return nil, ErrAccountsDisabled
+	}
+	return s.get(ctx, id)
+}
+
+// get returns the user unless they belong to another tenant.
+func (s *service) get(ctx context.Context, id string) (*User, error) {
+	user, err := s.repo.GetUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !inTenant(ctx, user) {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+// inTenant reports whether user belongs to the tenant of ctx.
+func inTenant(ctx context.Context, user *User) bool {
+	return user.TenantID == auth.Tenant(ctx)
+}
+
+func (s *service) List(ctx context.Context) ([]User, error) {
+	if !s.enabled() {
+		return nil, ErrAccountsDisabled
+	}
+	return s.repo.ListUsers(ctx, auth.Tenant(ctx))
+}
+
+func (s *service) SetRole(ctx context.Context, req *RoleRequest) (*User, error) {
+	if !s.enabled() {
+		return nil, ErrAccountsDisabled
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	// Otherwise the last admin could demote themselves and leave nobody
+	// able to manage users.
+	if current, ok := auth.CurrentUser(ctx); ok && current.ID == req.UserID {
+		return nil, ErrOwnRole
+	}
+
+	user, err := s.get(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == req.Role {
+		return user, nil
+	}
+	previous := user.Role
+	user.Role = req.Role
+	user.UpdatedAt = time.Now().UTC()
+	if err := s.repo.UpdateUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("User role changed",
+		zap.String("user_id", user.ID),
+		zap.String("from", string(previous)),
+		zap.String("to", string(user.Role)))
+	return user, nil
+}
+
+func (s *service) Promote(ctx context.Context, email string) (*User, error) {
+	if s.repo == nil {
+		return nil, ErrAccountsDisabled
+	}
+	email, ok := normalizeEmail(email)
+	if !ok {
+		return nil, ErrInvalidEmail
+	}
+
+	user, err := s.repo.GetUserByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == auth.RoleAdmin {
+		return user, nil
+	}
+	previous := user.Role
+	user.Role = auth.RoleAdmin
+	user.UpdatedAt = time.Now().UTC()
+	if err := s.repo.UpdateUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("User promoted to admin",
+		zap.String("user_id", user.ID),
+		zap.String("from", string(previous)))
+	return user, nil
+}
+
+// newSession issues an access token and a refresh token for user.
+func (s *service) newSession(ctx context.Context, user *User) (*Session, error) {
+	now := time.Now().UTC()
+	claims := &auth.Claims{
+		Subject:   user.ID,
+		Issuer:    s.config.Issuer,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(s.config.AccessTTL).Unix(),
+		Email:     user.Email,
+		Name:      user.Name,
+		Role:      string(user.Role),
+		Tenant:    user.TenantID,
+	}
+	if s.config.Audience != "" {
+		claims.Audience = []string{s.config.Audience}
+	}
+	accessToken, err := auth.SignHS256([]byte(s.config.Secret), claims)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign access token: %w", err)
+	}
+
+	refreshToken := rand.Text()
+	token := &RefreshToken{
+		ID:        uuid.NewString(),
+		UserID:    user.ID,
+		Hash:      hashToken(refreshToken),
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.config.RefreshTTL),
+	}
+	if err := s.repo.CreateRefreshToken(ctx, token); err != nil {
+		return nil, err
+	}
+
+	return &Session{
+		User:             *user,
+		AccessToken:      accessToken,
+		TokenType:        "Bearer",
+		ExpiresIn:        int(s.config.AccessTTL / time.Second),
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: token.ExpiresAt,
+	}, nil
+}
+
+// hashToken is the stored form of a refresh token: its SHA-256, in hex.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,207 @@
+package account
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	userColumns  = `id, email, name, role, tenant_id, password_hash, created_at, updated_at`
+	tokenColumns = `id, user_id, token_hash, created_at, expires_at, revoked_at`
+)
+
+type sqlRepository struct {
+	db *sql.DB
+	// placeholder prefixes numbered parameters: "$" for Postgres ($1),
+	// "?" for SQLite (?1).
+	placeholder string
+}
+
+// NewPostgresRepository creates a repository on the users and
+// refresh_tokens tables of a Postgres database (see the scribequery
+// migrations).
+func NewPostgresRepository(db *sql.DB) Repository {
+	return &sqlRepository{db: db, placeholder: "$"}
+}
+
+// NewSQLiteRepository creates a repository on the users and
+// refresh_tokens tables of a SQLite database.
+func NewSQLiteRepository(db *sql.DB) Repository {
+	return &sqlRepository{db: db, placeholder: "?"}
+}
+
+func (r *sqlRepository) rebind(query string) string {
+	if r.placeholder == "$" {
+		return query
+	}
+	return strings.ReplaceAll(query, "$", r.placeholder)
+}
+
+func (r *sqlRepository) CreateUser(ctx context.Context, u *User) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+	defer tx.Rollback()
+
+	// The unique index on email is the last line of defence; checking
+	// first reports the common case without parsing driver errors.
+	var taken bool
+	if err := tx.QueryRowContext(ctx, r.rebind(
+		`SELECT EXISTS (SELECT 1 FROM users WHERE email = $1)`), u.Email,
+	).Scan(&taken); err != nil {
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+	if taken {
+		return ErrEmailTaken
+	}
+
+	if _, err := tx.ExecContext(ctx, r.rebind(`INSERT INTO users (`+userColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`),
+		u.ID, u.Email, u.Name, u.Role, u.TenantID, u.PasswordHash, u.CreatedAt, u.UpdatedAt,
+	); err != nil {
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+	return nil
+}
+
+func (r *sqlRepository) GetUser(ctx context.Context, id string) (*User, error) {
+	return r.getUser(ctx, `id = $1`, id)
+}
+
+func (r *sqlRepository) GetUserByEmail(ctx context.Context, email string) (*User, error) {
+	return r.getUser(ctx, `email = $1`, email)
+}
+
+func (r *sqlRepository) getUser(ctx context.Context, where string, arg any) (*User, error) {
+	u, err := scanUser(r.db.QueryRowContext(ctx, r.rebind(`SELECT `+userColumns+` FROM users WHERE `+where), arg))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return u, nil
+}
+
+func (r *sqlRepository) ListUsers(ctx context.Context, tenantID string) ([]User, error) {
+	query, args := `SELECT `+userColumns+` FROM users ORDER BY created_at, id`, []any{}
+	if tenantID != "" {
+		query, args = `SELECT `+userColumns+` FROM users WHERE tenant_id = $1 ORDER BY created_at, id`, []any{tenantID}
+	}
+	rows, err := r.db.QueryContext(ctx, r.rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	defer rows.Close()
+
+	users := []User{}
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list users: %w", err)
+		}
+		users = append(users, *u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	return users, nil
+}
+
+func (r *sqlRepository) UpdateUser(ctx context.Context, u *User) error {
+	result, err := r.db.ExecContext(ctx, r.rebind(
+		`UPDATE users SET email = $2, name = $3, role = $4, password_hash = $5, updated_at = $6 WHERE id = $1`),
+		u.ID, u.Email, u.Name, u.Role, u.PasswordHash, u.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+	if n == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanUser(row scanner) (*User, error) {
+	var u User
+	if err := row.Scan(&u.ID, &u.Email, &u.Name, &u.Role, &u.TenantID, &u.PasswordHash, &u.CreatedAt, &u.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+func (r *sqlRepository) CreateRefreshToken(ctx context.Context, t *RefreshToken) error {
+	if _, err := r.db.ExecContext(ctx, r.rebind(`INSERT INTO refresh_tokens (`+tokenColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6)`),
+		t.ID, t.UserID, t.Hash, t.CreatedAt, t.ExpiresAt, t.RevokedAt,
+	); err != nil {
+		return fmt.Errorf("failed to create refresh token: %w", err)
+	}
+	return nil
+}
+
+func (r *sqlRepository) GetRefreshToken(ctx context.Context, hash string) (*RefreshToken, error) {
+	var t RefreshToken
+	var revokedAt sql.NullTime
+	err := r.db.QueryRowContext(ctx, r.rebind(`SELECT `+tokenColumns+` FROM refresh_tokens WHERE token_hash = $1`), hash).
+		Scan(&t.ID, &t.UserID, &t.Hash, &t.CreatedAt, &t.ExpiresAt, &revokedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrRefreshTokenNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+	if revokedAt.Valid {
+		t.RevokedAt = &revokedAt.Time
+	}
+	return &t, nil
+}
+
+func (r *sqlRepository) RevokeRefreshToken(ctx context.Context, id string, at time.Time) error {
+	result, err := r.db.ExecContext(ctx, r.rebind(
+		`UPDATE refresh_tokens SET revoked_at = $2 WHERE id = $1 AND revoked_at IS NULL`), id, at)
+	if err != nil {
+		return fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to revoke refresh token: %w", err)
+	} else if n > 0 {
+		return nil
+	}
+
+	// Nothing was updated: tell an unknown token from a revoked one.
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, r.rebind(
+		`SELECT EXISTS (SELECT 1 FROM refresh_tokens WHERE id = $1)`), id,
+	).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+	if !exists {
+		return ErrRefreshTokenNotFound
+	}
+	return ErrRefreshTokenRevoked
+}
+
+func (r *sqlRepository) RevokeUserTokens(ctx context.Context, userID string, at time.Time) error {
+	if _, err := r.db.ExecContext(ctx, r.rebind(
+		`UPDATE refresh_tokens SET revoked_at = $2 WHERE user_id = $1 AND revoked_at IS NULL`), userID, at,
+	); err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+package chat
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/ai"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/text/chunker"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultAttachmentTokens    = 3000
+	defaultAttachmentChunkSize = 1000
+)
+
+// Attachment is a file uploaded with a chat message. Documents carry their
+// extracted Text; images carry their raw bytes in Image instead.
+type Attachment struct {
+	Name      string `json:"name"`
+	MediaType string `json:"media_type"`
+	Text      string `json:"-"`
+	Image     []byte `json:"-"`
+}
+
+// AttachmentConfig limits how much attachment text is sent with a turn.
+// Zero values use the defaults.
+type AttachmentConfig struct {
+	TokenBudget int // estimated tokens of attachment text sent (default: 3000)
+	ChunkSize   int // characters per excerpt when attachments exceed the budget (default: 1000)
+}
+
+func (c AttachmentConfig) withDefaults() AttachmentConfig {
+	if c.TokenBudget <= 0 {
+		c.TokenBudget = defaultAttachmentTokens
+	}
+	if c.ChunkSize <= 0 {
+		c.ChunkSize = defaultAttachmentChunkSize
+	}
+	return c
+}
+
+// excerpt is a piece of an attachment's text.
+type excerpt struct {
+	name  string
+	index int
+	text  string
+	score float64
+}
+
+// attach makes attachments available to the last message, which must be
+// the user's: images are added to it, and document text is sent in a
+// system message just before it. Text that does not fit the token budget
+// is split into excerpts, and those most similar to the question are kept
+// (or the leading ones, without an embeddings provider).
+func (s *service) attach(ctx context.Context, messages []ai.Message, attachments []Attachment) []ai.Message {
+	if len(attachments) == 0 || len(messages) == 0 {
+		return messages
+	}
+
+	last := len(messages) - 1
+	question := messages[last]
+	question.Images = append([][]byte(nil), question.Images...)
+
+	var documents []Attachment
+	for _, a := range attachments {
+		if len(a.Image) > 0 {
+			question.Images = append(question.Images, a.Image)
+		} else if strings.TrimSpace(a.Text) != "" {
+			documents = append(documents, a)
+		}
+	}
+
+	out := make([]ai.Message, 0, len(messages)+1)
+	out = append(out, messages[:last]...)
+	if len(documents) > 0 {
+		excerpts := s.selectExcerpts(ctx, question.Content, documents)
+		out = append(out, attachmentMessage(excerpts))
+	}
+	return append(out, question)
+}
+
+// selectExcerpts returns the attachment text to send, in document order.
+func (s *service) selectExcerpts(ctx context.Context, question string, documents []Attachment) []excerpt {
+	cfg := s.cfg.Attachments
+
+	total := 0
+	for _, d := range documents {
+		total += ai.EstimateTokens(d.Text)
+	}
+	if total <= cfg.TokenBudget {
+		excerpts := make([]excerpt, len(documents))
+		for i, d := range documents {
+			excerpts[i] = excerpt{name: d.Name, text: d.Text}
+		}
+		return excerpts
+	}
+
+	split, err := chunker.New(chunker.StrategyRecursive, chunker.Config{Size: cfg.ChunkSize})
+	if err != nil {
+		s.logger.Warn("Failed to split attachments", zap.Error(err))
+		return nil
+	}
+
+	var candidates []excerpt
+	for _, d := range documents {
+		for _, c := range split.Split(d.Text) {
+			candidates = append(candidates, excerpt{name: d.Name, index: c.Index, text: c.Text})
+		}
+	}
+	s.rankExcerpts(ctx, question, candidates)
+
+	var kept []excerpt
+	used := 0
+	for _, e := range candidates {
+		cost := ai.EstimateTokens(e.text)
+		if used+cost > cfg.TokenBudget {
+			continue
+		}
+		used += cost
+		kept = append(kept, e)
+	}
+	if len(kept) == 0 && len(candidates) > 0 {
+		// The budget is smaller than one excerpt; send the best one cut short.
+		best := candidates[0]
+		best.text = truncateTokens(best.text, cfg.TokenBudget)
+		kept = append(kept, best)
+	}
+
+	// Put the kept excerpts back in reading order.
+	order := make(map[string]int, len(documents))
+	for i, d := range documents {
+		if _, ok := order[d.Name]; !ok {
+			order[d.Name] = i
+		}
+	}
+	sort.SliceStable(kept, func(i, j int) bool {
+		if kept[i].name != kept[j].name {
+			return order[kept[i].name] < order[kept[j].name]
+		}
+		return kept[i].index < kept[j].index
+	})
+	return kept
+}
+
+// rankExcerpts sorts excerpts by embedding similarity to question. Without
+// an embeddings provider, or if embedding fails, the order is unchanged.
+func (s *service) rankExcerpts(ctx context.Context, question string, excerpts []excerpt) {
+	if s.embeddings == nil || !s.embeddings.IsEnabled() || strings.TrimSpace(question) == "" {
+		return
+	}
+
+	texts := make([]string, 0, len(excerpts)+1)
+	texts = append(texts, question)
+	for _, e := range excerpts {
+		texts = append(texts, e.text)
+	}
+	vectors, err := s.embeddings.Embed(ctx, texts)
+	if err != nil || len(vectors) != len(texts) {
+		s.logger.Warn("Failed to embed attachments, using leading excerpts", zap.Error(err))
+		return
+	}
+
+	for i := range excerpts {
+		excerpts[i].score = cosine(vectors[0], vectors[i+1])
+	}
+	sort.SliceStable(excerpts, func(i, j int) bool {
+		return excerpts[i].score > excerpts[j].score
+	})
+}
+
+// attachmentMessage renders excerpts as a system message, grouped by file.
+func attachmentMessage(excerpts []excerpt) ai.Message {
+	var b strings.Builder
+	b.WriteString("The user attached the following files to their message. ")
+	b.WriteString("Use them to answer; excerpts may be partial.\n")
+
+	current := ""
+	for _, e := range excerpts {
+		if e.name != current || current == "" {
+			fmt.Fprintf(&b, "\n### %s\n", e.name)
+			current = e.name
+		} else {
+			b.WriteString("\n…\n")
+		}
+		b.WriteString(strings.TrimSpace(e.text))
+		b.WriteString("\n")
+	}
+
+	return ai.Message{Role: ai.RoleSystem, Content: b.String()}
+}
+
+func cosine(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
@@ -0,0 +1,171 @@
+package chat
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// stopPollInterval is how often a generation checks the shared state for a
+// stop requested on another instance.
+const stopPollInterval = time.Second
+
+// GenerationState shares running generations between instances, so Stop
+// reaches a generation streaming on another one.
+type GenerationState interface {
+	// Track records that generationID is running for the conversation; it
+	// is called again while the generation runs to keep it alive, and the
+	// record expires if the instance dies. Untrack removes it.
+	Track(ctx context.Context, conversationID, generationID string) error
+	Untrack(ctx context.Context, conversationID, generationID string) error
+	// Running reports whether any instance is generating for the
+	// conversation.
+	Running(ctx context.Context, conversationID string) (bool, error)
+	// RequestStop asks every generation running for the conversation to
+	// stop. Generations notice it by StopCount changing.
+	RequestStop(ctx context.Context, conversationID string) error
+	StopCount(ctx context.Context, conversationID string) (int64, error)
+}
+
+// generations tracks in-flight completions per conversation so they can be
+// stopped from another request, on this instance or, through shared, on
+// another one.
+type generations struct {
+	mu     sync.Mutex
+	next   uint64
+	active map[string]map[uint64]context.CancelCauseFunc
+
+	shared GenerationState // nil: generations are local
+	logger *zap.Logger
+}
+
+// start derives a context that Stop cancels with ErrGenerationStopped.
+// Call done when the generation ends.
+func (g *generations) start(ctx context.Context, conversationID string) (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	if conversationID == "" {
+		return ctx, func() { cancel(nil) }
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.active == nil {
+		g.active = make(map[string]map[uint64]context.CancelCauseFunc)
+	}
+	if g.active[conversationID] == nil {
+		g.active[conversationID] = make(map[uint64]context.CancelCauseFunc)
+	}
+	g.next++
+	id := g.next
+	g.active[conversationID][id] = cancel
+
+	var watched chan struct{}
+	if g.shared != nil {
+		watched = make(chan struct{})
+		go func() {
+			defer close(watched)
+			g.watch(ctx, conversationID, cancel)
+		}()
+	}
+
+	return ctx, func() {
+		g.mu.Lock()
+		delete(g.active[conversationID], id)
+		if len(g.active[conversationID]) == 0 {
+			delete(g.active, conversationID)
+		}
+		g.mu.Unlock()
+		cancel(nil)
+		if watched != nil {
+			<-watched
+		}
+	}
+}
+
+// watch keeps the generation tracked in the shared state until ctx ends,
+// and cancels it when a stop is requested elsewhere.
+func (g *generations) watch(ctx context.Context, conversationID string, cancel context.CancelCauseFunc) {
+	generationID := uuid.NewString()
+	// The generation's ctx ends with it; shared state calls must outlive
+	// that to clean up.
+	background := context.WithoutCancel(ctx)
+
+	if err := g.shared.Track(background, conversationID, generationID); err != nil {
+		g.warn("Track generation", conversationID, err)
+	}
+	defer func() {
+		if err := g.shared.Untrack(background, conversationID, generationID); err != nil {
+			g.warn("Untrack generation", conversationID, err)
+		}
+	}()
+
+	baseline, err := g.shared.StopCount(background, conversationID)
+	if err != nil {
+		g.warn("Read stop requests", conversationID, err)
+	}
+
+	ticker := time.NewTicker(stopPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		count, err := g.shared.StopCount(ctx, conversationID)
+		if err != nil {
+			if ctx.Err() == nil {
+				g.warn("Read stop requests", conversationID, err)
+			}
+			continue
+		}
+		if count != baseline {
+			cancel(ErrGenerationStopped)
+			return
+		}
+		if err := g.shared.Track(ctx, conversationID, generationID); err != nil && ctx.Err() == nil {
+			g.warn("Track generation", conversationID, err)
+		}
+	}
+}
+
+// stop cancels every generation running for the conversation and reports
+// whether there were any.
+func (g *generations) stop(ctx context.Context, conversationID string) bool {
+	g.mu.Lock()
+	running := g.active[conversationID]
+	for _, cancel := range running {
+		cancel(ErrGenerationStopped)
+	}
+	stoppedLocal := len(running) > 0
+	g.mu.Unlock()
+
+	if g.shared == nil {
+		return stoppedLocal
+	}
+	elsewhere, err := g.shared.Running(ctx, conversationID)
+	if err != nil {
+		g.warn("Check running generations", conversationID, err)
+		return stoppedLocal
+	}
+	if elsewhere {
+		if err := g.shared.RequestStop(ctx, conversationID); err != nil {
+			g.warn("Request stop", conversationID, err)
+			return stoppedLocal
+		}
+	}
+	return stoppedLocal || elsewhere
+}
+
+func (g *generations) warn(action, conversationID string, err error) {
+	g.logger.Warn(action+" failed", zap.String("conversation_id", conversationID), zap.Error(err))
+}
+
+// stopped reports whether ctx was cancelled by stop.
+func stopped(ctx context.Context) bool {
+	return context.Cause(ctx) == ErrGenerationStopped
+}
@@ -0,0 +1,125 @@
+package chat
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/retrieval"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/ai"
+)
+
+const snippetLength = 200
+
+const groundingPrompt = `Answer the user's question using the numbered sources below.
+Cite every statement that relies on a source with its number in square brackets, e.g. [1] or [1][3].
+Only cite sources that support the statement. If the sources do not contain the answer, say so.
+
+Sources:
+`
+
+// citationMarker matches "[1]" and grouped markers such as "[1, 2]".
+var citationMarker = regexp.MustCompile(`\[(\d+(?:\s*,\s*\d+)*)\]`)
+
+// groundingMessage builds the system message listing chunks as numbered
+// sources, starting at [1].
+func groundingMessage(chunks []retrieval.Chunk) ai.Message {
+	var sb strings.Builder
+	sb.WriteString(groundingPrompt)
+	for i, c := range chunks {
+		fmt.Fprintf(&sb, "\n[%d]", i+1)
+		if label := sourceLabel(c); label != "" {
+			fmt.Fprintf(&sb, " %s", label)
+		}
+		fmt.Fprintf(&sb, "\n%s\n", c.Content)
+	}
+	return ai.Message{Role: ai.RoleSystem, Content: sb.String()}
+}
+
+func sourceLabel(c retrieval.Chunk) string {
+	var parts []string
+	if c.Title != "" {
+		parts = append(parts, c.Title)
+	}
+	if c.Heading != "" {
+		parts = append(parts, c.Heading)
+	}
+	if c.Page > 0 {
+		parts = append(parts, "page "+strconv.Itoa(c.Page))
+	}
+	if c.Source != "" && c.Source != c.Title {
+		parts = append(parts, c.Source)
+	}
+	return strings.Join(parts, " — ")
+}
+
+// applyCitations rewrites the source markers in answer as footnote markers
+// numbered by first appearance, drops markers that name no source, and
+// returns the cited sources with each marker's position in the new text.
+func applyCitations(answer string, chunks []retrieval.Chunk) (string, []Citation, []CitationMarker) {
+	var (
+		citations []Citation
+		markers   []CitationMarker
+		footnotes = make(map[int]int) // source number -> footnote number
+		out       strings.Builder
+		runes     int
+		last      int
+	)
+
+	write := func(s string) {
+		out.WriteString(s)
+		runes += utf8.RuneCountInString(s)
+	}
+
+	for _, m := range citationMarker.FindAllStringSubmatchIndex(answer, -1) {
+		var sources []int
+		for _, field := range strings.Split(answer[m[2]:m[3]], ",") {
+			if source, err := strconv.Atoi(strings.TrimSpace(field)); err == nil && source >= 1 && source <= len(chunks) {
+				sources = append(sources, source)
+			}
+		}
+
+		prefix := answer[last:m[0]]
+		if len(sources) == 0 && (m[1] == len(answer) || strings.ContainsAny(answer[m[1]:m[1]+1], ".,;:!?)")) {
+			prefix = strings.TrimRight(prefix, " ") // "claim [9]." -> "claim."
+		}
+		write(prefix)
+		last = m[1]
+
+		for _, source := range sources {
+			number, ok := footnotes[source]
+			if !ok {
+				number = len(citations) + 1
+				footnotes[source] = number
+				citations = append(citations, newCitation(number, chunks[source-1]))
+			}
+
+			start := runes
+			write("[" + strconv.Itoa(number) + "]")
+			markers = append(markers, CitationMarker{Number: number, Start: start, End: runes})
+		}
+	}
+	write(answer[last:])
+
+	return out.String(), citations, markers
+}
+
+func newCitation(number int, c retrieval.Chunk) Citation {
+	snippet := c.Content
+	if utf8.RuneCountInString(snippet) > snippetLength {
+		snippet = string([]rune(snippet)[:snippetLength]) + "…"
+	}
+	return Citation{
+		Number:      number,
+		DocumentID:  c.DocumentID,
+		ChunkID:     c.ID,
+		Title:       c.Title,
+		Source:      c.Source,
+		Page:        c.Page,
+		StartOffset: c.StartOffset,
+		EndOffset:   c.EndOffset,
+		Snippet:     snippet,
+	}
+}
@@ -0,0 +1,33 @@
+package chat
+
+import (
+	"context"
+
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/ai"
+)
+
+// Credentials looks up users' own provider keys. credential.Service
+// implements it.
+type Credentials interface {
+	// APIKey returns the current user's key for provider, or "" when they
+	// have none.
+	APIKey(ctx context.Context, provider string) (string, error)
+}
+
+// withUserKey makes requests made with ctx use the current user's own key
+// for each provider they stored one for, so their usage is billed to them.
+func (s *service) withUserKey(ctx context.Context) (context.Context, error) {
+	if s.credentials == nil {
+		return ctx, nil
+	}
+	for _, provider := range ai.KeyedProviders {
+		key, err := s.credentials.APIKey(ctx, string(provider))
+		if err != nil {
+			return ctx, err
+		}
+		if key != "" {
+			ctx = ai.WithAPIKey(ctx, provider, key)
+		}
+	}
+	return ctx, nil
+}
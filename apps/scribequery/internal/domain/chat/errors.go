@@ -1 +1,16 @@
 package chat
+
+import "errors"
+
+var (
+	ErrNoMessages      = errors.New("messages are required")
+	ErrInvalidRole     = errors.New("role must be system, user or assistant")
+	ErrEmptyMessage    = errors.New("message content is required")
+	ErrLastMessageRole = errors.New("the last message must be from the user")
+	ErrInvalidOptions  = errors.New("invalid chat options")
+
+	ErrConversationsDisabled = errors.New("conversations are not configured")
+	ErrNothingToRegenerate   = errors.New("the conversation has no user message to answer")
+	ErrGenerationStopped     = errors.New("generation was stopped")
+	ErrNoActiveGeneration    = errors.New("no generation is running for this conversation")
+)
@@ -0,0 +1,156 @@
+package chat
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/conversation"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/ai"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultHistoryMessages    = 20
+	defaultHistoryTokens      = 4000
+	defaultHistoryMessageSize = 1000
+)
+
+// HistoryConfig limits the stored conversation history sent with each
+// request. Zero values use the defaults.
+type HistoryConfig struct {
+	MaxMessages      int // most recent stored messages considered (default: 20)
+	TokenBudget      int // estimated tokens of history sent (default: 4000)
+	MaxMessageTokens int // longer stored messages are truncated to this (default: 1000)
+}
+
+func (c HistoryConfig) withDefaults() HistoryConfig {
+	if c.MaxMessages <= 0 {
+		c.MaxMessages = defaultHistoryMessages
+	}
+	if c.TokenBudget <= 0 {
+		c.TokenBudget = defaultHistoryTokens
+	}
+	if c.MaxMessageTokens <= 0 {
+		c.MaxMessageTokens = defaultHistoryMessageSize
+	}
+	return c
+}
+
+// assemble returns the messages to send for req: the system prompt, the
+// windowed history of its conversation (if any), then the new messages,
+// with the request's attachments added to the last one. It also returns
+// the conversation, if any.
+func (s *service) assemble(ctx context.Context, req *ChatRequest) (*conversation.Conversation, []ai.Message, error) {
+	var (
+		conv    *conversation.Conversation
+		history []conversation.Message
+	)
+	if req.ConversationID != "" {
+		if s.conversations == nil {
+			return nil, nil, ErrConversationsDisabled
+		}
+
+		var err error
+		conv, history, err = s.conversations.History(ctx, req.ConversationID)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return conv, s.attach(ctx, s.compose(conv, history, req.Messages), req.Attachments), nil
+}
+
+// compose windows history, appends the new messages and prepends the
+// system prompt unless the result already starts with a system message.
+// The prompt is conv's own, when it has one, or the configured default.
+func (s *service) compose(conv *conversation.Conversation, history []conversation.Message, newMessages []ai.Message) []ai.Message {
+	window := windowHistory(history, s.cfg.History)
+
+	messages := make([]ai.Message, 0, len(window)+len(newMessages)+1)
+	messages = append(messages, window...)
+	messages = append(messages, newMessages...)
+
+	prompt := s.cfg.SystemPrompt
+	if conv != nil && conv.SystemPrompt != "" {
+		prompt = conv.SystemPrompt
+	}
+	if prompt != "" && (len(messages) == 0 || messages[0].Role != ai.RoleSystem) {
+		messages = append([]ai.Message{{Role: ai.RoleSystem, Content: prompt}}, messages...)
+	}
+	return messages
+}
+
+// windowHistory keeps the most recent stored messages that fit cfg:
+// at most MaxMessages, each truncated to MaxMessageTokens, within
+// TokenBudget in total. The window never starts with an assistant message,
+// so it opens on the user turn the answer belongs to.
+func windowHistory(stored []conversation.Message, cfg HistoryConfig) []ai.Message {
+	if len(stored) > cfg.MaxMessages {
+		stored = stored[len(stored)-cfg.MaxMessages:]
+	}
+
+	start, used := len(stored), 0
+	window := make([]ai.Message, len(stored))
+	for i := len(stored) - 1; i >= 0; i-- {
+		m := stored[i].AIMessage()
+		m.Content = truncateTokens(m.Content, cfg.MaxMessageTokens)
+
+		cost := ai.EstimateMessageTokens([]ai.Message{m})
+		if used+cost > cfg.TokenBudget {
+			break
+		}
+		used += cost
+		window[i] = m
+		start = i
+	}
+	window = window[start:]
+
+	for len(window) > 0 && window[0].Role == ai.RoleAssistant {
+		window = window[1:]
+	}
+	return window
+}
+
+// truncateTokens shortens text to about tokens estimated tokens, cutting at
+// a word boundary where possible.
+func truncateTokens(text string, tokens int) string {
+	if ai.EstimateTokens(text) <= tokens {
+		return text
+	}
+
+	cut := []rune(text)
+	if limit := tokens * 4; len(cut) > limit {
+		cut = cut[:limit]
+	}
+	truncated := string(cut)
+	if i := strings.LastIndexAny(truncated, " \n"); i > len(truncated)/2 {
+		truncated = truncated[:i]
+	}
+	return strings.TrimSpace(truncated) + " …"
+}
+
+// record stores the new messages of req and the answer in its
+// conversation and returns the stored answer's ID. The answer has already
+// been generated, so a storage failure is logged rather than returned.
+func (s *service) record(ctx context.Context, req *ChatRequest, answer conversation.Message) string {
+	if req.ConversationID == "" || s.conversations == nil {
+		return ""
+	}
+
+	messages := make([]conversation.Message, 0, len(req.Messages)+1)
+	for _, m := range req.Messages {
+		messages = append(messages, conversation.Message{Role: m.Role, Content: m.Content})
+	}
+	for _, a := range req.Attachments {
+		last := &messages[len(messages)-1]
+		last.Attachments = append(last.Attachments, a.Name)
+	}
+	messages = append(messages, answer)
+
+	stored, err := s.conversations.Append(context.WithoutCancel(ctx), req.ConversationID, messages)
+	if err != nil {
+		s.logger.Error("Failed to store conversation messages",
+			zap.String("conversation_id", req.ConversationID), zap.Error(err))
+		return ""
+	}
+	return stored[len(stored)-1].ID
+}
@@ -7,6 +7,29 @@ import (
 )
 
 type Service interface {
-	Chat(ctx context.Context, messages []ai.Message) (ai.ChatResponse, error)
-	ChatStream(ctx context.Context, messages []ai.Message, onDelta func(delta ai.ChatStreamDelta) error) error
+	// Chat answers the conversation. When a retriever is configured, the
+	// answer is grounded in chunks retrieved for the last user message and
+	// the response carries citations. Invalid requests fail Validate.
+	Chat(ctx context.Context, req *ChatRequest) (ChatResponse, error)
+	// ChatStream answers like Chat, passing the answer to onDelta as it is
+	// generated. Deltas carry the source numbers the model wrote; the
+	// returned response has the whole answer with the markers rewritten
+	// as footnotes, its citations and the stored message's ID.
+	ChatStream(ctx context.Context, req *ChatRequest, onDelta func(delta ai.ChatStreamDelta) error) (ChatResponse, error)
+
+	// CheckModel returns the error Chat would for the model req's options
+	// set, so a stream can reject the request before it opens: a
+	// validation error for a model the provider does not serve, or
+	// conversation.ErrModelsUnavailable.
+	CheckModel(ctx context.Context, req *ChatRequest) error
+
+	// Regenerate replaces the last answer of a conversation with a new one,
+	// keeping the old answer for comparison.
+	Regenerate(ctx context.Context, req *RegenerateRequest) (ChatResponse, error)
+
+	// Stop cancels the generations running for a conversation; they return
+	// ErrGenerationStopped. A stopped stream stores the partial answer.
+	// It returns conversation.ErrConversationNotFound for a conversation
+	// the caller cannot see.
+	Stop(ctx context.Context, conversationID string) error
 }
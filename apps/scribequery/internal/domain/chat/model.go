@@ -1 +1,133 @@
 package chat
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/ai"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/validate"
+)
+
+// ChatResponse is a completion plus, when the answer was grounded in
+// retrieved chunks, the sources it cites.
+type ChatResponse struct {
+	ai.ChatResponse
+
+	// Citations lists the cited sources in footnote order; Number matches
+	// the "[n]" markers in Content. Markers gives the position of each
+	// marker in Content so clients can render footnotes.
+	Citations []Citation       `json:"citations,omitempty"`
+	Markers   []CitationMarker `json:"markers,omitempty"`
+
+	// ConversationID echoes the request's conversation, if any, and
+	// MessageID is the stored answer's ID within it.
+	ConversationID string `json:"conversation_id,omitempty"`
+	MessageID      string `json:"message_id,omitempty"`
+
+	// Provider names the provider that generated the answer, and LatencyMs
+	// is how long the turn took, retrieval included, in milliseconds.
+	Provider  string `json:"provider"`
+	LatencyMs int64  `json:"latency_ms"`
+}
+
+type Citation struct {
+	Number      int    `json:"number"`
+	DocumentID  string `json:"document_id"`
+	ChunkID     string `json:"chunk_id"`
+	Title       string `json:"title,omitempty"`
+	Source      string `json:"source,omitempty"` // file name or URL
+	Page        int    `json:"page,omitempty"`
+	StartOffset int    `json:"start_offset"` // character offsets of the chunk in the document
+	EndOffset   int    `json:"end_offset"`
+	Snippet     string `json:"snippet"`
+}
+
+// CitationMarker is a "[n]" marker in the answer, at character offsets
+// [Start, End).
+type CitationMarker struct {
+	Number int `json:"number"`
+	Start  int `json:"start"`
+	End    int `json:"end"`
+}
+
+// ChatRequest is a conversation to answer, oldest message first, with
+// optional generation parameters. With a ConversationID, Messages are the
+// new turn only: the stored history is sent before them, and they are
+// saved together with the answer. Attachments are files uploaded with the
+// last message; they are sent as context for this turn only.
+type ChatRequest struct {
+	ConversationID string          `json:"conversation_id,omitempty"`
+	Messages       []ai.Message    `json:"messages"`
+	Options        *ai.ChatOptions `json:"options,omitempty"`
+	Attachments    []Attachment    `json:"-"`
+}
+
+// Validate checks that every message has a known role and content (user
+// messages may carry images instead, and the last one may carry only
+// attachments), that the conversation ends with a user message, and that
+// sampling options are in range. All failed fields are reported.
+func (r *ChatRequest) Validate() error {
+	var errs validate.Errors
+	if len(r.Messages) == 0 {
+		errs.Add("messages", ErrNoMessages)
+	}
+
+	last := len(r.Messages) - 1
+	for i, m := range r.Messages {
+		field := validate.Index("messages", i)
+		switch m.Role {
+		case ai.RoleSystem, ai.RoleUser, ai.RoleAssistant:
+		default:
+			errs.Add(field+".role", ErrInvalidRole)
+		}
+		attached := len(m.Images) > 0 || (i == last && len(r.Attachments) > 0)
+		if strings.TrimSpace(m.Content) == "" && (m.Role != ai.RoleUser || !attached) {
+			errs.Add(field+".content", ErrEmptyMessage)
+		}
+	}
+	if last >= 0 && r.Messages[last].Role != ai.RoleUser {
+		errs.Add(validate.Index("messages", last)+".role", ErrLastMessageRole)
+	}
+
+	validateOptions(r.Options, &errs)
+	return errs.Err()
+}
+
+// RegenerateRequest asks for a new answer to the last user turn of a
+// conversation, optionally with different generation parameters.
+type RegenerateRequest struct {
+	ConversationID string          `json:"-"`
+	Options        *ai.ChatOptions `json:"options,omitempty"`
+}
+
+// Validate checks that sampling options are in range.
+func (r *RegenerateRequest) Validate() error {
+	var errs validate.Errors
+	validateOptions(r.Options, &errs)
+	return errs.Err()
+}
+
+// validateOptions adds an error to errs for each out-of-range option.
+func validateOptions(o *ai.ChatOptions, errs *validate.Errors) {
+	if o == nil {
+		return
+	}
+	if o.Temperature != nil && (*o.Temperature < 0 || *o.Temperature > 2) {
+		errs.Add("options.temperature", fmt.Errorf("%w: must be between 0 and 2", ErrInvalidOptions))
+	}
+	if o.TopP != nil && (*o.TopP < 0 || *o.TopP > 1) {
+		errs.Add("options.top_p", fmt.Errorf("%w: must be between 0 and 1", ErrInvalidOptions))
+	}
+	if o.MaxTokens < 0 {
+		errs.Add("options.max_tokens", fmt.Errorf("%w: must not be negative", ErrInvalidOptions))
+	}
+	if o.MaxCompletionTokens < 0 {
+		errs.Add("options.max_completion_tokens", fmt.Errorf("%w: must not be negative", ErrInvalidOptions))
+	}
+	if o.N < 0 {
+		errs.Add("options.n", fmt.Errorf("%w: must not be negative", ErrInvalidOptions))
+	}
+	if o.TopLogprobs != nil && (*o.TopLogprobs < 0 || *o.TopLogprobs > 20) {
+		errs.Add("options.top_logprobs", fmt.Errorf("%w: must be between 0 and 20", ErrInvalidOptions))
+	}
+}
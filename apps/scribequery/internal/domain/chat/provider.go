@@ -0,0 +1,92 @@
+package chat
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/conversation"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/ai"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/validate"
+	"go.uber.org/zap"
+)
+
+// target is the provider a turn is generated with, and the options sent
+// to it.
+type target struct {
+	name     string
+	provider ai.ChatProvider
+	options  *ai.ChatOptions
+}
+
+// model returns the model the turn is generated with.
+func (t target) model() string {
+	if t.options != nil && t.options.Model != "" {
+		return t.options.Model
+	}
+	return t.provider.GetModel()
+}
+
+// target picks the provider and model chosen for conv, falling back to the
+// default provider when conv has none or its provider is no longer
+// configured. A model set in opts takes precedence over conv's, and must
+// be one the provider serves: like a conversation's model, an unknown one
+// is a validation error and an unreachable model list
+// conversation.ErrModelsUnavailable.
+func (s *service) target(ctx context.Context, conv *conversation.Conversation, opts *ai.ChatOptions) (target, error) {
+	t := target{
+		name:     s.providers.DefaultName(),
+		provider: s.providers.Default(),
+		options:  opts,
+	}
+	if conv != nil && conv.Provider != "" {
+		if p, ok := s.providers.Get(conv.Provider); ok {
+			t.name, t.provider = conv.Provider, p
+		} else {
+			s.logger.Warn("Conversation provider is not configured, using the default",
+				zap.String("conversation_id", conv.ID), zap.String("provider", conv.Provider))
+			conv = nil
+		}
+	}
+
+	if opts != nil && opts.Model != "" {
+		ok, err := ai.Serves(ctx, t.provider, opts.Model)
+		if err != nil {
+			return target{}, fmt.Errorf("%w: %v", conversation.ErrModelsUnavailable, err)
+		}
+		if !ok {
+			var errs validate.Errors
+			errs.Add("options.model", fmt.Errorf("%w: %q", conversation.ErrUnknownModel, opts.Model))
+			return target{}, errs
+		}
+		return t, nil
+	}
+
+	if conv != nil && conv.Model != "" {
+		options := ai.ChatOptions{}
+		if opts != nil {
+			options = *opts
+		}
+		options.Model = conv.Model
+		t.options = &options
+	}
+	return t, nil
+}
+
+func (s *service) CheckModel(ctx context.Context, req *ChatRequest) error {
+	if req.Options == nil || req.Options.Model == "" {
+		return nil
+	}
+	ctx, err := s.withUserKey(ctx)
+	if err != nil {
+		return err
+	}
+
+	var conv *conversation.Conversation
+	if req.ConversationID != "" && s.conversations != nil {
+		if conv, _, err = s.conversations.History(ctx, req.ConversationID); err != nil {
+			return err
+		}
+	}
+	_, err = s.target(ctx, conv, req.Options)
+	return err
+}
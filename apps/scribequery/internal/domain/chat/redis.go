@@ -0,0 +1,100 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/redis"
+)
+
+const (
+	// generationTTL is how long a generation stays tracked without being
+	// refreshed, i.e. after its instance died.
+	generationTTL = 30 * time.Second
+	// stopCountTTL keeps a conversation's stop counter well past any
+	// generation; a counter that expired mid-generation would look like a
+	// new stop request.
+	stopCountTTL = 24 * time.Hour
+)
+
+// redisGenerations keeps each conversation's running generations in a
+// sorted set scored by expiry time, and its stop requests in a counter.
+type redisGenerations struct {
+	client *redis.Client
+}
+
+// NewRedisGenerationState shares running generations and stop requests
+// through Redis.
+func NewRedisGenerationState(client *redis.Client) GenerationState {
+	return &redisGenerations{client: client}
+}
+
+// A conversation's keys share the {id} hash tag, so trackScript, which
+// touches both, runs on a cluster too.
+func generationsKey(conversationID string) string {
+	return "generations:{" + conversationID + "}"
+}
+
+func stopCountKey(conversationID string) string {
+	return "generations:{" + conversationID + "}:stops"
+}
+
+// KEYS[1] generations, KEYS[2] stop counter; ARGV[1] member, ARGV[2]
+// generation ttl in ms, ARGV[3] stop counter ttl in ms.
+const trackScript = `
+local now = redis.call('TIME')
+local ms = now[1] * 1000 + math.floor(now[2] / 1000)
+redis.call('ZADD', KEYS[1], ms + ARGV[2], ARGV[1])
+redis.call('PEXPIRE', KEYS[1], ARGV[2])
+redis.call('PEXPIRE', KEYS[2], ARGV[3])
+return 1`
+
+// KEYS[1] generations; returns how many are live.
+const runningScript = `
+local now = redis.call('TIME')
+local ms = now[1] * 1000 + math.floor(now[2] / 1000)
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', ms)
+return redis.call('ZCARD', KEYS[1])`
+
+func (r *redisGenerations) Track(ctx context.Context, conversationID, generationID string) error {
+	_, err := r.client.Eval(ctx, trackScript,
+		[]string{generationsKey(conversationID), stopCountKey(conversationID)},
+		generationID, generationTTL, stopCountTTL)
+	return err
+}
+
+func (r *redisGenerations) Untrack(ctx context.Context, conversationID, generationID string) error {
+	_, err := r.client.Do(ctx, "ZREM", generationsKey(conversationID), generationID)
+	return err
+}
+
+func (r *redisGenerations) Running(ctx context.Context, conversationID string) (bool, error) {
+	reply, err := r.client.Eval(ctx, runningScript, []string{generationsKey(conversationID)})
+	if err != nil {
+		return false, err
+	}
+	n, _ := reply.(int64)
+	return n > 0, nil
+}
+
+func (r *redisGenerations) RequestStop(ctx context.Context, conversationID string) error {
+	key := stopCountKey(conversationID)
+	if _, err := r.client.Incr(ctx, key); err != nil {
+		return err
+	}
+	_, err := r.client.Expire(ctx, key, stopCountTTL)
+	return err
+}
+
+func (r *redisGenerations) StopCount(ctx context.Context, conversationID string) (int64, error) {
+	value, err := r.client.Get(ctx, stopCountKey(conversationID))
+	if errors.Is(err, redis.ErrNil) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(value, 10, 64)
+}
@@ -1 +1,22 @@
 package chat
+
+import (
+	"context"
+
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/conversation"
+)
+
+// Repository stores the history of chats that name a conversation.
+// conversation.Service implements it; for tests, use one backed by
+// conversation.NewMemoryRepository. Methods return
+// conversation.ErrConversationNotFound for unknown conversations.
+type Repository interface {
+	// History returns the conversation and its messages, oldest first,
+	// without superseded answers.
+	History(ctx context.Context, id string) (*conversation.Conversation, []conversation.Message, error)
+	// Append stores messages at the end of the conversation and returns
+	// them with IDs and timestamps.
+	Append(ctx context.Context, id string, messages []conversation.Message) ([]conversation.Message, error)
+	// Replace marks the message superseded and appends replacement.
+	Replace(ctx context.Context, id, messageID string, replacement conversation.Message) (*conversation.Message, error)
+}
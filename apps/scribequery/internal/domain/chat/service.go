@@ -2,28 +2,336 @@ package chat
 
 import (
 	"context"
+	"errors"
+	"strings"
+	"time"
 
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/conversation"
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/retrieval"
 	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/ai"
+	"go.uber.org/zap"
 )
 
+// contextCandidates is how many chunks are retrieved for packing into the
+// prompt; packing then keeps as many as the token budget allows.
+const contextCandidates = 12
+
+// Config holds the chat service settings. Zero values use the defaults.
+type Config struct {
+	// SystemPrompt is sent first on every request that does not start with
+	// its own system message, unless the conversation sets its own.
+	SystemPrompt string
+
+	History     HistoryConfig
+	Packing     retrieval.PackConfig
+	Attachments AttachmentConfig
+}
+
 type service struct {
-	aiProvider ai.ChatProvider
+	providers     *ai.ChatProviderRegistry
+	retriever     retrieval.Service
+	conversations Repository
+	embeddings    ai.EmbeddingsProvider
+	credentials   Credentials
+	cfg           Config
+	running       generations
+	logger        *zap.Logger
 }
 
-func NewService(aiProvider ai.ChatProvider) Service {
+// NewService creates the chat service. Turns are answered by the default
+// provider in providers unless their conversation chose another. retriever
+// may be nil, in which case answers are not grounded in documents.
+// conversations stores the history of requests that name a conversation;
+// cfg.History limits how much of it is sent and cfg.Packing limits the
+// retrieved context. embeddings, which may be nil, ranks excerpts of
+// attachments too long to send whole. shared, when not nil, lets Stop
+// reach generations running on other instances. credentials, when not nil,
+// supplies the users' own OpenAI keys.
+func NewService(providers *ai.ChatProviderRegistry, retriever retrieval.Service, conversations Repository, embeddings ai.EmbeddingsProvider, shared GenerationState, credentials Credentials, cfg Config, logger *zap.Logger) Service {
+	cfg.History = cfg.History.withDefaults()
+	cfg.Attachments = cfg.Attachments.withDefaults()
 	return &service{
-		aiProvider: aiProvider,
+		providers:     providers,
+		retriever:     retriever,
+		conversations: conversations,
+		embeddings:    embeddings,
+		credentials:   credentials,
+		cfg:           cfg,
+		running:       generations{shared: shared, logger: logger},
+		logger:        logger,
+	}
+}
+
+func (s *service) Chat(ctx context.Context, req *ChatRequest) (ChatResponse, error) {
+	if err := req.Validate(); err != nil {
+		return ChatResponse{}, err
+	}
+	ctx, err := s.withUserKey(ctx)
+	if err != nil {
+		return ChatResponse{}, err
+	}
+
+	conv, messages, err := s.assemble(ctx, req)
+	if err != nil {
+		return ChatResponse{}, err
+	}
+	t, err := s.target(ctx, conv, req.Options)
+	if err != nil {
+		return ChatResponse{}, err
+	}
+
+	ctx, done := s.running.start(ctx, req.ConversationID)
+	defer done()
+
+	response, err := s.complete(ctx, t, messages)
+	if err != nil {
+		if stopped(ctx) {
+			return ChatResponse{}, ErrGenerationStopped
+		}
+		return ChatResponse{}, err
+	}
+
+	response.ConversationID = req.ConversationID
+	response.MessageID = s.record(ctx, req, response.message())
+	return response, nil
+}
+
+// Regenerate answers the last user turn of a conversation again. The
+// previous answer is kept, marked superseded, and left out of the prompt.
+func (s *service) Regenerate(ctx context.Context, req *RegenerateRequest) (ChatResponse, error) {
+	if err := req.Validate(); err != nil {
+		return ChatResponse{}, err
+	}
+	if s.conversations == nil {
+		return ChatResponse{}, ErrConversationsDisabled
+	}
+	ctx, err := s.withUserKey(ctx)
+	if err != nil {
+		return ChatResponse{}, err
+	}
+
+	conv, history, err := s.conversations.History(ctx, req.ConversationID)
+	if err != nil {
+		return ChatResponse{}, err
+	}
+
+	var previous string
+	if n := len(history); n > 0 && history[n-1].Role == ai.RoleAssistant {
+		previous = history[n-1].ID
+		history = history[:n-1]
+	}
+	if len(history) == 0 || history[len(history)-1].Role != ai.RoleUser {
+		return ChatResponse{}, ErrNothingToRegenerate
+	}
+
+	t, err := s.target(ctx, conv, req.Options)
+	if err != nil {
+		return ChatResponse{}, err
+	}
+
+	ctx, done := s.running.start(ctx, req.ConversationID)
+	defer done()
+
+	response, err := s.complete(ctx, t, s.compose(conv, history, nil))
+	if err != nil {
+		if stopped(ctx) {
+			return ChatResponse{}, ErrGenerationStopped
+		}
+		return ChatResponse{}, err
+	}
+
+	answer := response.message()
+	var stored *conversation.Message
+	if previous != "" {
+		stored, err = s.conversations.Replace(context.WithoutCancel(ctx), req.ConversationID, previous, answer)
+	} else {
+		// The last turn has no answer yet, e.g. after a failed generation.
+		var appended []conversation.Message
+		appended, err = s.conversations.Append(context.WithoutCancel(ctx), req.ConversationID, []conversation.Message{answer})
+		if err == nil {
+			stored = &appended[0]
+		}
+	}
+	if err != nil {
+		return ChatResponse{}, err
+	}
+
+	response.ConversationID = req.ConversationID
+	response.MessageID = stored.ID
+	return response, nil
+}
+
+// complete grounds messages in retrieved chunks, when any are found, and
+// runs the completion with t. The response's latency covers both.
+func (s *service) complete(ctx context.Context, t target, messages []ai.Message) (ChatResponse, error) {
+	start := time.Now()
+	messages, chunks := s.ground(ctx, messages)
+
+	resp, err := t.provider.Completion(ctx, messages, t.options)
+	if err != nil {
+		return ChatResponse{}, err
+	}
+
+	response := ChatResponse{
+		ChatResponse: *resp,
+		Provider:     t.name,
+		LatencyMs:    time.Since(start).Milliseconds(),
+	}
+	response.cite(chunks)
+	return response, nil
+}
+
+// ground puts a message listing the chunks retrieved for the last user
+// message, when any are found, before messages. It returns the messages to
+// send and the chunks, numbered as the grounding message lists them.
+func (s *service) ground(ctx context.Context, messages []ai.Message) ([]ai.Message, []retrieval.Chunk) {
+	chunks := s.retrieve(ctx, messages)
+	if len(chunks) == 0 {
+		return messages, nil
+	}
+	return append([]ai.Message{groundingMessage(chunks)}, messages...), chunks
+}
+
+// cite rewrites the source markers in r's content as footnotes and sets
+// its citations, when the answer was grounded in chunks.
+func (r *ChatResponse) cite(chunks []retrieval.Chunk) {
+	if len(chunks) > 0 {
+		r.Content, r.Citations, r.Markers = applyCitations(r.Content, chunks)
+	}
+}
+
+// message returns the answer in r as a conversation message, with how it
+// was generated.
+func (r ChatResponse) message() conversation.Message {
+	usage := r.Usage
+	return conversation.Message{
+		Role:         ai.RoleAssistant,
+		Content:      r.Content,
+		Provider:     r.Provider,
+		Model:        r.Model,
+		Usage:        &usage,
+		LatencyMs:    r.LatencyMs,
+		FinishReason: r.FinishReason,
 	}
 }
 
-func (s *service) Chat(ctx context.Context, messages []ai.Message) (ai.ChatResponse, error) {
-	resp, err := s.aiProvider.Completion(ctx, messages, nil)
+func (s *service) ChatStream(ctx context.Context, req *ChatRequest, onDelta func(delta ai.ChatStreamDelta) error) (ChatResponse, error) {
+	if err := req.Validate(); err != nil {
+		return ChatResponse{}, err
+	}
+	ctx, err := s.withUserKey(ctx)
+	if err != nil {
+		return ChatResponse{}, err
+	}
+
+	conv, messages, err := s.assemble(ctx, req)
+	if err != nil {
+		return ChatResponse{}, err
+	}
+	t, err := s.target(ctx, conv, req.Options)
 	if err != nil {
-		return ai.ChatResponse{}, err
+		return ChatResponse{}, err
 	}
-	return *resp, nil
+
+	ctx, done := s.running.start(ctx, req.ConversationID)
+	defer done()
+
+	start := time.Now()
+	messages, chunks := s.ground(ctx, messages)
+
+	var content strings.Builder
+	response := ChatResponse{
+		ChatResponse:   ai.ChatResponse{Model: t.model()},
+		ConversationID: req.ConversationID,
+		Provider:       t.name,
+	}
+	err = t.provider.CompletionStream(ctx, messages, t.options, func(delta ai.ChatStreamDelta) error {
+		if delta.Index == 0 {
+			content.WriteString(delta.Content)
+			if delta.FinishReason != "" {
+				response.FinishReason = delta.FinishReason
+			}
+		}
+		if delta.Usage != nil {
+			response.Usage = *delta.Usage
+		}
+		if delta.RequestID != "" {
+			response.RequestID = delta.RequestID
+		}
+		return onDelta(delta)
+	})
+	response.Content = content.String()
+	response.LatencyMs = time.Since(start).Milliseconds()
+	response.cite(chunks)
+	if err != nil {
+		if !stopped(ctx) {
+			return ChatResponse{}, err
+		}
+		// Keep what was generated before the stop, as the client has
+		// already shown it.
+		if response.Content != "" {
+			s.record(ctx, req, response.streamed())
+		}
+		return ChatResponse{}, ErrGenerationStopped
+	}
+
+	response.MessageID = s.record(ctx, req, response.streamed())
+	return response, nil
 }
 
-func (s *service) ChatStream(ctx context.Context, messages []ai.Message, onDelta func(delta ai.ChatStreamDelta) error) error {
-	return s.aiProvider.CompletionStream(ctx, messages, nil, onDelta)
+// streamed returns the streamed answer in r as a conversation message.
+// Unlike message, it leaves out usage the provider did not report.
+func (r ChatResponse) streamed() conversation.Message {
+	m := r.message()
+	if r.Usage == (ai.ChatUsage{}) {
+		m.Usage = nil
+	}
+	return m
+}
+
+func (s *service) Stop(ctx context.Context, conversationID string) error {
+	// Only a caller who can see the conversation may stop it.
+	if s.conversations != nil {
+		if _, _, err := s.conversations.History(ctx, conversationID); err != nil {
+			return err
+		}
+	}
+	if !s.running.stop(ctx, conversationID) {
+		return ErrNoActiveGeneration
+	}
+	s.logger.Info("Generation stopped", zap.String("conversation_id", conversationID))
+	return nil
+}
+
+// retrieve returns chunks relevant to the last user message, packed to the
+// context token budget. Retrieval is best effort: failures are logged and
+// the question is answered ungrounded.
+func (s *service) retrieve(ctx context.Context, messages []ai.Message) []retrieval.Chunk {
+	if s.retriever == nil {
+		return nil
+	}
+
+	last := -1
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == ai.RoleUser {
+			last = i
+			break
+		}
+	}
+	if last < 0 || messages[last].Content == "" {
+		return nil
+	}
+
+	result, err := s.retriever.Retrieve(ctx, &retrieval.RetrieveRequest{
+		Query:   messages[last].Content,
+		History: messages[:last],
+		TopK:    contextCandidates,
+	})
+	if err != nil {
+		if !errors.Is(err, retrieval.ErrRetrievalDisabled) {
+			s.logger.Warn("Retrieval failed, answering without sources", zap.Error(err))
+		}
+		return nil
+	}
+	return retrieval.PackContext(result.Chunks, s.cfg.Packing)
 }
@@ -0,0 +1,18 @@
+package conversation
+
+import "errors"
+
+var (
+	ErrConversationNotFound = errors.New("conversation not found")
+	ErrMessageNotFound      = errors.New("message not found")
+	ErrTitleRequired        = errors.New("title is required")
+	ErrTitleTooLong         = errors.New("title must be at most 200 characters")
+	ErrSystemPromptTooLong  = errors.New("system prompt must be at most 20000 characters")
+	ErrUnknownProvider      = errors.New("unknown provider")
+	ErrUnknownModel         = errors.New("the provider does not serve this model")
+	ErrModelsUnavailable    = errors.New("failed to list the provider's models")
+	ErrInvalidStatus        = errors.New("status must be active, archived, all or deleted")
+	ErrEmptyQuery           = errors.New("search query is required")
+	ErrInvalidDateRange     = errors.New("from must be before to")
+	ErrRetentionDisabled    = errors.New("no retention period is configured")
+)
@@ -0,0 +1,95 @@
+package conversation
+
+import (
+	"context"
+	"time"
+
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/outbox"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/pagination"
+)
+
+type Service interface {
+	Create(ctx context.Context, req *CreateRequest) (*Conversation, error)
+	// List returns a page of the conversations with the requested status,
+	// most recently updated first.
+	List(ctx context.Context, req *ListRequest) (*ListResponse, error)
+	Get(ctx context.Context, id string) (*ConversationDetail, error)
+	// Messages returns a page of the conversation's messages, oldest
+	// first, including superseded answers.
+	Messages(ctx context.Context, id string, page pagination.Request) (*MessagesResponse, error)
+	// Search finds conversations by title and message text, best matches
+	// first.
+	Search(ctx context.Context, req *SearchRequest) (*SearchResponse, error)
+	Rename(ctx context.Context, id string, req *RenameRequest) (*Conversation, error)
+	SetSystemPrompt(ctx context.Context, id string, req *SystemPromptRequest) (*Conversation, error)
+	Archive(ctx context.Context, id string) (*Conversation, error)
+	Unarchive(ctx context.Context, id string) (*Conversation, error)
+	// Delete hides the conversation until Restore is called or it is
+	// purged after the retention window.
+	Delete(ctx context.Context, id string) error
+	Restore(ctx context.Context, id string) (*Conversation, error)
+	// Purge permanently removes conversations deleted longer ago than the
+	// retention window and returns how many it removed.
+	Purge(ctx context.Context) (int, error)
+	// ApplyRetention permanently deletes conversations that have not been
+	// updated for longer than their tenant's retention period, or with
+	// dryRun only reports them. It returns ErrRetentionDisabled when no
+	// period is set.
+	ApplyRetention(ctx context.Context, dryRun bool) (*RetentionReport, error)
+
+	// GetMessage finds a message in any conversation.
+	GetMessage(ctx context.Context, messageID string) (*Message, error)
+	// History returns the conversation and its messages, oldest first,
+	// without superseded answers.
+	History(ctx context.Context, id string) (*Conversation, []Message, error)
+	// Append stores messages at the end of the conversation, assigning IDs
+	// and timestamps. An untitled conversation is titled after its first
+	// user message.
+	Append(ctx context.Context, id string, messages []Message) ([]Message, error)
+	// Replace marks the message superseded and appends replacement, which
+	// records the message it replaced.
+	Replace(ctx context.Context, id, messageID string, replacement Message) (*Message, error)
+}
+
+// Repository stores conversations and their messages. Methods return
+// ErrConversationNotFound for unknown conversation IDs. It also keeps the
+// outbox of message events, written in the same transaction as the
+// messages they describe.
+type Repository interface {
+	outbox.Repository
+
+	CreateConversation(ctx context.Context, c *Conversation) error
+	GetConversation(ctx context.Context, id string) (*Conversation, error)
+	// ListConversations returns the conversations filter selects, most
+	// recently updated first, with ties in descending ID order;
+	// ListMessages returns a conversation's messages, oldest first.
+	ListConversations(ctx context.Context, filter ConversationFilter) ([]Conversation, error)
+	// SetTitle, SetSystemPrompt, SetArchivedAt and SetDeletedAt change one
+	// field of a conversation in place, so that concurrent changes to its
+	// other fields and its message count are kept, and return the
+	// conversation as updated. SetTitle and SetSystemPrompt also set
+	// updated_at to at; a nil time clears archived_at or deleted_at.
+	SetTitle(ctx context.Context, id, title string, at time.Time) (*Conversation, error)
+	SetSystemPrompt(ctx context.Context, id, prompt string, at time.Time) (*Conversation, error)
+	SetArchivedAt(ctx context.Context, id string, at *time.Time) (*Conversation, error)
+	SetDeletedAt(ctx context.Context, id string, at *time.Time) (*Conversation, error)
+	// DeleteConversation permanently removes the conversation and its
+	// messages, storing audit as the record of the deletion.
+	DeleteConversation(ctx context.Context, id string, audit *Deletion) error
+
+	// AppendMessages stores messages and, in the same write, adds them to
+	// the conversation's message count, sets its updated_at to when the
+	// last one was created and, when it is untitled, titles it with
+	// appendedTitle.
+	AppendMessages(ctx context.Context, conversationID string, messages []Message) error
+	// GetMessage and UpdateMessage return ErrMessageNotFound for unknown
+	// message IDs.
+	GetMessage(ctx context.Context, messageID string) (*Message, error)
+	UpdateMessage(ctx context.Context, m *Message) error
+	ListMessages(ctx context.Context, conversationID string) ([]Message, error)
+
+	// Search returns one page of matching conversations, leaving out
+	// deleted ones, and the total number of matches. req has been
+	// validated and has a positive Limit.
+	Search(ctx context.Context, req *SearchRequest) ([]SearchResult, int, error)
+}
@@ -0,0 +1,364 @@
+package conversation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/outbox"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/auth"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/pagination"
+)
+
+type memoryRepository struct {
+	mu            sync.RWMutex
+	conversations map[string]*Conversation
+	messages      map[string][]Message
+	events        []outbox.Event // oldest first
+	deletions     []Deletion
+
+	// path, when set, is a JSON file rewritten after every change.
+	path string
+}
+
+type memorySnapshot struct {
+	Conversations []Conversation       `json:"conversations"`
+	Messages      map[string][]Message `json:"messages"`
+	Outbox        []outbox.Event       `json:"outbox,omitempty"`
+	Deletions     []Deletion           `json:"deletions,omitempty"`
+}
+
+// NewMemoryRepository creates an in-process repository. With a non-empty
+// path it loads the file if it exists and saves every change to it, so
+// conversations survive restarts of a single instance.
+func NewMemoryRepository(path string) (Repository, error) {
+	r := &memoryRepository{
+		conversations: make(map[string]*Conversation),
+		messages:      make(map[string][]Message),
+		path:          path,
+	}
+	if path == "" {
+		return r, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read conversations file: %w", err)
+	}
+
+	var snapshot memorySnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse conversations file: %w", err)
+	}
+	for _, c := range snapshot.Conversations {
+		if c.TenantID == "" {
+			// Saved before every conversation had a tenant.
+			c.TenantID = auth.DefaultTenant
+		}
+		r.conversations[c.ID] = &c
+	}
+	for id, messages := range snapshot.Messages {
+		if _, ok := r.conversations[id]; ok {
+			r.messages[id] = messages
+		}
+	}
+	r.events = snapshot.Outbox
+	r.deletions = snapshot.Deletions
+	return r, nil
+}
+
+func (r *memoryRepository) CreateConversation(ctx context.Context, c *Conversation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *c
+	r.conversations[c.ID] = &stored
+	return r.save()
+}
+
+func (r *memoryRepository) GetConversation(ctx context.Context, id string) (*Conversation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	c, ok := r.conversations[id]
+	if !ok {
+		return nil, ErrConversationNotFound
+	}
+	found := *c
+	return &found, nil
+}
+
+func (r *memoryRepository) ListConversations(ctx context.Context, filter ConversationFilter) ([]Conversation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	conversations := []Conversation{}
+	for _, c := range r.conversations {
+		if filter.matches(c) {
+			conversations = append(conversations, *c)
+		}
+	}
+	sortConversations(conversations)
+	if filter.Limit > 0 && len(conversations) > filter.Limit {
+		conversations = conversations[:filter.Limit]
+	}
+	return conversations, nil
+}
+
+func (r *memoryRepository) SetTitle(ctx context.Context, id, title string, at time.Time) (*Conversation, error) {
+	return r.update(id, func(c *Conversation) {
+		c.Title, c.UpdatedAt = title, at
+	})
+}
+
+func (r *memoryRepository) SetSystemPrompt(ctx context.Context, id, prompt string, at time.Time) (*Conversation, error) {
+	return r.update(id, func(c *Conversation) {
+		c.SystemPrompt, c.UpdatedAt = prompt, at
+	})
+}
+
+func (r *memoryRepository) SetArchivedAt(ctx context.Context, id string, at *time.Time) (*Conversation, error) {
+	return r.update(id, func(c *Conversation) { c.ArchivedAt = at })
+}
+
+func (r *memoryRepository) SetDeletedAt(ctx context.Context, id string, at *time.Time) (*Conversation, error) {
+	return r.update(id, func(c *Conversation) { c.DeletedAt = at })
+}
+
+// update applies change to the stored conversation and returns a copy of
+// the result.
+func (r *memoryRepository) update(id string, change func(c *Conversation)) (*Conversation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c, ok := r.conversations[id]
+	if !ok {
+		return nil, ErrConversationNotFound
+	}
+	change(c)
+	if err := r.save(); err != nil {
+		return nil, err
+	}
+	updated := *c
+	return &updated, nil
+}
+
+func (r *memoryRepository) DeleteConversation(ctx context.Context, id string, audit *Deletion) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.conversations[id]; !ok {
+		return ErrConversationNotFound
+	}
+	delete(r.conversations, id)
+	delete(r.messages, id)
+	r.deletions = append(r.deletions, *audit)
+	return r.save()
+}
+
+func (r *memoryRepository) AppendMessages(ctx context.Context, conversationID string, messages []Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c, ok := r.conversations[conversationID]
+	if !ok {
+		return ErrConversationNotFound
+	}
+	events, err := messageEvents(c, messages)
+	if err != nil {
+		return err
+	}
+	r.messages[conversationID] = append(r.messages[conversationID], messages...)
+	r.events = append(r.events, events...)
+	if len(messages) > 0 {
+		c.MessageCount += len(messages)
+		c.UpdatedAt = messages[len(messages)-1].CreatedAt
+		if c.Title == "" {
+			c.Title = appendedTitle(messages)
+		}
+	}
+	return r.save()
+}
+
+func (r *memoryRepository) GetMessage(ctx context.Context, messageID string) (*Message, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, messages := range r.messages {
+		for _, m := range messages {
+			if m.ID == messageID {
+				return &m, nil
+			}
+		}
+	}
+	return nil, ErrMessageNotFound
+}
+
+func (r *memoryRepository) UpdateMessage(ctx context.Context, m *Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	messages := r.messages[m.ConversationID]
+	for i := range messages {
+		if messages[i].ID == m.ID {
+			messages[i] = *m
+			return r.save()
+		}
+	}
+	return ErrMessageNotFound
+}
+
+func (r *memoryRepository) ListMessages(ctx context.Context, conversationID string) ([]Message, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, ok := r.conversations[conversationID]; !ok {
+		return nil, ErrConversationNotFound
+	}
+	return slices.Clone(r.messages[conversationID]), nil
+}
+
+// Search scans every conversation, ranking matches with rankResults.
+func (r *memoryRepository) Search(ctx context.Context, req *SearchRequest) ([]SearchResult, int, error) {
+	terms := searchTerms(req.Query)
+
+	scope := ConversationFilter{OwnerID: req.OwnerID, TenantID: req.TenantID, Status: StatusAll}
+
+	r.mu.RLock()
+	results := []SearchResult{}
+	for id, c := range r.conversations {
+		if !scope.matches(c) {
+			continue
+		}
+		result := SearchResult{Conversation: *c}
+		result.TitleMatch = containsAll(c.Title, terms) && inRange(c.UpdatedAt, req.From, req.To)
+		for _, m := range r.messages[id] {
+			if inRange(m.CreatedAt, req.From, req.To) && containsAll(m.Content, terms) {
+				result.Matches = append(result.Matches, MessageMatch{
+					MessageID: m.ID,
+					Role:      m.Role,
+					Snippet:   snippet(m.Content, terms),
+					CreatedAt: m.CreatedAt,
+				})
+			}
+		}
+		if result.TitleMatch || len(result.Matches) > 0 {
+			results = append(results, result)
+		}
+	}
+	r.mu.RUnlock()
+
+	page, total := rankResults(results, req)
+	return page, total, nil
+}
+
+// save writes the snapshot to a temporary file and renames it over path,
+// so a crash never leaves a partial file. Callers hold the write lock.
+func (r *memoryRepository) save() error {
+	if r.path == "" {
+		return nil
+	}
+
+	snapshot := memorySnapshot{
+		Conversations: make([]Conversation, 0, len(r.conversations)),
+		Messages:      r.messages,
+		Outbox:        r.events,
+		Deletions:     r.deletions,
+	}
+	for _, c := range r.conversations {
+		snapshot.Conversations = append(snapshot.Conversations, *c)
+	}
+	sortConversations(snapshot.Conversations)
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to encode conversations: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(r.path), filepath.Base(r.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to save conversations: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save conversations: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save conversations: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), r.path); err != nil {
+		return fmt.Errorf("failed to save conversations: %w", err)
+	}
+	return nil
+}
+
+// sortConversations orders by most recent update, then ID for stability.
+func sortConversations(conversations []Conversation) {
+	pagination.Sort(conversations, pagination.Descending, Conversation.pageKey)
+}
+
+func (r *memoryRepository) PendingEvents(ctx context.Context, limit int) ([]outbox.Event, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	events := []outbox.Event{}
+	for _, e := range r.events {
+		if len(events) == limit {
+			break
+		}
+		if e.PublishedAt == nil {
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
+
+func (r *memoryRepository) MarkPublished(ctx context.Context, id string, at time.Time) error {
+	return r.updateEvent(id, func(e *outbox.Event) { e.PublishedAt = &at })
+}
+
+func (r *memoryRepository) MarkFailed(ctx context.Context, id, reason string) error {
+	return r.updateEvent(id, func(e *outbox.Event) {
+		e.Attempts++
+		e.LastError = reason
+	})
+}
+
+func (r *memoryRepository) updateEvent(id string, update func(*outbox.Event)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.events {
+		if r.events[i].ID == id {
+			update(&r.events[i])
+			return r.save()
+		}
+	}
+	return outbox.ErrEventNotFound
+}
+
+func (r *memoryRepository) DeletePublishedEvents(ctx context.Context, cutoff time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	before := len(r.events)
+	r.events = slices.DeleteFunc(r.events, func(e outbox.Event) bool {
+		return e.PublishedAt != nil && e.PublishedAt.Before(cutoff)
+	})
+	removed := before - len(r.events)
+	if removed == 0 {
+		return 0, nil
+	}
+	return removed, r.save()
+}
@@ -0,0 +1,337 @@
+package conversation
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/ai"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/pagination"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/validate"
+)
+
+// Conversation is a stored chat. SystemPrompt, when set, replaces the
+// configured default system prompt for its turns, and Provider and Model
+// the default provider and its model. Archived conversations are left out
+// of the default listing; deleted ones are hidden everywhere but the
+// deleted listing until they are restored or purged. OwnerID is the user
+// who created the conversation; only they and admins can see it.
+// Conversations created without an authenticated user have none and are
+// visible only to requests without one and to admins. TenantID is the
+// customer it belongs to; no other tenant's requests can see it.
+type Conversation struct {
+	ID           string    `json:"id"`
+	OwnerID      string    `json:"owner_id,omitempty"`
+	TenantID     string    `json:"tenant_id,omitempty"`
+	Title        string    `json:"title"`
+	SystemPrompt string    `json:"system_prompt,omitempty"`
+	Provider     string    `json:"provider,omitempty"`
+	Model        string    `json:"model,omitempty"`
+	MessageCount int       `json:"message_count"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
+	DeletedAt  *time.Time `json:"deleted_at,omitempty"`
+}
+
+func (c Conversation) pageKey() pagination.Key {
+	return pagination.Key{Time: c.UpdatedAt, ID: c.ID}
+}
+
+// Message is a stored conversation turn. Provider and Model are set on
+// assistant messages to what generated them. A regenerated answer keeps
+// the answer it replaced, marked Superseded, for comparison; superseded
+// messages are not sent as history.
+type Message struct {
+	ID             string    `json:"id"`
+	ConversationID string    `json:"conversation_id"`
+	Role           string    `json:"role"`
+	Content        string    `json:"content"`
+	Provider       string    `json:"provider,omitempty"`
+	Model          string    `json:"model,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+
+	// Attachments names the files uploaded with a user message. Their
+	// content was only sent with that turn and is not stored.
+	Attachments []string `json:"attachments,omitempty"`
+
+	// Usage, LatencyMs and FinishReason record how an assistant message
+	// was generated, as far as the provider reported it.
+	Usage        *ai.ChatUsage `json:"usage,omitempty"`
+	LatencyMs    int64         `json:"latency_ms,omitempty"`
+	FinishReason string        `json:"finish_reason,omitempty"`
+
+	// OwnerID and TenantID are the user (empty without one) and tenant of
+	// the request that stored the message, so usage is attributed to who
+	// generated it, e.g. an admin regenerating another user's answer.
+	OwnerID  string `json:"owner_id,omitempty"`
+	TenantID string `json:"tenant_id,omitempty"`
+
+	Superseded      bool   `json:"superseded,omitempty"`
+	RegeneratedFrom string `json:"regenerated_from,omitempty"` // ID of the answer this one replaced
+}
+
+// pageKey orders messages by creation; IDs are time-ordered, so messages
+// stored together keep their order.
+func (m Message) pageKey() pagination.Key {
+	return pagination.Key{Time: m.CreatedAt, ID: m.ID}
+}
+
+// AIMessage converts m for sending to a chat provider.
+func (m Message) AIMessage() ai.Message {
+	return ai.Message{Role: m.Role, Content: m.Content}
+}
+
+// Conversation listing statuses.
+const (
+	StatusActive   = "active" // neither archived nor deleted (default)
+	StatusArchived = "archived"
+	StatusAll      = "all"     // active and archived
+	StatusDeleted  = "deleted" // deleted and awaiting purge
+)
+
+type ListRequest struct {
+	Status string
+	Page   pagination.Request
+}
+
+// Validate checks the "status", "limit" and "cursor" query parameters.
+func (r *ListRequest) Validate() error {
+	var errs validate.Errors
+	switch r.Status {
+	case "", StatusActive, StatusArchived, StatusAll, StatusDeleted:
+	default:
+		errs.Add("status", ErrInvalidStatus)
+	}
+	if err := r.Page.Validate(); err != nil {
+		errs = append(errs, validate.Fields(err)...)
+	}
+	return errs.Err()
+}
+
+// ConversationFilter selects the conversations ListConversations returns;
+// empty fields match every conversation. Status is StatusActive,
+// StatusArchived, StatusAll or StatusDeleted, as in ListRequest, except
+// that empty matches deleted and archived conversations too.
+// ExcludeTenants leaves out those tenants' conversations when TenantID is
+// empty. UpdatedBefore and DeletedBefore select conversations updated, or
+// deleted, before that time. After, when set, skips to the conversations
+// listed after that key, and Limit, when positive, returns at most that
+// many.
+type ConversationFilter struct {
+	OwnerID        string
+	TenantID       string
+	ExcludeTenants []string
+	Status         string
+	UpdatedBefore  time.Time
+	DeletedBefore  time.Time
+	After          *pagination.Key
+	Limit          int
+}
+
+// matches reports whether f selects c, ignoring Limit.
+func (f *ConversationFilter) matches(c *Conversation) bool {
+	if f.After != nil && pagination.Descending.Compare(c.pageKey(), *f.After) <= 0 {
+		return false
+	}
+	if f.OwnerID != "" && c.OwnerID != f.OwnerID || f.TenantID != "" && c.TenantID != f.TenantID {
+		return false
+	}
+	if f.TenantID == "" && slices.Contains(f.ExcludeTenants, c.TenantID) {
+		return false
+	}
+	if !f.UpdatedBefore.IsZero() && !c.UpdatedAt.Before(f.UpdatedBefore) {
+		return false
+	}
+	if !f.DeletedBefore.IsZero() && (c.DeletedAt == nil || !c.DeletedAt.Before(f.DeletedBefore)) {
+		return false
+	}
+	switch f.Status {
+	case "":
+		return true
+	case StatusDeleted:
+		return c.DeletedAt != nil
+	case StatusAll:
+		return c.DeletedAt == nil
+	case StatusArchived:
+		return c.DeletedAt == nil && c.ArchivedAt != nil
+	default:
+		return c.DeletedAt == nil && c.ArchivedAt == nil
+	}
+}
+
+type ListResponse struct {
+	Conversations []Conversation `json:"conversations"`
+	NextCursor    string         `json:"next_cursor,omitempty"`
+}
+
+type MessagesResponse struct {
+	Messages   []Message `json:"messages"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+}
+
+// ConversationDetail is a conversation with its messages, oldest first.
+type ConversationDetail struct {
+	Conversation
+	Messages []Message `json:"messages"`
+}
+
+// CreateRequest starts a conversation. Provider names a configured chat
+// provider and Model one of the models it serves; either may be empty for
+// the default.
+type CreateRequest struct {
+	Title        string `json:"title"`
+	SystemPrompt string `json:"system_prompt"`
+	Provider     string `json:"provider"`
+	Model        string `json:"model"`
+}
+
+func (r *CreateRequest) Validate() error {
+	var errs validate.Errors
+	if utf8.RuneCountInString(strings.TrimSpace(r.Title)) > maxTitleLength {
+		errs.Add("title", ErrTitleTooLong)
+	}
+	if utf8.RuneCountInString(strings.TrimSpace(r.SystemPrompt)) > maxSystemPromptLength {
+		errs.Add("system_prompt", ErrSystemPromptTooLong)
+	}
+	return errs.Err()
+}
+
+type RenameRequest struct {
+	Title string `json:"title"`
+}
+
+func (r *RenameRequest) Validate() error {
+	var errs validate.Errors
+	switch title := strings.TrimSpace(r.Title); {
+	case title == "":
+		errs.Add("title", ErrTitleRequired)
+	case utf8.RuneCountInString(title) > maxTitleLength:
+		errs.Add("title", ErrTitleTooLong)
+	}
+	return errs.Err()
+}
+
+// SystemPromptRequest sets a conversation's system prompt; an empty
+// prompt restores the configured default.
+type SystemPromptRequest struct {
+	SystemPrompt string `json:"system_prompt"`
+}
+
+func (r *SystemPromptRequest) Validate() error {
+	var errs validate.Errors
+	if utf8.RuneCountInString(strings.TrimSpace(r.SystemPrompt)) > maxSystemPromptLength {
+		errs.Add("system_prompt", ErrSystemPromptTooLong)
+	}
+	return errs.Err()
+}
+
+// SearchRequest finds conversations whose title or messages contain every
+// term of Query (case-insensitive). From and To, when set, bound when the
+// matching messages were written (for title matches, when the conversation
+// was last updated).
+type SearchRequest struct {
+	Query  string
+	From   time.Time
+	To     time.Time
+	Limit  int
+	Offset int
+
+	// OwnerID limits the search to one user's conversations. The service
+	// sets it to the current user, unless they are an admin; empty
+	// searches every conversation.
+	OwnerID string
+	// TenantID limits the search to one tenant's conversations; the
+	// service sets it to the current tenant.
+	TenantID string
+}
+
+// Validate checks the query parameters the request was built from: "q"
+// must have a search term and "to" must not be before "from".
+func (r *SearchRequest) Validate() error {
+	var errs validate.Errors
+	if len(searchTerms(r.Query)) == 0 {
+		errs.Add("q", ErrEmptyQuery)
+	}
+	if !r.From.IsZero() && !r.To.IsZero() && r.To.Before(r.From) {
+		errs.Add("to", ErrInvalidDateRange)
+	}
+	return errs.Err()
+}
+
+type SearchResult struct {
+	Conversation Conversation   `json:"conversation"`
+	TitleMatch   bool           `json:"title_match"`
+	Matches      []MessageMatch `json:"matches,omitempty"`
+}
+
+// MessageMatch is a message that contains the query, with a snippet around
+// the first match.
+type MessageMatch struct {
+	MessageID string    `json:"message_id"`
+	Role      string    `json:"role"`
+	Snippet   string    `json:"snippet"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type SearchResponse struct {
+	Results []SearchResult `json:"results"`
+	Total   int            `json:"total"`
+	Limit   int            `json:"limit"`
+	Offset  int            `json:"offset"`
+}
+
+// Reasons a conversation was permanently deleted.
+const (
+	DeletionPurged    = "purged"    // deleted by the user and not restored in time
+	DeletionRetention = "retention" // inactive for longer than the retention period
+)
+
+// Deletion is the audit record of a conversation's permanent deletion. It
+// keeps no title or message content.
+type Deletion struct {
+	ID                    string    `json:"id"`
+	ConversationID        string    `json:"conversation_id"`
+	TenantID              string    `json:"tenant_id,omitempty"`
+	Reason                string    `json:"reason"`
+	MessageCount          int       `json:"message_count"`
+	ConversationCreatedAt time.Time `json:"conversation_created_at"`
+	ConversationUpdatedAt time.Time `json:"conversation_updated_at"`
+	DeletedAt             time.Time `json:"deleted_at"`
+}
+
+// RetentionReport lists the conversations a retention run deleted or, in
+// a dry run, would have deleted. Conversations last updated before their
+// tenant's cutoff are deleted: TenantCutoffs for tenants with their own
+// retention period, Cutoff for the others unless it is zero.
+type RetentionReport struct {
+	DryRun        bool                 `json:"dry_run"`
+	Cutoff        time.Time            `json:"cutoff,omitzero"`
+	TenantCutoffs map[string]time.Time `json:"tenant_cutoffs,omitempty"`
+	Deletions     []Deletion           `json:"deletions"`
+}
+
+// ParseTenantRetention parses retention periods in the form
+// "acme=30,globex=0", as used in environment variables: days after its
+// last update a tenant's conversation is deleted, zero keeping them.
+func ParseTenantRetention(s string) (map[string]time.Duration, error) {
+	periods := make(map[string]time.Duration)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		tenant, value, ok := strings.Cut(pair, "=")
+		tenant = strings.TrimSpace(tenant)
+		days, err := strconv.Atoi(strings.TrimSpace(value))
+		if !ok || tenant == "" || err != nil || days < 0 {
+			return nil, fmt.Errorf("invalid tenant retention %q (want tenant=days)", pair)
+		}
+		periods[tenant] = time.Duration(days) * 24 * time.Hour
+	}
+	return periods, nil
+}
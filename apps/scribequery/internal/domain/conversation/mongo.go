@@ -0,0 +1,688 @@
+package conversation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/outbox"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/auth"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/ai"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/db/mongo"
+	"go.mongodb.org/mongo-driver/bson"
+	driver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	conversationsCollection = "conversations"
+	messagesCollection      = "messages"
+	deletionsCollection     = "conversation_deletions"
+)
+
+// mongoConversation and mongoMessage are the stored documents. Mongo keeps
+// times to the millisecond.
+type mongoConversation struct {
+	ID           string     `bson:"_id"`
+	OwnerID      string     `bson:"owner_id,omitempty"`
+	TenantID     string     `bson:"tenant_id,omitempty"`
+	Title        string     `bson:"title"`
+	SystemPrompt string     `bson:"system_prompt,omitempty"`
+	Provider     string     `bson:"provider,omitempty"`
+	Model        string     `bson:"model,omitempty"`
+	MessageCount int        `bson:"message_count"`
+	CreatedAt    time.Time  `bson:"created_at"`
+	UpdatedAt    time.Time  `bson:"updated_at"`
+	ArchivedAt   *time.Time `bson:"archived_at,omitempty"`
+	DeletedAt    *time.Time `bson:"deleted_at,omitempty"`
+}
+
+type mongoMessage struct {
+	ID              string        `bson:"_id"`
+	ConversationID  string        `bson:"conversation_id"`
+	Role            string        `bson:"role"`
+	Content         string        `bson:"content"`
+	Provider        string        `bson:"provider,omitempty"`
+	Model           string        `bson:"model,omitempty"`
+	Attachments     []string      `bson:"attachments,omitempty"`
+	Usage           *ai.ChatUsage `bson:"usage,omitempty"`
+	LatencyMs       int64         `bson:"latency_ms,omitempty"`
+	FinishReason    string        `bson:"finish_reason,omitempty"`
+	Superseded      bool          `bson:"superseded,omitempty"`
+	RegeneratedFrom string        `bson:"regenerated_from,omitempty"`
+	CreatedAt       time.Time     `bson:"created_at"`
+	OwnerID         string        `bson:"owner_id,omitempty"`
+	TenantID        string        `bson:"tenant_id,omitempty"`
+
+	// Outbox is the message's outbox event. It lives in the message
+	// document because Mongo writes a single document atomically, which
+	// does not need a transaction (or a replica set).
+	Outbox *mongoEvent `bson:"outbox,omitempty"`
+}
+
+// mongoMatch is a message found by Search, with its conversation.
+type mongoMatch struct {
+	mongoMessage `bson:",inline"`
+	Conversation mongoConversation `bson:"conversation"`
+}
+
+type mongoDeletion struct {
+	ID                    string    `bson:"_id"`
+	ConversationID        string    `bson:"conversation_id"`
+	TenantID              string    `bson:"tenant_id,omitempty"`
+	Reason                string    `bson:"reason"`
+	MessageCount          int       `bson:"message_count"`
+	ConversationCreatedAt time.Time `bson:"conversation_created_at"`
+	ConversationUpdatedAt time.Time `bson:"conversation_updated_at"`
+	DeletedAt             time.Time `bson:"deleted_at"`
+}
+
+type mongoEvent struct {
+	ID          string     `bson:"id"`
+	Topic       string     `bson:"topic"`
+	Key         string     `bson:"key"`
+	Payload     string     `bson:"payload"` // JSON
+	CreatedAt   time.Time  `bson:"created_at"`
+	Attempts    int        `bson:"attempts"`
+	LastError   string     `bson:"last_error,omitempty"`
+	PublishedAt *time.Time `bson:"published_at"`
+}
+
+// conversationOrder sorts conversations by most recent update, and
+// conversations updated in the same millisecond by descending ID.
+var conversationOrder = bson.D{{Key: "updated_at", Value: -1}, {Key: "_id", Value: -1}}
+
+// messageOrder sorts messages as they were appended: IDs are time-ordered,
+// and break ties between messages stored in the same millisecond.
+var messageOrder = bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}
+
+type mongoRepository struct {
+	conversations *driver.Collection
+	messages      *driver.Collection
+	deletions     *driver.Collection
+}
+
+// NewMongoRepository creates a repository on the conversations and
+// messages collections of client's database, creating their indexes if
+// needed. Outbox events are kept in the messages they describe, and
+// deletion audit records in the conversation_deletions collection.
+func NewMongoRepository(ctx context.Context, client *mongo.Client) (Repository, error) {
+	r := &mongoRepository{
+		conversations: client.Collection(conversationsCollection),
+		messages:      client.Collection(messagesCollection),
+		deletions:     client.Collection(deletionsCollection),
+	}
+	if _, err := r.conversations.Indexes().CreateMany(ctx, []driver.IndexModel{
+		{Keys: conversationOrder, Options: options.Index().SetName("updated_at_id")},
+		{Keys: append(bson.D{{Key: "tenant_id", Value: 1}}, conversationOrder...), Options: options.Index().SetName("tenant_updated_at")},
+		{Keys: bson.D{{Key: "deleted_at", Value: 1}}, Options: options.Index().SetName("deleted_at").SetSparse(true)},
+		{Keys: bson.D{{Key: "title", Value: "text"}}, Options: textIndex("title_text")},
+	}); err != nil {
+		return nil, fmt.Errorf("failed to create conversation indexes: %w", err)
+	}
+	// updated_at ordered ties by ascending ID, which listings no longer
+	// use; updated_at_id replaces it.
+	if _, err := r.conversations.Indexes().DropOne(ctx, "updated_at"); err != nil && !indexNotFound(err) {
+		return nil, fmt.Errorf("failed to drop conversation index: %w", err)
+	}
+	// Conversations saved before every conversation had a tenant belong
+	// to the default one.
+	if _, err := r.conversations.UpdateMany(ctx,
+		bson.M{"tenant_id": bson.M{"$in": bson.A{nil, ""}}},
+		bson.M{"$set": bson.M{"tenant_id": auth.DefaultTenant}},
+	); err != nil {
+		return nil, fmt.Errorf("failed to backfill conversation tenants: %w", err)
+	}
+	if _, err := r.messages.Indexes().CreateMany(ctx, []driver.IndexModel{
+		{Keys: append(bson.D{{Key: "conversation_id", Value: 1}}, messageOrder...), Options: options.Index().SetName("conversation_order")},
+		{Keys: bson.D{{Key: "outbox.id", Value: 1}}, Options: options.Index().SetName("outbox_id").SetUnique(true).SetSparse(true)},
+		{Keys: outboxOrder, Options: options.Index().SetName("outbox_pending").SetSparse(true)},
+		{Keys: bson.D{{Key: "content", Value: "text"}}, Options: textIndex("content_text")},
+	}); err != nil {
+		return nil, fmt.Errorf("failed to create message indexes: %w", err)
+	}
+	// Messages stored before they recorded who generated them take their
+	// conversation's owner and tenant.
+	cursor, err := r.messages.Aggregate(ctx, driver.Pipeline{
+		{{Key: "$match", Value: bson.M{"tenant_id": bson.M{"$exists": false}}}},
+		{{Key: "$lookup", Value: bson.M{"from": conversationsCollection, "localField": "conversation_id", "foreignField": "_id", "as": "conversation"}}},
+		{{Key: "$unwind", Value: "$conversation"}},
+		{{Key: "$project", Value: bson.M{"owner_id": "$conversation.owner_id", "tenant_id": "$conversation.tenant_id"}}},
+		{{Key: "$merge", Value: bson.M{"into": messagesCollection, "on": "_id", "whenMatched": "merge", "whenNotMatched": "discard"}}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to backfill message owners: %w", err)
+	}
+	cursor.Close(ctx)
+	return r, nil
+}
+
+func (r *mongoRepository) CreateConversation(ctx context.Context, c *Conversation) error {
+	if _, err := r.conversations.InsertOne(ctx, toMongoConversation(c)); err != nil {
+		return fmt.Errorf("failed to create conversation: %w", err)
+	}
+	return nil
+}
+
+func (r *mongoRepository) GetConversation(ctx context.Context, id string) (*Conversation, error) {
+	var doc mongoConversation
+	err := r.conversations.FindOne(ctx, bson.M{"_id": id}).Decode(&doc)
+	if errors.Is(err, driver.ErrNoDocuments) {
+		return nil, ErrConversationNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conversation: %w", err)
+	}
+	return doc.conversation(), nil
+}
+
+func (r *mongoRepository) ListConversations(ctx context.Context, filter ConversationFilter) ([]Conversation, error) {
+	opts := options.Find().SetSort(conversationOrder)
+	if filter.Limit > 0 {
+		opts.SetLimit(int64(filter.Limit))
+	}
+	docs, err := find[mongoConversation](ctx, r.conversations, conversationFilter(filter), opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list conversations: %w", err)
+	}
+	conversations := make([]Conversation, len(docs))
+	for i, doc := range docs {
+		conversations[i] = *doc.conversation()
+	}
+	return conversations, nil
+}
+
+func (r *mongoRepository) SetTitle(ctx context.Context, id, title string, at time.Time) (*Conversation, error) {
+	return r.update(ctx, id, bson.M{"$set": bson.M{"title": title, "updated_at": at}})
+}
+
+func (r *mongoRepository) SetSystemPrompt(ctx context.Context, id, prompt string, at time.Time) (*Conversation, error) {
+	return r.update(ctx, id, bson.M{"$set": bson.M{"system_prompt": prompt, "updated_at": at}})
+}
+
+func (r *mongoRepository) SetArchivedAt(ctx context.Context, id string, at *time.Time) (*Conversation, error) {
+	return r.update(ctx, id, setOrUnset("archived_at", at))
+}
+
+func (r *mongoRepository) SetDeletedAt(ctx context.Context, id string, at *time.Time) (*Conversation, error) {
+	return r.update(ctx, id, setOrUnset("deleted_at", at))
+}
+
+// update applies update to conversation id and returns the result.
+func (r *mongoRepository) update(ctx context.Context, id string, update bson.M) (*Conversation, error) {
+	var doc mongoConversation
+	err := r.conversations.FindOneAndUpdate(ctx, bson.M{"_id": id}, update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&doc)
+	if errors.Is(err, driver.ErrNoDocuments) {
+		return nil, ErrConversationNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update conversation: %w", err)
+	}
+	return doc.conversation(), nil
+}
+
+// setOrUnset sets an optional time field, or removes it when at is nil,
+// as the stored documents omit unset times.
+func setOrUnset(field string, at *time.Time) bson.M {
+	if at == nil {
+		return bson.M{"$unset": bson.M{field: ""}}
+	}
+	return bson.M{"$set": bson.M{field: *at}}
+}
+
+// DeleteConversation stores the audit record first: without transactions,
+// a failure part way leaves a record of an attempted deletion rather than
+// a deletion nobody recorded.
+func (r *mongoRepository) DeleteConversation(ctx context.Context, id string, audit *Deletion) error {
+	if _, err := r.GetConversation(ctx, id); err != nil {
+		return err
+	}
+	if _, err := r.deletions.InsertOne(ctx, toMongoDeletion(audit)); err != nil {
+		return fmt.Errorf("failed to record conversation deletion: %w", err)
+	}
+
+	result, err := r.conversations.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return fmt.Errorf("failed to delete conversation: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return ErrConversationNotFound
+	}
+	if _, err := r.messages.DeleteMany(ctx, bson.M{"conversation_id": id}); err != nil {
+		return fmt.Errorf("failed to delete conversation messages: %w", err)
+	}
+	return nil
+}
+
+func (r *mongoRepository) AppendMessages(ctx context.Context, conversationID string, messages []Message) error {
+	c, err := r.GetConversation(ctx, conversationID)
+	if err != nil {
+		return err
+	}
+	events, err := messageEvents(c, messages)
+	if err != nil {
+		return err
+	}
+	docs := make([]any, len(messages))
+	for i := range messages {
+		doc := toMongoMessage(&messages[i])
+		doc.ConversationID = conversationID
+		// messageEvents returns one event per assistant message, in order.
+		if doc.Role == ai.RoleAssistant && len(events) > 0 {
+			doc.Outbox = toMongoEvent(&events[0])
+			events = events[1:]
+		}
+		docs[i] = doc
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+	if _, err := r.messages.InsertMany(ctx, docs); err != nil {
+		return fmt.Errorf("failed to append messages: %w", err)
+	}
+
+	// $inc keeps concurrent appends from losing each other's count, and
+	// the title is only set while the conversation is still untitled.
+	if _, err := r.conversations.UpdateOne(ctx, bson.M{"_id": conversationID}, bson.M{
+		"$inc": bson.M{"message_count": len(messages)},
+		"$set": bson.M{"updated_at": messages[len(messages)-1].CreatedAt},
+	}); err != nil {
+		return fmt.Errorf("failed to count appended messages: %w", err)
+	}
+	if title := appendedTitle(messages); title != "" {
+		if _, err := r.conversations.UpdateOne(ctx,
+			bson.M{"_id": conversationID, "title": ""}, bson.M{"$set": bson.M{"title": title}}); err != nil {
+			return fmt.Errorf("failed to title conversation: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *mongoRepository) GetMessage(ctx context.Context, messageID string) (*Message, error) {
+	var doc mongoMessage
+	err := r.messages.FindOne(ctx, bson.M{"_id": messageID}).Decode(&doc)
+	if errors.Is(err, driver.ErrNoDocuments) {
+		return nil, ErrMessageNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get message: %w", err)
+	}
+	return doc.message(), nil
+}
+
+// UpdateMessage sets the message fields rather than replacing the
+// document, which would drop its outbox event.
+func (r *mongoRepository) UpdateMessage(ctx context.Context, m *Message) error {
+	result, err := r.messages.UpdateOne(ctx,
+		bson.M{"_id": m.ID, "conversation_id": m.ConversationID}, bson.M{"$set": bson.M{
+			"role":             m.Role,
+			"content":          m.Content,
+			"provider":         m.Provider,
+			"model":            m.Model,
+			"attachments":      m.Attachments,
+			"usage":            m.Usage,
+			"latency_ms":       m.LatencyMs,
+			"finish_reason":    m.FinishReason,
+			"superseded":       m.Superseded,
+			"regenerated_from": m.RegeneratedFrom,
+		}})
+	if err != nil {
+		return fmt.Errorf("failed to update message: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return ErrMessageNotFound
+	}
+	return nil
+}
+
+func (r *mongoRepository) ListMessages(ctx context.Context, conversationID string) ([]Message, error) {
+	if _, err := r.GetConversation(ctx, conversationID); err != nil {
+		return nil, err
+	}
+	return r.findMessages(ctx, bson.M{"conversation_id": conversationID},
+		options.Find().SetSort(messageOrder))
+}
+
+// Search finds title matches and matching messages through the text
+// indexes, requiring every term as a phrase, and ranks them with
+// rankResults. Unlike the SQL repository, which matches substrings, a term
+// must be a whole word: "deploy" does not find "deployment". Search terms
+// are letters and digits only, so they need no escaping.
+func (r *mongoRepository) Search(ctx context.Context, req *SearchRequest) ([]SearchResult, int, error) {
+	terms := searchTerms(req.Query)
+	results := make(map[string]*SearchResult)
+
+	// Deleted conversations and those of other owners and tenants are
+	// left out by the queries.
+	scope := conversationFilter(ConversationFilter{OwnerID: req.OwnerID, TenantID: req.TenantID, Status: StatusAll})
+
+	titles, err := find[mongoConversation](ctx, r.conversations, append(searchFilter("updated_at", terms, req), scope...))
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search conversations: %w", err)
+	}
+	for _, doc := range titles {
+		results[doc.ID] = &SearchResult{Conversation: *doc.conversation(), TitleMatch: true}
+	}
+
+	inScope := bson.D{}
+	for _, e := range scope {
+		inScope = append(inScope, bson.E{Key: "conversation." + e.Key, Value: e.Value})
+	}
+	cursor, err := r.messages.Aggregate(ctx, driver.Pipeline{
+		{{Key: "$match", Value: searchFilter("created_at", terms, req)}},
+		{{Key: "$lookup", Value: bson.M{
+			"from":         conversationsCollection,
+			"localField":   "conversation_id",
+			"foreignField": "_id",
+			"as":           "conversation",
+		}}},
+		{{Key: "$unwind", Value: "$conversation"}},
+		{{Key: "$match", Value: inScope}},
+		{{Key: "$sort", Value: messageOrder}},
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search messages: %w", err)
+	}
+	matches := []mongoMatch{}
+	if err := cursor.All(ctx, &matches); err != nil {
+		return nil, 0, fmt.Errorf("failed to search messages: %w", err)
+	}
+	for _, m := range matches {
+		result, ok := results[m.ConversationID]
+		if !ok {
+			result = &SearchResult{Conversation: *m.Conversation.conversation()}
+			results[m.ConversationID] = result
+		}
+		result.Matches = append(result.Matches, MessageMatch{
+			MessageID: m.ID,
+			Role:      m.Role,
+			Snippet:   snippet(m.Content, terms),
+			CreatedAt: m.CreatedAt,
+		})
+	}
+
+	ranked := make([]SearchResult, 0, len(results))
+	for _, result := range results {
+		ranked = append(ranked, *result)
+	}
+	page, total := rankResults(ranked, req)
+	return page, total, nil
+}
+
+// outboxOrder sorts pending events as they were written.
+var outboxOrder = bson.D{{Key: "outbox.published_at", Value: 1}, {Key: "outbox.created_at", Value: 1}, {Key: "_id", Value: 1}}
+
+func (r *mongoRepository) PendingEvents(ctx context.Context, limit int) ([]outbox.Event, error) {
+	docs, err := find[mongoMessage](ctx, r.messages, bson.D{
+		{Key: "outbox.id", Value: bson.M{"$exists": true}},
+		{Key: "outbox.published_at", Value: nil},
+	}, options.Find().SetSort(outboxOrder).SetLimit(int64(limit)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list outbox events: %w", err)
+	}
+	events := make([]outbox.Event, len(docs))
+	for i, doc := range docs {
+		events[i] = doc.Outbox.event()
+	}
+	return events, nil
+}
+
+func (r *mongoRepository) MarkPublished(ctx context.Context, id string, at time.Time) error {
+	return r.updateEvent(ctx, id, bson.M{"$set": bson.M{"outbox.published_at": at}})
+}
+
+func (r *mongoRepository) MarkFailed(ctx context.Context, id, reason string) error {
+	return r.updateEvent(ctx, id, bson.M{
+		"$inc": bson.M{"outbox.attempts": 1},
+		"$set": bson.M{"outbox.last_error": reason},
+	})
+}
+
+func (r *mongoRepository) updateEvent(ctx context.Context, id string, update bson.M) error {
+	result, err := r.messages.UpdateOne(ctx, bson.M{"outbox.id": id}, update)
+	if err != nil {
+		return fmt.Errorf("failed to update outbox event: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return outbox.ErrEventNotFound
+	}
+	return nil
+}
+
+func (r *mongoRepository) DeletePublishedEvents(ctx context.Context, cutoff time.Time) (int, error) {
+	result, err := r.messages.UpdateMany(ctx,
+		bson.M{"outbox.published_at": bson.M{"$lt": cutoff}},
+		bson.M{"$unset": bson.M{"outbox": ""}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete outbox events: %w", err)
+	}
+	return int(result.MatchedCount), nil
+}
+
+func (r *mongoRepository) findMessages(ctx context.Context, filter any, opts ...*options.FindOptions) ([]Message, error) {
+	docs, err := find[mongoMessage](ctx, r.messages, filter, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list messages: %w", err)
+	}
+	messages := make([]Message, len(docs))
+	for i, doc := range docs {
+		messages[i] = *doc.message()
+	}
+	return messages, nil
+}
+
+// find decodes every document of collection matching filter.
+func find[T any](ctx context.Context, collection *driver.Collection, filter any, opts ...*options.FindOptions) ([]T, error) {
+	cursor, err := collection.Find(ctx, filter, opts...)
+	if err != nil {
+		return nil, err
+	}
+	docs := []T{}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+// conversationFilter matches the conversations filter selects.
+func conversationFilter(filter ConversationFilter) bson.D {
+	d := bson.D{}
+	if filter.After != nil {
+		d = append(d, bson.E{Key: "$or", Value: bson.A{
+			bson.M{"updated_at": bson.M{"$lt": filter.After.Time}},
+			bson.M{"updated_at": filter.After.Time, "_id": bson.M{"$lt": filter.After.ID}},
+		}})
+	}
+	if filter.OwnerID != "" {
+		d = append(d, bson.E{Key: "owner_id", Value: filter.OwnerID})
+	}
+	if filter.TenantID != "" {
+		d = append(d, bson.E{Key: "tenant_id", Value: filter.TenantID})
+	} else if len(filter.ExcludeTenants) > 0 {
+		d = append(d, bson.E{Key: "tenant_id", Value: bson.M{"$nin": filter.ExcludeTenants}})
+	}
+	if !filter.UpdatedBefore.IsZero() {
+		d = append(d, bson.E{Key: "updated_at", Value: bson.M{"$lt": filter.UpdatedBefore}})
+	}
+	// A date comparison only matches dates, so it also selects deleted
+	// conversations, and stands in for StatusDeleted's condition.
+	if !filter.DeletedBefore.IsZero() {
+		d = append(d, bson.E{Key: "deleted_at", Value: bson.M{"$lt": filter.DeletedBefore}})
+	}
+	switch filter.Status {
+	case "":
+	case StatusDeleted:
+		if filter.DeletedBefore.IsZero() {
+			d = append(d, bson.E{Key: "deleted_at", Value: bson.M{"$ne": nil}})
+		}
+	case StatusAll:
+		d = append(d, bson.E{Key: "deleted_at", Value: nil})
+	case StatusArchived:
+		d = append(d, bson.E{Key: "deleted_at", Value: nil}, bson.E{Key: "archived_at", Value: bson.M{"$ne": nil}})
+	default:
+		d = append(d, bson.E{Key: "deleted_at", Value: nil}, bson.E{Key: "archived_at", Value: nil})
+	}
+	return d
+}
+
+// indexNotFound reports whether err is Mongo's IndexNotFound error.
+func indexNotFound(err error) bool {
+	var cmdErr driver.CommandError
+	return errors.As(err, &cmdErr) && cmdErr.Code == 27
+}
+
+// textIndex is a text index without stemming or stop words, so that a
+// term matches the word as it was written, in any language.
+func textIndex(name string) *options.IndexOptions {
+	return options.Index().SetName(name).SetDefaultLanguage("none")
+}
+
+// searchFilter matches documents whose text-indexed field (a collection
+// has only one) contains every term, case-insensitively, and whose
+// timeField is within req's date range.
+func searchFilter(timeField string, terms []string, req *SearchRequest) bson.D {
+	phrases := make([]string, len(terms))
+	for i, t := range terms {
+		// Quoted terms are phrases, which must all match; bare terms
+		// would match any one of them.
+		phrases[i] = `"` + t + `"`
+	}
+	filter := bson.D{{Key: "$text", Value: bson.M{"$search": strings.Join(phrases, " ")}}}
+
+	period := bson.M{}
+	if !req.From.IsZero() {
+		period["$gte"] = req.From
+	}
+	if !req.To.IsZero() {
+		period["$lte"] = req.To
+	}
+	if len(period) > 0 {
+		filter = append(filter, bson.E{Key: timeField, Value: period})
+	}
+	return filter
+}
+
+func toMongoConversation(c *Conversation) mongoConversation {
+	return mongoConversation{
+		ID:           c.ID,
+		OwnerID:      c.OwnerID,
+		TenantID:     c.TenantID,
+		Title:        c.Title,
+		SystemPrompt: c.SystemPrompt,
+		Provider:     c.Provider,
+		Model:        c.Model,
+		MessageCount: c.MessageCount,
+		CreatedAt:    c.CreatedAt,
+		UpdatedAt:    c.UpdatedAt,
+		ArchivedAt:   c.ArchivedAt,
+		DeletedAt:    c.DeletedAt,
+	}
+}
+
+func (d mongoConversation) conversation() *Conversation {
+	return &Conversation{
+		ID:           d.ID,
+		OwnerID:      d.OwnerID,
+		TenantID:     d.TenantID,
+		Title:        d.Title,
+		SystemPrompt: d.SystemPrompt,
+		Provider:     d.Provider,
+		Model:        d.Model,
+		MessageCount: d.MessageCount,
+		CreatedAt:    d.CreatedAt.UTC(),
+		UpdatedAt:    d.UpdatedAt.UTC(),
+		ArchivedAt:   utc(d.ArchivedAt),
+		DeletedAt:    utc(d.DeletedAt),
+	}
+}
+
+func toMongoMessage(m *Message) mongoMessage {
+	return mongoMessage{
+		ID:              m.ID,
+		ConversationID:  m.ConversationID,
+		Role:            m.Role,
+		Content:         m.Content,
+		Provider:        m.Provider,
+		Model:           m.Model,
+		Attachments:     m.Attachments,
+		Usage:           m.Usage,
+		LatencyMs:       m.LatencyMs,
+		FinishReason:    m.FinishReason,
+		Superseded:      m.Superseded,
+		RegeneratedFrom: m.RegeneratedFrom,
+		CreatedAt:       m.CreatedAt,
+		OwnerID:         m.OwnerID,
+		TenantID:        m.TenantID,
+	}
+}
+
+func (d mongoMessage) message() *Message {
+	return &Message{
+		ID:              d.ID,
+		ConversationID:  d.ConversationID,
+		Role:            d.Role,
+		Content:         d.Content,
+		Provider:        d.Provider,
+		Model:           d.Model,
+		Attachments:     d.Attachments,
+		Usage:           d.Usage,
+		LatencyMs:       d.LatencyMs,
+		FinishReason:    d.FinishReason,
+		Superseded:      d.Superseded,
+		RegeneratedFrom: d.RegeneratedFrom,
+		CreatedAt:       d.CreatedAt.UTC(),
+		OwnerID:         d.OwnerID,
+		TenantID:        d.TenantID,
+	}
+}
+
+func toMongoDeletion(d *Deletion) mongoDeletion {
+	return mongoDeletion{
+		ID:                    d.ID,
+		ConversationID:        d.ConversationID,
+		TenantID:              d.TenantID,
+		Reason:                d.Reason,
+		MessageCount:          d.MessageCount,
+		ConversationCreatedAt: d.ConversationCreatedAt,
+		ConversationUpdatedAt: d.ConversationUpdatedAt,
+		DeletedAt:             d.DeletedAt,
+	}
+}
+
+func toMongoEvent(e *outbox.Event) *mongoEvent {
+	return &mongoEvent{
+		ID:          e.ID,
+		Topic:       e.Topic,
+		Key:         e.Key,
+		Payload:     string(e.Payload),
+		CreatedAt:   e.CreatedAt,
+		Attempts:    e.Attempts,
+		LastError:   e.LastError,
+		PublishedAt: e.PublishedAt,
+	}
+}
+
+func (d mongoEvent) event() outbox.Event {
+	return outbox.Event{
+		ID:          d.ID,
+		Topic:       d.Topic,
+		Key:         d.Key,
+		Payload:     json.RawMessage(d.Payload),
+		CreatedAt:   d.CreatedAt.UTC(),
+		Attempts:    d.Attempts,
+		LastError:   d.LastError,
+		PublishedAt: utc(d.PublishedAt),
+	}
+}
+
+func utc(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
@@ -0,0 +1,61 @@
+package conversation
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/outbox"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/ai"
+	"github.com/google/uuid"
+)
+
+// TopicMessageCompleted is published for every stored assistant message,
+// keyed by conversation ID. Each repository writes the event together with
+// the message, so one is never stored without the other, and also
+// implements outbox.Repository for the relay.
+const TopicMessageCompleted = "chat.message.completed"
+
+// MessageEvent is the payload of TopicMessageCompleted.
+type MessageEvent struct {
+	ConversationID string `json:"conversation_id"`
+	// OwnerID is the user whose request generated the message, or else
+	// the conversation's owner, so consumers can attribute usage; empty
+	// when there is neither.
+	OwnerID string `json:"owner_id,omitempty"`
+	// TenantID is the tenant the message was generated for, so consumers
+	// serving several customers can route the event.
+	TenantID string  `json:"tenant_id,omitempty"`
+	Message  Message `json:"message"`
+}
+
+// messageEvents returns the outbox events for messages being appended to
+// c.
+func messageEvents(c *Conversation, messages []Message) ([]outbox.Event, error) {
+	conversationID := c.ID
+	var events []outbox.Event
+	for _, m := range messages {
+		if m.Role != ai.RoleAssistant {
+			continue
+		}
+		m.ConversationID = conversationID
+		payload, err := json.Marshal(MessageEvent{
+			ConversationID: conversationID,
+			OwnerID:        cmp.Or(m.OwnerID, c.OwnerID),
+			TenantID:       cmp.Or(m.TenantID, c.TenantID),
+			Message:        m,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode message event: %w", err)
+		}
+		events = append(events, outbox.Event{
+			ID:        uuid.NewString(),
+			Topic:     TopicMessageCompleted,
+			Key:       conversationID,
+			Payload:   payload,
+			CreatedAt: time.Now().UTC(),
+		})
+	}
+	return events, nil
+}
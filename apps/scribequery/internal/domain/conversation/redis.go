@@ -0,0 +1,300 @@
+package conversation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/redis"
+	"go.uber.org/zap"
+)
+
+const defaultCacheTTL = time.Hour
+
+// CacheConfig controls how long an idle conversation stays in Redis.
+type CacheConfig struct {
+	TTL time.Duration // since the last read or write (default: 1h)
+}
+
+// redisCache keeps active conversations and their messages in Redis in
+// front of the durable repository. Writes go to the repository first and
+// then to the cache; reads fall back to the repository on a miss or when
+// Redis fails, so the cache is never the only copy.
+//
+// Each conversation has a version key bumped by every write. A read that
+// missed only fills the cache if the version is unchanged since before it
+// read the repository, so a slow reader cannot cache data that a
+// concurrent write has already replaced.
+type redisCache struct {
+	Repository
+	client *redis.Client
+	ttl    time.Duration
+	logger *zap.Logger
+}
+
+// NewRedisCache wraps repo with a write-through Redis cache. Search,
+// ListConversations and GetMessage always go to repo.
+func NewRedisCache(repo Repository, client *redis.Client, cfg CacheConfig, logger *zap.Logger) Repository {
+	ttl := cfg.TTL
+	if ttl <= 0 {
+		ttl = defaultCacheTTL
+	}
+	return &redisCache{Repository: repo, client: client, ttl: ttl, logger: logger}
+}
+
+// A conversation's keys share the {id} hash tag, so the scripts that touch
+// several of them run on a cluster too.
+func conversationKey(id string) string { return "conversation:{" + id + "}" }
+func messagesKey(id string) string     { return "conversation:{" + id + "}:messages" }
+func versionKey(id string) string      { return "conversation:{" + id + "}:version" }
+
+// Fill scripts: KEYS[1] version, KEYS[2] target; ARGV[1] expected version,
+// ARGV[2] ttl in ms, then the value(s).
+const (
+	fillValueScript = `
+if (redis.call('GET', KEYS[1]) or '') ~= ARGV[1] then return 0 end
+redis.call('SET', KEYS[2], ARGV[3], 'PX', ARGV[2])
+return 1`
+
+	fillListScript = `
+if (redis.call('GET', KEYS[1]) or '') ~= ARGV[1] then return 0 end
+redis.call('DEL', KEYS[2])
+if #ARGV > 2 then redis.call('RPUSH', KEYS[2], unpack(ARGV, 3)) end
+redis.call('PEXPIRE', KEYS[2], ARGV[2])
+return 1`
+)
+
+// Write scripts bump KEYS[1], the version, and keep it alive as long as
+// the entries it guards.
+const (
+	// KEYS[2] conversation; ARGV[1] ttl in ms, ARGV[2] value.
+	writeValueScript = `
+redis.call('INCR', KEYS[1])
+redis.call('PEXPIRE', KEYS[1], ARGV[1])
+redis.call('SET', KEYS[2], ARGV[2], 'PX', ARGV[1])
+return 1`
+
+	// KEYS[2] messages, KEYS[3] conversation; ARGV[1] ttl in ms, then the
+	// messages. Appends only to a cached list, which is complete; a missing
+	// one is filled on the next read. The conversation, whose count and
+	// title the append changed, is dropped.
+	appendListScript = `
+redis.call('INCR', KEYS[1])
+redis.call('PEXPIRE', KEYS[1], ARGV[1])
+if redis.call('RPUSHX', KEYS[2], unpack(ARGV, 2)) > 0 then
+  redis.call('PEXPIRE', KEYS[2], ARGV[1])
+end
+redis.call('DEL', KEYS[3])
+return 1`
+
+	// KEYS[2..] entries to drop; ARGV[1] ttl in ms.
+	invalidateScript = `
+redis.call('INCR', KEYS[1])
+redis.call('PEXPIRE', KEYS[1], ARGV[1])
+for i = 2, #KEYS do redis.call('DEL', KEYS[i]) end
+return 1`
+)
+
+func (r *redisCache) CreateConversation(ctx context.Context, c *Conversation) error {
+	if err := r.Repository.CreateConversation(ctx, c); err != nil {
+		return err
+	}
+	r.writeConversation(ctx, c)
+	return nil
+}
+
+func (r *redisCache) GetConversation(ctx context.Context, id string) (*Conversation, error) {
+	if data, err := r.client.Get(ctx, conversationKey(id)); err == nil {
+		var c Conversation
+		if err := json.Unmarshal([]byte(data), &c); err == nil {
+			return &c, nil
+		}
+	} else if !errors.Is(err, redis.ErrNil) {
+		r.warn("Read cached conversation", id, err)
+	}
+
+	version := r.version(ctx, id)
+	c, err := r.Repository.GetConversation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if data, err := json.Marshal(c); err == nil {
+		r.eval(ctx, "Cache conversation", id, fillValueScript,
+			[]string{versionKey(id), conversationKey(id)}, version, r.ttl, data)
+	}
+	return c, nil
+}
+
+func (r *redisCache) SetTitle(ctx context.Context, id, title string, at time.Time) (*Conversation, error) {
+	c, err := r.Repository.SetTitle(ctx, id, title, at)
+	if err != nil {
+		return nil, err
+	}
+	r.forget(ctx, id)
+	return c, nil
+}
+
+func (r *redisCache) SetSystemPrompt(ctx context.Context, id, prompt string, at time.Time) (*Conversation, error) {
+	c, err := r.Repository.SetSystemPrompt(ctx, id, prompt, at)
+	if err != nil {
+		return nil, err
+	}
+	r.forget(ctx, id)
+	return c, nil
+}
+
+func (r *redisCache) SetArchivedAt(ctx context.Context, id string, at *time.Time) (*Conversation, error) {
+	c, err := r.Repository.SetArchivedAt(ctx, id, at)
+	if err != nil {
+		return nil, err
+	}
+	r.forget(ctx, id)
+	return c, nil
+}
+
+func (r *redisCache) SetDeletedAt(ctx context.Context, id string, at *time.Time) (*Conversation, error) {
+	c, err := r.Repository.SetDeletedAt(ctx, id, at)
+	if err != nil {
+		return nil, err
+	}
+	r.forget(ctx, id)
+	return c, nil
+}
+
+func (r *redisCache) DeleteConversation(ctx context.Context, id string, audit *Deletion) error {
+	if err := r.Repository.DeleteConversation(ctx, id, audit); err != nil {
+		return err
+	}
+	r.invalidate(ctx, id)
+	return nil
+}
+
+func (r *redisCache) AppendMessages(ctx context.Context, conversationID string, messages []Message) error {
+	if err := r.Repository.AppendMessages(ctx, conversationID, messages); err != nil {
+		return err
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+
+	args := []any{r.ttl}
+	for _, m := range messages {
+		data, err := json.Marshal(m)
+		if err != nil {
+			r.invalidate(ctx, conversationID)
+			return nil
+		}
+		args = append(args, data)
+	}
+	r.eval(ctx, "Cache messages", conversationID, appendListScript,
+		[]string{versionKey(conversationID), messagesKey(conversationID), conversationKey(conversationID)}, args...)
+	return nil
+}
+
+// UpdateMessage drops the cached messages rather than rewriting one entry
+// in place; updates only happen when an answer is regenerated.
+func (r *redisCache) UpdateMessage(ctx context.Context, m *Message) error {
+	if err := r.Repository.UpdateMessage(ctx, m); err != nil {
+		return err
+	}
+	r.eval(ctx, "Invalidate cached messages", m.ConversationID, invalidateScript,
+		[]string{versionKey(m.ConversationID), messagesKey(m.ConversationID)}, r.ttl)
+	return nil
+}
+
+func (r *redisCache) ListMessages(ctx context.Context, conversationID string) ([]Message, error) {
+	if messages, ok := r.cachedMessages(ctx, conversationID); ok {
+		return messages, nil
+	}
+
+	version := r.version(ctx, conversationID)
+	messages, err := r.Repository.ListMessages(ctx, conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []any{version, r.ttl}
+	for _, m := range messages {
+		data, err := json.Marshal(m)
+		if err != nil {
+			return messages, nil
+		}
+		args = append(args, data)
+	}
+	r.eval(ctx, "Cache messages", conversationID, fillListScript,
+		[]string{versionKey(conversationID), messagesKey(conversationID)}, args...)
+	return messages, nil
+}
+
+// cachedMessages returns the cached messages and refreshes their expiry.
+// An empty list is a miss: Redis does not keep empty lists, and a
+// conversation without messages is cheap to read.
+func (r *redisCache) cachedMessages(ctx context.Context, conversationID string) ([]Message, bool) {
+	items, err := r.client.LRange(ctx, messagesKey(conversationID), 0, -1)
+	if err != nil {
+		r.warn("Read cached messages", conversationID, err)
+		return nil, false
+	}
+	if len(items) == 0 {
+		return nil, false
+	}
+
+	messages := make([]Message, len(items))
+	for i, item := range items {
+		if err := json.Unmarshal([]byte(item), &messages[i]); err != nil {
+			r.warn("Decode cached message", conversationID, err)
+			return nil, false
+		}
+	}
+	if _, err := r.client.Expire(ctx, messagesKey(conversationID), r.ttl); err != nil {
+		r.warn("Refresh cached messages", conversationID, err)
+	}
+	return messages, true
+}
+
+func (r *redisCache) writeConversation(ctx context.Context, c *Conversation) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		r.invalidate(ctx, c.ID)
+		return
+	}
+	r.eval(ctx, "Cache conversation", c.ID, writeValueScript,
+		[]string{versionKey(c.ID), conversationKey(c.ID)}, r.ttl, data)
+}
+
+// forget drops the cached conversation after an update. Writing the
+// updated one instead could cache an older version than that of a
+// concurrent update that finished first.
+func (r *redisCache) forget(ctx context.Context, id string) {
+	r.eval(ctx, "Invalidate cached conversation", id, invalidateScript,
+		[]string{versionKey(id), conversationKey(id)}, r.ttl)
+}
+
+func (r *redisCache) invalidate(ctx context.Context, id string) {
+	r.eval(ctx, "Invalidate cached conversation", id, invalidateScript,
+		[]string{versionKey(id), conversationKey(id), messagesKey(id)}, r.ttl)
+}
+
+// version returns the conversation's current version, or "" when it has
+// none or Redis fails; a failed read makes the later fill a no-op at worst.
+func (r *redisCache) version(ctx context.Context, id string) string {
+	v, err := r.client.Get(ctx, versionKey(id))
+	if err != nil && !errors.Is(err, redis.ErrNil) {
+		r.warn("Read cache version", id, err)
+		return "unavailable"
+	}
+	return v
+}
+
+// eval runs a cache script. Failures are logged, not returned: the write
+// already reached the repository, and stale entries expire with the TTL.
+func (r *redisCache) eval(ctx context.Context, action, id, script string, keys []string, args ...any) {
+	if _, err := r.client.Eval(ctx, script, keys, args...); err != nil {
+		r.warn(action, id, err)
+	}
+}
+
+func (r *redisCache) warn(action, id string, err error) {
+	r.logger.Warn(action+" failed", zap.String("conversation_id", id), zap.Error(err))
+}
@@ -0,0 +1,117 @@
+package conversation
+
+import (
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+
+	// snippetRadius is how many characters of context a snippet keeps on
+	// each side of the first match.
+	snippetRadius = 80
+)
+
+// rankResults sorts results, title matches first, then conversations with
+// more matching messages, then the most recent, and returns the page req
+// asks for and the total number of results.
+func rankResults(results []SearchResult, req *SearchRequest) ([]SearchResult, int) {
+	slices.SortFunc(results, func(a, b SearchResult) int {
+		switch {
+		case a.TitleMatch != b.TitleMatch:
+			if a.TitleMatch {
+				return -1
+			}
+			return 1
+		case len(a.Matches) != len(b.Matches):
+			return len(b.Matches) - len(a.Matches)
+		case !a.Conversation.UpdatedAt.Equal(b.Conversation.UpdatedAt):
+			return b.Conversation.UpdatedAt.Compare(a.Conversation.UpdatedAt)
+		}
+		return strings.Compare(a.Conversation.ID, b.Conversation.ID)
+	})
+
+	total := len(results)
+	start := min(req.Offset, total)
+	end := min(start+req.Limit, total)
+	return results[start:end], total
+}
+
+// searchTerms splits a query into lowercase terms, dropping duplicates.
+func searchTerms(query string) []string {
+	seen := make(map[string]bool)
+	terms := []string{}
+	for _, t := range strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	}) {
+		if !seen[t] {
+			seen[t] = true
+			terms = append(terms, t)
+		}
+	}
+	return terms
+}
+
+// containsAll reports whether text contains every term, ignoring case.
+func containsAll(text string, terms []string) bool {
+	lower := strings.ToLower(text)
+	for _, t := range terms {
+		if !strings.Contains(lower, t) {
+			return false
+		}
+	}
+	return true
+}
+
+// inRange reports whether t falls within [from, to]; zero bounds are open.
+func inRange(t, from, to time.Time) bool {
+	return (from.IsZero() || !t.Before(from)) && (to.IsZero() || !t.After(to))
+}
+
+// snippet returns text around the first occurrence of any term, trimmed
+// to word boundaries and marked with ellipses where cut.
+func snippet(text string, terms []string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	lower := strings.ToLower(text)
+
+	at := -1
+	for _, t := range terms {
+		if i := strings.Index(lower, t); i >= 0 && (at < 0 || i < at) {
+			at = i
+		}
+	}
+	if at < 0 || len(lower) != len(text) {
+		// Lowercasing changed byte offsets (rare scripts); fall back to the
+		// start of the text.
+		at = 0
+	}
+
+	start := max(0, at-snippetRadius)
+	end := min(len(text), at+snippetRadius)
+	for start > 0 && !utf8.RuneStart(text[start]) {
+		start--
+	}
+	for end < len(text) && !utf8.RuneStart(text[end]) {
+		end++
+	}
+
+	s := text[start:end]
+	if start > 0 {
+		if i := strings.IndexByte(s, ' '); i >= 0 && i < snippetRadius/2 {
+			s = s[i+1:]
+		}
+		s = "…" + s
+	}
+	if end < len(text) {
+		if i := strings.LastIndexByte(s, ' '); i > len(s)-snippetRadius/2 {
+			s = s[:i]
+		}
+		s += "…"
+	}
+	return s
+}
@@ -0,0 +1,529 @@
+package conversation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Joepolymath/DaVinci/libs/shared-go/auth"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/ai"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/pagination"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/validate"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	maxTitleLength        = 200
+	autoTitleLength       = 60
+	maxSystemPromptLength = 20000
+
+	defaultPurgeAfter = 30 * 24 * time.Hour
+)
+
+// Config holds the conversation service settings. Zero values use the
+// defaults.
+type Config struct {
+	// PurgeAfter is how long deleted conversations can be restored before
+	// Purge removes them (default: 30 days).
+	PurgeAfter time.Duration
+	// RetainFor is how long a conversation is kept after its last update
+	// before ApplyRetention deletes it, whatever its status. Zero keeps
+	// conversations until they are deleted.
+	RetainFor time.Duration
+	// TenantRetention replaces RetainFor for the conversations of the
+	// tenants it lists.
+	TenantRetention map[string]time.Duration
+}
+
+// retentionBatch is how many conversations Purge and ApplyRetention load
+// at a time.
+const retentionBatch = 500
+
+type service struct {
+	repo      Repository
+	providers *ai.ChatProviderRegistry
+	cfg       Config
+	logger    *zap.Logger
+}
+
+// NewService creates the conversation service. providers is used to check
+// the provider and model a conversation is created with.
+func NewService(repo Repository, providers *ai.ChatProviderRegistry, cfg Config, logger *zap.Logger) Service {
+	if cfg.PurgeAfter <= 0 {
+		cfg.PurgeAfter = defaultPurgeAfter
+	}
+	return &service{
+		repo:      repo,
+		providers: providers,
+		cfg:       cfg,
+		logger:    logger,
+	}
+}
+
+func (s *service) Create(ctx context.Context, req *CreateRequest) (*Conversation, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	provider, model := strings.TrimSpace(req.Provider), strings.TrimSpace(req.Model)
+	if err := s.checkModel(ctx, provider, model); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	c := &Conversation{
+		ID:           uuid.NewString(),
+		OwnerID:      ownerID(ctx),
+		TenantID:     auth.Tenant(ctx),
+		Title:        strings.TrimSpace(req.Title),
+		SystemPrompt: strings.TrimSpace(req.SystemPrompt),
+		Provider:     provider,
+		Model:        model,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if err := s.repo.CreateConversation(ctx, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// checkModel verifies that provider is registered (empty is the default)
+// and, when model is set, that the provider lists it.
+func (s *service) checkModel(ctx context.Context, provider, model string) error {
+	if provider == "" && model == "" {
+		return nil
+	}
+
+	var errs validate.Errors
+	if s.providers == nil {
+		errs.Add("provider", ErrUnknownProvider)
+		return errs
+	}
+
+	p := s.providers.Default()
+	if provider != "" {
+		var ok bool
+		if p, ok = s.providers.Get(provider); !ok {
+			errs.Add("provider", fmt.Errorf("%w: %q (available: %s)", ErrUnknownProvider, provider, strings.Join(s.providers.Names(), ", ")))
+			return errs
+		}
+	}
+	if model == "" {
+		return nil
+	}
+
+	ok, err := ai.Serves(ctx, p, model)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrModelsUnavailable, err)
+	}
+	if ok {
+		return nil
+	}
+	errs.Add("model", fmt.Errorf("%w: %q", ErrUnknownModel, model))
+	return errs
+}
+
+func (s *service) List(ctx context.Context, req *ListRequest) (*ListResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	filter := ConversationFilter{
+		OwnerID:  scope(ctx),
+		TenantID: auth.Tenant(ctx),
+		Status:   req.Status,
+		After:    req.Page.After(),
+		Limit:    req.Page.Size() + 1,
+	}
+	if filter.Status == "" {
+		filter.Status = StatusActive
+	}
+	conversations, err := s.repo.ListConversations(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	items, next := pagination.Next(conversations, req.Page, Conversation.pageKey)
+	return &ListResponse{Conversations: items, NextCursor: next}, nil
+}
+
+func (s *service) Messages(ctx context.Context, id string, page pagination.Request) (*MessagesResponse, error) {
+	if err := page.Validate(); err != nil {
+		return nil, err
+	}
+	if _, err := s.get(ctx, id); err != nil {
+		return nil, err
+	}
+	messages, err := s.repo.ListMessages(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	items, next, err := pagination.Paginate(messages, page, pagination.Ascending, Message.pageKey)
+	if err != nil {
+		return nil, err
+	}
+	return &MessagesResponse{Messages: items, NextCursor: next}, nil
+}
+
+// get returns the conversation unless it is missing, deleted or not the
+// current user's.
+func (s *service) get(ctx context.Context, id string) (*Conversation, error) {
+	c, err := s.repo.GetConversation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if c.DeletedAt != nil || !visible(ctx, c) {
+		return nil, ErrConversationNotFound
+	}
+	return c, nil
+}
+
+// ownerID is the current user's ID, or empty outside a user's request.
+func ownerID(ctx context.Context) string {
+	u, _ := auth.CurrentUser(ctx)
+	return u.ID
+}
+
+// scope is the owner whose conversations the current user may see, or
+// empty for every conversation: without a user, as when authentication is
+// off or for background jobs, and for admins.
+func scope(ctx context.Context) string {
+	if auth.Can(ctx, auth.PermManage) {
+		return ""
+	}
+	return ownerID(ctx)
+}
+
+// visible reports whether the current user may see c: it must be in
+// their tenant, and theirs unless they are an admin.
+func visible(ctx context.Context, c *Conversation) bool {
+	owner, tenant := scope(ctx), auth.Tenant(ctx)
+	return (owner == "" || c.OwnerID == owner) && c.TenantID == tenant
+}
+
+func (s *service) Get(ctx context.Context, id string) (*ConversationDetail, error) {
+	c, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	messages, err := s.repo.ListMessages(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &ConversationDetail{Conversation: *c, Messages: messages}, nil
+}
+
+func (s *service) Search(ctx context.Context, req *SearchRequest) (*SearchResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	normalized := *req
+	if normalized.Limit <= 0 {
+		normalized.Limit = defaultSearchLimit
+	}
+	normalized.Limit = min(normalized.Limit, maxSearchLimit)
+	normalized.Offset = max(normalized.Offset, 0)
+	normalized.OwnerID = scope(ctx)
+	normalized.TenantID = auth.Tenant(ctx)
+
+	results, total, err := s.repo.Search(ctx, &normalized)
+	if err != nil {
+		return nil, err
+	}
+	return &SearchResponse{
+		Results: results,
+		Total:   total,
+		Limit:   normalized.Limit,
+		Offset:  normalized.Offset,
+	}, nil
+}
+
+func (s *service) Rename(ctx context.Context, id string, req *RenameRequest) (*Conversation, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.get(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.SetTitle(ctx, id, strings.TrimSpace(req.Title), time.Now().UTC())
+}
+
+func (s *service) SetSystemPrompt(ctx context.Context, id string, req *SystemPromptRequest) (*Conversation, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.get(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.SetSystemPrompt(ctx, id, strings.TrimSpace(req.SystemPrompt), time.Now().UTC())
+}
+
+func (s *service) Archive(ctx context.Context, id string) (*Conversation, error) {
+	c, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if c.ArchivedAt != nil {
+		return c, nil
+	}
+	now := time.Now().UTC()
+	return s.repo.SetArchivedAt(ctx, id, &now)
+}
+
+func (s *service) Unarchive(ctx context.Context, id string) (*Conversation, error) {
+	c, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if c.ArchivedAt == nil {
+		return c, nil
+	}
+	return s.repo.SetArchivedAt(ctx, id, nil)
+}
+
+func (s *service) Delete(ctx context.Context, id string) error {
+	if _, err := s.get(ctx, id); err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	_, err := s.repo.SetDeletedAt(ctx, id, &now)
+	return err
+}
+
+func (s *service) Restore(ctx context.Context, id string) (*Conversation, error) {
+	c, err := s.repo.GetConversation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !visible(ctx, c) {
+		return nil, ErrConversationNotFound
+	}
+	if c.DeletedAt == nil {
+		return c, nil
+	}
+	return s.repo.SetDeletedAt(ctx, id, nil)
+}
+
+func (s *service) Purge(ctx context.Context) (int, error) {
+	now := time.Now().UTC()
+	purged := 0
+	err := s.deleteAll(ctx, ConversationFilter{DeletedBefore: now.Add(-s.cfg.PurgeAfter)}, DeletionPurged, now, false,
+		func(*Deletion) { purged++ })
+	return purged, err
+}
+
+func (s *service) ApplyRetention(ctx context.Context, dryRun bool) (*RetentionReport, error) {
+	if !s.retentionEnabled() {
+		return nil, ErrRetentionDisabled
+	}
+
+	now := time.Now().UTC()
+	report := &RetentionReport{DryRun: dryRun, TenantCutoffs: map[string]time.Time{}, Deletions: []Deletion{}}
+	record := func(d *Deletion) { report.Deletions = append(report.Deletions, *d) }
+	if s.cfg.RetainFor > 0 {
+		report.Cutoff = now.Add(-s.cfg.RetainFor)
+		filter := ConversationFilter{
+			ExcludeTenants: slices.Sorted(maps.Keys(s.cfg.TenantRetention)),
+			UpdatedBefore:  report.Cutoff,
+		}
+		if err := s.deleteAll(ctx, filter, DeletionRetention, now, dryRun, record); err != nil {
+			return report, err
+		}
+	}
+	for _, tenant := range slices.Sorted(maps.Keys(s.cfg.TenantRetention)) {
+		period := s.cfg.TenantRetention[tenant]
+		if period <= 0 {
+			continue
+		}
+		report.TenantCutoffs[tenant] = now.Add(-period)
+		filter := ConversationFilter{TenantID: tenant, UpdatedBefore: report.TenantCutoffs[tenant]}
+		if err := s.deleteAll(ctx, filter, DeletionRetention, now, dryRun, record); err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
+
+// retentionEnabled reports whether any conversations have a retention
+// period.
+func (s *service) retentionEnabled() bool {
+	if s.cfg.RetainFor > 0 {
+		return true
+	}
+	for _, period := range s.cfg.TenantRetention {
+		if period > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteAll permanently deletes the conversations filter selects, a batch
+// at a time, and passes each audit record to visit. A dry run only visits
+// them.
+func (s *service) deleteAll(ctx context.Context, filter ConversationFilter, reason string, now time.Time, dryRun bool, visit func(*Deletion)) error {
+	filter.Limit = retentionBatch
+	for {
+		conversations, err := s.repo.ListConversations(ctx, filter)
+		if err != nil {
+			return err
+		}
+		for i := range conversations {
+			audit := deletion(&conversations[i], reason, now)
+			if !dryRun {
+				err := s.repo.DeleteConversation(ctx, audit.ConversationID, audit)
+				if errors.Is(err, ErrConversationNotFound) {
+					continue
+				}
+				if err != nil {
+					return err
+				}
+			}
+			visit(audit)
+		}
+		if len(conversations) < filter.Limit {
+			return nil
+		}
+		last := conversations[len(conversations)-1].pageKey()
+		filter.After = &last
+	}
+}
+
+// deletion creates the audit record of deleting c.
+func deletion(c *Conversation, reason string, at time.Time) *Deletion {
+	return &Deletion{
+		ID:                    uuid.NewString(),
+		ConversationID:        c.ID,
+		TenantID:              c.TenantID,
+		Reason:                reason,
+		MessageCount:          c.MessageCount,
+		ConversationCreatedAt: c.CreatedAt,
+		ConversationUpdatedAt: c.UpdatedAt,
+		DeletedAt:             at,
+	}
+}
+
+func (s *service) GetMessage(ctx context.Context, messageID string) (*Message, error) {
+	m, err := s.repo.GetMessage(ctx, messageID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.get(ctx, m.ConversationID); err != nil {
+		if errors.Is(err, ErrConversationNotFound) {
+			return nil, ErrMessageNotFound
+		}
+		return nil, err
+	}
+	return m, nil
+}
+
+func (s *service) History(ctx context.Context, id string) (*Conversation, []Message, error) {
+	c, err := s.get(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	messages, err := s.repo.ListMessages(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	active := messages[:0]
+	for _, m := range messages {
+		if !m.Superseded {
+			active = append(active, m)
+		}
+	}
+	return c, active, nil
+}
+
+func (s *service) Append(ctx context.Context, id string, messages []Message) ([]Message, error) {
+	if _, err := s.get(ctx, id); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	stored := make([]Message, len(messages))
+	for i, m := range messages {
+		m.ID = newMessageID()
+		m.ConversationID = id
+		m.OwnerID, m.TenantID = ownerID(ctx), auth.Tenant(ctx)
+		m.CreatedAt = now
+		stored[i] = m
+	}
+	if err := s.repo.AppendMessages(ctx, id, stored); err != nil {
+		return nil, err
+	}
+	return stored, nil
+}
+
+func (s *service) Replace(ctx context.Context, id, messageID string, replacement Message) (*Message, error) {
+	if _, err := s.get(ctx, id); err != nil {
+		return nil, err
+	}
+	messages, err := s.repo.ListMessages(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	i := slices.IndexFunc(messages, func(m Message) bool { return m.ID == messageID })
+	if i < 0 {
+		return nil, ErrMessageNotFound
+	}
+	old := messages[i]
+	old.Superseded = true
+	if err := s.repo.UpdateMessage(ctx, &old); err != nil {
+		return nil, err
+	}
+
+	replacement.RegeneratedFrom = messageID
+	stored, err := s.Append(ctx, id, []Message{replacement})
+	if err != nil {
+		return nil, err
+	}
+	return &stored[0], nil
+}
+
+// newMessageID returns a time-ordered ID, so that messages sharing a
+// timestamp still sort in the order they were stored.
+func newMessageID() string {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return uuid.NewString()
+	}
+	return id.String()
+}
+
+// appendedTitle is the title an untitled conversation takes from messages
+// appended to it: that of the first user message, or "" without one.
+func appendedTitle(messages []Message) string {
+	for _, m := range messages {
+		if m.Role == ai.RoleUser {
+			return autoTitle(m.Content)
+		}
+	}
+	return ""
+}
+
+// autoTitle is the first line of content, shortened at a word boundary.
+func autoTitle(content string) string {
+	title, _, _ := strings.Cut(strings.TrimSpace(content), "\n")
+	title = strings.Join(strings.Fields(title), " ")
+	if utf8.RuneCountInString(title) <= autoTitleLength {
+		return title
+	}
+
+	runes := []rune(title)[:autoTitleLength]
+	if i := strings.LastIndexByte(string(runes), ' '); i > autoTitleLength/2 {
+		return string(runes)[:i] + "…"
+	}
+	return string(runes) + "…"
+}
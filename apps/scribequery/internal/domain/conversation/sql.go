@@ -0,0 +1,595 @@
+package conversation
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/outbox"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/ai"
+)
+
+const (
+	conversationColumns = `id, title, system_prompt, provider, model, message_count,
+		created_at, updated_at, archived_at, deleted_at, owner_id, tenant_id`
+	messageColumns = `id, conversation_id, role, content, provider, model, attachments,
+		usage, latency_ms, finish_reason, superseded, regenerated_from, created_at,
+		owner_id, tenant_id`
+	eventColumns    = `id, topic, event_key, payload, created_at, attempts, last_error, published_at`
+	deletionColumns = `id, conversation_id, reason, message_count, conversation_created_at,
+		conversation_updated_at, deleted_at, tenant_id`
+)
+
+// dialect adapts the repository's queries, written for Postgres, to a
+// database.
+type dialect struct {
+	placeholder string // prefix of numbered parameters: "$" for $1
+	ilike       string // case-insensitive LIKE operator
+}
+
+var (
+	postgresDialect = dialect{placeholder: "$", ilike: "ILIKE"}
+	// SQLite numbers parameters ?1, ?2, ...; its LIKE ignores ASCII case.
+	sqliteDialect = dialect{placeholder: "?", ilike: "LIKE"}
+)
+
+// rebind rewrites query's $n parameters for d.
+func (d dialect) rebind(query string) string {
+	if d.placeholder == "$" {
+		return query
+	}
+	return strings.ReplaceAll(query, "$", d.placeholder)
+}
+
+type sqlRepository struct {
+	db      *sql.DB
+	dialect dialect
+}
+
+// NewPostgresRepository creates a repository on the conversations,
+// messages and outbox_events tables of a Postgres database (see the
+// scribequery migrations).
+func NewPostgresRepository(db *sql.DB) Repository {
+	return &sqlRepository{db: db, dialect: postgresDialect}
+}
+
+// NewSQLiteRepository creates a repository on the conversations,
+// messages and outbox_events tables of a SQLite database.
+func NewSQLiteRepository(db *sql.DB) Repository {
+	return &sqlRepository{db: db, dialect: sqliteDialect}
+}
+
+func (r *sqlRepository) exec(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return r.db.ExecContext(ctx, r.dialect.rebind(query), args...)
+}
+
+func (r *sqlRepository) query(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return r.db.QueryContext(ctx, r.dialect.rebind(query), args...)
+}
+
+func (r *sqlRepository) queryRow(ctx context.Context, query string, args ...any) *sql.Row {
+	return r.db.QueryRowContext(ctx, r.dialect.rebind(query), args...)
+}
+
+func (r *sqlRepository) CreateConversation(ctx context.Context, c *Conversation) error {
+	_, err := r.exec(ctx, `INSERT INTO conversations (`+conversationColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+		c.ID, c.Title, c.SystemPrompt, c.Provider, c.Model, c.MessageCount,
+		c.CreatedAt, c.UpdatedAt, c.ArchivedAt, c.DeletedAt, c.OwnerID, c.TenantID)
+	if err != nil {
+		return fmt.Errorf("failed to create conversation: %w", err)
+	}
+	return nil
+}
+
+func (r *sqlRepository) GetConversation(ctx context.Context, id string) (*Conversation, error) {
+	row := r.queryRow(ctx, `SELECT `+conversationColumns+` FROM conversations WHERE id = $1`, id)
+	c, err := scanConversation(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrConversationNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conversation: %w", err)
+	}
+	return c, nil
+}
+
+func (r *sqlRepository) ListConversations(ctx context.Context, filter ConversationFilter) ([]Conversation, error) {
+	where, args := filterQuery(filter)
+	query := `SELECT ` + conversationColumns + ` FROM conversations
+		WHERE ` + where + ` ORDER BY updated_at DESC, id DESC`
+	if filter.Limit > 0 {
+		query += ` LIMIT ` + strconv.Itoa(filter.Limit)
+	}
+	rows, err := r.query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list conversations: %w", err)
+	}
+	defer rows.Close()
+
+	conversations := []Conversation{}
+	for rows.Next() {
+		c, err := scanConversation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list conversations: %w", err)
+		}
+		conversations = append(conversations, *c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list conversations: %w", err)
+	}
+	return conversations, nil
+}
+
+func (r *sqlRepository) SetTitle(ctx context.Context, id, title string, at time.Time) (*Conversation, error) {
+	return r.update(ctx, `title = $2, updated_at = $3`, id, title, at)
+}
+
+func (r *sqlRepository) SetSystemPrompt(ctx context.Context, id, prompt string, at time.Time) (*Conversation, error) {
+	return r.update(ctx, `system_prompt = $2, updated_at = $3`, id, prompt, at)
+}
+
+func (r *sqlRepository) SetArchivedAt(ctx context.Context, id string, at *time.Time) (*Conversation, error) {
+	return r.update(ctx, `archived_at = $2`, id, at)
+}
+
+func (r *sqlRepository) SetDeletedAt(ctx context.Context, id string, at *time.Time) (*Conversation, error) {
+	return r.update(ctx, `deleted_at = $2`, id, at)
+}
+
+// update sets the columns of assignments on conversation id, $1, and
+// returns the updated row.
+func (r *sqlRepository) update(ctx context.Context, assignments, id string, args ...any) (*Conversation, error) {
+	row := r.queryRow(ctx, `UPDATE conversations SET `+assignments+` WHERE id = $1
+		RETURNING `+conversationColumns, append([]any{id}, args...)...)
+	c, err := scanConversation(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrConversationNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update conversation: %w", err)
+	}
+	return c, nil
+}
+
+func (r *sqlRepository) DeleteConversation(ctx context.Context, id string, audit *Deletion) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete conversation: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Messages are removed by the foreign key's ON DELETE CASCADE.
+	result, err := tx.ExecContext(ctx, r.dialect.rebind(`DELETE FROM conversations WHERE id = $1`), id)
+	if err != nil {
+		return fmt.Errorf("failed to delete conversation: %w", err)
+	}
+	if err := affected(result, ErrConversationNotFound); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, r.dialect.rebind(`INSERT INTO conversation_deletions (`+deletionColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`),
+		audit.ID, audit.ConversationID, audit.Reason, audit.MessageCount,
+		audit.ConversationCreatedAt, audit.ConversationUpdatedAt, audit.DeletedAt, audit.TenantID,
+	); err != nil {
+		return fmt.Errorf("failed to record conversation deletion: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to delete conversation: %w", err)
+	}
+	return nil
+}
+
+func (r *sqlRepository) AppendMessages(ctx context.Context, conversationID string, messages []Message) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to append messages: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Counting the messages in the row, rather than writing back a count
+	// read earlier, keeps concurrent appends from losing each other's.
+	// On Postgres the update also locks the row until the messages are
+	// stored.
+	c := &Conversation{ID: conversationID}
+	var updatedAt *time.Time
+	if len(messages) > 0 {
+		updatedAt = &messages[len(messages)-1].CreatedAt
+	}
+	err = tx.QueryRowContext(ctx, r.dialect.rebind(`UPDATE conversations
+		SET message_count = message_count + $2, updated_at = COALESCE($3, updated_at),
+			title = CASE WHEN title = '' THEN $4 ELSE title END
+		WHERE id = $1
+		RETURNING owner_id, tenant_id`), conversationID, len(messages), updatedAt, appendedTitle(messages),
+	).Scan(&c.OwnerID, &c.TenantID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrConversationNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to append messages: %w", err)
+	}
+
+	insert := r.dialect.rebind(`INSERT INTO messages (` + messageColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)`)
+	for _, m := range messages {
+		attachments, usage, err := encodeMessage(&m)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, insert,
+			m.ID, conversationID, m.Role, m.Content, m.Provider, m.Model, attachments,
+			usage, m.LatencyMs, m.FinishReason, m.Superseded, m.RegeneratedFrom, m.CreatedAt,
+			m.OwnerID, m.TenantID,
+		); err != nil {
+			return fmt.Errorf("failed to append messages: %w", err)
+		}
+	}
+
+	events, err := messageEvents(c, messages)
+	if err != nil {
+		return err
+	}
+	insertEvent := r.dialect.rebind(`INSERT INTO outbox_events (` + eventColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`)
+	for _, e := range events {
+		if _, err := tx.ExecContext(ctx, insertEvent,
+			e.ID, e.Topic, e.Key, string(e.Payload), e.CreatedAt, e.Attempts, e.LastError, e.PublishedAt,
+		); err != nil {
+			return fmt.Errorf("failed to append messages: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to append messages: %w", err)
+	}
+	return nil
+}
+
+func (r *sqlRepository) GetMessage(ctx context.Context, messageID string) (*Message, error) {
+	row := r.queryRow(ctx, `SELECT `+messageColumns+` FROM messages WHERE id = $1`, messageID)
+	m, err := scanMessage(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrMessageNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get message: %w", err)
+	}
+	return m, nil
+}
+
+func (r *sqlRepository) UpdateMessage(ctx context.Context, m *Message) error {
+	attachments, usage, err := encodeMessage(m)
+	if err != nil {
+		return err
+	}
+	result, err := r.exec(ctx, `UPDATE messages SET role = $3, content = $4, provider = $5,
+		model = $6, attachments = $7, usage = $8, latency_ms = $9, finish_reason = $10,
+		superseded = $11, regenerated_from = $12, created_at = $13
+		WHERE id = $1 AND conversation_id = $2`,
+		m.ID, m.ConversationID, m.Role, m.Content, m.Provider, m.Model, attachments,
+		usage, m.LatencyMs, m.FinishReason, m.Superseded, m.RegeneratedFrom, m.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update message: %w", err)
+	}
+	return affected(result, ErrMessageNotFound)
+}
+
+func (r *sqlRepository) ListMessages(ctx context.Context, conversationID string) ([]Message, error) {
+	if _, err := r.GetConversation(ctx, conversationID); err != nil {
+		return nil, err
+	}
+	return r.queryMessages(ctx, `SELECT `+messageColumns+` FROM messages
+		WHERE conversation_id = $1 ORDER BY seq`, conversationID)
+}
+
+// Search finds title matches and matching messages with a case-insensitive
+// LIKE, one condition per term, and ranks them with rankResults. Search terms are
+// letters and digits only, so they need no escaping in patterns. Postgres
+// serves the conditions from trigram indexes when pg_trgm is installed;
+// SQLite has none and scans the messages.
+func (r *sqlRepository) Search(ctx context.Context, req *SearchRequest) ([]SearchResult, int, error) {
+	terms := searchTerms(req.Query)
+	results := make(map[string]*SearchResult)
+
+	titleQuery, args := r.searchQuery(`SELECT `+conversationColumns+` FROM conversations
+		WHERE deleted_at IS NULL`, "title", "updated_at", "owner_id", "tenant_id", terms, req)
+	rows, err := r.query(ctx, titleQuery, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search conversations: %w", err)
+	}
+	for rows.Next() {
+		c, err := scanConversation(rows)
+		if err != nil {
+			rows.Close()
+			return nil, 0, fmt.Errorf("failed to search conversations: %w", err)
+		}
+		results[c.ID] = &SearchResult{Conversation: *c, TitleMatch: true}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to search conversations: %w", err)
+	}
+
+	messageQuery, args := r.searchQuery(`SELECT `+prefixColumns("m.", messageColumns)+`
+		FROM messages m JOIN conversations c ON c.id = m.conversation_id
+		WHERE c.deleted_at IS NULL`, "m.content", "m.created_at", "c.owner_id", "c.tenant_id", terms, req)
+	matches, err := r.queryMessages(ctx, messageQuery+` ORDER BY m.seq`, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search messages: %w", err)
+	}
+	for _, m := range matches {
+		result, ok := results[m.ConversationID]
+		if !ok {
+			c, err := r.GetConversation(ctx, m.ConversationID)
+			if err != nil {
+				return nil, 0, err
+			}
+			result = &SearchResult{Conversation: *c}
+			results[c.ID] = result
+		}
+		result.Matches = append(result.Matches, MessageMatch{
+			MessageID: m.ID,
+			Role:      m.Role,
+			Snippet:   snippet(m.Content, terms),
+			CreatedAt: m.CreatedAt,
+		})
+	}
+
+	ranked := make([]SearchResult, 0, len(results))
+	for _, result := range results {
+		ranked = append(ranked, *result)
+	}
+	page, total := rankResults(ranked, req)
+	return page, total, nil
+}
+
+func (r *sqlRepository) queryMessages(ctx context.Context, query string, args ...any) ([]Message, error) {
+	rows, err := r.query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list messages: %w", err)
+	}
+	defer rows.Close()
+
+	messages := []Message{}
+	for rows.Next() {
+		m, err := scanMessage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list messages: %w", err)
+		}
+		messages = append(messages, *m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list messages: %w", err)
+	}
+	return messages, nil
+}
+
+// searchQuery appends to base a condition that column contains every term
+// and that timeColumn is within req's date range, and, when req has an
+// owner or tenant, that ownerColumn and tenantColumn are those. On Postgres the trigram
+// indexes on messages.content and conversations.title serve the term
+// conditions; SQLite scans the tables.
+func (r *sqlRepository) searchQuery(base, column, timeColumn, ownerColumn, tenantColumn string, terms []string, req *SearchRequest) (string, []any) {
+	var b strings.Builder
+	b.WriteString(base)
+	args := []any{}
+	param := func(v any) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	for _, t := range terms {
+		b.WriteString(" AND " + column + " " + r.dialect.ilike + " " + param("%"+t+"%"))
+	}
+	if !req.From.IsZero() {
+		b.WriteString(" AND " + timeColumn + " >= " + param(req.From))
+	}
+	if !req.To.IsZero() {
+		b.WriteString(" AND " + timeColumn + " <= " + param(req.To))
+	}
+	if req.OwnerID != "" {
+		b.WriteString(" AND " + ownerColumn + " = " + param(req.OwnerID))
+	}
+	if req.TenantID != "" {
+		b.WriteString(" AND " + tenantColumn + " = " + param(req.TenantID))
+	}
+	return b.String(), args
+}
+
+// filterQuery is the condition on conversations that filter selects them
+// and its arguments. After is a row comparison, which the
+// (updated_at DESC, id DESC) indexes serve.
+func filterQuery(filter ConversationFilter) (string, []any) {
+	conditions := []string{"TRUE"}
+	args := []any{}
+	param := func(v any) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	if filter.After != nil {
+		conditions = append(conditions, "(updated_at, id) < ("+param(filter.After.Time)+", "+param(filter.After.ID)+")")
+	}
+	if filter.OwnerID != "" {
+		conditions = append(conditions, "owner_id = "+param(filter.OwnerID))
+	}
+	if filter.TenantID != "" {
+		conditions = append(conditions, "tenant_id = "+param(filter.TenantID))
+	} else if len(filter.ExcludeTenants) > 0 {
+		excluded := make([]string, len(filter.ExcludeTenants))
+		for i, tenant := range filter.ExcludeTenants {
+			excluded[i] = param(tenant)
+		}
+		conditions = append(conditions, "tenant_id NOT IN ("+strings.Join(excluded, ", ")+")")
+	}
+	if !filter.UpdatedBefore.IsZero() {
+		conditions = append(conditions, "updated_at < "+param(filter.UpdatedBefore))
+	}
+	if !filter.DeletedBefore.IsZero() {
+		conditions = append(conditions, "deleted_at < "+param(filter.DeletedBefore))
+	}
+	switch filter.Status {
+	case "":
+	case StatusDeleted:
+		conditions = append(conditions, "deleted_at IS NOT NULL")
+	case StatusAll:
+		conditions = append(conditions, "deleted_at IS NULL")
+	case StatusArchived:
+		conditions = append(conditions, "deleted_at IS NULL", "archived_at IS NOT NULL")
+	default:
+		conditions = append(conditions, "deleted_at IS NULL", "archived_at IS NULL")
+	}
+	return strings.Join(conditions, " AND "), args
+}
+
+// prefixColumns qualifies each column in a comma-separated list.
+func prefixColumns(prefix, columns string) string {
+	fields := strings.Split(columns, ",")
+	for i, f := range fields {
+		fields[i] = prefix + strings.TrimSpace(f)
+	}
+	return strings.Join(fields, ", ")
+}
+
+// affected returns notFound when result changed no rows.
+func affected(result sql.Result, notFound error) error {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return notFound
+	}
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanConversation(row scanner) (*Conversation, error) {
+	var (
+		c                 Conversation
+		archived, deleted sql.NullTime
+	)
+	if err := row.Scan(&c.ID, &c.Title, &c.SystemPrompt, &c.Provider, &c.Model, &c.MessageCount,
+		&c.CreatedAt, &c.UpdatedAt, &archived, &deleted, &c.OwnerID, &c.TenantID); err != nil {
+		return nil, err
+	}
+	c.CreatedAt, c.UpdatedAt = c.CreatedAt.UTC(), c.UpdatedAt.UTC()
+	c.ArchivedAt, c.DeletedAt = nullTime(archived), nullTime(deleted)
+	return &c, nil
+}
+
+func scanMessage(row scanner) (*Message, error) {
+	var (
+		m                  Message
+		attachments, usage []byte
+	)
+	if err := row.Scan(&m.ID, &m.ConversationID, &m.Role, &m.Content, &m.Provider, &m.Model,
+		&attachments, &usage, &m.LatencyMs, &m.FinishReason, &m.Superseded, &m.RegeneratedFrom,
+		&m.CreatedAt, &m.OwnerID, &m.TenantID); err != nil {
+		return nil, err
+	}
+	m.CreatedAt = m.CreatedAt.UTC()
+	if len(attachments) > 0 {
+		if err := json.Unmarshal(attachments, &m.Attachments); err != nil {
+			return nil, fmt.Errorf("decode message attachments: %w", err)
+		}
+	}
+	if len(usage) > 0 {
+		m.Usage = &ai.ChatUsage{}
+		if err := json.Unmarshal(usage, m.Usage); err != nil {
+			return nil, fmt.Errorf("decode message usage: %w", err)
+		}
+	}
+	return &m, nil
+}
+
+// encodeMessage returns m's attachments and usage as JSON column values,
+// or nil when they are unset.
+func encodeMessage(m *Message) (attachments, usage any, err error) {
+	if len(m.Attachments) > 0 {
+		data, err := json.Marshal(m.Attachments)
+		if err != nil {
+			return nil, nil, err
+		}
+		attachments = string(data)
+	}
+	if m.Usage != nil {
+		data, err := json.Marshal(m.Usage)
+		if err != nil {
+			return nil, nil, err
+		}
+		usage = string(data)
+	}
+	return attachments, usage, nil
+}
+
+func nullTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	utc := t.Time.UTC()
+	return &utc
+}
+
+func (r *sqlRepository) PendingEvents(ctx context.Context, limit int) ([]outbox.Event, error) {
+	rows, err := r.query(ctx, `SELECT `+eventColumns+` FROM outbox_events
+		WHERE published_at IS NULL ORDER BY seq LIMIT $1`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list outbox events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []outbox.Event{}
+	for rows.Next() {
+		var (
+			e         outbox.Event
+			payload   []byte
+			published sql.NullTime
+		)
+		if err := rows.Scan(&e.ID, &e.Topic, &e.Key, &payload, &e.CreatedAt,
+			&e.Attempts, &e.LastError, &published); err != nil {
+			return nil, fmt.Errorf("failed to list outbox events: %w", err)
+		}
+		e.Payload = payload
+		e.PublishedAt = nullTime(published)
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list outbox events: %w", err)
+	}
+	return events, nil
+}
+
+func (r *sqlRepository) MarkPublished(ctx context.Context, id string, at time.Time) error {
+	return r.updateEvent(ctx, `UPDATE outbox_events SET published_at = $2 WHERE id = $1`, id, at)
+}
+
+func (r *sqlRepository) MarkFailed(ctx context.Context, id, reason string) error {
+	return r.updateEvent(ctx, `UPDATE outbox_events SET attempts = attempts + 1, last_error = $2
+		WHERE id = $1`, id, reason)
+}
+
+func (r *sqlRepository) updateEvent(ctx context.Context, query, id string, arg any) error {
+	result, err := r.exec(ctx, query, id, arg)
+	if err != nil {
+		return fmt.Errorf("failed to update outbox event: %w", err)
+	}
+	return affected(result, outbox.ErrEventNotFound)
+}
+
+func (r *sqlRepository) DeletePublishedEvents(ctx context.Context, cutoff time.Time) (int, error) {
+	result, err := r.exec(ctx, `DELETE FROM outbox_events
+		WHERE published_at IS NOT NULL AND published_at < $1`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete outbox events: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	return int(n), nil
+}
@@ -0,0 +1,13 @@
+package credential
+
+import "errors"
+
+var (
+	ErrEncryptionDisabled = errors.New("provider keys cannot be stored: ENCRYPTION_KEYS is not set")
+	ErrUserRequired       = errors.New("provider keys belong to a signed-in user")
+
+	ErrCredentialNotFound  = errors.New("provider key not found")
+	ErrUnsupportedProvider = errors.New("provider must be openai")
+	ErrAPIKeyMissing       = errors.New("api_key is required")
+	ErrAPIKeyInvalid       = errors.New("api_key must be at most 512 characters without spaces")
+)
@@ -0,0 +1,32 @@
+package credential
+
+import "context"
+
+type Service interface {
+	// Set stores the current user's key for a provider, replacing any
+	// they stored before.
+	Set(ctx context.Context, req *SetRequest) (*Credential, error)
+	// List returns the current user's keys or, with all, every key of
+	// their tenant, which requires auth.PermManage. Keys are never
+	// returned, only their hint.
+	List(ctx context.Context, all bool) ([]Credential, error)
+	// Delete removes one of the current user's keys; admins may remove any
+	// key of their tenant.
+	Delete(ctx context.Context, id string) error
+
+	// APIKey returns the current user's key for provider, or "" when they
+	// have not stored one or keys are disabled.
+	APIKey(ctx context.Context, provider string) (string, error)
+}
+
+// Repository stores sealed keys. Lookups return ErrCredentialNotFound for
+// unknown keys.
+type Repository interface {
+	Create(ctx context.Context, c *Credential) error
+	Get(ctx context.Context, id string) (*Credential, error)
+	// Find returns an owner's key for a provider.
+	Find(ctx context.Context, ownerID, provider string) (*Credential, error)
+	List(ctx context.Context) ([]Credential, error)
+	Update(ctx context.Context, c *Credential) error
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,153 @@
+package credential
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+
+	"github.com/Joepolymath/DaVinci/libs/shared-go/auth"
+)
+
+type memoryRepository struct {
+	mu          sync.RWMutex
+	credentials map[string]Credential
+
+	// path, when set, is a JSON file rewritten after every change.
+	path string
+}
+
+// memoryCredential saves the sealed key, which Credential leaves out of
+// its JSON.
+type memoryCredential struct {
+	Credential
+	SealedKey string `json:"sealed_key"`
+}
+
+// NewMemoryRepository creates an in-process repository, optionally saved
+// to a JSON file at path. Keys are saved sealed, and the file is written
+// readable by the owner only.
+func NewMemoryRepository(path string) (Repository, error) {
+	r := &memoryRepository{
+		credentials: make(map[string]Credential),
+		path:        path,
+	}
+	if path == "" {
+		return r, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credentials file: %w", err)
+	}
+
+	var credentials []memoryCredential
+	if err := json.Unmarshal(data, &credentials); err != nil {
+		return nil, fmt.Errorf("failed to parse credentials file: %w", err)
+	}
+	for _, c := range credentials {
+		c.Credential.SealedKey = c.SealedKey
+		if c.TenantID == "" {
+			// Saved before every credential had a tenant.
+			c.TenantID = auth.DefaultTenant
+		}
+		r.credentials[c.ID] = c.Credential
+	}
+	return r, nil
+}
+
+func (r *memoryRepository) Create(ctx context.Context, c *Credential) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.credentials[c.ID] = *c
+	return r.save()
+}
+
+func (r *memoryRepository) Get(ctx context.Context, id string) (*Credential, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	c, ok := r.credentials[id]
+	if !ok {
+		return nil, ErrCredentialNotFound
+	}
+	return &c, nil
+}
+
+func (r *memoryRepository) Find(ctx context.Context, ownerID, provider string) (*Credential, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, c := range r.credentials {
+		if c.OwnerID == ownerID && c.Provider == provider {
+			return &c, nil
+		}
+	}
+	return nil, ErrCredentialNotFound
+}
+
+func (r *memoryRepository) List(ctx context.Context) ([]Credential, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	credentials := make([]Credential, 0, len(r.credentials))
+	for _, c := range r.credentials {
+		credentials = append(credentials, c)
+	}
+	return credentials, nil
+}
+
+func (r *memoryRepository) Update(ctx context.Context, c *Credential) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.credentials[c.ID]; !ok {
+		return ErrCredentialNotFound
+	}
+	r.credentials[c.ID] = *c
+	return r.save()
+}
+
+func (r *memoryRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.credentials[id]; !ok {
+		return ErrCredentialNotFound
+	}
+	delete(r.credentials, id)
+	return r.save()
+}
+
+// save rewrites the file through a temporary file and rename. Callers
+// hold the write lock.
+func (r *memoryRepository) save() error {
+	if r.path == "" {
+		return nil
+	}
+
+	credentials := make([]memoryCredential, 0, len(r.credentials))
+	for _, c := range r.credentials {
+		credentials = append(credentials, memoryCredential{Credential: c, SealedKey: c.SealedKey})
+	}
+	data, err := json.Marshal(credentials)
+	if err != nil {
+		return fmt.Errorf("failed to encode credentials: %w", err)
+	}
+
+	tmp := r.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to save credentials: %w", err)
+	}
+	if err := os.Rename(tmp, r.path); err != nil {
+		return fmt.Errorf("failed to save credentials: %w", err)
+	}
+	return nil
+}
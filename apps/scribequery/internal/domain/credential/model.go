@@ -0,0 +1,65 @@
+package credential
+
+import (
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/ai"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/validate"
+)
+
+const (
+	maxAPIKeyLength = 512
+	// hintLength trailing characters of a key are kept in clear so users
+	// can tell keys apart; shorter keys get no hint.
+	hintLength    = 4
+	minHintedKeys = 16
+)
+
+// Credential is a user's own API key for a provider ("bring your own
+// key"), used instead of the deployment's key for their chats. The key is
+// stored only in SealedKey, encrypted with the application keyring, and
+// never leaves the service in API responses.
+type Credential struct {
+	ID        string    `json:"id"`
+	TenantID  string    `json:"tenant_id,omitempty"`
+	OwnerID   string    `json:"owner_id"`
+	Provider  string    `json:"provider"`
+	Hint      string    `json:"hint,omitempty"` // e.g. "…a1b2"
+	SealedKey string    `json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type SetRequest struct {
+	Provider string `json:"-"`
+	APIKey   string `json:"api_key"`
+}
+
+// Validate checks the request and trims its key.
+func (r *SetRequest) Validate() error {
+	var errs validate.Errors
+	r.Provider = strings.ToLower(strings.TrimSpace(r.Provider))
+	// Only providers the chat service can hand a user's key to.
+	if !slices.Contains(ai.KeyedProviders, ai.ProviderType(r.Provider)) {
+		errs.Add("provider", ErrUnsupportedProvider)
+	}
+	r.APIKey = strings.TrimSpace(r.APIKey)
+	switch {
+	case r.APIKey == "":
+		errs.Add("api_key", ErrAPIKeyMissing)
+	case len(r.APIKey) > maxAPIKeyLength || strings.IndexFunc(r.APIKey, unicode.IsSpace) >= 0:
+		errs.Add("api_key", ErrAPIKeyInvalid)
+	}
+	return errs.Err()
+}
+
+// hint returns the last characters of key for display.
+func hint(key string) string {
+	if len(key) < minHintedKeys {
+		return ""
+	}
+	return "…" + key[len(key)-hintLength:]
+}
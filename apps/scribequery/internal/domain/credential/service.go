@@ -0,0 +1,176 @@
+package credential
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/Joepolymath/DaVinci/libs/shared-go/auth"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/secrets"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type Config struct {
+	// Keyring encrypts stored keys. Without it keys cannot be stored and
+	// every chat uses the deployment's keys.
+	Keyring *secrets.Keyring
+}
+
+type service struct {
+	repo   Repository
+	config Config
+	logger *zap.Logger
+}
+
+func NewService(repo Repository, cfg Config, logger *zap.Logger) Service {
+	return &service{repo: repo, config: cfg, logger: logger}
+}
+
+func (s *service) enabled() bool {
+	return s.repo != nil && s.config.Keyring != nil
+}
+
+func (s *service) Set(ctx context.Context, req *SetRequest) (*Credential, error) {
+	if !s.enabled() {
+		return nil, ErrEncryptionDisabled
+	}
+	user, ok := auth.CurrentUser(ctx)
+	if !ok {
+		return nil, ErrUserRequired
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	existing, err := s.repo.Find(ctx, user.ID, req.Provider)
+	if err != nil && !errors.Is(err, ErrCredentialNotFound) {
+		return nil, err
+	}
+	c := existing
+	if c == nil {
+		c = &Credential{
+			ID:        uuid.NewString(),
+			TenantID:  auth.Tenant(ctx),
+			OwnerID:   user.ID,
+			Provider:  req.Provider,
+			CreatedAt: now,
+		}
+	}
+	c.Hint = hint(req.APIKey)
+	c.UpdatedAt = now
+	if c.SealedKey, err = s.config.Keyring.Seal([]byte(req.APIKey), []byte(c.ID)); err != nil {
+		return nil, err
+	}
+
+	if existing != nil {
+		err = s.repo.Update(ctx, c)
+	} else {
+		err = s.repo.Create(ctx, c)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Provider key stored", zap.String("credential_id", c.ID), zap.String("provider", c.Provider), zap.String("owner_id", c.OwnerID))
+	return c, nil
+}
+
+func (s *service) List(ctx context.Context, all bool) ([]Credential, error) {
+	if !s.enabled() {
+		return nil, ErrEncryptionDisabled
+	}
+	user, ok := auth.CurrentUser(ctx)
+	if !ok {
+		return nil, ErrUserRequired
+	}
+	if all && !auth.Can(ctx, auth.PermManage) {
+		return nil, auth.ErrForbidden
+	}
+
+	stored, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tenant := auth.Tenant(ctx)
+	credentials := []Credential{}
+	for _, c := range stored {
+		if all && c.TenantID == tenant || c.OwnerID == user.ID {
+			c.SealedKey = ""
+			credentials = append(credentials, c)
+		}
+	}
+	slices.SortFunc(credentials, func(a, b Credential) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return credentials, nil
+}
+
+func (s *service) Delete(ctx context.Context, id string) error {
+	if !s.enabled() {
+		return ErrEncryptionDisabled
+	}
+	user, ok := auth.CurrentUser(ctx)
+	if !ok {
+		return ErrUserRequired
+	}
+
+	c, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	tenant := auth.Tenant(ctx)
+	admin := auth.Can(ctx, auth.PermManage) && c.TenantID == tenant
+	if c.OwnerID != user.ID && !admin {
+		return ErrCredentialNotFound
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.logger.Info("Provider key deleted", zap.String("credential_id", c.ID), zap.String("provider", c.Provider),
+		zap.String("owner_id", c.OwnerID), zap.String("deleted_by", user.ID))
+	return nil
+}
+
+func (s *service) APIKey(ctx context.Context, provider string) (string, error) {
+	if !s.enabled() {
+		return "", nil
+	}
+	user, ok := auth.CurrentUser(ctx)
+	if !ok {
+		return "", nil
+	}
+
+	c, err := s.repo.Find(ctx, user.ID, provider)
+	if errors.Is(err, ErrCredentialNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	key, err := s.config.Keyring.Open(c.SealedKey, []byte(c.ID))
+	if err != nil {
+		return "", err
+	}
+
+	// Keys sealed before the keyring was rotated are sealed again with the
+	// current key, so the old one can eventually be retired.
+	if !s.config.Keyring.Current(c.SealedKey) {
+		s.reseal(context.WithoutCancel(ctx), c, key)
+	}
+	return string(key), nil
+}
+
+func (s *service) reseal(ctx context.Context, c *Credential, key []byte) {
+	sealed, err := s.config.Keyring.Seal(key, []byte(c.ID))
+	if err == nil {
+		c.SealedKey = sealed
+		err = s.repo.Update(ctx, c)
+	}
+	if err != nil {
+		s.logger.Warn("Failed to re-encrypt provider key", zap.String("credential_id", c.ID), zap.Error(err))
+	}
+}
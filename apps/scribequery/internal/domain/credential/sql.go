@@ -0,0 +1,129 @@
+package credential
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const credentialColumns = `id, tenant_id, owner_id, provider, hint, sealed_key, created_at, updated_at`
+
+type sqlRepository struct {
+	db *sql.DB
+	// placeholder prefixes numbered parameters: "$" for Postgres ($1),
+	// "?" for SQLite (?1).
+	placeholder string
+}
+
+// NewPostgresRepository creates a repository on the provider_credentials
+// table of a Postgres database (see the scribequery migrations).
+func NewPostgresRepository(db *sql.DB) Repository {
+	return &sqlRepository{db: db, placeholder: "$"}
+}
+
+// NewSQLiteRepository creates a repository on the provider_credentials
+// table of a SQLite database.
+func NewSQLiteRepository(db *sql.DB) Repository {
+	return &sqlRepository{db: db, placeholder: "?"}
+}
+
+func (r *sqlRepository) rebind(query string) string {
+	if r.placeholder == "$" {
+		return query
+	}
+	return strings.ReplaceAll(query, "$", r.placeholder)
+}
+
+func (r *sqlRepository) Create(ctx context.Context, c *Credential) error {
+	if _, err := r.db.ExecContext(ctx, r.rebind(`INSERT INTO provider_credentials (`+credentialColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`),
+		c.ID, c.TenantID, c.OwnerID, c.Provider, c.Hint, c.SealedKey, c.CreatedAt, c.UpdatedAt,
+	); err != nil {
+		return fmt.Errorf("failed to create credential: %w", err)
+	}
+	return nil
+}
+
+func (r *sqlRepository) Get(ctx context.Context, id string) (*Credential, error) {
+	return r.get(ctx, `id = $1`, id)
+}
+
+func (r *sqlRepository) Find(ctx context.Context, ownerID, provider string) (*Credential, error) {
+	return r.get(ctx, `owner_id = $1 AND provider = $2`, ownerID, provider)
+}
+
+func (r *sqlRepository) get(ctx context.Context, where string, args ...any) (*Credential, error) {
+	c, err := scanCredential(r.db.QueryRowContext(ctx, r.rebind(`SELECT `+credentialColumns+` FROM provider_credentials WHERE `+where), args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrCredentialNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credential: %w", err)
+	}
+	return c, nil
+}
+
+func (r *sqlRepository) List(ctx context.Context) ([]Credential, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+credentialColumns+` FROM provider_credentials ORDER BY created_at, id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list credentials: %w", err)
+	}
+	defer rows.Close()
+
+	credentials := []Credential{}
+	for rows.Next() {
+		c, err := scanCredential(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list credentials: %w", err)
+		}
+		credentials = append(credentials, *c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list credentials: %w", err)
+	}
+	return credentials, nil
+}
+
+func (r *sqlRepository) Update(ctx context.Context, c *Credential) error {
+	result, err := r.db.ExecContext(ctx, r.rebind(
+		`UPDATE provider_credentials SET hint = $2, sealed_key = $3, updated_at = $4 WHERE id = $1`),
+		c.ID, c.Hint, c.SealedKey, c.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update credential: %w", err)
+	}
+	return affected(result, "update")
+}
+
+func (r *sqlRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, r.rebind(`DELETE FROM provider_credentials WHERE id = $1`), id)
+	if err != nil {
+		return fmt.Errorf("failed to delete credential: %w", err)
+	}
+	return affected(result, "delete")
+}
+
+// affected returns ErrCredentialNotFound when result changed no row.
+func affected(result sql.Result, action string) error {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to %s credential: %w", action, err)
+	}
+	if n == 0 {
+		return ErrCredentialNotFound
+	}
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanCredential(row scanner) (*Credential, error) {
+	var c Credential
+	if err := row.Scan(&c.ID, &c.TenantID, &c.OwnerID, &c.Provider, &c.Hint, &c.SealedKey, &c.CreatedAt, &c.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
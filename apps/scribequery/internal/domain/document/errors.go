@@ -0,0 +1,10 @@
+package document
+
+import "errors"
+
+var (
+	ErrEmptyDocument      = errors.New("document has no text")
+	ErrIngestionDisabled  = errors.New("document ingestion requires an embeddings provider and a vector store")
+	ErrInvalidChunkConfig = errors.New("invalid chunking configuration")
+	ErrInvalidCrawlDepth  = errors.New("max_depth must be between 0 and 3")
+)
@@ -0,0 +1,23 @@
+package document
+
+import (
+	"context"
+
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/db/vector"
+)
+
+type Service interface {
+	// Ingest chunks a document, embeds the chunks and stores them in the vector store.
+	Ingest(ctx context.Context, req *IngestRequest) (*IngestResult, error)
+
+	// IngestURL fetches a web page, and optionally same-site pages linked from
+	// it, and ingests each as a separate document.
+	IngestURL(ctx context.Context, req *IngestURLRequest) (*IngestURLResult, error)
+}
+
+// Repository stores embedded chunks, keyed by point ID, with the Field*
+// payload keys retrieval reads back.
+type Repository interface {
+	// SaveChunks stores chunks, replacing any with the same IDs.
+	SaveChunks(ctx context.Context, chunks []vector.Point) error
+}
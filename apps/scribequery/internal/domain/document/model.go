@@ -0,0 +1,109 @@
+package document
+
+import (
+	"github.com/Joepolymath/DaVinci/libs/shared-go/text/extract"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/validate"
+)
+
+type IngestRequest struct {
+	Title    string         `json:"title,omitempty"`
+	Source   string         `json:"source,omitempty"` // e.g. the file name or URL
+	Text     string         `json:"text"`
+	Pages    []Page         `json:"pages,omitempty"`    // when set, chunked per page instead of Text
+	Sections []Section      `json:"sections,omitempty"` // when set (and Pages is not), chunked per section
+	Metadata map[string]any `json:"metadata,omitempty"` // stored with every chunk
+
+	// Strategy selects the chunker: "fixed", "sentence", "markdown" or
+	// "recursive" (the default). ChunkSize and ChunkOverlap are in
+	// characters; zero uses the defaults.
+	Strategy     string `json:"strategy,omitempty"`
+	ChunkSize    int    `json:"chunk_size,omitempty"`
+	ChunkOverlap int    `json:"chunk_overlap,omitempty"`
+}
+
+// Page is one page of a paginated source such as a PDF. Chunks never span
+// pages and record the page number.
+type Page struct {
+	Number int    `json:"number"`
+	Text   string `json:"text"`
+}
+
+// Section is the text under a heading path such as "Install > Linux".
+// Chunks never span sections and record the heading.
+type Section struct {
+	Heading string `json:"heading,omitempty"`
+	Text    string `json:"text"`
+}
+
+type IngestResult struct {
+	DocumentID string `json:"document_id"`
+	Source     string `json:"source,omitempty"`
+	Chunks     int    `json:"chunks"`
+}
+
+type IngestURLRequest struct {
+	URL string `json:"url"`
+
+	// MaxDepth is how many links away from URL to crawl within the same
+	// host; 0 ingests only URL. MaxPages caps the number of pages fetched.
+	MaxDepth int `json:"max_depth,omitempty"`
+	MaxPages int `json:"max_pages,omitempty"`
+
+	Metadata     map[string]any `json:"metadata,omitempty"`
+	Strategy     string         `json:"strategy,omitempty"`
+	ChunkSize    int            `json:"chunk_size,omitempty"`
+	ChunkOverlap int            `json:"chunk_overlap,omitempty"`
+}
+
+func (r *IngestURLRequest) Validate() error {
+	var errs validate.Errors
+	if r.MaxDepth < 0 || r.MaxDepth > maxCrawlDepth {
+		errs.Add("max_depth", ErrInvalidCrawlDepth)
+	}
+	return errs.Err()
+}
+
+type IngestURLResult struct {
+	Documents []IngestResult `json:"documents"`
+	Skipped   []string       `json:"skipped,omitempty"` // pages fetched but not ingested
+}
+
+// Chunk payload fields stored in the vector store.
+const (
+	FieldDocumentID = "document_id"
+	FieldContent    = "content"
+	FieldTitle      = "title"
+	FieldSource     = "source"
+	FieldChunkIndex = "chunk_index"
+	FieldHeading    = "heading"
+	FieldPage       = "page"
+	// FieldOwnerID is the ID of the user who ingested the document, when
+	// the request was made for one; other users' searches, admins' aside,
+	// do not see its chunks.
+	FieldOwnerID = "owner_id"
+	// FieldTenantID is the tenant the document was ingested for; searches
+	// only see their own tenant's chunks.
+	FieldTenantID = "tenant_id"
+
+	// FieldStartOffset and FieldEndOffset are the chunk's character (rune)
+	// offsets in the document text, end exclusive.
+	FieldStartOffset = "start_offset"
+	FieldEndOffset   = "end_offset"
+)
+
+// NewIngestRequest builds an ingestion request from extracted text, keeping
+// its pages, sections, title and metadata.
+func NewIngestRequest(doc *extract.Document) *IngestRequest {
+	req := &IngestRequest{
+		Title:    doc.Title,
+		Text:     doc.Text,
+		Metadata: doc.Metadata,
+	}
+	for _, page := range doc.Pages {
+		req.Pages = append(req.Pages, Page{Number: page.Number, Text: page.Text})
+	}
+	for _, section := range doc.Sections {
+		req.Sections = append(req.Sections, Section{Heading: section.Heading, Text: section.Text})
+	}
+	return req
+}
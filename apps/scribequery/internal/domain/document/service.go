@@ -0,0 +1,260 @@
+package document
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Joepolymath/DaVinci/libs/shared-go/auth"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/ai"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/db/vector"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/web"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/text/chunker"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/text/extract"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/validate"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// maxCrawlDepth bounds IngestURLRequest.MaxDepth.
+const maxCrawlDepth = 3
+
+type service struct {
+	embeddings ai.EmbeddingsProvider
+	repo       Repository
+	crawler    *web.Crawler
+	extractors *extract.Registry
+	logger     *zap.Logger
+}
+
+// NewService creates the document service. Ingestion is disabled while
+// embeddings or repo is nil, and URL ingestion while crawler is.
+func NewService(embeddings ai.EmbeddingsProvider, repo Repository, crawler *web.Crawler, logger *zap.Logger) Service {
+	return &service{
+		embeddings: embeddings,
+		repo:       repo,
+		crawler:    crawler,
+		extractors: extract.NewRegistry(),
+		logger:     logger,
+	}
+}
+
+func (s *service) Ingest(ctx context.Context, req *IngestRequest) (*IngestResult, error) {
+	if s.embeddings == nil || s.repo == nil {
+		return nil, ErrIngestionDisabled
+	}
+
+	splitter, err := newSplitter(req.Strategy, req.ChunkSize, req.ChunkOverlap)
+	if err != nil {
+		return nil, err
+	}
+
+	chunks := splitDocument(splitter, req)
+	if len(chunks) == 0 {
+		var errs validate.Errors
+		errs.Add("text", ErrEmptyDocument)
+		return nil, errs
+	}
+	locateChunks(documentText(req), chunks)
+
+	texts := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		texts[i] = chunk.Text
+	}
+
+	vectors, err := s.embeddings.Embed(ctx, texts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed chunks: %w", err)
+	}
+
+	documentID := uuid.NewString()
+	owner, hasOwner := auth.CurrentUser(ctx)
+	tenant := auth.Tenant(ctx)
+	points := make([]vector.Point, len(chunks))
+	for i, chunk := range chunks {
+		payload := make(vector.Payload, len(req.Metadata)+10)
+		for k, v := range req.Metadata {
+			payload[k] = v
+		}
+		payload[FieldDocumentID] = documentID
+		payload[FieldContent] = chunk.Text
+		payload[FieldTitle] = req.Title
+		payload[FieldSource] = req.Source
+		payload[FieldChunkIndex] = chunk.Index
+		if chunk.Heading != "" {
+			payload[FieldHeading] = chunk.Heading
+		}
+		if chunk.page > 0 {
+			payload[FieldPage] = chunk.page
+		}
+		if hasOwner {
+			payload[FieldOwnerID] = owner.ID
+		}
+		// Metadata cannot place a document in another tenant.
+		payload[FieldTenantID] = tenant
+		if chunk.end > 0 {
+			payload[FieldStartOffset] = chunk.start
+			payload[FieldEndOffset] = chunk.end
+		}
+
+		points[i] = vector.Point{
+			ID:      chunkID(documentID, i),
+			Vector:  vectors[i],
+			Payload: payload,
+		}
+	}
+
+	if err := s.repo.SaveChunks(ctx, points); err != nil {
+		return nil, fmt.Errorf("failed to store chunks: %w", err)
+	}
+
+	s.logger.Info("Document ingested",
+		zap.String("document_id", documentID),
+		zap.String("source", req.Source),
+		zap.Int("chunks", len(chunks)))
+
+	return &IngestResult{DocumentID: documentID, Source: req.Source, Chunks: len(chunks)}, nil
+}
+
+func (s *service) IngestURL(ctx context.Context, req *IngestURLRequest) (*IngestURLResult, error) {
+	if s.embeddings == nil || s.repo == nil || s.crawler == nil {
+		return nil, ErrIngestionDisabled
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	result := &IngestURLResult{Documents: []IngestResult{}}
+	crawl := web.CrawlRequest{URL: req.URL, MaxDepth: req.MaxDepth, MaxPages: req.MaxPages}
+
+	err := s.crawler.Crawl(ctx, crawl, func(page *web.Page) error {
+		doc, err := s.extractors.Extract(page.MediaType, page.Body)
+		if err != nil {
+			s.logger.Warn("Skipping page", zap.String("url", page.URL), zap.Error(err))
+			result.Skipped = append(result.Skipped, page.URL)
+			return nil
+		}
+
+		ingest := NewIngestRequest(doc)
+		ingest.Source = page.URL
+		ingest.Strategy = req.Strategy
+		ingest.ChunkSize = req.ChunkSize
+		ingest.ChunkOverlap = req.ChunkOverlap
+		for k, v := range req.Metadata {
+			if ingest.Metadata == nil {
+				ingest.Metadata = make(map[string]any, len(req.Metadata))
+			}
+			ingest.Metadata[k] = v
+		}
+
+		ingested, err := s.Ingest(ctx, ingest)
+		if errors.Is(err, ErrEmptyDocument) {
+			result.Skipped = append(result.Skipped, page.URL)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to ingest %s: %w", page.URL, err)
+		}
+		result.Documents = append(result.Documents, *ingested)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+type pageChunk struct {
+	chunker.Chunk
+	page       int
+	start, end int // rune offsets in the document text; end is 0 if unknown
+}
+
+// newSplitter returns the chunker for a request's chunking fields,
+// reporting an invalid configuration against the field at fault.
+func newSplitter(strategy string, size, overlap int) (chunker.Chunker, error) {
+	splitter, err := chunker.New(strategy, chunker.Config{Size: size, Overlap: overlap})
+	if err != nil {
+		field := "chunk_overlap"
+		if errors.Is(err, chunker.ErrUnsupportedStrategy) {
+			field = "strategy"
+		}
+		var errs validate.Errors
+		errs.Add(field, fmt.Errorf("%w: %w", ErrInvalidChunkConfig, err))
+		return nil, errs
+	}
+	return splitter, nil
+}
+
+// splitDocument chunks req.Pages or req.Sections one at a time, or req.Text when
+// there are neither, numbering chunks across the whole document.
+func splitDocument(splitter chunker.Chunker, req *IngestRequest) []pageChunk {
+	pages := req.Pages
+	if len(pages) == 0 && len(req.Sections) == 0 {
+		pages = []Page{{Text: req.Text}}
+	}
+
+	var chunks []pageChunk
+	add := func(text, heading string, page int) {
+		for _, chunk := range splitter.Split(text) {
+			chunk.Index = len(chunks)
+			if chunk.Heading == "" {
+				chunk.Heading = heading
+			}
+			chunks = append(chunks, pageChunk{Chunk: chunk, page: page})
+		}
+	}
+
+	for _, page := range pages {
+		add(page.Text, "", page.Number)
+	}
+	if len(req.Pages) == 0 {
+		for _, section := range req.Sections {
+			add(section.Text, section.Heading, 0)
+		}
+	}
+	return chunks
+}
+
+// documentText returns the text offsets are measured against: req.Text, or
+// the pages or sections joined by blank lines as the extractors do.
+func documentText(req *IngestRequest) string {
+	if req.Text != "" {
+		return req.Text
+	}
+	var parts []string
+	for _, p := range req.Pages {
+		parts = append(parts, p.Text)
+	}
+	for _, s := range req.Sections {
+		parts = append(parts, s.Text)
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// locateChunks finds each chunk in text, searching forward from the start of
+// the previous chunk since chunks overlap. Chunks that cannot be found (for
+// example because a page was cleaned up) keep unknown offsets.
+func locateChunks(text string, chunks []pageChunk) {
+	byteFrom, runeFrom := 0, 0
+	for i := range chunks {
+		idx := strings.Index(text[byteFrom:], chunks[i].Text)
+		if idx < 0 {
+			continue
+		}
+		start := runeFrom + utf8.RuneCountInString(text[byteFrom:byteFrom+idx])
+		chunks[i].start = start
+		chunks[i].end = start + utf8.RuneCountInString(chunks[i].Text)
+
+		byteFrom += idx
+		runeFrom = start
+	}
+}
+
+// chunkID derives a stable UUID for a chunk, as required by Weaviate and Qdrant.
+func chunkID(documentID string, index int) string {
+	return uuid.NewSHA1(uuid.NameSpaceURL, fmt.Appendf(nil, "%s#%d", documentID, index)).String()
+}
@@ -0,0 +1,73 @@
+package document
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/db/vector"
+)
+
+type vectorRepository struct {
+	store      vector.Store
+	collection string
+
+	mu              sync.Mutex
+	collectionReady bool
+}
+
+// NewVectorRepository stores chunks in a collection of store, creating it
+// on first use. Pass memory.NewStore() for an in-process repository.
+//
+// All tenants share the collection, whose schema is declared once and
+// migrated at startup, rather than one collection per tenant created as
+// keys name new tenants under each backend's own naming rules. Every
+// chunk carries FieldTenantID instead, and every search filters on it in
+// the store's query.
+func NewVectorRepository(store vector.Store, collection string) Repository {
+	return &vectorRepository{store: store, collection: collection}
+}
+
+func (r *vectorRepository) SaveChunks(ctx context.Context, chunks []vector.Point) error {
+	if len(chunks) == 0 {
+		return nil
+	}
+	if err := r.ensureCollection(ctx, len(chunks[0].Vector)); err != nil {
+		return err
+	}
+	return r.store.Upsert(ctx, r.collection, chunks)
+}
+
+// ensureCollection creates the chunk collection on first use if the store
+// does not have it yet.
+func (r *vectorRepository) ensureCollection(ctx context.Context, dimension int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.collectionReady {
+		return nil
+	}
+
+	collections, err := r.store.Collections(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list collections: %w", err)
+	}
+	for _, c := range collections {
+		if strings.EqualFold(c.Name, r.collection) {
+			r.collectionReady = true
+			return nil
+		}
+	}
+
+	err = r.store.CreateCollection(ctx, vector.Collection{
+		Name:      r.collection,
+		Dimension: dimension,
+		Distance:  vector.DistanceCosine,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create collection: %w", err)
+	}
+	r.collectionReady = true
+	return nil
+}
@@ -0,0 +1,57 @@
+package eval
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// LoadDataset reads cases from a JSON array or from JSON Lines (one case
+// per line; blank lines are skipped). Cases without an ID are numbered
+// in file order.
+func LoadDataset(r io.Reader) ([]Case, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dataset: %w", err)
+	}
+
+	var cases []Case
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &cases); err != nil {
+			return nil, fmt.Errorf("failed to parse dataset: %w", err)
+		}
+	} else {
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(make([]byte, 64*1024), len(data)+1)
+		for line := 1; scanner.Scan(); line++ {
+			text := strings.TrimSpace(scanner.Text())
+			if text == "" {
+				continue
+			}
+			var c Case
+			if err := json.Unmarshal([]byte(text), &c); err != nil {
+				return nil, fmt.Errorf("failed to parse dataset line %d: %w", line, err)
+			}
+			cases = append(cases, c)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read dataset: %w", err)
+		}
+	}
+
+	if len(cases) == 0 {
+		return nil, ErrEmptyDataset
+	}
+	for i := range cases {
+		if strings.TrimSpace(cases[i].Question) == "" {
+			return nil, fmt.Errorf("case %d: %w", i+1, ErrInvalidCase)
+		}
+		if cases[i].ID == "" {
+			cases[i].ID = fmt.Sprintf("case-%d", i+1)
+		}
+	}
+	return cases, nil
+}
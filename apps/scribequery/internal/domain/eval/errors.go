@@ -0,0 +1,10 @@
+package eval
+
+import "errors"
+
+var (
+	ErrEmptyDataset      = errors.New("dataset has no cases")
+	ErrInvalidCase       = errors.New("case question is required")
+	ErrJudgeRequired     = errors.New("faithfulness scoring requires a chat provider")
+	ErrUnsupportedFormat = errors.New("unsupported report format")
+)
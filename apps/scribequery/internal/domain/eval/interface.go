@@ -0,0 +1,10 @@
+package eval
+
+import "context"
+
+type Service interface {
+	// Run sends every case through retrieval and chat and scores the
+	// results. Per-case failures are recorded in the report rather than
+	// aborting the run.
+	Run(ctx context.Context, cases []Case, opts RunOptions) (*Report, error)
+}
@@ -0,0 +1,80 @@
+package eval
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/retrieval"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/ai"
+)
+
+const judgePrompt = `You grade answers from a document question-answering system for faithfulness.
+Given a question, the retrieved context and an answer, rate from 0 to 1 how fully the answer's claims are supported by the context:
+1 means every claim is supported, 0 means the answer is unsupported or contradicts the context.
+Judge support only, not whether the answer is complete or correct in general. An answer that says the context does not contain the information is faithful when that is true.
+Respond with JSON only: {"score": 0.0, "reason": "..."}`
+
+var judgeFormat = &ai.ResponseFormat{
+	Type: ai.ResponseFormatJSONSchema,
+	Name: "faithfulness",
+	Schema: json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"score": {"type": "number"},
+			"reason": {"type": "string"}
+		},
+		"required": ["score", "reason"],
+		"additionalProperties": false
+	}`),
+	Strict: true,
+}
+
+type judgement struct {
+	Score  float64 `json:"score"`
+	Reason string  `json:"reason"`
+}
+
+// judgeFaithfulness asks the judge how well answer is supported by chunks.
+// An empty answer scores 0 without a judge call.
+func judgeFaithfulness(ctx context.Context, judge ai.ChatProvider, question, answer string, chunks []retrieval.Chunk) (float64, string, error) {
+	if strings.TrimSpace(answer) == "" {
+		return 0, "empty answer", nil
+	}
+
+	var contextText strings.Builder
+	for i, c := range chunks {
+		fmt.Fprintf(&contextText, "[%d] %s\n\n", i+1, c.Content)
+	}
+	if contextText.Len() == 0 {
+		contextText.WriteString("(no context was retrieved)\n")
+	}
+
+	resp, err := judge.Completion(ctx, []ai.Message{
+		{Role: ai.RoleSystem, Content: judgePrompt},
+		{Role: ai.RoleUser, Content: fmt.Sprintf("Question: %s\n\nContext:\n%s\nAnswer: %s", question, contextText.String(), answer)},
+	}, &ai.ChatOptions{
+		Temperature:    ai.Float64(0),
+		ResponseFormat: judgeFormat,
+	})
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to judge faithfulness: %w", err)
+	}
+
+	var result judgement
+	if err := json.Unmarshal([]byte(extractJSON(resp.Content)), &result); err != nil {
+		return 0, "", fmt.Errorf("failed to parse faithfulness judgement: %w", err)
+	}
+	return min(max(result.Score, 0), 1), result.Reason, nil
+}
+
+// extractJSON strips a Markdown code fence, which some local models add
+// even when asked for bare JSON.
+func extractJSON(s string) string {
+	s = strings.TrimSpace(s)
+	if start, end := strings.Index(s, "{"), strings.LastIndex(s, "}"); start >= 0 && end > start {
+		return s[start : end+1]
+	}
+	return s
+}
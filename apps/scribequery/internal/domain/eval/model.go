@@ -0,0 +1,62 @@
+package eval
+
+import "time"
+
+// Case is one evaluation question. ExpectedSources are document IDs, source
+// names or URLs that should be retrieved for the question.
+type Case struct {
+	ID              string   `json:"id"`
+	Question        string   `json:"question"`
+	ExpectedAnswer  string   `json:"expected_answer"`
+	ExpectedSources []string `json:"expected_sources,omitempty"`
+}
+
+type CaseResult struct {
+	ID       string `json:"id"`
+	Question string `json:"question"`
+	Answer   string `json:"answer"`
+
+	RetrievedSources []string `json:"retrieved_sources"`
+
+	// RecallAtK is the fraction of expected sources among the top K
+	// retrieved chunks; nil when the case lists no expected sources.
+	RecallAtK *float64 `json:"recall_at_k,omitempty"`
+
+	// Faithfulness is the judge's 0–1 rating of how well the answer is
+	// supported by the retrieved context.
+	Faithfulness       float64 `json:"faithfulness"`
+	FaithfulnessReason string  `json:"faithfulness_reason,omitempty"`
+
+	// AnswerSimilarity compares the answer with the expected answer:
+	// embedding cosine similarity, or token F1 without an embeddings provider.
+	// Nil when the case has no expected answer.
+	AnswerSimilarity *float64 `json:"answer_similarity,omitempty"`
+
+	LatencyMS int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+type Summary struct {
+	Cases            int     `json:"cases"`
+	Failed           int     `json:"failed"`
+	MeanRecallAtK    float64 `json:"mean_recall_at_k"`
+	MeanFaithfulness float64 `json:"mean_faithfulness"`
+	MeanSimilarity   float64 `json:"mean_answer_similarity"`
+}
+
+// RunOptions control an evaluation run. K defaults to 5.
+type RunOptions struct {
+	K int
+
+	// Dataset names the dataset in the report.
+	Dataset string
+}
+
+type Report struct {
+	Dataset    string       `json:"dataset,omitempty"`
+	K          int          `json:"k"`
+	StartedAt  time.Time    `json:"started_at"`
+	DurationMS int64        `json:"duration_ms"`
+	Summary    Summary      `json:"summary"`
+	Results    []CaseResult `json:"results"`
+}
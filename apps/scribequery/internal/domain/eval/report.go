@@ -0,0 +1,69 @@
+package eval
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Report formats.
+const (
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+)
+
+// WriteReport writes report in format: indented JSON, or CSV with one row
+// per case.
+func WriteReport(w io.Writer, report *Report, format string) error {
+	switch format {
+	case FormatJSON, "":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return fmt.Errorf("failed to write report: %w", err)
+		}
+		return nil
+	case FormatCSV:
+		return writeCSV(w, report)
+	default:
+		return fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+	}
+}
+
+func writeCSV(w io.Writer, report *Report) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{
+		"id", "question", "answer", "retrieved_sources",
+		"recall_at_" + strconv.Itoa(report.K), "faithfulness", "faithfulness_reason",
+		"answer_similarity", "latency_ms", "error",
+	})
+	for _, r := range report.Results {
+		cw.Write([]string{
+			r.ID,
+			r.Question,
+			r.Answer,
+			strings.Join(r.RetrievedSources, ";"),
+			formatScore(r.RecallAtK),
+			formatScore(&r.Faithfulness),
+			r.FaithfulnessReason,
+			formatScore(r.AnswerSimilarity),
+			strconv.FormatInt(r.LatencyMS, 10),
+			r.Error,
+		})
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
+func formatScore(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', 4, 64)
+}
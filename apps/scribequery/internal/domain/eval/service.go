@@ -0,0 +1,222 @@
+package eval
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/chat"
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/retrieval"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/ai"
+	"go.uber.org/zap"
+)
+
+const defaultK = 5
+
+type service struct {
+	retriever  retrieval.Service
+	chat       chat.Service
+	judge      ai.ChatProvider
+	embeddings ai.EmbeddingsProvider
+	logger     *zap.Logger
+}
+
+// NewService creates the evaluation service. judge grades faithfulness;
+// embeddings, when enabled, score answer similarity, which otherwise falls
+// back to token overlap.
+func NewService(retriever retrieval.Service, chatService chat.Service, judge ai.ChatProvider, embeddings ai.EmbeddingsProvider, logger *zap.Logger) Service {
+	return &service{
+		retriever:  retriever,
+		chat:       chatService,
+		judge:      judge,
+		embeddings: embeddings,
+		logger:     logger,
+	}
+}
+
+func (s *service) Run(ctx context.Context, cases []Case, opts RunOptions) (*Report, error) {
+	if len(cases) == 0 {
+		return nil, ErrEmptyDataset
+	}
+	if s.judge == nil {
+		return nil, ErrJudgeRequired
+	}
+	if opts.K <= 0 {
+		opts.K = defaultK
+	}
+
+	report := &Report{
+		Dataset:   opts.Dataset,
+		K:         opts.K,
+		StartedAt: time.Now().UTC(),
+		Results:   make([]CaseResult, 0, len(cases)),
+	}
+
+	for _, c := range cases {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		result := s.runCase(ctx, c, opts.K)
+		if result.Error != "" {
+			s.logger.Warn("Eval case failed", zap.String("case", c.ID), zap.String("error", result.Error))
+		}
+		report.Results = append(report.Results, result)
+	}
+
+	report.DurationMS = time.Since(report.StartedAt).Milliseconds()
+	report.Summary = summarize(report.Results)
+
+	s.logger.Info("Eval run complete",
+		zap.Int("cases", report.Summary.Cases),
+		zap.Int("failed", report.Summary.Failed),
+		zap.Float64("recall_at_k", report.Summary.MeanRecallAtK),
+		zap.Float64("faithfulness", report.Summary.MeanFaithfulness),
+		zap.Float64("answer_similarity", report.Summary.MeanSimilarity),
+	)
+	return report, nil
+}
+
+func (s *service) runCase(ctx context.Context, c Case, k int) CaseResult {
+	result := CaseResult{ID: c.ID, Question: c.Question, RetrievedSources: []string{}}
+	start := time.Now()
+	defer func() { result.LatencyMS = time.Since(start).Milliseconds() }()
+
+	var chunks []retrieval.Chunk
+	if s.retriever != nil {
+		retrieved, err := s.retriever.Retrieve(ctx, &retrieval.RetrieveRequest{Query: c.Question, TopK: k})
+		if err != nil && !errors.Is(err, retrieval.ErrRetrievalDisabled) {
+			result.Error = fmt.Sprintf("retrieve: %v", err)
+			return result
+		}
+		if retrieved != nil {
+			chunks = retrieved.Chunks
+		}
+	}
+	result.RetrievedSources = sourcesOf(chunks)
+	if len(c.ExpectedSources) > 0 {
+		recall := recallAtK(c.ExpectedSources, chunks)
+		result.RecallAtK = &recall
+	}
+
+	resp, err := s.chat.Chat(ctx, &chat.ChatRequest{
+		Messages: []ai.Message{{Role: ai.RoleUser, Content: c.Question}},
+	})
+	if err != nil {
+		result.Error = fmt.Sprintf("chat: %v", err)
+		return result
+	}
+	result.Answer = resp.Content
+
+	result.Faithfulness, result.FaithfulnessReason, err = judgeFaithfulness(ctx, s.judge, c.Question, result.Answer, chunks)
+	if err != nil {
+		result.Error = fmt.Sprintf("judge: %v", err)
+		return result
+	}
+
+	if c.ExpectedAnswer != "" {
+		similarity := s.similarity(ctx, result.Answer, c.ExpectedAnswer)
+		result.AnswerSimilarity = &similarity
+	}
+	return result
+}
+
+// similarity is the cosine similarity of the two texts' embeddings, or
+// their token F1 when embeddings are unavailable or fail.
+func (s *service) similarity(ctx context.Context, answer, expected string) float64 {
+	if s.embeddings != nil && s.embeddings.IsEnabled() {
+		vectors, err := s.embeddings.Embed(ctx, []string{answer, expected})
+		if err == nil && len(vectors) == 2 {
+			return cosine(vectors[0], vectors[1])
+		}
+		s.logger.Warn("Falling back to token F1 for answer similarity", zap.Error(err))
+	}
+	return tokenF1(answer, expected)
+}
+
+// sourcesOf lists the distinct sources of chunks in rank order, by source
+// name or URL where known and document ID otherwise.
+func sourcesOf(chunks []retrieval.Chunk) []string {
+	sources := []string{}
+	seen := make(map[string]bool)
+	for _, c := range chunks {
+		source := c.Source
+		if source == "" {
+			source = c.DocumentID
+		}
+		if source == "" || seen[source] {
+			continue
+		}
+		seen[source] = true
+		sources = append(sources, source)
+	}
+	return sources
+}
+
+// recallAtK is the fraction of expected sources matched by any retrieved
+// chunk's document ID, source or title (case-insensitive).
+func recallAtK(expected []string, chunks []retrieval.Chunk) float64 {
+	retrieved := make(map[string]bool)
+	for _, c := range chunks {
+		for _, v := range []string{c.DocumentID, c.Source, c.Title} {
+			if v = normalizeSource(v); v != "" {
+				retrieved[v] = true
+			}
+		}
+	}
+
+	want := make(map[string]bool)
+	for _, e := range expected {
+		if e = normalizeSource(e); e != "" {
+			want[e] = true
+		}
+	}
+	if len(want) == 0 {
+		return 0
+	}
+
+	hits := 0
+	for e := range want {
+		if retrieved[e] {
+			hits++
+		}
+	}
+	return float64(hits) / float64(len(want))
+}
+
+func normalizeSource(s string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(s)), "/")
+}
+
+func summarize(results []CaseResult) Summary {
+	summary := Summary{Cases: len(results)}
+	var recalls, scored, similar int
+	for _, r := range results {
+		if r.Error != "" {
+			summary.Failed++
+			continue
+		}
+		if r.RecallAtK != nil {
+			summary.MeanRecallAtK += *r.RecallAtK
+			recalls++
+		}
+		summary.MeanFaithfulness += r.Faithfulness
+		scored++
+		if r.AnswerSimilarity != nil {
+			summary.MeanSimilarity += *r.AnswerSimilarity
+			similar++
+		}
+	}
+	if recalls > 0 {
+		summary.MeanRecallAtK /= float64(recalls)
+	}
+	if scored > 0 {
+		summary.MeanFaithfulness /= float64(scored)
+	}
+	if similar > 0 {
+		summary.MeanSimilarity /= float64(similar)
+	}
+	return summary
+}
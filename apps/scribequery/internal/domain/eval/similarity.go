@@ -0,0 +1,56 @@
+package eval
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+func cosine(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
+
+// tokenF1 is the SQuAD-style F1 over lowercase word tokens.
+func tokenF1(answer, expected string) float64 {
+	got, want := tokens(answer), tokens(expected)
+	if len(got) == 0 || len(want) == 0 {
+		return 0
+	}
+
+	counts := make(map[string]int, len(want))
+	for _, t := range want {
+		counts[t]++
+	}
+	common := 0
+	for _, t := range got {
+		if counts[t] > 0 {
+			counts[t]--
+			common++
+		}
+	}
+	if common == 0 {
+		return 0
+	}
+
+	precision := float64(common) / float64(len(got))
+	recall := float64(common) / float64(len(want))
+	return 2 * precision * recall / (precision + recall)
+}
+
+func tokens(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
@@ -0,0 +1,10 @@
+package feedback
+
+import "errors"
+
+var (
+	ErrInvalidRating   = errors.New(`rating must be "up" or "down"`)
+	ErrInvalidCategory = errors.New("unknown feedback category")
+	ErrCommentTooLong  = errors.New("comment must be at most 2000 characters")
+	ErrNotAnswer       = errors.New("feedback can only be given on assistant messages")
+)
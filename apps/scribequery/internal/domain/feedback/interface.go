@@ -0,0 +1,18 @@
+package feedback
+
+import "context"
+
+type Service interface {
+	// Submit records feedback on an assistant message. Feedback given again
+	// on the same message replaces the earlier feedback.
+	Submit(ctx context.Context, req *SubmitRequest) (*Feedback, error)
+	// List returns matching feedback, newest first.
+	List(ctx context.Context, filter ListFilter) ([]Feedback, error)
+}
+
+type Repository interface {
+	// Save stores f, replacing any feedback on the same message.
+	Save(ctx context.Context, f *Feedback) error
+	// List returns the feedback filter selects, in no particular order.
+	List(ctx context.Context, filter ListFilter) ([]Feedback, error)
+}
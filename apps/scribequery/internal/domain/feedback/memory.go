@@ -0,0 +1,104 @@
+package feedback
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+
+	"github.com/Joepolymath/DaVinci/libs/shared-go/auth"
+)
+
+type memoryRepository struct {
+	mu        sync.RWMutex
+	byMessage map[string]Feedback
+
+	// path, when set, is a JSON Lines file each Save appends to; on load
+	// the last line for a message wins.
+	path string
+}
+
+// NewMemoryRepository creates an in-process repository, optionally
+// backed by an append-only JSON Lines file at path.
+func NewMemoryRepository(path string) (Repository, error) {
+	r := &memoryRepository{
+		byMessage: make(map[string]Feedback),
+		path:      path,
+	}
+	if path == "" {
+		return r, nil
+	}
+
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open feedback file: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var fb Feedback
+		if err := json.Unmarshal(scanner.Bytes(), &fb); err != nil {
+			return nil, fmt.Errorf("failed to parse feedback file line %d: %w", line, err)
+		}
+		if fb.TenantID == "" {
+			// Saved before all feedback had a tenant.
+			fb.TenantID = auth.DefaultTenant
+		}
+		r.byMessage[fb.MessageID] = fb
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read feedback file: %w", err)
+	}
+	return r, nil
+}
+
+func (r *memoryRepository) Save(ctx context.Context, f *Feedback) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.path != "" {
+		data, err := json.Marshal(f)
+		if err != nil {
+			return fmt.Errorf("failed to encode feedback: %w", err)
+		}
+		file, err := os.OpenFile(r.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return fmt.Errorf("failed to save feedback: %w", err)
+		}
+		_, err = file.Write(append(data, '\n'))
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("failed to save feedback: %w", err)
+		}
+	}
+
+	r.byMessage[f.MessageID] = *f
+	return nil
+}
+
+func (r *memoryRepository) List(ctx context.Context, filter ListFilter) ([]Feedback, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	matched := []Feedback{}
+	for _, f := range r.byMessage {
+		if filter.matches(&f) {
+			matched = append(matched, f)
+		}
+	}
+	return matched, nil
+}
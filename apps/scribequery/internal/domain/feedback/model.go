@@ -0,0 +1,99 @@
+package feedback
+
+import (
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Joepolymath/DaVinci/libs/shared-go/validate"
+)
+
+const maxCommentLength = 2000
+
+// Ratings.
+const (
+	RatingUp   = "up"
+	RatingDown = "down"
+)
+
+// Categories say what was wrong (or right) with an answer.
+var Categories = []string{
+	"accurate",
+	"helpful",
+	"inaccurate",
+	"incomplete",
+	"irrelevant",
+	"bad_citation",
+	"harmful",
+	"formatting",
+	"other",
+}
+
+// Feedback is a user's rating of an assistant message. Question and Answer
+// are snapshots taken when the feedback was given, with the provider and
+// model that produced the answer, so rated pairs can become eval cases.
+// TenantID is the tenant of the conversation.
+type Feedback struct {
+	ID             string    `json:"id"`
+	MessageID      string    `json:"message_id"`
+	ConversationID string    `json:"conversation_id"`
+	TenantID       string    `json:"tenant_id,omitempty"`
+	Rating         string    `json:"rating"`
+	Category       string    `json:"category,omitempty"`
+	Comment        string    `json:"comment,omitempty"`
+	Question       string    `json:"question"`
+	Answer         string    `json:"answer"`
+	Provider       string    `json:"provider,omitempty"`
+	Model          string    `json:"model,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type SubmitRequest struct {
+	MessageID string `json:"-"`
+	Rating    string `json:"rating"`
+	Category  string `json:"category,omitempty"`
+	Comment   string `json:"comment,omitempty"`
+}
+
+func (r *SubmitRequest) Validate() error {
+	var errs validate.Errors
+	if rating := strings.ToLower(strings.TrimSpace(r.Rating)); rating != RatingUp && rating != RatingDown {
+		errs.Add("rating", ErrInvalidRating)
+	}
+	if category := strings.ToLower(strings.TrimSpace(r.Category)); category != "" && !slices.Contains(Categories, category) {
+		errs.Add("category", ErrInvalidCategory)
+	}
+	if utf8.RuneCountInString(strings.TrimSpace(r.Comment)) > maxCommentLength {
+		errs.Add("comment", ErrCommentTooLong)
+	}
+	return errs.Err()
+}
+
+// ListFilter selects feedback; empty fields match everything. The
+// service sets TenantID to the current tenant.
+type ListFilter struct {
+	Rating   string
+	Category string
+	Model    string
+	TenantID string
+}
+
+// fields pairs the stored field names of feedback with the values f
+// selects, for the database repositories.
+func (f *ListFilter) fields() []struct{ name, value string } {
+	return []struct{ name, value string }{
+		{"tenant_id", f.TenantID},
+		{"rating", f.Rating},
+		{"category", f.Category},
+		{"model", f.Model},
+	}
+}
+
+// matches reports whether f selects fb.
+func (f *ListFilter) matches(fb *Feedback) bool {
+	return (f.TenantID == "" || fb.TenantID == f.TenantID) &&
+		(f.Rating == "" || fb.Rating == f.Rating) &&
+		(f.Category == "" || fb.Category == f.Category) &&
+		(f.Model == "" || fb.Model == f.Model)
+}
@@ -0,0 +1,106 @@
+package feedback
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/db/mongo"
+	"go.mongodb.org/mongo-driver/bson"
+	driver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const feedbackCollection = "feedback"
+
+// mongoFeedback is the stored document, keyed by message since a message
+// has at most one feedback. Mongo keeps times to the millisecond.
+type mongoFeedback struct {
+	MessageID      string    `bson:"_id"`
+	ID             string    `bson:"id"`
+	ConversationID string    `bson:"conversation_id"`
+	TenantID       string    `bson:"tenant_id"`
+	Rating         string    `bson:"rating"`
+	Category       string    `bson:"category,omitempty"`
+	Comment        string    `bson:"comment,omitempty"`
+	Question       string    `bson:"question"`
+	Answer         string    `bson:"answer"`
+	Provider       string    `bson:"provider,omitempty"`
+	Model          string    `bson:"model,omitempty"`
+	CreatedAt      time.Time `bson:"created_at"`
+}
+
+type mongoRepository struct {
+	feedback *driver.Collection
+}
+
+// NewMongoRepository creates a repository on the feedback collection,
+// creating its indexes.
+func NewMongoRepository(ctx context.Context, client *mongo.Client) (Repository, error) {
+	r := &mongoRepository{feedback: client.Collection(feedbackCollection)}
+	if _, err := r.feedback.Indexes().CreateOne(ctx, driver.IndexModel{
+		Keys:    bson.D{{Key: "tenant_id", Value: 1}, {Key: "created_at", Value: -1}},
+		Options: options.Index().SetName("tenant_created_at"),
+	}); err != nil {
+		return nil, fmt.Errorf("failed to create feedback indexes: %w", err)
+	}
+	return r, nil
+}
+
+func (r *mongoRepository) Save(ctx context.Context, f *Feedback) error {
+	doc := mongoFeedback{
+		MessageID:      f.MessageID,
+		ID:             f.ID,
+		ConversationID: f.ConversationID,
+		TenantID:       f.TenantID,
+		Rating:         f.Rating,
+		Category:       f.Category,
+		Comment:        f.Comment,
+		Question:       f.Question,
+		Answer:         f.Answer,
+		Provider:       f.Provider,
+		Model:          f.Model,
+		CreatedAt:      f.CreatedAt,
+	}
+	if _, err := r.feedback.ReplaceOne(ctx, bson.M{"_id": f.MessageID}, doc, options.Replace().SetUpsert(true)); err != nil {
+		return fmt.Errorf("failed to save feedback: %w", err)
+	}
+	return nil
+}
+
+func (r *mongoRepository) List(ctx context.Context, filter ListFilter) ([]Feedback, error) {
+	query := bson.D{}
+	for _, field := range filter.fields() {
+		if field.value != "" {
+			query = append(query, bson.E{Key: field.name, Value: field.value})
+		}
+	}
+
+	cursor, err := r.feedback.Find(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list feedback: %w", err)
+	}
+	var docs []mongoFeedback
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to list feedback: %w", err)
+	}
+
+	feedback := make([]Feedback, len(docs))
+	for i, d := range docs {
+		feedback[i] = Feedback{
+			ID:             d.ID,
+			MessageID:      d.MessageID,
+			ConversationID: d.ConversationID,
+			TenantID:       d.TenantID,
+			Rating:         d.Rating,
+			Category:       d.Category,
+			Comment:        d.Comment,
+			Question:       d.Question,
+			Answer:         d.Answer,
+			Provider:       d.Provider,
+			Model:          d.Model,
+			CreatedAt:      d.CreatedAt.UTC(),
+		}
+	}
+	return feedback, nil
+}
@@ -0,0 +1,129 @@
+package feedback
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/conversation"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/auth"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/ai"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type service struct {
+	repo          Repository
+	conversations conversation.Service
+	logger        *zap.Logger
+}
+
+func NewService(repo Repository, conversations conversation.Service, logger *zap.Logger) Service {
+	return &service{
+		repo:          repo,
+		conversations: conversations,
+		logger:        logger,
+	}
+}
+
+func (s *service) Submit(ctx context.Context, req *SubmitRequest) (*Feedback, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	rating := strings.ToLower(strings.TrimSpace(req.Rating))
+	category := strings.ToLower(strings.TrimSpace(req.Category))
+	comment := strings.TrimSpace(req.Comment)
+
+	answer, err := s.conversations.GetMessage(ctx, req.MessageID)
+	if err != nil {
+		return nil, err
+	}
+	if answer.Role != ai.RoleAssistant {
+		return nil, ErrNotAnswer
+	}
+
+	detail, err := s.conversations.Get(ctx, answer.ConversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &Feedback{
+		ID:             uuid.NewString(),
+		MessageID:      answer.ID,
+		ConversationID: answer.ConversationID,
+		TenantID:       detail.TenantID,
+		Rating:         rating,
+		Category:       category,
+		Comment:        comment,
+		Question:       question(detail, answer),
+		Answer:         answer.Content,
+		Provider:       answer.Provider,
+		Model:          answer.Model,
+		CreatedAt:      time.Now().UTC(),
+	}
+	if err := s.repo.Save(ctx, f); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Feedback recorded",
+		zap.String("message_id", f.MessageID),
+		zap.String("rating", f.Rating),
+		zap.String("category", f.Category),
+		zap.String("model", f.Model),
+	)
+	return f, nil
+}
+
+// question is the user message answer replies to: the closest user
+// message before it in its conversation.
+func question(detail *conversation.ConversationDetail, answer *conversation.Message) string {
+	i := slices.IndexFunc(detail.Messages, func(m conversation.Message) bool { return m.ID == answer.ID })
+	for i--; i >= 0; i-- {
+		if detail.Messages[i].Role == ai.RoleUser {
+			return detail.Messages[i].Content
+		}
+	}
+	return ""
+}
+
+// visible keeps the feedback on conversations the current user can see,
+// since feedback quotes the question and answer.
+func (s *service) visible(ctx context.Context, feedback []Feedback) ([]Feedback, error) {
+	seen := make(map[string]bool)
+	kept := feedback[:0]
+	for _, f := range feedback {
+		ok, checked := seen[f.ConversationID]
+		if !checked {
+			_, err := s.conversations.Get(ctx, f.ConversationID)
+			if err != nil && !errors.Is(err, conversation.ErrConversationNotFound) {
+				return nil, err
+			}
+			ok = err == nil
+			seen[f.ConversationID] = ok
+		}
+		if ok {
+			kept = append(kept, f)
+		}
+	}
+	return kept, nil
+}
+
+func (s *service) List(ctx context.Context, filter ListFilter) ([]Feedback, error) {
+	filter.TenantID = auth.Tenant(ctx)
+	matched, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if !auth.Can(ctx, auth.PermManage) {
+		if matched, err = s.visible(ctx, matched); err != nil {
+			return nil, err
+		}
+	}
+	slices.SortStableFunc(matched, func(a, b Feedback) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return matched, nil
+}
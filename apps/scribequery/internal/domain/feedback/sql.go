@@ -0,0 +1,87 @@
+package feedback
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const feedbackColumns = `id, message_id, conversation_id, tenant_id, rating, category, comment,
+	question, answer, provider, model, created_at`
+
+type sqlRepository struct {
+	db *sql.DB
+	// placeholder prefixes numbered parameters: "$" for Postgres ($1),
+	// "?" for SQLite (?1).
+	placeholder string
+}
+
+// NewPostgresRepository creates a repository on the feedback table of a
+// Postgres database (see the scribequery migrations).
+func NewPostgresRepository(db *sql.DB) Repository {
+	return &sqlRepository{db: db, placeholder: "$"}
+}
+
+// NewSQLiteRepository creates a repository on the feedback table of a
+// SQLite database.
+func NewSQLiteRepository(db *sql.DB) Repository {
+	return &sqlRepository{db: db, placeholder: "?"}
+}
+
+func (r *sqlRepository) rebind(query string) string {
+	if r.placeholder == "$" {
+		return query
+	}
+	return strings.ReplaceAll(query, "$", r.placeholder)
+}
+
+func (r *sqlRepository) Save(ctx context.Context, f *Feedback) error {
+	if _, err := r.db.ExecContext(ctx, r.rebind(`INSERT INTO feedback (`+feedbackColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (message_id) DO UPDATE SET
+			id = excluded.id, conversation_id = excluded.conversation_id, tenant_id = excluded.tenant_id,
+			rating = excluded.rating, category = excluded.category, comment = excluded.comment,
+			question = excluded.question, answer = excluded.answer, provider = excluded.provider,
+			model = excluded.model, created_at = excluded.created_at`),
+		f.ID, f.MessageID, f.ConversationID, f.TenantID, f.Rating, f.Category, f.Comment,
+		f.Question, f.Answer, f.Provider, f.Model, f.CreatedAt,
+	); err != nil {
+		return fmt.Errorf("failed to save feedback: %w", err)
+	}
+	return nil
+}
+
+func (r *sqlRepository) List(ctx context.Context, filter ListFilter) ([]Feedback, error) {
+	conditions := []string{"TRUE"}
+	args := []any{}
+	for _, field := range filter.fields() {
+		if field.value != "" {
+			args = append(args, field.value)
+			conditions = append(conditions, field.name+" = $"+strconv.Itoa(len(args)))
+		}
+	}
+
+	rows, err := r.db.QueryContext(ctx, r.rebind(`SELECT `+feedbackColumns+` FROM feedback
+		WHERE `+strings.Join(conditions, " AND ")), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list feedback: %w", err)
+	}
+	defer rows.Close()
+
+	feedback := []Feedback{}
+	for rows.Next() {
+		var f Feedback
+		if err := rows.Scan(&f.ID, &f.MessageID, &f.ConversationID, &f.TenantID, &f.Rating, &f.Category, &f.Comment,
+			&f.Question, &f.Answer, &f.Provider, &f.Model, &f.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to list feedback: %w", err)
+		}
+		f.CreatedAt = f.CreatedAt.UTC()
+		feedback = append(feedback, f)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list feedback: %w", err)
+	}
+	return feedback, nil
+}
@@ -0,0 +1,9 @@
+package health
+
+import "context"
+
+type Service interface {
+	// Check probes every dependency concurrently and reports each one's
+	// status and latency, and the overall status.
+	Check(ctx context.Context) *Report
+}
@@ -0,0 +1,43 @@
+package health
+
+import (
+	"context"
+	"time"
+)
+
+// Statuses, from best to worst.
+const (
+	StatusOK       = "ok"
+	StatusDegraded = "degraded" // a non-critical dependency is down
+	StatusDown     = "down"
+)
+
+// Check is a dependency to probe. Probe returns nil when the dependency
+// can serve requests.
+type Check struct {
+	Name string
+	Kind string // e.g. "provider", "database"
+	// Critical dependencies take the service down with them; others only
+	// degrade it (e.g. answers without retrieval).
+	Critical bool
+	Probe    func(ctx context.Context) error
+	// Details, if set, adds dependency-specific figures to the report,
+	// e.g. connection pool usage.
+	Details func() any
+}
+
+type DependencyStatus struct {
+	Name      string `json:"name"`
+	Kind      string `json:"kind"`
+	Status    string `json:"status"` // ok or down
+	Critical  bool   `json:"critical"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+	Details   any    `json:"details,omitempty"`
+}
+
+type Report struct {
+	Status       string             `json:"status"`
+	CheckedAt    time.Time          `json:"checked_at"`
+	Dependencies []DependencyStatus `json:"dependencies"`
+}
@@ -0,0 +1,92 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const defaultTimeout = 5 * time.Second
+
+// Config holds the health service settings. Zero values use the defaults.
+type Config struct {
+	// Timeout bounds each probe; a slower dependency is reported down
+	// (default: 5 seconds).
+	Timeout time.Duration
+}
+
+type service struct {
+	checks []Check
+	cfg    Config
+	logger *zap.Logger
+}
+
+// NewService creates the health service for checks, reported in order.
+func NewService(checks []Check, cfg Config, logger *zap.Logger) Service {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	return &service{
+		checks: checks,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+func (s *service) Check(ctx context.Context) *Report {
+	report := &Report{
+		Status:       StatusOK,
+		CheckedAt:    time.Now().UTC(),
+		Dependencies: make([]DependencyStatus, len(s.checks)),
+	}
+
+	var wg sync.WaitGroup
+	for i, check := range s.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report.Dependencies[i] = s.probe(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	for _, d := range report.Dependencies {
+		if d.Status == StatusOK {
+			continue
+		}
+		if d.Critical {
+			report.Status = StatusDown
+		} else if report.Status == StatusOK {
+			report.Status = StatusDegraded
+		}
+	}
+	return report
+}
+
+func (s *service) probe(ctx context.Context, check Check) DependencyStatus {
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check.Probe(ctx)
+	status := DependencyStatus{
+		Name:      check.Name,
+		Kind:      check.Kind,
+		Status:    StatusOK,
+		Critical:  check.Critical,
+		LatencyMs: time.Since(start).Milliseconds(),
+	}
+	if check.Details != nil {
+		status.Details = check.Details()
+	}
+	if err != nil {
+		status.Status = StatusDown
+		status.Error = err.Error()
+		s.logger.Warn("Health check failed",
+			zap.String("dependency", check.Name),
+			zap.Error(err))
+	}
+	return status
+}
@@ -0,0 +1,10 @@
+package idempotency
+
+import "errors"
+
+var (
+	ErrRecordNotFound    = errors.New("idempotency key not found")
+	ErrInvalidKey        = errors.New("idempotency key must be 1 to 255 printable ASCII characters")
+	ErrRequestInProgress = errors.New("a request with this idempotency key is in progress")
+	ErrKeyReused         = errors.New("idempotency key was used for a different request")
+)
@@ -0,0 +1,38 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+)
+
+// Service tracks idempotency keys. Keys are namespaced by the tenant and
+// user (or API key) of ctx, so callers only ever see their own.
+type Service interface {
+	// Begin claims key for a request with the given fingerprint. When key
+	// already holds the response to the same request, that response is
+	// returned to replay instead. Begin fails with ErrRequestInProgress
+	// while the first request with key is still running, and ErrKeyReused
+	// when key was used for a different request.
+	Begin(ctx context.Context, key, fingerprint string) (*Response, error)
+	// Complete stores the response to the request holding key, to be
+	// replayed until the key expires.
+	Complete(ctx context.Context, key string, resp Response) error
+	// Release frees key after a request that should run again on retry.
+	Release(ctx context.Context, key string) error
+
+	// Purge removes expired keys and returns how many it removed.
+	Purge(ctx context.Context) (int, error)
+}
+
+// Repository stores records by key. Get returns ErrRecordNotFound for
+// unknown keys.
+type Repository interface {
+	// Claim stores r unless an unexpired record holds its key, in which
+	// case it returns that record and stores nothing.
+	Claim(ctx context.Context, r *Record) (*Record, error)
+	Get(ctx context.Context, key string) (*Record, error)
+	Save(ctx context.Context, r *Record) error
+	Delete(ctx context.Context, key string) error
+	// DeleteExpired removes the records that expired before now.
+	DeleteExpired(ctx context.Context, now time.Time) (int, error)
+}
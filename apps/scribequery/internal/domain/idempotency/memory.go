@@ -0,0 +1,72 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type memoryRepository struct {
+	mu      sync.Mutex
+	records map[string]Record
+}
+
+// NewMemoryRepository creates an in-process repository. Records only need
+// to outlive client retries, so they are not saved across restarts.
+func NewMemoryRepository() Repository {
+	return &memoryRepository{
+		records: make(map[string]Record),
+	}
+}
+
+func (r *memoryRepository) Claim(ctx context.Context, rec *Record) (*Record, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.records[rec.Key]; ok && existing.ExpiresAt.After(rec.CreatedAt) {
+		return &existing, nil
+	}
+	r.records[rec.Key] = *rec
+	return nil, nil
+}
+
+func (r *memoryRepository) Get(ctx context.Context, key string) (*Record, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rec, ok := r.records[key]
+	if !ok {
+		return nil, ErrRecordNotFound
+	}
+	return &rec, nil
+}
+
+func (r *memoryRepository) Save(ctx context.Context, rec *Record) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.records[rec.Key] = *rec
+	return nil
+}
+
+func (r *memoryRepository) Delete(ctx context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.records, key)
+	return nil
+}
+
+func (r *memoryRepository) DeleteExpired(ctx context.Context, now time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deleted := 0
+	for key, rec := range r.records {
+		if !rec.ExpiresAt.After(now) {
+			delete(r.records, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,35 @@
+package idempotency
+
+import "time"
+
+// Record is a request made with an idempotency key. Key is the client's
+// key within its caller's namespace. Fingerprint identifies the request, so
+// a key cannot be replayed for another one; Response is nil until the
+// request completes.
+type Record struct {
+	Key         string    `json:"key"`
+	Fingerprint string    `json:"fingerprint"`
+	Response    *Response `json:"response,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// Response is a completed response, replayed as it is to retries.
+type Response struct {
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body"`
+}
+
+// validKey reports whether key is 1 to 255 printable ASCII characters.
+func validKey(key string) bool {
+	if key == "" || len(key) > maxKeyLength {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x20 || key[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
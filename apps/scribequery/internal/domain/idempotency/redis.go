@@ -0,0 +1,96 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/redis"
+)
+
+// redisRepository keeps each record as JSON under its key until it
+// expires, so that every instance sees the keys claimed by the others.
+type redisRepository struct {
+	client *redis.Client
+}
+
+// NewRedisRepository shares records between instances through Redis.
+// Redis expires them itself, so DeleteExpired has nothing to do.
+func NewRedisRepository(client *redis.Client) Repository {
+	return &redisRepository{client: client}
+}
+
+func recordKey(key string) string { return "idempotency:" + key }
+
+// KEYS[1] record; ARGV[1] record JSON, ARGV[2] ms until it expires.
+// Returns the existing record, or nil after storing the new one.
+const claimScript = `
+local existing = redis.call('GET', KEYS[1])
+if existing then
+  return existing
+end
+redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+return false`
+
+func (r *redisRepository) Claim(ctx context.Context, rec *Record) (*Record, error) {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return nil, err
+	}
+	reply, err := r.client.Eval(ctx, claimScript, []string{recordKey(rec.Key)}, string(data), ttlMillis(rec))
+	if err != nil {
+		return nil, err
+	}
+	if reply == nil {
+		return nil, nil
+	}
+	existing, ok := reply.(string)
+	if !ok {
+		return nil, fmt.Errorf("redis: unexpected claim reply %T", reply)
+	}
+	return decodeRecord(existing)
+}
+
+func (r *redisRepository) Get(ctx context.Context, key string) (*Record, error) {
+	data, err := r.client.Get(ctx, recordKey(key))
+	if errors.Is(err, redis.ErrNil) {
+		return nil, ErrRecordNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return decodeRecord(data)
+}
+
+func (r *redisRepository) Save(ctx context.Context, rec *Record) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	return r.client.Set(ctx, recordKey(rec.Key), string(data), time.Duration(ttlMillis(rec))*time.Millisecond)
+}
+
+func (r *redisRepository) Delete(ctx context.Context, key string) error {
+	_, err := r.client.Del(ctx, recordKey(key))
+	return err
+}
+
+func (r *redisRepository) DeleteExpired(ctx context.Context, now time.Time) (int, error) {
+	return 0, nil
+}
+
+// ttlMillis is how long rec has left, at least a millisecond so that Redis
+// accepts it.
+func ttlMillis(rec *Record) int64 {
+	return max(time.Until(rec.ExpiresAt).Milliseconds(), 1)
+}
+
+func decodeRecord(data string) (*Record, error) {
+	var rec Record
+	if err := json.Unmarshal([]byte(data), &rec); err != nil {
+		return nil, fmt.Errorf("failed to decode idempotency record: %w", err)
+	}
+	return &rec, nil
+}
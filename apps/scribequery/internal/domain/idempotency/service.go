@@ -0,0 +1,99 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/Joepolymath/DaVinci/libs/shared-go/auth"
+	"go.uber.org/zap"
+)
+
+const (
+	maxKeyLength = 255
+
+	defaultTTL = 24 * time.Hour
+)
+
+// Config holds the idempotency service settings. Zero values use the
+// defaults.
+type Config struct {
+	// TTL is how long a key's response is replayed (default: 24 hours).
+	TTL time.Duration
+}
+
+type service struct {
+	repo   Repository
+	cfg    Config
+	logger *zap.Logger
+}
+
+// NewService creates the idempotency service.
+func NewService(repo Repository, cfg Config, logger *zap.Logger) Service {
+	if cfg.TTL <= 0 {
+		cfg.TTL = defaultTTL
+	}
+	return &service{
+		repo:   repo,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+func (s *service) Begin(ctx context.Context, key, fingerprint string) (*Response, error) {
+	if !validKey(key) {
+		return nil, ErrInvalidKey
+	}
+
+	now := time.Now().UTC()
+	existing, err := s.repo.Claim(ctx, &Record{
+		Key:         scoped(ctx, key),
+		Fingerprint: fingerprint,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(s.cfg.TTL),
+	})
+	switch {
+	case err != nil:
+		return nil, err
+	case existing == nil:
+		return nil, nil
+	case existing.Fingerprint != fingerprint:
+		return nil, ErrKeyReused
+	case existing.Response == nil:
+		return nil, ErrRequestInProgress
+	}
+	return existing.Response, nil
+}
+
+func (s *service) Complete(ctx context.Context, key string, resp Response) error {
+	r, err := s.repo.Get(ctx, scoped(ctx, key))
+	if err != nil {
+		return err
+	}
+	r.Response = &resp
+	return s.repo.Save(ctx, r)
+}
+
+func (s *service) Release(ctx context.Context, key string) error {
+	return s.repo.Delete(ctx, scoped(ctx, key))
+}
+
+func (s *service) Purge(ctx context.Context) (int, error) {
+	return s.repo.DeleteExpired(ctx, time.Now().UTC())
+}
+
+// scoped is the stored form of a client's key. Clients choose keys
+// independently, so each tenant and user (or API key, for requests made
+// for no user) gets its own namespace, and one caller's key can neither
+// replay nor block another's request.
+func scoped(ctx context.Context, key string) string {
+	var owner string
+	if u, ok := auth.CurrentUser(ctx); ok {
+		owner = "user:" + u.ID
+	} else if id, ok := auth.FromContext(ctx); ok {
+		owner = "key:" + id.KeyID
+	}
+	h := sha256.Sum256([]byte(auth.Tenant(ctx) + "\x00" + owner))
+	return hex.EncodeToString(h[:16]) + ":" + key
+}
@@ -0,0 +1,7 @@
+package outbox
+
+import "errors"
+
+var (
+	ErrEventNotFound = errors.New("outbox event not found")
+)
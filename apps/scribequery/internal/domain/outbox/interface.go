@@ -0,0 +1,29 @@
+package outbox
+
+import (
+	"context"
+	"time"
+)
+
+type Service interface {
+	// Relay publishes pending events, oldest first, and returns how many
+	// it published. An event that fails to publish holds back the later
+	// events with the same key until a later Relay.
+	Relay(ctx context.Context) (int, error)
+	// Purge removes events published longer ago than the retention window
+	// and returns how many it removed.
+	Purge(ctx context.Context) (int, error)
+}
+
+// Repository holds events written in the same transaction as the change
+// they describe. MarkPublished and MarkFailed return ErrEventNotFound for
+// unknown IDs.
+type Repository interface {
+	// PendingEvents returns up to limit unpublished events, oldest first.
+	PendingEvents(ctx context.Context, limit int) ([]Event, error)
+	MarkPublished(ctx context.Context, id string, at time.Time) error
+	// MarkFailed counts a failed publish attempt and records its reason.
+	MarkFailed(ctx context.Context, id, reason string) error
+	// DeletePublishedEvents removes the events published before cutoff.
+	DeletePublishedEvents(ctx context.Context, cutoff time.Time) (int, error)
+}
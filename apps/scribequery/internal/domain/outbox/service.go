@@ -0,0 +1,91 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/bus"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultBatchSize = 100
+	defaultRetention = 7 * 24 * time.Hour
+)
+
+// Config holds the relay settings. Zero values use the defaults.
+type Config struct {
+	// BatchSize is how many events one Relay reads (default: 100).
+	BatchSize int
+	// Retention is how long published events are kept (default: 7 days).
+	Retention time.Duration
+}
+
+type service struct {
+	repo      Repository
+	publisher bus.Publisher
+	cfg       Config
+	logger    *zap.Logger
+}
+
+// NewService creates the relay that publishes repo's events to publisher.
+func NewService(repo Repository, publisher bus.Publisher, cfg Config, logger *zap.Logger) Service {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultBatchSize
+	}
+	if cfg.Retention <= 0 {
+		cfg.Retention = defaultRetention
+	}
+	return &service{
+		repo:      repo,
+		publisher: publisher,
+		cfg:       cfg,
+		logger:    logger,
+	}
+}
+
+func (s *service) Relay(ctx context.Context) (int, error) {
+	events, err := s.repo.PendingEvents(ctx, s.cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	blocked := make(map[string]bool)
+	for _, e := range events {
+		if blocked[e.Key] {
+			continue
+		}
+
+		err := s.publisher.Publish(ctx, bus.Message{
+			ID:      e.ID,
+			Topic:   e.Topic,
+			Key:     e.Key,
+			Payload: e.Payload,
+		})
+		if err != nil {
+			blocked[e.Key] = true
+			s.logger.Warn("Failed to publish event",
+				zap.String("event_id", e.ID),
+				zap.String("topic", e.Topic),
+				zap.Int("attempts", e.Attempts+1),
+				zap.Error(err))
+			if err := s.repo.MarkFailed(ctx, e.ID, err.Error()); err != nil {
+				return published, err
+			}
+			continue
+		}
+
+		// A failure here publishes the event again on the next Relay,
+		// which consumers tolerate by deduplicating on ID.
+		if err := s.repo.MarkPublished(ctx, e.ID, time.Now().UTC()); err != nil {
+			return published, err
+		}
+		published++
+	}
+	return published, nil
+}
+
+func (s *service) Purge(ctx context.Context) (int, error) {
+	return s.repo.DeletePublishedEvents(ctx, time.Now().UTC().Add(-s.cfg.Retention))
+}
@@ -0,0 +1,15 @@
+package ratelimit
+
+import "context"
+
+type Service interface {
+	// Allow takes one request from caller's bucket for class. Classes
+	// without a limit always allow.
+	Allow(ctx context.Context, class Class, caller string) (*Decision, error)
+}
+
+// Store keeps token buckets. Take refills the bucket at key for the time
+// since it was last used, then takes one request from it if it can.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (*Decision, error)
+}
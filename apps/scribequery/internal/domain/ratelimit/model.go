@@ -0,0 +1,80 @@
+package ratelimit
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Class groups routes that share a limit. Each caller has one bucket per
+// class.
+type Class string
+
+const (
+	// ClassChat covers generating answers: chats, streams and
+	// regenerations, which cost provider tokens.
+	ClassChat Class = "chat"
+	// ClassList covers listings and searches.
+	ClassList Class = "list"
+	// ClassAuth covers sign-in and registration, limited per client IP
+	// against password guessing.
+	ClassAuth Class = "auth"
+)
+
+var classes = []Class{ClassChat, ClassList, ClassAuth}
+
+// Limit is a token bucket: it holds up to Burst requests and refills at
+// PerMinute. A zero PerMinute is unlimited.
+type Limit struct {
+	PerMinute int
+	Burst     int // default: PerMinute
+}
+
+// interval is the time to refill one request.
+func (l Limit) interval() time.Duration {
+	return time.Minute / time.Duration(l.PerMinute)
+}
+
+// Decision is the outcome of taking a request from a bucket.
+type Decision struct {
+	Allowed   bool
+	Limit     int // the bucket's size
+	Remaining int // whole requests left
+	// RetryAfter is how long until the next request would be allowed;
+	// zero when Allowed.
+	RetryAfter time.Duration
+}
+
+// ParseLimits parses limits in the form "chat=20:5,list=120", as used in
+// environment variables: requests per minute per class, optionally
+// followed by a burst size.
+func ParseLimits(s string) (map[Class]Limit, error) {
+	limits := make(map[Class]Limit)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(pair, "=")
+		class := Class(strings.TrimSpace(name))
+		if !ok || !slices.Contains(classes, class) {
+			return nil, fmt.Errorf("invalid rate limit %q (want class=per_minute[:burst] with class %v)", pair, classes)
+		}
+
+		rate, burst, hasBurst := strings.Cut(strings.TrimSpace(value), ":")
+		var limit Limit
+		var err error
+		if limit.PerMinute, err = strconv.Atoi(rate); err != nil || limit.PerMinute < 0 {
+			return nil, fmt.Errorf("invalid rate limit %q: bad requests per minute", pair)
+		}
+		if hasBurst {
+			if limit.Burst, err = strconv.Atoi(burst); err != nil || limit.Burst <= 0 {
+				return nil, fmt.Errorf("invalid rate limit %q: bad burst", pair)
+			}
+		}
+		limits[class] = limit
+	}
+	return limits, nil
+}
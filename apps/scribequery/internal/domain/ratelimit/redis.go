@@ -0,0 +1,65 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/redis"
+)
+
+// redisStore keeps each bucket in a hash of its tokens and last update,
+// so that every instance draws from the same buckets.
+type redisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore shares buckets between instances through Redis.
+func NewRedisStore(client *redis.Client) Store {
+	return &redisStore{client: client}
+}
+
+// KEYS[1] bucket; ARGV[1] capacity, ARGV[2] ms to refill one request.
+// Returns {allowed, whole tokens left, ms until the next request}. The
+// bucket expires once it would be full again.
+const takeScript = `
+local now = redis.call('TIME')
+local ms = now[1] * 1000 + math.floor(now[2] / 1000)
+local capacity = tonumber(ARGV[1])
+local interval = tonumber(ARGV[2])
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1]) or capacity
+local ts = tonumber(state[2]) or ms
+tokens = math.min(capacity, tokens + math.max(0, ms - ts) / interval)
+local allowed, wait = 0, 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+else
+  wait = math.ceil((1 - tokens) * interval)
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', ms)
+redis.call('PEXPIRE', KEYS[1], math.ceil((capacity - tokens) * interval) + 1)
+return {allowed, math.floor(tokens), wait}`
+
+func (s *redisStore) Take(ctx context.Context, key string, limit Limit) (*Decision, error) {
+	interval := strconv.FormatFloat(float64(limit.interval())/float64(time.Millisecond), 'f', -1, 64)
+	reply, err := s.client.Eval(ctx, takeScript, []string{key}, limit.Burst, interval)
+	if err != nil {
+		return nil, err
+	}
+	values, ok := reply.([]any)
+	if !ok || len(values) != 3 {
+		return nil, fmt.Errorf("redis: unexpected rate limit reply %v", reply)
+	}
+	allowed, _ := values[0].(int64)
+	remaining, _ := values[1].(int64)
+	wait, _ := values[2].(int64)
+	return &Decision{
+		Allowed:    allowed == 1,
+		Limit:      limit.Burst,
+		Remaining:  int(remaining),
+		RetryAfter: time.Duration(wait) * time.Millisecond,
+	}, nil
+}
@@ -0,0 +1,40 @@
+package ratelimit
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+type Config struct {
+	// Limits by class; a class without one is unlimited.
+	Limits map[Class]Limit
+}
+
+type service struct {
+	store  Store
+	limits map[Class]Limit
+	logger *zap.Logger
+}
+
+func NewService(store Store, cfg Config, logger *zap.Logger) Service {
+	limits := make(map[Class]Limit, len(cfg.Limits))
+	for class, limit := range cfg.Limits {
+		if limit.PerMinute <= 0 {
+			continue
+		}
+		if limit.Burst <= 0 {
+			limit.Burst = limit.PerMinute
+		}
+		limits[class] = limit
+	}
+	return &service{store: store, limits: limits, logger: logger}
+}
+
+func (s *service) Allow(ctx context.Context, class Class, caller string) (*Decision, error) {
+	limit, ok := s.limits[class]
+	if !ok {
+		return &Decision{Allowed: true}, nil
+	}
+	return s.store.Take(ctx, "ratelimit:"+string(class)+":"+caller, limit)
+}
@@ -0,0 +1,10 @@
+package retrieval
+
+import "errors"
+
+var (
+	ErrEmptyQuery        = errors.New("query is required")
+	ErrRetrievalDisabled = errors.New("retrieval requires an embeddings provider and a vector store")
+	ErrRerankUnavailable = errors.New("reranking was requested but no reranker is configured")
+	ErrExpandUnavailable = errors.New("query expansion was requested but no chat provider is configured")
+)
@@ -0,0 +1,48 @@
+package retrieval
+
+import (
+	"sort"
+
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/db/vector"
+)
+
+// rrfK is the reciprocal rank fusion constant from Cormack et al. (2009).
+const rrfK = 60
+
+// fusedMatch is a chunk found by one or more queries.
+type fusedMatch struct {
+	match vector.Match
+	score float64 // RRF score
+	query int     // index of the query that ranked the chunk highest
+	rank  int     // the chunk's best rank (0-based)
+}
+
+// fuseRRF merges per-query result lists with reciprocal rank fusion:
+// each list contributes 1/(rrfK+rank) to a chunk's score.
+func fuseRRF(results [][]vector.Match) []fusedMatch {
+	byID := make(map[string]*fusedMatch)
+	var order []string
+	for q, matches := range results {
+		for rank, m := range matches {
+			f, ok := byID[m.ID]
+			if !ok {
+				f = &fusedMatch{match: m, query: q, rank: rank}
+				byID[m.ID] = f
+				order = append(order, m.ID)
+			} else if rank < f.rank {
+				f.query, f.rank = q, rank
+			}
+			if m.Score > f.match.Score {
+				f.match.Score = m.Score
+			}
+			f.score += 1 / float64(rrfK+rank+1)
+		}
+	}
+
+	fused := make([]fusedMatch, len(order))
+	for i, id := range order {
+		fused[i] = *byID[id]
+	}
+	sort.SliceStable(fused, func(i, j int) bool { return fused[i].score > fused[j].score })
+	return fused
+}
@@ -0,0 +1,20 @@
+package retrieval
+
+import (
+	"context"
+
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/db/vector"
+)
+
+type Service interface {
+	// Retrieve finds the document chunks most relevant to a query: vector
+	// search, then (optionally) reranking.
+	Retrieve(ctx context.Context, req *RetrieveRequest) (*RetrieveResult, error)
+}
+
+// Repository searches the chunks stored by document ingestion.
+type Repository interface {
+	// Search returns the chunks nearest to q.Vector, best match first,
+	// with their payloads.
+	Search(ctx context.Context, q *SearchQuery) ([]vector.Match, error)
+}
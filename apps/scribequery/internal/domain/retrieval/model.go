@@ -0,0 +1,80 @@
+package retrieval
+
+import (
+	"strings"
+
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/ai"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/db/vector"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/validate"
+)
+
+type RetrieveRequest struct {
+	Query    string             `json:"query"`
+	TopK     int                `json:"top_k,omitempty"`     // default: 5
+	MinScore float32            `json:"min_score,omitempty"` // applied to vector search scores
+	Filter   map[string]any     `json:"filter,omitempty"`    // exact-match payload filter
+	Where    []vector.Condition `json:"where,omitempty"`
+
+	// History is the conversation before Query, used to rewrite Query as a
+	// standalone question. Expand overrides whether the query is rewritten
+	// and searched alongside Paraphrases paraphrases (default: 2, max: 3)
+	// with results fused by reciprocal rank; nil uses the server default
+	// (QUERY_EXPANSION).
+	History     []ai.Message `json:"history,omitempty"`
+	Expand      *bool        `json:"expand,omitempty"`
+	Paraphrases int          `json:"paraphrases,omitempty"`
+
+	// Rerank overrides whether results are reranked; nil reranks when a
+	// reranker is configured. RerankCandidates is how many vector hits are
+	// passed to the reranker (default: 4×TopK).
+	Rerank           *bool `json:"rerank,omitempty"`
+	RerankCandidates int   `json:"rerank_candidates,omitempty"`
+
+	// TokenBudget, when set, packs the results with PackContext as they
+	// would be placed in a prompt.
+	TokenBudget int `json:"token_budget,omitempty"`
+}
+
+func (r *RetrieveRequest) Validate() error {
+	var errs validate.Errors
+	if strings.TrimSpace(r.Query) == "" {
+		errs.Add("query", ErrEmptyQuery)
+	}
+	return errs.Err()
+}
+
+// Chunk is a retrieved document chunk. Score is the vector similarity;
+// RerankScore is set when the chunk was reranked.
+type Chunk struct {
+	ID          string         `json:"id"`
+	DocumentID  string         `json:"document_id"`
+	Content     string         `json:"content"`
+	Title       string         `json:"title,omitempty"`
+	Source      string         `json:"source,omitempty"`
+	Heading     string         `json:"heading,omitempty"`
+	Page        int            `json:"page,omitempty"`
+	ChunkIndex  int            `json:"chunk_index"`
+	StartOffset int            `json:"start_offset"` // character offsets in the document text
+	EndOffset   int            `json:"end_offset"`
+	Score       float32        `json:"score"`
+	RerankScore *float64       `json:"rerank_score,omitempty"`
+	FusionScore float64        `json:"fusion_score,omitempty"` // reciprocal rank fusion score, when expanded
+	Query       string         `json:"query,omitempty"`        // the query that ranked the chunk highest, when expanded
+	Truncated   bool           `json:"truncated,omitempty"`    // Content was cut to fit a token budget
+	Metadata    map[string]any `json:"metadata,omitempty"`
+}
+
+type RetrieveResult struct {
+	Chunks   []Chunk  `json:"chunks"`
+	Queries  []string `json:"queries,omitempty"` // the queries searched, when expanded
+	Reranked bool     `json:"reranked"`
+}
+
+// SearchQuery is one vector search against the stored chunks.
+type SearchQuery struct {
+	Vector   []float32
+	TopK     int
+	MinScore float32
+	Filter   map[string]any // exact-match payload filter
+	Where    []vector.Condition
+}
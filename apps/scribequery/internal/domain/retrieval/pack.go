@@ -0,0 +1,155 @@
+package retrieval
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/ai"
+)
+
+const (
+	defaultTokenBudget    = 3000
+	defaultMaxPerDocument = 3
+	defaultMinChunkTokens = 50
+
+	// chunkOverheadTokens covers the source number and label written
+	// before each chunk in the prompt.
+	chunkOverheadTokens = 16
+
+	// duplicateOverlap is the word-shingle overlap above which two chunks
+	// count as the same passage (e.g. overlapping chunks of one document).
+	duplicateOverlap = 0.8
+)
+
+// PackConfig controls how retrieved chunks are packed into a prompt.
+// Zero values use the defaults.
+type PackConfig struct {
+	TokenBudget    int // total tokens for all chunks (default: 3000)
+	MaxPerDocument int // chunks from one document before others get a turn (default: 3)
+	MinChunkTokens int // smallest truncated chunk worth including (default: 50)
+}
+
+func (c PackConfig) withDefaults() PackConfig {
+	if c.TokenBudget <= 0 {
+		c.TokenBudget = defaultTokenBudget
+	}
+	if c.MaxPerDocument <= 0 {
+		c.MaxPerDocument = defaultMaxPerDocument
+	}
+	if c.MinChunkTokens <= 0 {
+		c.MinChunkTokens = defaultMinChunkTokens
+	}
+	return c
+}
+
+// PackContext selects chunks, most relevant first, to fit cfg.TokenBudget
+// (estimated with ai.EstimateTokens). Duplicate and near-duplicate chunks are
+// dropped, and chunks beyond MaxPerDocument from one document are only used
+// if budget remains after every other chunk. The first chunk that does not
+// fit is truncated to the remaining budget; less relevant chunks are dropped.
+func PackContext(chunks []Chunk, cfg PackConfig) []Chunk {
+	cfg = cfg.withDefaults()
+
+	var (
+		primary, overflow []Chunk
+		kept              [][]string // shingles of accepted chunks
+		perDocument       = make(map[string]int)
+		seen              = make(map[string]bool)
+	)
+	for _, c := range chunks {
+		if seen[c.ID] || strings.TrimSpace(c.Content) == "" {
+			continue
+		}
+		seen[c.ID] = true
+
+		sh := shingles(c.Content)
+		if isDuplicate(sh, kept) {
+			continue
+		}
+		kept = append(kept, sh)
+
+		if perDocument[c.DocumentID]++; c.DocumentID != "" && perDocument[c.DocumentID] > cfg.MaxPerDocument {
+			overflow = append(overflow, c)
+			continue
+		}
+		primary = append(primary, c)
+	}
+
+	var (
+		packed    []Chunk
+		remaining = cfg.TokenBudget
+	)
+	for _, c := range append(primary, overflow...) {
+		cost := ai.EstimateTokens(c.Content) + chunkOverheadTokens
+		if cost <= remaining {
+			packed = append(packed, c)
+			remaining -= cost
+			continue
+		}
+
+		if available := remaining - chunkOverheadTokens; available >= cfg.MinChunkTokens {
+			packed = append(packed, truncateChunk(c, available))
+		}
+		break
+	}
+	return packed
+}
+
+// truncateChunk shortens c to about tokens tokens, cutting at a word
+// boundary, and adjusts its end offset.
+func truncateChunk(c Chunk, tokens int) Chunk {
+	limit := tokens * 4 // inverse of ai.EstimateTokens
+	if limit >= len(c.Content) {
+		return c
+	}
+	for limit > 0 && !utf8.RuneStart(c.Content[limit]) {
+		limit--
+	}
+	cut := strings.LastIndexFunc(c.Content[:limit], unicode.IsSpace)
+	if cut < limit/2 {
+		cut = limit
+	}
+
+	c.Content = strings.TrimSpace(c.Content[:cut])
+	if c.EndOffset > 0 {
+		c.EndOffset = c.StartOffset + utf8.RuneCountInString(c.Content)
+	}
+	c.Truncated = true
+	return c
+}
+
+// shingles returns the overlapping three-word sequences of text, lower-cased.
+func shingles(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	if len(words) < 3 {
+		return []string{strings.Join(words, " ")}
+	}
+	out := make([]string, 0, len(words)-2)
+	for i := 0; i+3 <= len(words); i++ {
+		out = append(out, words[i]+" "+words[i+1]+" "+words[i+2])
+	}
+	return out
+}
+
+// isDuplicate reports whether most of sh's shingles appear in one of kept.
+func isDuplicate(sh []string, kept [][]string) bool {
+	for _, other := range kept {
+		set := make(map[string]bool, len(other))
+		for _, s := range other {
+			set[s] = true
+		}
+		common := 0
+		for _, s := range sh {
+			if set[s] {
+				common++
+			}
+		}
+		if float64(common) >= duplicateOverlap*float64(min(len(sh), len(other))) {
+			return true
+		}
+	}
+	return false
+}
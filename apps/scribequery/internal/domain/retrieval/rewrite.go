@@ -0,0 +1,104 @@
+package retrieval
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/ai"
+)
+
+const (
+	defaultParaphrases = 2
+	maxParaphrases     = 3
+	maxHistoryMessages = 6
+)
+
+const rewritePrompt = `You rewrite search queries for a document retrieval system.
+Given a conversation and the user's latest question:
+1. Rewrite the question as a standalone query, resolving pronouns and references using the conversation. Keep it close to the original wording.
+2. Write %d paraphrases that use different vocabulary but ask for the same information.
+Respond with JSON only: {"standalone": "...", "paraphrases": ["...", "..."]}`
+
+var rewriteFormat = &ai.ResponseFormat{
+	Type: ai.ResponseFormatJSONSchema,
+	Name: "query_rewrite",
+	Schema: json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"standalone": {"type": "string"},
+			"paraphrases": {"type": "array", "items": {"type": "string"}}
+		},
+		"required": ["standalone", "paraphrases"],
+		"additionalProperties": false
+	}`),
+	Strict: true,
+}
+
+type rewriteResult struct {
+	Standalone  string   `json:"standalone"`
+	Paraphrases []string `json:"paraphrases"`
+}
+
+// rewriteQuery asks the chat provider for a standalone version of query
+// (decontextualized against history) and n paraphrases. It returns the
+// distinct queries to search, standalone first.
+func rewriteQuery(ctx context.Context, provider ai.ChatProvider, query string, history []ai.Message, n int) ([]string, error) {
+	var transcript strings.Builder
+	for _, m := range history[max(0, len(history)-maxHistoryMessages):] {
+		if m.Role == ai.RoleSystem || strings.TrimSpace(m.Content) == "" {
+			continue
+		}
+		fmt.Fprintf(&transcript, "%s: %s\n", m.Role, m.Content)
+	}
+
+	user := "Question: " + query
+	if transcript.Len() > 0 {
+		user = "Conversation:\n" + transcript.String() + "\n" + user
+	}
+
+	resp, err := provider.Completion(ctx, []ai.Message{
+		{Role: ai.RoleSystem, Content: fmt.Sprintf(rewritePrompt, n)},
+		{Role: ai.RoleUser, Content: user},
+	}, &ai.ChatOptions{
+		Temperature:    ai.Float64(0.2),
+		ResponseFormat: rewriteFormat,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to rewrite query: %w", err)
+	}
+
+	var result rewriteResult
+	if err := json.Unmarshal([]byte(extractJSON(resp.Content)), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse query rewrite: %w", err)
+	}
+
+	queries := []string{}
+	seen := make(map[string]bool)
+	for _, q := range append([]string{result.Standalone}, result.Paraphrases...) {
+		q = strings.TrimSpace(q)
+		if q == "" || seen[strings.ToLower(q)] {
+			continue
+		}
+		if len(queries) > n {
+			break
+		}
+		seen[strings.ToLower(q)] = true
+		queries = append(queries, q)
+	}
+	if len(queries) == 0 {
+		return nil, fmt.Errorf("query rewrite returned no queries")
+	}
+	return queries, nil
+}
+
+// extractJSON strips a Markdown code fence, which some local models add
+// even when asked for bare JSON.
+func extractJSON(s string) string {
+	s = strings.TrimSpace(s)
+	if start, end := strings.Index(s, "{"), strings.LastIndex(s, "}"); start >= 0 && end > start {
+		return s[start : end+1]
+	}
+	return s
+}
@@ -0,0 +1,266 @@
+package retrieval
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/document"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/ai"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/db/vector"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultTopK         = 5
+	defaultRerankFactor = 4
+	maxRerankCandidates = 100
+)
+
+// Config holds the defaults for requests that leave them unset.
+type Config struct {
+	ExpandQueries bool // rewrite and expand queries unless a request opts out
+}
+
+type service struct {
+	embeddings ai.EmbeddingsProvider
+	repo       Repository
+	reranker   ai.Reranker
+	rewriter   ai.ChatProvider
+	cfg        Config
+	logger     *zap.Logger
+}
+
+// NewService creates the retrieval service. reranker and rewriter (the chat
+// provider used for query rewriting) may be nil; retrieval is disabled
+// while embeddings or repo is.
+func NewService(embeddings ai.EmbeddingsProvider, repo Repository, reranker ai.Reranker, rewriter ai.ChatProvider, cfg Config, logger *zap.Logger) Service {
+	return &service{
+		embeddings: embeddings,
+		repo:       repo,
+		reranker:   reranker,
+		rewriter:   rewriter,
+		cfg:        cfg,
+		logger:     logger,
+	}
+}
+
+func (s *service) Retrieve(ctx context.Context, req *RetrieveRequest) (*RetrieveResult, error) {
+	if s.embeddings == nil || s.repo == nil {
+		return nil, ErrRetrievalDisabled
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	rerank := s.reranker != nil
+	if req.Rerank != nil {
+		if *req.Rerank && s.reranker == nil {
+			return nil, ErrRerankUnavailable
+		}
+		rerank = *req.Rerank
+	}
+
+	topK := req.TopK
+	if topK <= 0 {
+		topK = defaultTopK
+	}
+	candidates := topK
+	if rerank {
+		candidates = req.RerankCandidates
+		if candidates <= 0 {
+			candidates = topK * defaultRerankFactor
+		}
+		candidates = min(max(candidates, topK), maxRerankCandidates)
+	}
+
+	expand := s.cfg.ExpandQueries && s.rewriter != nil
+	if req.Expand != nil {
+		if *req.Expand && s.rewriter == nil {
+			return nil, ErrExpandUnavailable
+		}
+		expand = *req.Expand
+	}
+
+	queries := []string{req.Query}
+	if expand {
+		paraphrases := req.Paraphrases
+		if paraphrases <= 0 {
+			paraphrases = defaultParaphrases
+		}
+		rewritten, err := rewriteQuery(ctx, s.rewriter, req.Query, req.History, min(paraphrases, maxParaphrases))
+		if err != nil {
+			s.logger.Warn("Query rewriting failed, searching the original query", zap.Error(err))
+		} else {
+			queries = rewritten
+		}
+	}
+	expanded := len(queries) > 1 || queries[0] != req.Query
+
+	chunks, err := s.search(ctx, req, queries, candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RetrieveResult{Chunks: chunks}
+	if expanded {
+		result.Queries = queries
+	}
+	if rerank && len(chunks) > 0 {
+		reranked, err := s.rerank(ctx, queries[0], chunks, topK)
+		if err != nil {
+			// Vector order is still a usable answer.
+			s.logger.Warn("Reranking failed, using vector search order", zap.Error(err))
+		} else {
+			result.Chunks, result.Reranked = reranked, true
+		}
+	}
+	if len(result.Chunks) > topK {
+		result.Chunks = result.Chunks[:topK]
+	}
+	if req.TokenBudget > 0 {
+		result.Chunks = PackContext(result.Chunks, PackConfig{TokenBudget: req.TokenBudget})
+	}
+	if len(queries) > 1 {
+		s.logWinningQueries(queries, result.Chunks)
+	}
+
+	return result, nil
+}
+
+// search runs every query against the store. With several queries the
+// result lists are fused by reciprocal rank and each chunk records the
+// query that ranked it highest.
+func (s *service) search(ctx context.Context, req *RetrieveRequest, queries []string, limit int) ([]Chunk, error) {
+	vectors, err := s.embeddings.Embed(ctx, queries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed query: %w", err)
+	}
+
+	results := make([][]vector.Match, len(queries))
+	for i := range queries {
+		results[i], err = s.repo.Search(ctx, &SearchQuery{
+			Vector:   vectors[i],
+			TopK:     limit,
+			MinScore: req.MinScore,
+			Filter:   req.Filter,
+			Where:    req.Where,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to search chunks: %w", err)
+		}
+	}
+
+	if len(queries) == 1 {
+		chunks := make([]Chunk, len(results[0]))
+		for i, m := range results[0] {
+			chunks[i] = chunkFromMatch(m)
+		}
+		return chunks, nil
+	}
+
+	fused := fuseRRF(results)
+	chunks := make([]Chunk, 0, min(len(fused), limit))
+	for _, f := range fused[:min(len(fused), limit)] {
+		chunk := chunkFromMatch(f.match)
+		chunk.FusionScore = f.score
+		chunk.Query = queries[f.query]
+		chunks = append(chunks, chunk)
+	}
+	return chunks, nil
+}
+
+// logWinningQueries records how many returned chunks each query ranked
+// highest, to judge whether expansion pays for its extra completion.
+func (s *service) logWinningQueries(queries []string, chunks []Chunk) {
+	wins := make([]int, len(queries))
+	for _, c := range chunks {
+		for i, q := range queries {
+			if c.Query == q {
+				wins[i]++
+				break
+			}
+		}
+	}
+	s.logger.Info("Query expansion results",
+		zap.Strings("queries", queries),
+		zap.Ints("wins", wins))
+}
+
+func (s *service) rerank(ctx context.Context, query string, chunks []Chunk, topK int) ([]Chunk, error) {
+	documents := make([]string, len(chunks))
+	for i, c := range chunks {
+		documents[i] = c.Content
+	}
+
+	results, err := s.reranker.Rerank(ctx, query, documents, topK)
+	if err != nil {
+		return nil, err
+	}
+
+	reranked := make([]Chunk, 0, len(results))
+	for _, r := range results {
+		if r.Index < 0 || r.Index >= len(chunks) {
+			return nil, fmt.Errorf("reranker returned out-of-range index %d", r.Index)
+		}
+		chunk := chunks[r.Index]
+		score := r.Score
+		chunk.RerankScore = &score
+		reranked = append(reranked, chunk)
+	}
+
+	s.logger.Debug("Chunks reranked",
+		zap.String("model", s.reranker.GetModel()),
+		zap.Int("candidates", len(chunks)),
+		zap.Int("kept", len(reranked)))
+
+	return reranked, nil
+}
+
+// chunkFromMatch maps the payload written by document ingestion back to a
+// Chunk; unknown payload fields are kept as metadata.
+func chunkFromMatch(m vector.Match) Chunk {
+	c := Chunk{ID: m.ID, Score: m.Score}
+	for k, v := range m.Payload {
+		switch k {
+		case document.FieldDocumentID:
+			c.DocumentID, _ = v.(string)
+		case document.FieldContent:
+			c.Content, _ = v.(string)
+		case document.FieldTitle:
+			c.Title, _ = v.(string)
+		case document.FieldSource:
+			c.Source, _ = v.(string)
+		case document.FieldHeading:
+			c.Heading, _ = v.(string)
+		case document.FieldPage:
+			c.Page = toInt(v)
+		case document.FieldChunkIndex:
+			c.ChunkIndex = toInt(v)
+		case document.FieldStartOffset:
+			c.StartOffset = toInt(v)
+		case document.FieldEndOffset:
+			c.EndOffset = toInt(v)
+		default:
+			if c.Metadata == nil {
+				c.Metadata = make(map[string]any)
+			}
+			c.Metadata[k] = v
+		}
+	}
+	return c
+}
+
+// toInt converts the numeric types vector stores decode payloads into.
+func toInt(v any) int {
+	switch n := v.(type) {
+	case int:
+		return n
+	case int64:
+		return int(n)
+	case float64:
+		return int(n)
+	case float32:
+		return int(n)
+	}
+	return 0
+}
@@ -0,0 +1,57 @@
+package retrieval
+
+import (
+	"context"
+	"maps"
+
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/document"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/auth"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/db/vector"
+)
+
+type vectorRepository struct {
+	store      vector.Store
+	collection string
+}
+
+// NewVectorRepository searches a collection of store. Pass the store
+// given to document.NewVectorRepository, e.g. a shared memory.NewStore()
+// in tests.
+func NewVectorRepository(store vector.Store, collection string) Repository {
+	return &vectorRepository{store: store, collection: collection}
+}
+
+// Search only returns chunks of the current tenant and, for a user other
+// than an admin, chunks they ingested, whatever q's filter says about
+// those fields. As with conversations, requests without a user see the
+// whole tenant.
+func (r *vectorRepository) Search(ctx context.Context, q *SearchQuery) ([]vector.Match, error) {
+	filter := maps.Clone(q.Filter)
+	if filter == nil {
+		filter = make(map[string]any, 2)
+	}
+	filter[document.FieldTenantID] = auth.Tenant(ctx)
+	if owner := scope(ctx); owner != "" {
+		filter[document.FieldOwnerID] = owner
+	}
+	return r.store.Query(ctx, &vector.QueryRequest{
+		Collection:  r.collection,
+		Vector:      q.Vector,
+		TopK:        q.TopK,
+		MinScore:    q.MinScore,
+		Filter:      filter,
+		Where:       q.Where,
+		WithPayload: true,
+	})
+}
+
+// scope is the owner whose chunks the current user may search, or empty
+// for the whole tenant: without a user, as when authentication is off,
+// and for admins.
+func scope(ctx context.Context) string {
+	u, ok := auth.CurrentUser(ctx)
+	if !ok || auth.Can(ctx, auth.PermManage) {
+		return ""
+	}
+	return u.ID
+}
@@ -0,0 +1,11 @@
+package share
+
+import "errors"
+
+var (
+	ErrShareNotFound = errors.New("share not found")
+	ErrInvalidToken  = errors.New("invalid share token")
+	ErrShareRevoked  = errors.New("share link has been revoked")
+	ErrShareExpired  = errors.New("share link has expired")
+	ErrInvalidExpiry = errors.New("expires_in_hours must be between 0 and 8760")
+)
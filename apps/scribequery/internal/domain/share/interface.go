@@ -0,0 +1,23 @@
+package share
+
+import "context"
+
+type Service interface {
+	// Create shares a conversation and returns the link token.
+	Create(ctx context.Context, req *CreateRequest) (*CreateResult, error)
+	// List returns the conversation's shares, newest first.
+	List(ctx context.Context, conversationID string) ([]Share, error)
+	// Revoke disables a share of a conversation the caller can see.
+	Revoke(ctx context.Context, conversationID, shareID string) (*Share, error)
+
+	// Resolve verifies a token and returns the shared transcript.
+	Resolve(ctx context.Context, token string) (*Transcript, error)
+}
+
+// Repository stores shares. Get returns ErrShareNotFound for unknown IDs.
+type Repository interface {
+	Save(ctx context.Context, s *Share) error
+	Get(ctx context.Context, id string) (*Share, error)
+	// ListByConversation returns the conversation's shares in tenantID.
+	ListByConversation(ctx context.Context, conversationID, tenantID string) ([]Share, error)
+}
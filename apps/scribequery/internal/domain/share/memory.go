@@ -0,0 +1,112 @@
+package share
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+
+	"github.com/Joepolymath/DaVinci/libs/shared-go/auth"
+)
+
+type memoryRepository struct {
+	mu     sync.RWMutex
+	shares map[string]Share
+
+	// path, when set, is a JSON file rewritten after every change.
+	path string
+}
+
+// NewMemoryRepository creates an in-process repository, optionally saved
+// to a JSON file at path.
+func NewMemoryRepository(path string) (Repository, error) {
+	r := &memoryRepository{
+		shares: make(map[string]Share),
+		path:   path,
+	}
+	if path == "" {
+		return r, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read shares file: %w", err)
+	}
+
+	var shares []Share
+	if err := json.Unmarshal(data, &shares); err != nil {
+		return nil, fmt.Errorf("failed to parse shares file: %w", err)
+	}
+	for _, s := range shares {
+		if s.TenantID == "" {
+			// Saved before every share had a tenant.
+			s.TenantID = auth.DefaultTenant
+		}
+		r.shares[s.ID] = s
+	}
+	return r, nil
+}
+
+func (r *memoryRepository) Save(ctx context.Context, s *Share) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.shares[s.ID] = *s
+	return r.save()
+}
+
+func (r *memoryRepository) Get(ctx context.Context, id string) (*Share, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	s, ok := r.shares[id]
+	if !ok {
+		return nil, ErrShareNotFound
+	}
+	return &s, nil
+}
+
+func (r *memoryRepository) ListByConversation(ctx context.Context, conversationID, tenantID string) ([]Share, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	shares := []Share{}
+	for _, s := range r.shares {
+		if s.ConversationID == conversationID && inTenant(&s, tenantID) {
+			shares = append(shares, s)
+		}
+	}
+	return shares, nil
+}
+
+// save rewrites the file through a temporary file and rename. Callers
+// hold the write lock.
+func (r *memoryRepository) save() error {
+	if r.path == "" {
+		return nil
+	}
+
+	shares := make([]Share, 0, len(r.shares))
+	for _, s := range r.shares {
+		shares = append(shares, s)
+	}
+	data, err := json.Marshal(shares)
+	if err != nil {
+		return fmt.Errorf("failed to encode shares: %w", err)
+	}
+
+	tmp := r.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to save shares: %w", err)
+	}
+	if err := os.Rename(tmp, r.path); err != nil {
+		return fmt.Errorf("failed to save shares: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package share
+
+import (
+	"time"
+
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/conversation"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/validate"
+)
+
+// Share is a read-only link to a conversation. The link's token is signed
+// and names the share, so revoking the share disables the link. TenantID
+// is the tenant of the conversation.
+type Share struct {
+	ID             string     `json:"id"`
+	ConversationID string     `json:"conversation_id"`
+	TenantID       string     `json:"tenant_id,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+	RevokedAt      *time.Time `json:"revoked_at,omitempty"`
+}
+
+type CreateRequest struct {
+	ConversationID string `json:"-"`
+	// ExpiresInHours limits the link's lifetime; zero never expires.
+	ExpiresInHours int `json:"expires_in_hours,omitempty"`
+}
+
+func (r *CreateRequest) Validate() error {
+	var errs validate.Errors
+	if r.ExpiresInHours < 0 || r.ExpiresInHours > maxExpiryHours {
+		errs.Add("expires_in_hours", ErrInvalidExpiry)
+	}
+	return errs.Err()
+}
+
+type CreateResult struct {
+	Share Share  `json:"share"`
+	Token string `json:"token"`
+}
+
+// Transcript is the public view of a shared conversation: its title and
+// current messages, without superseded answers or internal metadata.
+type Transcript struct {
+	Title    string              `json:"title"`
+	SharedAt time.Time           `json:"shared_at"`
+	Messages []TranscriptMessage `json:"messages"`
+}
+
+type TranscriptMessage struct {
+	Role      string    `json:"role"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func newTranscript(detail *conversation.ConversationDetail, s *Share) *Transcript {
+	t := &Transcript{
+		Title:    detail.Title,
+		SharedAt: s.CreatedAt,
+		Messages: []TranscriptMessage{},
+	}
+	for _, m := range detail.Messages {
+		if m.Superseded {
+			continue
+		}
+		t.Messages = append(t.Messages, TranscriptMessage{Role: m.Role, Content: m.Content, CreatedAt: m.CreatedAt})
+	}
+	return t
+}
@@ -0,0 +1,104 @@
+package share
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/db/mongo"
+	"go.mongodb.org/mongo-driver/bson"
+	driver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const sharesCollection = "shares"
+
+// mongoShare is the stored document. Mongo keeps times to the millisecond.
+type mongoShare struct {
+	ID             string     `bson:"_id"`
+	ConversationID string     `bson:"conversation_id"`
+	TenantID       string     `bson:"tenant_id"`
+	CreatedAt      time.Time  `bson:"created_at"`
+	ExpiresAt      *time.Time `bson:"expires_at,omitempty"`
+	RevokedAt      *time.Time `bson:"revoked_at,omitempty"`
+}
+
+type mongoRepository struct {
+	shares *driver.Collection
+}
+
+// NewMongoRepository creates a repository on the shares collection,
+// creating its indexes.
+func NewMongoRepository(ctx context.Context, client *mongo.Client) (Repository, error) {
+	r := &mongoRepository{shares: client.Collection(sharesCollection)}
+	if _, err := r.shares.Indexes().CreateOne(ctx, driver.IndexModel{
+		Keys:    bson.D{{Key: "conversation_id", Value: 1}},
+		Options: options.Index().SetName("conversation_id"),
+	}); err != nil {
+		return nil, fmt.Errorf("failed to create share indexes: %w", err)
+	}
+	return r, nil
+}
+
+func (r *mongoRepository) Save(ctx context.Context, s *Share) error {
+	doc := mongoShare{
+		ID:             s.ID,
+		ConversationID: s.ConversationID,
+		TenantID:       s.TenantID,
+		CreatedAt:      s.CreatedAt,
+		ExpiresAt:      s.ExpiresAt,
+		RevokedAt:      s.RevokedAt,
+	}
+	if _, err := r.shares.ReplaceOne(ctx, bson.M{"_id": s.ID}, doc, options.Replace().SetUpsert(true)); err != nil {
+		return fmt.Errorf("failed to save share: %w", err)
+	}
+	return nil
+}
+
+func (r *mongoRepository) Get(ctx context.Context, id string) (*Share, error) {
+	var doc mongoShare
+	err := r.shares.FindOne(ctx, bson.M{"_id": id}).Decode(&doc)
+	if errors.Is(err, driver.ErrNoDocuments) {
+		return nil, ErrShareNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get share: %w", err)
+	}
+	return fromMongoShare(&doc), nil
+}
+
+func (r *mongoRepository) ListByConversation(ctx context.Context, conversationID, tenantID string) ([]Share, error) {
+	cursor, err := r.shares.Find(ctx, bson.M{"conversation_id": conversationID, "tenant_id": tenantID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shares: %w", err)
+	}
+	var docs []mongoShare
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to list shares: %w", err)
+	}
+
+	shares := make([]Share, len(docs))
+	for i := range docs {
+		shares[i] = *fromMongoShare(&docs[i])
+	}
+	return shares, nil
+}
+
+func fromMongoShare(d *mongoShare) *Share {
+	s := &Share{
+		ID:             d.ID,
+		ConversationID: d.ConversationID,
+		TenantID:       d.TenantID,
+		CreatedAt:      d.CreatedAt.UTC(),
+	}
+	if d.ExpiresAt != nil {
+		t := d.ExpiresAt.UTC()
+		s.ExpiresAt = &t
+	}
+	if d.RevokedAt != nil {
+		t := d.RevokedAt.UTC()
+		s.RevokedAt = &t
+	}
+	return s
+}
@@ -0,0 +1,136 @@
+package share
+
+import (
+	"context"
+	"crypto/rand"
+	"slices"
+	"time"
+
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/conversation"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/auth"
+	"go.uber.org/zap"
+)
+
+const maxExpiryHours = 24 * 365
+
+type service struct {
+	repo          Repository
+	conversations conversation.Service
+	signer        signer
+	logger        *zap.Logger
+}
+
+// NewService creates the share service. Tokens are signed with secret; an
+// empty secret uses a random one, so links stop working on restart.
+func NewService(repo Repository, conversations conversation.Service, secret string, logger *zap.Logger) Service {
+	key := []byte(secret)
+	if len(key) == 0 {
+		logger.Warn("SHARE_SECRET is not set; share links will not survive a restart")
+		key = make([]byte, 32)
+		rand.Read(key)
+	}
+
+	return &service{
+		repo:          repo,
+		conversations: conversations,
+		signer:        signer{secret: key},
+		logger:        logger,
+	}
+}
+
+func (s *service) Create(ctx context.Context, req *CreateRequest) (*CreateResult, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	detail, err := s.conversations.Get(ctx, req.ConversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	share := &Share{
+		ID:             rand.Text(),
+		ConversationID: req.ConversationID,
+		TenantID:       detail.TenantID,
+		CreatedAt:      now,
+	}
+	if req.ExpiresInHours > 0 {
+		expires := now.Add(time.Duration(req.ExpiresInHours) * time.Hour)
+		share.ExpiresAt = &expires
+	}
+	if err := s.repo.Save(ctx, share); err != nil {
+		return nil, err
+	}
+
+	return &CreateResult{Share: *share, Token: s.signer.sign(share.ID)}, nil
+}
+
+func (s *service) List(ctx context.Context, conversationID string) ([]Share, error) {
+	if _, err := s.conversations.Get(ctx, conversationID); err != nil {
+		return nil, err
+	}
+
+	shares, err := s.repo.ListByConversation(ctx, conversationID, auth.Tenant(ctx))
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(shares, func(a, b Share) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return shares, nil
+}
+
+func (s *service) Revoke(ctx context.Context, conversationID, shareID string) (*Share, error) {
+	if _, err := s.conversations.Get(ctx, conversationID); err != nil {
+		return nil, err
+	}
+
+	share, err := s.repo.Get(ctx, shareID)
+	if err != nil {
+		return nil, err
+	}
+	if share.ConversationID != conversationID || !inTenant(share, auth.Tenant(ctx)) {
+		return nil, ErrShareNotFound
+	}
+	if share.RevokedAt != nil {
+		return share, nil
+	}
+
+	now := time.Now().UTC()
+	share.RevokedAt = &now
+	if err := s.repo.Save(ctx, share); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Share revoked", zap.String("share_id", share.ID), zap.String("conversation_id", conversationID))
+	return share, nil
+}
+
+// inTenant reports whether s belongs to tenantID.
+func inTenant(s *Share, tenantID string) bool {
+	return s.TenantID == tenantID
+}
+
+func (s *service) Resolve(ctx context.Context, token string) (*Transcript, error) {
+	id, err := s.signer.verify(token)
+	if err != nil {
+		return nil, err
+	}
+
+	share, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case share.RevokedAt != nil:
+		return nil, ErrShareRevoked
+	case share.ExpiresAt != nil && time.Now().After(*share.ExpiresAt):
+		return nil, ErrShareExpired
+	}
+
+	detail, err := s.conversations.Get(ctx, share.ConversationID)
+	if err != nil {
+		return nil, err
+	}
+	return newTranscript(detail, share), nil
+}
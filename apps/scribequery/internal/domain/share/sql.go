@@ -0,0 +1,109 @@
+package share
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const shareColumns = `id, conversation_id, tenant_id, created_at, expires_at, revoked_at`
+
+type sqlRepository struct {
+	db *sql.DB
+	// placeholder prefixes numbered parameters: "$" for Postgres ($1),
+	// "?" for SQLite (?1).
+	placeholder string
+}
+
+// NewPostgresRepository creates a repository on the shares table of a
+// Postgres database (see the scribequery migrations).
+func NewPostgresRepository(db *sql.DB) Repository {
+	return &sqlRepository{db: db, placeholder: "$"}
+}
+
+// NewSQLiteRepository creates a repository on the shares table of a
+// SQLite database.
+func NewSQLiteRepository(db *sql.DB) Repository {
+	return &sqlRepository{db: db, placeholder: "?"}
+}
+
+func (r *sqlRepository) rebind(query string) string {
+	if r.placeholder == "$" {
+		return query
+	}
+	return strings.ReplaceAll(query, "$", r.placeholder)
+}
+
+// Save inserts s or, for a share already stored, updates the fields that
+// change after creation.
+func (r *sqlRepository) Save(ctx context.Context, s *Share) error {
+	if _, err := r.db.ExecContext(ctx, r.rebind(`INSERT INTO shares (`+shareColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (id) DO UPDATE SET expires_at = excluded.expires_at, revoked_at = excluded.revoked_at`),
+		s.ID, s.ConversationID, s.TenantID, s.CreatedAt, s.ExpiresAt, s.RevokedAt,
+	); err != nil {
+		return fmt.Errorf("failed to save share: %w", err)
+	}
+	return nil
+}
+
+func (r *sqlRepository) Get(ctx context.Context, id string) (*Share, error) {
+	s, err := scanShare(r.db.QueryRowContext(ctx, r.rebind(`SELECT `+shareColumns+` FROM shares WHERE id = $1`), id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrShareNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get share: %w", err)
+	}
+	return s, nil
+}
+
+func (r *sqlRepository) ListByConversation(ctx context.Context, conversationID, tenantID string) ([]Share, error) {
+	rows, err := r.db.QueryContext(ctx, r.rebind(`SELECT `+shareColumns+` FROM shares
+		WHERE conversation_id = $1 AND tenant_id = $2`), conversationID, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shares: %w", err)
+	}
+	defer rows.Close()
+
+	shares := []Share{}
+	for rows.Next() {
+		s, err := scanShare(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list shares: %w", err)
+		}
+		shares = append(shares, *s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list shares: %w", err)
+	}
+	return shares, nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanShare(row scanner) (*Share, error) {
+	var (
+		s                Share
+		expires, revoked sql.NullTime
+	)
+	if err := row.Scan(&s.ID, &s.ConversationID, &s.TenantID, &s.CreatedAt, &expires, &revoked); err != nil {
+		return nil, err
+	}
+	s.CreatedAt = s.CreatedAt.UTC()
+	s.ExpiresAt, s.RevokedAt = nullTime(expires), nullTime(revoked)
+	return &s, nil
+}
+
+func nullTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	utc := t.Time.UTC()
+	return &utc
+}
@@ -0,0 +1,38 @@
+package share
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+)
+
+// signer issues tokens of the form "<share ID>.<signature>", where the
+// signature is an HMAC-SHA256 of the ID, so share IDs cannot be guessed
+// or altered.
+type signer struct {
+	secret []byte
+}
+
+func (s signer) sign(shareID string) string {
+	return shareID + "." + base64.RawURLEncoding.EncodeToString(s.mac(shareID))
+}
+
+// verify returns the share ID of a valid token.
+func (s signer) verify(token string) (string, error) {
+	id, sig, ok := strings.Cut(token, ".")
+	if !ok || id == "" {
+		return "", ErrInvalidToken
+	}
+	got, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(got, s.mac(id)) {
+		return "", ErrInvalidToken
+	}
+	return id, nil
+}
+
+func (s signer) mac(shareID string) []byte {
+	h := hmac.New(sha256.New, s.secret)
+	h.Write([]byte(shareID))
+	return h.Sum(nil)
+}
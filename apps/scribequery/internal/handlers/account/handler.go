@@ -0,0 +1,192 @@
+package account
+
+import (
+	"errors"
+
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/account"
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/ratelimit"
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/handlers"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/auth"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	service account.Service
+	env     *handlers.Environment
+}
+
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+func (h *Handler) Init(basePath string, env *handlers.Environment) error {
+	h.env = env
+	h.service = env.Services.AccountService
+
+	group := env.Fiber.Group(basePath + handlers.AuthPath)
+
+	limit := handlers.RateLimit(env, ratelimit.ClassAuth)
+
+	group.Post("/register", limit, h.register)
+	group.Post("/login", limit, h.login)
+	group.Post("/refresh", limit, h.refresh)
+	group.Post("/logout", h.logout)
+
+	users := env.Fiber.Group(basePath + "/users")
+
+	users.Get("/me", h.me)
+	users.Get("/", auth.Require(auth.PermManage), handlers.RateLimit(env, ratelimit.ClassList), h.list)
+	users.Get("/:id", auth.Require(auth.PermManage), h.get)
+	users.Put("/:id/role", auth.Require(auth.PermManage), h.setRole)
+
+	return nil
+}
+
+func (h *Handler) register(c *fiber.Ctx) error {
+	var request account.RegisterRequest
+	if err := handlers.ParseBody(c, &request); err != nil {
+		return handlers.BadRequest(c, err)
+	}
+
+	result, err := h.service.Register(c.Context(), &request)
+	if err != nil {
+		return h.error(c, err, "Failed to register user")
+	}
+
+	c.Set("Cache-Control", "no-store")
+	return c.Status(fiber.StatusCreated).JSON(result)
+}
+
+func (h *Handler) login(c *fiber.Ctx) error {
+	var request account.LoginRequest
+	if err := handlers.ParseBody(c, &request); err != nil {
+		return handlers.BadRequest(c, err)
+	}
+
+	result, err := h.service.Login(c.Context(), &request)
+	if err != nil {
+		return h.error(c, err, "Failed to sign in")
+	}
+
+	c.Set("Cache-Control", "no-store")
+	return c.JSON(result)
+}
+
+func (h *Handler) refresh(c *fiber.Ctx) error {
+	var request refreshRequest
+	if err := handlers.ParseBody(c, &request); err != nil {
+		return handlers.BadRequest(c, err)
+	}
+
+	result, err := h.service.Refresh(c.Context(), request.RefreshToken)
+	if err != nil {
+		return h.error(c, err, "Failed to refresh session")
+	}
+
+	c.Set("Cache-Control", "no-store")
+	return c.JSON(result)
+}
+
+func (h *Handler) logout(c *fiber.Ctx) error {
+	var request refreshRequest
+	if err := handlers.ParseBody(c, &request); err != nil {
+		return handlers.BadRequest(c, err)
+	}
+
+	if err := h.service.Logout(c.Context(), request.RefreshToken); err != nil {
+		return h.error(c, err, "Failed to sign out")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// me returns the signed-in user's account. Users authenticated by another
+// issuer have no account here, so only their token's profile is returned.
+func (h *Handler) me(c *fiber.Ctx) error {
+	u, ok := auth.CurrentUser(c.Context())
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": auth.ErrMissingToken.Error(),
+		})
+	}
+
+	result, err := h.service.Get(c.Context(), u.ID)
+	if errors.Is(err, account.ErrUserNotFound) || errors.Is(err, account.ErrAccountsDisabled) {
+		return c.JSON(u)
+	}
+	if err != nil {
+		return h.error(c, err, "Failed to get user")
+	}
+
+	return c.JSON(result)
+}
+
+func (h *Handler) list(c *fiber.Ctx) error {
+	result, err := h.service.List(c.Context())
+	if err != nil {
+		return h.error(c, err, "Failed to list users")
+	}
+
+	return c.JSON(fiber.Map{
+		"users": result,
+	})
+}
+
+func (h *Handler) get(c *fiber.Ctx) error {
+	result, err := h.service.Get(c.Context(), c.Params("id"))
+	if err != nil {
+		return h.error(c, err, "Failed to get user")
+	}
+
+	return c.JSON(result)
+}
+
+func (h *Handler) setRole(c *fiber.Ctx) error {
+	var request account.RoleRequest
+	if err := handlers.ParseBody(c, &request); err != nil {
+		return handlers.BadRequest(c, err)
+	}
+	request.UserID = c.Params("id")
+
+	result, err := h.service.SetRole(c.Context(), &request)
+	if err != nil {
+		return h.error(c, err, "Failed to change user role")
+	}
+
+	return c.JSON(result)
+}
+
+func (h *Handler) error(c *fiber.Ctx, err error, message string) error {
+	switch {
+	case errors.Is(err, account.ErrInvalidEmail), errors.Is(err, account.ErrPasswordLength),
+		errors.Is(err, account.ErrNameTooLong), errors.Is(err, account.ErrPasswordMissing),
+		errors.Is(err, account.ErrInvalidRole):
+		return handlers.BadRequest(c, err)
+	case errors.Is(err, account.ErrUserNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, account.ErrOwnRole):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, account.ErrEmailTaken):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, account.ErrInvalidCredentials), errors.Is(err, account.ErrInvalidRefreshToken):
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, account.ErrAccountsDisabled):
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	h.env.Logger.Error(message, zap.Error(err))
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": message,
+	})
+}
@@ -4,41 +4,79 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/chat"
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/conversation"
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/ratelimit"
 	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/handlers"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/auth"
 	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/ai"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/text/extract"
+	"github.com/gofiber/contrib/websocket"
 	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
 )
 
 type Handler struct {
-	service chat.Service
-	env     *handlers.Environment
+	service    chat.Service
+	env        *handlers.Environment
+	extractors *extract.Registry
+	upgrade    fiber.Handler // completes the websocket handshake for chatWS
 }
 
 func (h *Handler) Init(basePath string, env *handlers.Environment) error {
 	h.env = env
 	h.service = env.Services.ChatService
+	h.extractors = extract.NewRegistry()
+	h.upgrade = websocket.New(h.serveConn)
 
-	group := env.Fiber.Group(basePath + "/chats")
+	group := env.Fiber.Group(basePath+"/chats", auth.Require(auth.PermWrite))
 
-	group.Post("/", h.chat)
-	group.Post("/stream", h.chatStream)
+	// Stopping a generation costs nothing, so it is not rate limited.
+	limit := handlers.RateLimit(env, ratelimit.ClassChat)
+
+	// Streams cannot be replayed, so only the buffered routes take an
+	// Idempotency-Key.
+	group.Post("/", limit, handlers.Idempotent(env), h.chat)
+	group.Post("/stream", limit, h.chatStream)
+	env.Stream(basePath + "/chats/stream")
+	group.Get("/ws", limit, h.chatWS)
+	group.Post("/:conversationID/regenerate", limit, handlers.Idempotent(env), h.regenerate)
+	group.Post("/:conversationID/stop", h.stop)
 
 	return nil
 }
 
+// chat answers a conversation. The body is a JSON ChatRequest, or a
+// multipart form with files attached to the last message (see parseRequest).
+// A retry with the same Idempotency-Key gets the first answer back rather
+// than generating (and paying for) another.
 func (h *Handler) chat(c *fiber.Ctx) error {
-	var request ai.Message
-	if err := c.BodyParser(&request); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request body",
-		})
+	var request chat.ChatRequest
+	if err := h.parseRequest(c, &request); err != nil {
+		return requestError(c, err)
 	}
 
-	response, err := h.service.Chat(c.Context(), []ai.Message{request})
+	response, err := h.service.Chat(c.Context(), &request)
 	if err != nil {
+		switch {
+		case handlers.IsInvalid(err):
+			return handlers.BadRequest(c, err)
+		case errors.Is(err, conversation.ErrConversationNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, chat.ErrGenerationStopped):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, conversation.ErrModelsUnavailable):
+			return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to chat",
 		})
@@ -47,46 +85,200 @@ func (h *Handler) chat(c *fiber.Ctx) error {
 	return c.JSON(response)
 }
 
-func (h *Handler) chatStream(c *fiber.Ctx) error {
-	var request ai.Message
-	if err := c.BodyParser(&request); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request body",
+// regenerate replaces the conversation's last answer. The body is optional
+// and may set "options" (e.g. a different temperature or model).
+func (h *Handler) regenerate(c *fiber.Ctx) error {
+	var request chat.RegenerateRequest
+	if len(c.Body()) > 0 {
+		if err := handlers.ParseBody(c, &request); err != nil {
+			return handlers.BadRequest(c, err)
+		}
+	}
+	request.ConversationID = c.Params("conversationID")
+
+	response, err := h.service.Regenerate(c.Context(), &request)
+	if err != nil {
+		switch {
+		case handlers.IsInvalid(err):
+			return handlers.BadRequest(c, err)
+		case errors.Is(err, conversation.ErrConversationNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, chat.ErrNothingToRegenerate), errors.Is(err, chat.ErrGenerationStopped):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, conversation.ErrModelsUnavailable):
+			return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		h.env.Logger.Error("Failed to regenerate", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to regenerate",
 		})
 	}
 
-	messages := []ai.Message{request}
+	return c.JSON(response)
+}
+
+// stop cancels the conversation's in-flight generations, streaming or not.
+func (h *Handler) stop(c *fiber.Ctx) error {
+	if err := h.service.Stop(c.Context(), c.Params("conversationID")); err != nil {
+		if errors.Is(err, conversation.ErrConversationNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		if errors.Is(err, chat.ErrNoActiveGeneration) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to stop generation",
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// chatStream relays completion deltas as Server-Sent Events. Content
+// deltas are unnamed "message" events carrying an ai.ChatStreamDelta; the
+// final delta is followed by a "citations" event (when the answer was
+// grounded in documents) with the answer rewritten with footnote markers,
+// its citations and the markers' positions, a "usage" event (when the
+// provider reports usage) and a "done" event, or by a "stopped" event when the generation
+// is stopped via POST /:conversationID/stop. Failures are sent as an
+// "error" event. Each event is flushed as soon as it is written, and
+// generation is cancelled once the client goes away. The request may be a
+// multipart form with attachments, as for chat.
+func (h *Handler) chatStream(c *fiber.Ctx) error {
+	var request chat.ChatRequest
+	if err := h.parseRequest(c, &request); err != nil {
+		return requestError(c, err)
+	}
+	if err := request.Validate(); err != nil {
+		return handlers.BadRequest(c, err)
+	}
+	if request.ConversationID != "" {
+		// Check here so an unknown conversation is a 404 rather than an
+		// error event on an already-open stream.
+		if _, err := h.env.Services.ConversationService.Get(c.Context(), request.ConversationID); err != nil {
+			if errors.Is(err, conversation.ErrConversationNotFound) {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+					"error": err.Error(),
+				})
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to load conversation",
+			})
+		}
+	}
+	if err := h.service.CheckModel(c.Context(), &request); err != nil {
+		switch {
+		case handlers.IsInvalid(err):
+			return handlers.BadRequest(c, err)
+		case errors.Is(err, conversation.ErrModelsUnavailable):
+			return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		h.env.Logger.Error("Failed to check the chat model", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to chat",
+		})
+	}
 
 	c.Set("Content-Type", "text/event-stream")
 	c.Set("Cache-Control", "no-cache")
 	c.Set("Connection", "keep-alive")
 	c.Set("Transfer-Encoding", "chunked")
+	c.Set("X-Accel-Buffering", "no") // disable nginx response buffering
 
+	detached := handlers.Detached(c)
 	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
-		ctx := context.Background()
+		// The stream writer runs after the handler returns, so the request
+		// context is already done; a failed write is how a disconnect shows up.
+		ctx, cancel := context.WithCancel(detached)
+		defer cancel()
 
-		err := h.service.ChatStream(ctx, messages, func(delta ai.ChatStreamDelta) error {
-			data, err := json.Marshal(delta)
-			if err != nil {
-				return err
-			}
+		// Open the stream before the first token so proxies and browsers
+		// see the response immediately.
+		if _, err := fmt.Fprint(w, ": stream open\n\n"); err != nil || w.Flush() != nil {
+			return
+		}
 
-			if _, err := fmt.Fprintf(w, "data: %s\n\n", data); err != nil {
+		response, err := h.service.ChatStream(ctx, &request, func(delta ai.ChatStreamDelta) error {
+			if err := writeEvent(w, "", delta); err != nil {
+				cancel()
 				return err
 			}
-
-			return w.Flush()
+			return nil
 		})
 
 		if err != nil {
-			errData, _ := json.Marshal(fiber.Map{"error": err.Error()})
-			fmt.Fprintf(w, "event: error\ndata: %s\n\n", errData)
-			w.Flush()
+			if errors.Is(err, chat.ErrGenerationStopped) {
+				writeEvent(w, "stopped", fiber.Map{})
+				return
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			h.env.Logger.Error("Chat stream failed", zap.Error(err))
+			writeEvent(w, "error", fiber.Map{"error": err.Error()})
+			return
 		}
 
-		fmt.Fprintf(w, "data: [DONE]\n\n")
-		w.Flush()
+		if len(response.Citations) > 0 {
+			if writeEvent(w, "citations", citationsOf(&response)) != nil {
+				return
+			}
+		}
+		// The finish reason and usage come from the service's response:
+		// providers may report usage in a delta after the finishing one.
+		if response.Usage != (ai.ChatUsage{}) {
+			if writeEvent(w, "usage", response.Usage) != nil {
+				return
+			}
+		}
+		writeEvent(w, "done", fiber.Map{
+			"finish_reason": response.FinishReason,
+			"request_id":    response.RequestID,
+		})
 	})
 
 	return nil
 }
+
+// streamCitations is the final answer of a grounded stream: its content
+// with the model's source numbers rewritten as footnote markers, the
+// cited sources, and each marker's position in content.
+type streamCitations struct {
+	Content   string                `json:"content"`
+	Citations []chat.Citation       `json:"citations"`
+	Markers   []chat.CitationMarker `json:"markers"`
+}
+
+func citationsOf(r *chat.ChatResponse) *streamCitations {
+	return &streamCitations{Content: r.Content, Citations: r.Citations, Markers: r.Markers}
+}
+
+// writeEvent writes one Server-Sent Event with v as JSON data and flushes
+// it. An empty name writes an unnamed ("message") event.
+func writeEvent(w *bufio.Writer, name string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if name != "" {
+		if _, err := fmt.Fprintf(w, "event: %s\n", name); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(w, "data: %s\n\n", data); err != nil {
+		return err
+	}
+	return w.Flush()
+}
@@ -0,0 +1,98 @@
+package chat
+
+import (
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/chat"
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/handlers"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/ai"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/text/extract"
+	"github.com/gofiber/fiber/v2"
+)
+
+// maxAttachments is the most files accepted with one message.
+const maxAttachments = 5
+
+// imageTypes are the attachment media types sent to the model as images
+// rather than extracted as text.
+var imageTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// parseRequest fills request from a JSON body or, for multipart/form-data,
+// from a "request" field holding the JSON request (or, for a single user
+// message, "message" and optional "conversation_id" fields) plus up to
+// maxAttachments "files". Documents are read with the extractor registered
+// for their media type; images are passed through as they are.
+func (h *Handler) parseRequest(c *fiber.Ctx, request *chat.ChatRequest) error {
+	if !strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEMultipartForm) {
+		return handlers.ParseBody(c, request)
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid multipart form")
+	}
+
+	if raw := c.FormValue("request"); raw != "" {
+		if err := handlers.DecodeJSON([]byte(raw), request); err != nil {
+			return err
+		}
+	} else {
+		request.ConversationID = c.FormValue("conversation_id")
+		request.Messages = []ai.Message{{Role: ai.RoleUser, Content: c.FormValue("message")}}
+	}
+
+	files := form.File["files"]
+	if len(files) > maxAttachments {
+		return fiber.NewError(fiber.StatusBadRequest, "Too many files")
+	}
+
+	for _, file := range files {
+		mediaType := extract.MediaType(file.Header.Get(fiber.HeaderContentType), file.Filename)
+		if _, ok := h.extractors.Lookup(mediaType); !ok && !imageTypes[mediaType] {
+			return fiber.NewError(fiber.StatusUnsupportedMediaType, "Unsupported file type: "+mediaType)
+		}
+
+		f, err := file.Open()
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid file")
+		}
+		data, err := io.ReadAll(f)
+		f.Close()
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid file")
+		}
+
+		attachment := chat.Attachment{Name: file.Filename, MediaType: mediaType}
+		if imageTypes[mediaType] {
+			attachment.Image = data
+		} else {
+			doc, err := h.extractors.Extract(mediaType, data)
+			if err != nil {
+				return fiber.NewError(fiber.StatusUnprocessableEntity, "Failed to read "+file.Filename+": "+err.Error())
+			}
+			attachment.Text = doc.Text
+		}
+		request.Attachments = append(request.Attachments, attachment)
+	}
+
+	return nil
+}
+
+// requestError writes a parseRequest error: upload failures carry their
+// own status, anything else is an invalid request.
+func requestError(c *fiber.Ctx, err error) error {
+	var uploadErr *fiber.Error
+	if errors.As(err, &uploadErr) {
+		return c.Status(uploadErr.Code).JSON(fiber.Map{
+			"error": uploadErr.Message,
+		})
+	}
+	return handlers.BadRequest(c, err)
+}
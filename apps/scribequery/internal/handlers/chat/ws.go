@@ -0,0 +1,202 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/chat"
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/handlers"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/ai"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/validate"
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxFrameSize bounds an incoming message.
+	maxFrameSize = 1 << 20
+
+	// wsContextKey holds the upgrade request's detached context in the
+	// connection's locals.
+	wsContextKey = "chat.ws.context"
+)
+
+// Websocket frame types. The client sends "chat" to start a generation and
+// "stop" to abort it; the server answers with "delta" frames followed by
+// "citations" (when the answer was grounded in documents), "usage" (when
+// reported) and "done", or by "stopped" or "error".
+const (
+	frameChat      = "chat"
+	frameStop      = "stop"
+	frameDelta     = "delta"
+	frameCitations = "citations"
+	frameUsage     = "usage"
+	frameDone      = "done"
+	frameStopped   = "stopped"
+	frameError     = "error"
+)
+
+// clientFrame is a client message; for "chat" frames it carries the
+// conversation in the same shape as a POST /api/chats body.
+type clientFrame struct {
+	Type string `json:"type"`
+	chat.ChatRequest
+}
+
+type serverFrame struct {
+	Type         string                 `json:"type"`
+	Delta        *ai.ChatStreamDelta    `json:"delta,omitempty"`
+	Citations    *streamCitations       `json:"citations,omitempty"`
+	Usage        *ai.ChatUsage          `json:"usage,omitempty"`
+	FinishReason string                 `json:"finish_reason,omitempty"`
+	RequestID    string                 `json:"request_id,omitempty"`
+	Error        string                 `json:"error,omitempty"`
+	Fields       []*validate.FieldError `json:"fields,omitempty"`
+}
+
+// chatWS upgrades the request to a websocket running a chat session.
+// Requests that are not websocket upgrades get 426.
+func (h *Handler) chatWS(c *fiber.Ctx) error {
+	if !websocket.IsWebSocketUpgrade(c) {
+		return fiber.ErrUpgradeRequired
+	}
+	c.Locals(wsContextKey, handlers.Detached(c))
+	return h.upgrade(c)
+}
+
+// wsSession runs at most one generation at a time for a connection.
+type wsSession struct {
+	h    *Handler
+	conn *websocket.Conn
+
+	// writeMu serializes writes, which the connection does not allow
+	// concurrently: the read loop and the generation both send frames.
+	writeMu sync.Mutex
+
+	mu   sync.Mutex
+	stop context.CancelFunc // cancels the running generation; nil when idle
+	wg   sync.WaitGroup
+}
+
+// serveConn runs the session on an upgraded connection.
+func (h *Handler) serveConn(conn *websocket.Conn) {
+	ctx, ok := conn.Locals(wsContextKey).(context.Context)
+	if !ok {
+		ctx = context.Background()
+	}
+	h.serveWS(ctx, conn)
+}
+
+// serveWS runs the session until the connection closes; ctx carries the
+// upgrade request's user.
+func (h *Handler) serveWS(ctx context.Context, conn *websocket.Conn) {
+	ctx, cancel := context.WithCancel(ctx)
+	session := &wsSession{h: h, conn: conn}
+	defer func() {
+		cancel()
+		session.wg.Wait()
+	}()
+	conn.SetReadLimit(maxFrameSize)
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			if !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				h.env.Logger.Debug("Chat websocket read failed", zap.Error(err))
+			}
+			return
+		}
+
+		var frame clientFrame
+		if err := json.Unmarshal(data, &frame); err != nil {
+			session.send(serverFrame{Type: frameError, Error: "Invalid frame"})
+			continue
+		}
+
+		switch frame.Type {
+		case frameChat:
+			if err := frame.Validate(); err != nil {
+				session.send(serverFrame{Type: frameError, Error: err.Error(), Fields: validate.Fields(err)})
+				continue
+			}
+			if !session.start(ctx, &frame.ChatRequest) {
+				session.send(serverFrame{Type: frameError, Error: "A generation is already in progress"})
+			}
+		case frameStop:
+			session.abort()
+		default:
+			session.send(serverFrame{Type: frameError, Error: "Unknown frame type"})
+		}
+	}
+}
+
+// start begins a generation unless one is already running.
+func (s *wsSession) start(ctx context.Context, req *chat.ChatRequest) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop != nil {
+		return false
+	}
+
+	genCtx, stop := context.WithCancel(ctx)
+	s.stop = stop
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer s.finish()
+		s.generate(genCtx, req)
+	}()
+	return true
+}
+
+func (s *wsSession) abort() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop != nil {
+		s.stop()
+	}
+}
+
+func (s *wsSession) finish() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stop()
+	s.stop = nil
+}
+
+func (s *wsSession) generate(ctx context.Context, req *chat.ChatRequest) {
+	response, err := s.h.service.ChatStream(ctx, req, func(delta ai.ChatStreamDelta) error {
+		return s.send(serverFrame{Type: frameDelta, Delta: &delta})
+	})
+
+	switch {
+	case ctx.Err() != nil, errors.Is(err, chat.ErrGenerationStopped):
+		s.send(serverFrame{Type: frameStopped})
+	case handlers.IsInvalid(err):
+		s.send(serverFrame{Type: frameError, Error: err.Error(), Fields: validate.Fields(err)})
+	case err != nil:
+		s.h.env.Logger.Error("Chat websocket stream failed", zap.Error(err))
+		s.send(serverFrame{Type: frameError, Error: err.Error()})
+	default:
+		if len(response.Citations) > 0 {
+			s.send(serverFrame{Type: frameCitations, Citations: citationsOf(&response)})
+		}
+		if response.Usage != (ai.ChatUsage{}) {
+			s.send(serverFrame{Type: frameUsage, Usage: &response.Usage})
+		}
+		s.send(serverFrame{Type: frameDone, FinishReason: response.FinishReason, RequestID: response.RequestID})
+	}
+}
+
+func (s *wsSession) send(frame serverFrame) error {
+	data, err := json.Marshal(frame)
+	if err != nil {
+		return err
+	}
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	return s.conn.WriteMessage(websocket.TextMessage, data)
+}
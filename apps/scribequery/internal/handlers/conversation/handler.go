@@ -0,0 +1,277 @@
+package conversation
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/conversation"
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/ratelimit"
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/handlers"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/auth"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/pagination"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/validate"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	service conversation.Service
+	env     *handlers.Environment
+}
+
+func (h *Handler) Init(basePath string, env *handlers.Environment) error {
+	h.env = env
+	h.service = env.Services.ConversationService
+
+	group := env.Fiber.Group(basePath + "/conversations")
+
+	read, write := auth.Require(auth.PermRead), auth.Require(auth.PermWrite)
+	limit := handlers.RateLimit(env, ratelimit.ClassList)
+
+	group.Post("/", write, h.create)
+	group.Get("/", read, limit, h.list)
+	group.Get("/search", read, limit, h.search)
+	group.Get("/:id", read, h.get)
+	group.Get("/:id/messages", read, limit, h.messages)
+	group.Patch("/:id", write, h.rename)
+	group.Get("/:id/system-prompt", read, h.getSystemPrompt)
+	group.Put("/:id/system-prompt", auth.Require(auth.PermManage), h.setSystemPrompt)
+	group.Delete("/:id", write, h.delete)
+	group.Post("/:id/archive", write, h.archive)
+	group.Post("/:id/unarchive", write, h.unarchive)
+	group.Post("/:id/restore", write, h.restore)
+
+	return nil
+}
+
+func (h *Handler) create(c *fiber.Ctx) error {
+	var request conversation.CreateRequest
+	if len(c.Body()) > 0 {
+		if err := handlers.ParseBody(c, &request); err != nil {
+			return handlers.BadRequest(c, err)
+		}
+	}
+	// System prompts are managed by admins, like the default one.
+	if request.SystemPrompt != "" && !auth.Can(c.Context(), auth.PermManage) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": auth.ErrForbidden.Error(),
+		})
+	}
+
+	result, err := h.service.Create(c.Context(), &request)
+	if err != nil {
+		return h.error(c, err, "Failed to create conversation")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(result)
+}
+
+// list returns conversations, most recently updated first, a page at a
+// time: "limit" sets the page size and "cursor" takes the previous page's
+// "next_cursor". "status" selects active (the default), archived, all or
+// deleted conversations.
+func (h *Handler) list(c *fiber.Ctx) error {
+	result, err := h.service.List(c.Context(), &conversation.ListRequest{
+		Status: c.Query("status"),
+		Page:   pageRequest(c),
+	})
+	if err != nil {
+		return h.error(c, err, "Failed to list conversations")
+	}
+
+	return c.JSON(result)
+}
+
+// messages returns the conversation's messages, oldest first, paged like
+// list.
+func (h *Handler) messages(c *fiber.Ctx) error {
+	result, err := h.service.Messages(c.Context(), c.Params("id"), pageRequest(c))
+	if err != nil {
+		return h.error(c, err, "Failed to list messages")
+	}
+
+	return c.JSON(result)
+}
+
+func pageRequest(c *fiber.Ctx) pagination.Request {
+	return pagination.Request{
+		Limit:  c.QueryInt("limit"),
+		Cursor: c.Query("cursor"),
+	}
+}
+
+// search takes "q", optional "from" and "to" (RFC 3339 or YYYY-MM-DD; a
+// bare "to" date includes that whole day), and "limit" and "offset".
+func (h *Handler) search(c *fiber.Ctx) error {
+	request := conversation.SearchRequest{
+		Query:  c.Query("q"),
+		Limit:  c.QueryInt("limit"),
+		Offset: c.QueryInt("offset"),
+	}
+
+	var (
+		errs validate.Errors
+		err  error
+	)
+	if request.From, err = parseDate(c.Query("from"), false); err != nil {
+		errs.Add("from", errInvalidDate)
+	}
+	if request.To, err = parseDate(c.Query("to"), true); err != nil {
+		errs.Add("to", errInvalidDate)
+	}
+	if err := errs.Err(); err != nil {
+		return handlers.BadRequest(c, err)
+	}
+
+	result, err := h.service.Search(c.Context(), &request)
+	if err != nil {
+		return h.error(c, err, "Failed to search conversations")
+	}
+
+	return c.JSON(result)
+}
+
+var errInvalidDate = errors.New("must be an RFC 3339 time or a YYYY-MM-DD date")
+
+// parseDate parses an RFC 3339 timestamp or a YYYY-MM-DD date; with
+// endOfDay a date means the last instant of that day. Empty is zero.
+func parseDate(value string, endOfDay bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return t, nil
+}
+
+func (h *Handler) get(c *fiber.Ctx) error {
+	result, err := h.service.Get(c.Context(), c.Params("id"))
+	if err != nil {
+		return h.error(c, err, "Failed to get conversation")
+	}
+
+	return c.JSON(result)
+}
+
+func (h *Handler) rename(c *fiber.Ctx) error {
+	var request conversation.RenameRequest
+	if err := handlers.ParseBody(c, &request); err != nil {
+		return handlers.BadRequest(c, err)
+	}
+
+	result, err := h.service.Rename(c.Context(), c.Params("id"), &request)
+	if err != nil {
+		return h.error(c, err, "Failed to rename conversation")
+	}
+
+	return c.JSON(result)
+}
+
+// getSystemPrompt returns the prompt the conversation's turns are sent
+// with: its own, or the configured default when it has none ("custom" is
+// false).
+func (h *Handler) getSystemPrompt(c *fiber.Ctx) error {
+	result, err := h.service.Get(c.Context(), c.Params("id"))
+	if err != nil {
+		return h.error(c, err, "Failed to get system prompt")
+	}
+
+	return c.JSON(h.systemPrompt(&result.Conversation))
+}
+
+// setSystemPrompt replaces the conversation's system prompt; an empty
+// "system_prompt" restores the default.
+func (h *Handler) setSystemPrompt(c *fiber.Ctx) error {
+	var request conversation.SystemPromptRequest
+	if err := handlers.ParseBody(c, &request); err != nil {
+		return handlers.BadRequest(c, err)
+	}
+
+	result, err := h.service.SetSystemPrompt(c.Context(), c.Params("id"), &request)
+	if err != nil {
+		return h.error(c, err, "Failed to set system prompt")
+	}
+
+	return c.JSON(h.systemPrompt(result))
+}
+
+func (h *Handler) systemPrompt(conv *conversation.Conversation) fiber.Map {
+	if conv.SystemPrompt == "" {
+		return fiber.Map{
+			"system_prompt": h.env.Config.SystemPrompt,
+			"custom":        false,
+		}
+	}
+	return fiber.Map{
+		"system_prompt": conv.SystemPrompt,
+		"custom":        true,
+	}
+}
+
+// delete hides the conversation; it can be restored until it is purged
+// (CONVERSATION_PURGE_DAYS later).
+func (h *Handler) delete(c *fiber.Ctx) error {
+	if err := h.service.Delete(c.Context(), c.Params("id")); err != nil {
+		return h.error(c, err, "Failed to delete conversation")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *Handler) archive(c *fiber.Ctx) error {
+	result, err := h.service.Archive(c.Context(), c.Params("id"))
+	if err != nil {
+		return h.error(c, err, "Failed to archive conversation")
+	}
+
+	return c.JSON(result)
+}
+
+func (h *Handler) unarchive(c *fiber.Ctx) error {
+	result, err := h.service.Unarchive(c.Context(), c.Params("id"))
+	if err != nil {
+		return h.error(c, err, "Failed to unarchive conversation")
+	}
+
+	return c.JSON(result)
+}
+
+// restore undoes a delete that has not been purged yet.
+func (h *Handler) restore(c *fiber.Ctx) error {
+	result, err := h.service.Restore(c.Context(), c.Params("id"))
+	if err != nil {
+		return h.error(c, err, "Failed to restore conversation")
+	}
+
+	return c.JSON(result)
+}
+
+// error maps domain errors to status codes; anything else is logged and
+// reported as a 500 with message.
+func (h *Handler) error(c *fiber.Ctx, err error, message string) error {
+	switch {
+	case errors.Is(err, conversation.ErrConversationNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case handlers.IsInvalid(err):
+		return handlers.BadRequest(c, err)
+	case errors.Is(err, conversation.ErrModelsUnavailable):
+		return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	h.env.Logger.Error(message, zap.Error(err))
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": message,
+	})
+}
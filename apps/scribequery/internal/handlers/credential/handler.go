@@ -0,0 +1,93 @@
+package credential
+
+import (
+	"errors"
+
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/credential"
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/ratelimit"
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/handlers"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/auth"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	service credential.Service
+	env     *handlers.Environment
+}
+
+// Init registers the routes managing users' own provider keys. Responses
+// carry a key's hint, never the key.
+func (h *Handler) Init(basePath string, env *handlers.Environment) error {
+	h.env = env
+	h.service = env.Services.CredentialService
+
+	group := env.Fiber.Group(basePath + "/credentials")
+
+	group.Get("/", auth.Require(auth.PermWrite), handlers.RateLimit(env, ratelimit.ClassList), h.list)
+	group.Put("/:provider", auth.Require(auth.PermWrite), h.set)
+	group.Delete("/:id", auth.Require(auth.PermWrite), h.delete)
+
+	return nil
+}
+
+// list returns the caller's keys, or with ?all=true every key of their
+// tenant (admins only).
+func (h *Handler) list(c *fiber.Ctx) error {
+	result, err := h.service.List(c.Context(), c.QueryBool("all"))
+	if err != nil {
+		return h.error(c, err, "Failed to list provider keys")
+	}
+
+	return c.JSON(fiber.Map{
+		"credentials": result,
+	})
+}
+
+func (h *Handler) set(c *fiber.Ctx) error {
+	var request credential.SetRequest
+	if err := handlers.ParseBody(c, &request); err != nil {
+		return handlers.BadRequest(c, err)
+	}
+	request.Provider = c.Params("provider")
+
+	result, err := h.service.Set(c.Context(), &request)
+	if err != nil {
+		return h.error(c, err, "Failed to store provider key")
+	}
+
+	return c.JSON(result)
+}
+
+func (h *Handler) delete(c *fiber.Ctx) error {
+	if err := h.service.Delete(c.Context(), c.Params("id")); err != nil {
+		return h.error(c, err, "Failed to delete provider key")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *Handler) error(c *fiber.Ctx, err error, message string) error {
+	switch {
+	case errors.Is(err, credential.ErrUnsupportedProvider), errors.Is(err, credential.ErrAPIKeyMissing),
+		errors.Is(err, credential.ErrAPIKeyInvalid):
+		return handlers.BadRequest(c, err)
+	case errors.Is(err, credential.ErrCredentialNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, credential.ErrUserRequired), errors.Is(err, auth.ErrForbidden):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, credential.ErrEncryptionDisabled):
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	h.env.Logger.Error(message, zap.Error(err))
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": message,
+	})
+}
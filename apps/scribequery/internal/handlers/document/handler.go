@@ -0,0 +1,141 @@
+package document
+
+import (
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/document"
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/handlers"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/auth"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/web"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/text/extract"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	service    document.Service
+	extractors *extract.Registry
+	env        *handlers.Environment
+}
+
+func (h *Handler) Init(basePath string, env *handlers.Environment) error {
+	h.env = env
+	h.service = env.Services.DocumentService
+	h.extractors = extract.NewRegistry()
+
+	group := env.Fiber.Group(basePath+"/documents", auth.Require(auth.PermWrite))
+
+	group.Post("/", h.ingest)
+	group.Post("/url", h.ingestURL)
+
+	return nil
+}
+
+// ingest accepts either a JSON document ({"text": ...}) or a multipart upload
+// with a "file" field (text, Markdown, HTML, PDF or DOCX) and optional
+// "title", "source" and "strategy" fields.
+func (h *Handler) ingest(c *fiber.Ctx) error {
+	var request document.IngestRequest
+
+	if strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEMultipartForm) {
+		if err := h.parseUpload(c, &request); err != nil {
+			return c.Status(err.Code).JSON(fiber.Map{
+				"error": err.Message,
+			})
+		}
+	} else if err := handlers.ParseBody(c, &request); err != nil {
+		return handlers.BadRequest(c, err)
+	}
+
+	result, err := h.service.Ingest(c.Context(), &request)
+	if err != nil {
+		switch {
+		case errors.Is(err, document.ErrEmptyDocument), errors.Is(err, document.ErrInvalidChunkConfig):
+			return handlers.BadRequest(c, err)
+		case errors.Is(err, document.ErrIngestionDisabled):
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		h.env.Logger.Error("Failed to ingest document", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to ingest document",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(result)
+}
+
+// ingestURL fetches a web page (and, with max_depth, same-site pages it links
+// to) and ingests its main content.
+func (h *Handler) ingestURL(c *fiber.Ctx) error {
+	var request document.IngestURLRequest
+	if err := handlers.ParseBody(c, &request); err != nil {
+		return handlers.BadRequest(c, err)
+	}
+
+	result, err := h.service.IngestURL(c.Context(), &request)
+	if err != nil {
+		switch {
+		case errors.Is(err, web.ErrInvalidURL), errors.Is(err, document.ErrInvalidCrawlDepth),
+			errors.Is(err, document.ErrInvalidChunkConfig):
+			return handlers.BadRequest(c, err)
+		case errors.Is(err, web.ErrDisallowed), errors.Is(err, web.ErrForbiddenHost):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, web.ErrFetchFailed), errors.Is(err, extract.ErrUnsupportedType):
+			return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, document.ErrIngestionDisabled):
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		h.env.Logger.Error("Failed to ingest url", zap.String("url", request.URL), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to ingest url",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(result)
+}
+
+// parseUpload fills request from a multipart upload, extracting text with
+// the extractor registered for the file's media type.
+func (h *Handler) parseUpload(c *fiber.Ctx, request *document.IngestRequest) *fiber.Error {
+	file, err := c.FormFile("file")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Missing file")
+	}
+
+	mediaType := extract.MediaType(file.Header.Get(fiber.HeaderContentType), file.Filename)
+	if _, ok := h.extractors.Lookup(mediaType); !ok {
+		return fiber.NewError(fiber.StatusUnsupportedMediaType, "Unsupported file type: "+mediaType)
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid file")
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid file")
+	}
+
+	doc, err := h.extractors.Extract(mediaType, data)
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, "Failed to read file: "+err.Error())
+	}
+
+	*request = *document.NewIngestRequest(doc)
+	request.Title = c.FormValue("title", doc.Title)
+	request.Source = c.FormValue("source", file.Filename)
+	request.Strategy = c.FormValue("strategy")
+	return nil
+}
@@ -0,0 +1,75 @@
+package feedback
+
+import (
+	"errors"
+
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/conversation"
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/feedback"
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/ratelimit"
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/handlers"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/auth"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	service feedback.Service
+	env     *handlers.Environment
+}
+
+func (h *Handler) Init(basePath string, env *handlers.Environment) error {
+	h.env = env
+	h.service = env.Services.FeedbackService
+
+	env.Fiber.Post(basePath+"/messages/:id/feedback", auth.Require(auth.PermWrite), h.submit)
+	env.Fiber.Get(basePath+"/feedback", auth.Require(auth.PermRead), handlers.RateLimit(env, ratelimit.ClassList), h.list)
+
+	return nil
+}
+
+func (h *Handler) submit(c *fiber.Ctx) error {
+	var request feedback.SubmitRequest
+	if err := handlers.ParseBody(c, &request); err != nil {
+		return handlers.BadRequest(c, err)
+	}
+	request.MessageID = c.Params("id")
+
+	result, err := h.service.Submit(c.Context(), &request)
+	if err != nil {
+		switch {
+		case errors.Is(err, feedback.ErrInvalidRating), errors.Is(err, feedback.ErrInvalidCategory),
+			errors.Is(err, feedback.ErrCommentTooLong), errors.Is(err, feedback.ErrNotAnswer):
+			return handlers.BadRequest(c, err)
+		case errors.Is(err, conversation.ErrMessageNotFound), errors.Is(err, conversation.ErrConversationNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		h.env.Logger.Error("Failed to record feedback", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to record feedback",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(result)
+}
+
+// list returns recorded feedback, filtered by the optional "rating",
+// "category" and "model" query parameters.
+func (h *Handler) list(c *fiber.Ctx) error {
+	result, err := h.service.List(c.Context(), feedback.ListFilter{
+		Rating:   c.Query("rating"),
+		Category: c.Query("category"),
+		Model:    c.Query("model"),
+	})
+	if err != nil {
+		h.env.Logger.Error("Failed to list feedback", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to list feedback",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"feedback": result,
+	})
+}
@@ -1,12 +1,25 @@
 package handlers
 
 import (
+	"context"
+	"strings"
+	"time"
+
 	"github.com/Joepolymath/DaVinci/apps/scribequery/app"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/auth"
 	"github.com/Joepolymath/DaVinci/libs/shared-go/config"
 	"github.com/gofiber/fiber/v2"
 	"go.uber.org/zap"
 )
 
+// AuthPath, under the API base path, holds the sign-in routes. They are
+// the API routes that do not require a bearer token.
+const AuthPath = "/auth"
+
+// StreamWriteTimeout bounds writing a streamed response, which lasts as
+// long as its generation, in place of the server's write timeout.
+const StreamWriteTimeout = 15 * time.Minute
+
 type IHandler interface {
 	Init(string, *Environment) error
 }
@@ -17,6 +30,8 @@ type Environment struct {
 	Logger *zap.Logger
 
 	Services *app.Services
+
+	streams map[string]bool // paths of streamed routes
 }
 
 func NewEnvironment(cfg *config.Config, fiber *fiber.App, logger *zap.Logger, services *app.Services) *Environment {
@@ -27,3 +42,36 @@ func NewEnvironment(cfg *config.Config, fiber *fiber.App, logger *zap.Logger, se
 		Services: services,
 	}
 }
+
+// Stream marks path as a route that streams its response, so that it is
+// written under StreamWriteTimeout.
+func (e *Environment) Stream(path string) {
+	if e.streams == nil {
+		e.streams = make(map[string]bool)
+	}
+	e.streams[path] = true
+}
+
+// WriteTimeout is how long writing the response to a request for path may
+// take: StreamWriteTimeout for streamed routes, or zero for the server's
+// write timeout.
+func (e *Environment) WriteTimeout(path string) time.Duration {
+	if e.streams[strings.TrimSuffix(path, "/")] {
+		return StreamWriteTimeout
+	}
+	return 0
+}
+
+// Detached returns a context for work that outlives the request, such as a
+// stream written after the handler returns. It carries the request's api
+// key identity and user, and so its tenant, but not its cancellation.
+func Detached(c *fiber.Ctx) context.Context {
+	ctx := context.Background()
+	if id, ok := auth.FromContext(c.Context()); ok {
+		ctx = auth.WithIdentity(ctx, id)
+	}
+	if u, ok := auth.CurrentUser(c.Context()); ok {
+		ctx = auth.WithUser(ctx, u)
+	}
+	return ctx
+}
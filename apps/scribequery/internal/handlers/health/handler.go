@@ -0,0 +1,37 @@
+package health
+
+import (
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/health"
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/handlers"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Handler struct {
+	service health.Service
+	env     *handlers.Environment
+}
+
+// Init registers GET /health outside the API, where load balancers and
+// orchestrators probe it; basePath is not used.
+func (h *Handler) Init(basePath string, env *handlers.Environment) error {
+	h.env = env
+	h.service = env.Services.HealthService
+
+	env.Fiber.Get("/health", h.check)
+
+	return nil
+}
+
+// check reports every dependency's status and latency. It answers 503
+// when a critical dependency is down and 200 otherwise, including when
+// the service is degraded.
+func (h *Handler) check(c *fiber.Ctx) error {
+	report := h.service.Check(c.Context())
+
+	status := fiber.StatusOK
+	if report.Status == health.StatusDown {
+		status = fiber.StatusServiceUnavailable
+	}
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.Status(status).JSON(report)
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/idempotency"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/auth"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+const (
+	// IdempotencyKeyHeader names the client-chosen key that makes a POST
+	// safe to retry.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses replayed for a key.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+// Idempotent makes the routes it guards safe to retry: a request with an
+// Idempotency-Key header runs once, and retries with the same key and
+// body get the original response back instead of running again. Server
+// errors and conflicts are not stored, so their retries run again.
+// Requests without the header are not affected.
+func Idempotent(env *Environment) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get(IdempotencyKeyHeader)
+		service := env.Services.IdempotencyService
+		if key == "" || service == nil {
+			return c.Next()
+		}
+
+		ctx := c.Context()
+		replay, err := service.Begin(ctx, key, fingerprint(c))
+		if err != nil {
+			switch {
+			case errors.Is(err, idempotency.ErrInvalidKey):
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": err.Error(),
+				})
+			case errors.Is(err, idempotency.ErrRequestInProgress):
+				return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+					"error": err.Error(),
+				})
+			case errors.Is(err, idempotency.ErrKeyReused):
+				return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+					"error": err.Error(),
+				})
+			}
+			env.Logger.Error("Failed to check idempotency key", zap.Error(err))
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to check idempotency key",
+			})
+		}
+		if replay != nil {
+			c.Set(IdempotentReplayedHeader, "true")
+			c.Set(fiber.HeaderContentType, replay.ContentType)
+			return c.Status(replay.Status).Send(replay.Body)
+		}
+
+		err = c.Next()
+		status := c.Response().StatusCode()
+		if err != nil || status >= fiber.StatusInternalServerError || status == fiber.StatusConflict {
+			if releaseErr := service.Release(ctx, key); releaseErr != nil {
+				env.Logger.Error("Failed to release idempotency key", zap.Error(releaseErr))
+			}
+			return err
+		}
+
+		if err := service.Complete(ctx, key, idempotency.Response{
+			Status:      status,
+			ContentType: string(c.Response().Header.ContentType()),
+			Body:        bytes.Clone(c.Response().Body()),
+		}); err != nil {
+			env.Logger.Error("Failed to store idempotent response", zap.Error(err))
+		}
+		return nil
+	}
+}
+
+// fingerprint identifies the request by tenant, user, method, path and
+// body, so a key is only replayed for the request it was first used with
+// and never to another user or tenant.
+func fingerprint(c *fiber.Ctx) string {
+	h := sha256.New()
+	h.Write([]byte(auth.Tenant(c.Context())))
+	h.Write([]byte{0})
+	if u, ok := auth.CurrentUser(c.Context()); ok {
+		h.Write([]byte(u.ID))
+	}
+	h.Write([]byte{0})
+	h.Write([]byte(c.Method()))
+	h.Write([]byte{0})
+	h.Write([]byte(c.Path()))
+	h.Write([]byte{0})
+	h.Write(c.Body())
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,43 @@
+package provider
+
+import (
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/handlers"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Handler struct {
+	env *handlers.Environment
+}
+
+// Init registers the admin routes for the configured chat providers.
+// Their status includes provider errors, so only admins may read it.
+func (h *Handler) Init(basePath string, env *handlers.Environment) error {
+	h.env = env
+
+	group := env.Fiber.Group(basePath+"/providers", auth.Require(auth.PermManage))
+
+	group.Get("/", h.list)
+	group.Post("/check", h.check)
+
+	return nil
+}
+
+// list returns every provider's last known health, as the router sees it.
+func (h *Handler) list(c *fiber.Ctx) error {
+	return c.JSON(h.status())
+}
+
+// check probes every provider now instead of waiting for the next
+// scheduled check, e.g. after fixing a provider's credentials.
+func (h *Handler) check(c *fiber.Ctx) error {
+	h.env.Services.ProviderHealth.CheckNow(c.Context())
+	return c.JSON(h.status())
+}
+
+func (h *Handler) status() fiber.Map {
+	return fiber.Map{
+		"default":   h.env.Services.ChatProviders.DefaultName(),
+		"providers": h.env.Services.ProviderHealth.ProvidersStatus(),
+	}
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/ratelimit"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/auth"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+const (
+	// RateLimitHeader is the size of the caller's bucket for the route.
+	RateLimitHeader = "RateLimit-Limit"
+	// RateLimitRemainingHeader is how many requests the caller has left
+	// before being limited.
+	RateLimitRemainingHeader = "RateLimit-Remaining"
+)
+
+// RateLimit limits the routes it guards to class's limit per caller:
+// the signed-in user, else the api key, else the client IP. ClassAuth is
+// always limited by IP, since its callers have not signed in yet.
+// Limited requests get 429 with a Retry-After header. If the limiter
+// fails, requests are let through rather than taking the API down.
+func RateLimit(env *Environment, class ratelimit.Class) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		service := env.Services.RateLimiter
+		if service == nil {
+			return c.Next()
+		}
+
+		decision, err := service.Allow(c.Context(), class, caller(c, class))
+		if err != nil {
+			env.Logger.Error("Failed to check rate limit", zap.String("class", string(class)), zap.Error(err))
+			return c.Next()
+		}
+		if decision.Limit > 0 {
+			c.Set(RateLimitHeader, strconv.Itoa(decision.Limit))
+			c.Set(RateLimitRemainingHeader, strconv.Itoa(decision.Remaining))
+		}
+		if !decision.Allowed {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(decision.RetryAfter.Seconds()))))
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"error": "Too many requests",
+			})
+		}
+		return c.Next()
+	}
+}
+
+// caller names the bucket a request draws from.
+func caller(c *fiber.Ctx, class ratelimit.Class) string {
+	if class != ratelimit.ClassAuth {
+		if u, ok := auth.CurrentUser(c.Context()); ok {
+			return "user:" + u.ID
+		}
+		if id, ok := auth.FromContext(c.Context()); ok {
+			return "key:" + id.KeyID
+		}
+	}
+	return "ip:" + c.IP()
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+
+	"github.com/Joepolymath/DaVinci/libs/shared-go/validate"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ErrInvalidBody is wrapped by ParseBody errors.
+var ErrInvalidBody = errors.New("invalid request body")
+
+// ParseBody decodes the request body into out. A value of the wrong type
+// is reported against its field, e.g. {"field": "options.temperature",
+// "message": "must be a number"}.
+func ParseBody(c *fiber.Ctx, out any) error {
+	if err := c.BodyParser(out); err != nil {
+		return decodeError(err)
+	}
+	return nil
+}
+
+// DecodeJSON decodes data into out, reporting errors as ParseBody does.
+func DecodeJSON(data []byte, out any) error {
+	if err := json.Unmarshal(data, out); err != nil {
+		return decodeError(err)
+	}
+	return nil
+}
+
+// BadRequest writes err as a 400 response. Field errors are listed under
+// "fields" so clients can show them next to the inputs they concern.
+func BadRequest(c *fiber.Ctx, err error) error {
+	message := err.Error()
+	if errors.Is(err, ErrInvalidBody) {
+		message = "Invalid request body"
+	}
+
+	body := fiber.Map{"error": message}
+	if fields := validate.Fields(err); len(fields) > 0 {
+		body["fields"] = fields
+	}
+	return c.Status(fiber.StatusBadRequest).JSON(body)
+}
+
+// IsInvalid reports whether err holds field validation errors.
+func IsInvalid(err error) bool {
+	return len(validate.Fields(err)) > 0
+}
+
+func decodeError(err error) error {
+	var (
+		typeErr   *json.UnmarshalTypeError
+		syntaxErr *json.SyntaxError
+		message   string
+		field     string
+	)
+	switch {
+	case errors.As(err, &typeErr):
+		field, message = typeErr.Field, "must be "+describe(typeErr.Type)
+	case errors.As(err, &syntaxErr):
+		message = "malformed JSON at offset " + strconv.FormatInt(syntaxErr.Offset, 10)
+	case errors.Is(err, fiber.ErrUnprocessableEntity):
+		message = "unsupported content type"
+	default:
+		message = err.Error()
+	}
+	return validate.Errors{{Field: field, Message: message, Err: ErrInvalidBody}}
+}
+
+// describe names the JSON type a Go type is decoded from.
+func describe(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Pointer:
+		return describe(t.Elem())
+	default:
+		return "an object"
+	}
+}
@@ -0,0 +1,56 @@
+package retrieval
+
+import (
+	"errors"
+
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/ratelimit"
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/retrieval"
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/handlers"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/auth"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	service retrieval.Service
+	env     *handlers.Environment
+}
+
+func (h *Handler) Init(basePath string, env *handlers.Environment) error {
+	h.env = env
+	h.service = env.Services.RetrievalService
+
+	group := env.Fiber.Group(basePath+"/search", auth.Require(auth.PermRead), handlers.RateLimit(env, ratelimit.ClassList))
+
+	group.Post("/", h.search)
+
+	return nil
+}
+
+// search returns the chunks most relevant to a query. Reranking can be
+// switched per request with "rerank".
+func (h *Handler) search(c *fiber.Ctx) error {
+	var request retrieval.RetrieveRequest
+	if err := handlers.ParseBody(c, &request); err != nil {
+		return handlers.BadRequest(c, err)
+	}
+
+	result, err := h.service.Retrieve(c.Context(), &request)
+	if err != nil {
+		switch {
+		case errors.Is(err, retrieval.ErrEmptyQuery), errors.Is(err, retrieval.ErrRerankUnavailable),
+			errors.Is(err, retrieval.ErrExpandUnavailable):
+			return handlers.BadRequest(c, err)
+		case errors.Is(err, retrieval.ErrRetrievalDisabled):
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		h.env.Logger.Error("Failed to search documents", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to search documents",
+		})
+	}
+
+	return c.JSON(result)
+}
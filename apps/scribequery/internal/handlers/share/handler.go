@@ -0,0 +1,161 @@
+package share
+
+import (
+	"errors"
+	"html/template"
+	"strings"
+
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/conversation"
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/ratelimit"
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/domain/share"
+	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/handlers"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/auth"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	service share.Service
+	env     *handlers.Environment
+}
+
+// Init registers the share management routes under basePath and the
+// public transcript route at /share/:token, outside the API.
+func (h *Handler) Init(basePath string, env *handlers.Environment) error {
+	h.env = env
+	h.service = env.Services.ShareService
+
+	group := env.Fiber.Group(basePath + "/conversations/:id")
+
+	group.Post("/share", auth.Require(auth.PermWrite), h.create)
+	group.Get("/shares", auth.Require(auth.PermRead), handlers.RateLimit(env, ratelimit.ClassList), h.list)
+	group.Delete("/shares/:shareID", auth.Require(auth.PermWrite), h.revoke)
+
+	env.Fiber.Get("/share/:token", h.view)
+
+	return nil
+}
+
+func (h *Handler) create(c *fiber.Ctx) error {
+	var request share.CreateRequest
+	if len(c.Body()) > 0 {
+		if err := handlers.ParseBody(c, &request); err != nil {
+			return handlers.BadRequest(c, err)
+		}
+	}
+	request.ConversationID = c.Params("id")
+
+	result, err := h.service.Create(c.Context(), &request)
+	if err != nil {
+		return h.error(c, err, "Failed to share conversation")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"share": result.Share,
+		"token": result.Token,
+		"url":   c.BaseURL() + "/share/" + result.Token,
+	})
+}
+
+func (h *Handler) list(c *fiber.Ctx) error {
+	result, err := h.service.List(c.Context(), c.Params("id"))
+	if err != nil {
+		return h.error(c, err, "Failed to list shares")
+	}
+
+	return c.JSON(fiber.Map{
+		"shares": result,
+	})
+}
+
+func (h *Handler) revoke(c *fiber.Ctx) error {
+	result, err := h.service.Revoke(c.Context(), c.Params("id"), c.Params("shareID"))
+	if err != nil {
+		return h.error(c, err, "Failed to revoke share")
+	}
+
+	return c.JSON(result)
+}
+
+// view renders the shared transcript as HTML, or as JSON when the client
+// asks for it with ?format=json or an Accept header preferring JSON.
+func (h *Handler) view(c *fiber.Ctx) error {
+	asJSON := c.Query("format") == "json" || c.Accepts(fiber.MIMETextHTML, fiber.MIMEApplicationJSON) == fiber.MIMEApplicationJSON
+
+	transcript, err := h.service.Resolve(c.Context(), c.Params("token"))
+	if err != nil {
+		status := fiber.StatusInternalServerError
+		message := "Failed to load shared conversation"
+		switch {
+		case errors.Is(err, share.ErrInvalidToken), errors.Is(err, share.ErrShareNotFound),
+			errors.Is(err, conversation.ErrConversationNotFound):
+			status, message = fiber.StatusNotFound, "Shared conversation not found"
+		case errors.Is(err, share.ErrShareRevoked), errors.Is(err, share.ErrShareExpired):
+			status, message = fiber.StatusGone, err.Error()
+		default:
+			h.env.Logger.Error(message, zap.Error(err))
+		}
+
+		if asJSON {
+			return c.Status(status).JSON(fiber.Map{"error": message})
+		}
+		return c.Status(status).SendString(message)
+	}
+
+	c.Set("Cache-Control", "no-store")
+	c.Set("X-Robots-Tag", "noindex")
+	if asJSON {
+		return c.JSON(transcript)
+	}
+
+	var page strings.Builder
+	if err := transcriptPage.Execute(&page, transcript); err != nil {
+		h.env.Logger.Error("Failed to render shared conversation", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to render shared conversation")
+	}
+	c.Type("html", "utf-8")
+	return c.SendString(page.String())
+}
+
+func (h *Handler) error(c *fiber.Ctx, err error, message string) error {
+	switch {
+	case errors.Is(err, conversation.ErrConversationNotFound), errors.Is(err, share.ErrShareNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, share.ErrInvalidExpiry):
+		return handlers.BadRequest(c, err)
+	}
+
+	h.env.Logger.Error(message, zap.Error(err))
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": message,
+	})
+}
+
+var transcriptPage = template.Must(template.New("transcript").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>{{if .Title}}{{.Title}}{{else}}Shared conversation{{end}}</title>
+<style>
+body { font-family: system-ui, sans-serif; max-width: 46rem; margin: 2rem auto; padding: 0 1rem; color: #1f2328; }
+header { border-bottom: 1px solid #d0d7de; margin-bottom: 1.5rem; }
+.message { margin: 1rem 0; padding: 0.75rem 1rem; border-radius: 8px; white-space: pre-wrap; }
+.user { background: #ddf4ff; }
+.assistant { background: #f6f8fa; }
+.system { background: #fff8c5; }
+.role { font-size: 0.75rem; font-weight: 600; text-transform: uppercase; color: #656d76; }
+</style>
+</head>
+<body>
+<header>
+<h1>{{if .Title}}{{.Title}}{{else}}Shared conversation{{end}}</h1>
+<p>Shared {{.SharedAt.Format "2 Jan 2006"}} · read-only</p>
+</header>
+{{range .Messages}}<div class="message {{.Role}}"><div class="role">{{.Role}}</div>{{.Content}}</div>
+{{end}}</body>
+</html>
+`))
@@ -1,17 +1,21 @@
 package router
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/Joepolymath/DaVinci/apps/scribequery/internal/handlers"
+	"github.com/Joepolymath/DaVinci/libs/shared-go/auth"
 	"github.com/Joepolymath/DaVinci/libs/shared-go/config"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/valyala/fasthttp"
 )
 
 func InitRouterWithConfig(cfg *config.Config) *fiber.App {
@@ -28,9 +32,9 @@ func InitRouterWithConfig(cfg *config.Config) *fiber.App {
 
 	app.Use(cors.New(cors.Config{
 		AllowOrigins:  origins,
-		AllowMethods:  "GET,POST,PUT,DELETE,OPTIONS",
-		AllowHeaders:  "Origin, Content-Type, Accept, Authorization",
-		ExposeHeaders: "Content-Length",
+		AllowMethods:  "GET,POST,PUT,PATCH,DELETE,OPTIONS",
+		AllowHeaders:  "Origin, Content-Type, Accept, Authorization, " + auth.APIKeyHeader + ", " + handlers.IdempotencyKeyHeader,
+		ExposeHeaders: "Content-Length, Retry-After, " + handlers.IdempotentReplayedHeader + ", " + handlers.RateLimitHeader + ", " + handlers.RateLimitRemainingHeader,
 		MaxAge:        300,
 	}))
 
@@ -61,7 +65,29 @@ func RunWithGracefulShutdown(app *fiber.App, cfg *config.Config) error {
 	return nil
 }
 
+// authPrefix holds the sign-in routes, which need no bearer token.
+var authPrefix = "/api" + handlers.AuthPath + "/"
+
+// ErrNoAPIKeys is returned by InitHandlers when there are no api keys to
+// check and AUTH_DISABLED is not "true".
+var ErrNoAPIKeys = errors.New("API_KEYS_FILE is not set; set AUTH_DISABLED=true to serve /api without api keys")
+
 func InitHandlers(env *handlers.Environment, handlers []handlers.IHandler) error {
+	// Registered before the routes so that they run first; routes outside
+	// /api, such as /health and shared conversations, stay public. With
+	// both, the api key identifies the client application and the token
+	// the user it acts for.
+	switch {
+	case env.Services.APIKeys != nil:
+		env.Fiber.Use("/api", auth.APIKeyAuth(env.Services.APIKeys, env.Logger))
+	case env.Config.AuthDisabled == "true":
+		env.Logger.Warn("AUTH_DISABLED is set; /api routes do not require an api key")
+	default:
+		return ErrNoAPIKeys
+	}
+	if env.Services.JWTVerifier != nil {
+		env.Fiber.Use("/api", except(authPrefix, auth.JWTAuth(env.Services.JWTVerifier, env.Logger)))
+	}
 
 	for _, handler := range handlers {
 		if err := handler.Init("/api", env); err != nil {
@@ -69,5 +95,23 @@ func InitHandlers(env *handlers.Environment, handlers []handlers.IHandler) error
 		}
 	}
 
+	// fasthttp sets one write deadline for the whole response, which a
+	// streamed answer outlasts; streamed routes get a longer one.
+	env.Fiber.Server().HeaderReceived = func(header *fasthttp.RequestHeader) fasthttp.RequestConfig {
+		path, _, _ := strings.Cut(string(header.RequestURI()), "?")
+		return fasthttp.RequestConfig{WriteTimeout: env.WriteTimeout(path)}
+	}
+
 	return nil
 }
+
+// except runs h for every request except those under prefix, which go
+// straight to the next handler.
+func except(prefix string, h fiber.Handler) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if strings.HasPrefix(c.Path(), prefix) {
+			return c.Next()
+		}
+		return h(c)
+	}
+}
@@ -62,6 +62,10 @@ interface ChatStreamDelta {
   finish_reason?: string
 }
 
+interface StreamCitations {
+  content: string
+}
+
 export default function App() {
   const [messages, setMessages] = useState<Message[]>([])
   const [prompt, setPrompt] = useState('')
@@ -90,6 +94,8 @@ export default function App() {
     if (!prompt.trim() || isStreaming) return
 
     const content = prompt.trim()
+    // The server validates every message, so drop empty (aborted) replies.
+    const history = [...messages.filter(m => m.content), { role: 'user', content }]
     setPrompt('')
     setMessages(prev => [
       ...prev,
@@ -103,7 +109,7 @@ export default function App() {
       res = await fetch('http://localhost:8094/api/chats/stream', {
         method: 'POST',
         headers: { 'Content-Type': 'application/json' },
-        body: JSON.stringify({ role: 'user', content }),
+        body: JSON.stringify({ messages: history }),
       })
     } catch {
       updateLastMessage(() => 'Error: Could not reach the server.')
@@ -120,6 +126,7 @@ export default function App() {
     const reader = res.body.getReader()
     const decoder = new TextDecoder()
     let buffer = ''
+    let event = ''
     let streamDone = false
 
     abortRef.current = () => reader.cancel()
@@ -134,14 +141,34 @@ export default function App() {
         buffer = lines.pop() ?? ''
 
         for (const line of lines) {
+          if (line === '') {
+            event = ''
+            continue
+          }
+          if (line.startsWith('event: ')) {
+            event = line.slice(7).trim()
+            continue
+          }
           if (!line.startsWith('data: ')) continue
           const data = line.slice(6).trim()
-          if (data === '[DONE]') {
+          if (event === 'done' || event === 'stopped') {
             streamDone = true
             break
           }
           try {
-            const delta: ChatStreamDelta = JSON.parse(data)
+            const payload = JSON.parse(data)
+            if (event === 'error') {
+              updateLastMessage(prev => prev + `\n\nError: ${payload.error}`)
+              streamDone = true
+              break
+            }
+            if (event === 'citations') {
+              // The grounded answer, with source numbers rewritten as footnotes.
+              updateLastMessage(() => (payload as StreamCitations).content)
+              continue
+            }
+            if (event !== '') continue
+            const delta: ChatStreamDelta = payload
             if (delta.content) {
               updateLastMessage(prev => prev + delta.content)
             }
@@ -0,0 +1,93 @@
+// Package auth authenticates API requests. APIKeyAuth identifies the
+// calling application by an X-API-Key header, checked against stored
+// SHA-256 hashes, and attaches its Identity; JWTAuth verifies a bearer
+// token and attaches the User it was issued to.
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+var ErrKeyNotFound = errors.New("api key not found")
+
+// APIKey is a stored key. Hash is the hex SHA-256 of the key, e.g. the
+// output of `printf %s "$KEY" | sha256sum`.
+type APIKey struct {
+	ID   string `json:"id"`   // identifies the caller in logs and audit trails (default: the hash's first 12 characters)
+	Name string `json:"name"` // e.g. the client or team the key was issued to
+	Hash string `json:"hash"`
+	// Tenant is the customer the key acts for; see Tenant. Keys without
+	// one act for DefaultTenant, but admit users of any tenant, who then
+	// act for their own.
+	Tenant string `json:"tenant,omitempty"`
+}
+
+type KeyStore interface {
+	// FindKey returns the key with the given hash, or ErrKeyNotFound.
+	FindKey(ctx context.Context, hash string) (*APIKey, error)
+}
+
+// HashKey returns the hex SHA-256 of key, as stored in APIKey.Hash. Keys
+// are random, so a fast hash is enough to make a leaked store useless.
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+type memoryKeyStore struct {
+	keys map[string]APIKey // by hash
+}
+
+// NewFileKeyStore loads keys from a JSON file holding an array of APIKey.
+// The file is read once; restart to pick up added or revoked keys.
+func NewFileKeyStore(path string) (KeyStore, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read api keys file: %w", err)
+	}
+	var keys []APIKey
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("failed to parse api keys file: %w", err)
+	}
+	return NewMemoryKeyStore(keys)
+}
+
+// NewMemoryKeyStore serves a fixed set of keys. It fails on a hash that is
+// not hex SHA-256 and on repeated hashes or IDs.
+func NewMemoryKeyStore(keys []APIKey) (KeyStore, error) {
+	s := &memoryKeyStore{keys: make(map[string]APIKey, len(keys))}
+	ids := make(map[string]bool, len(keys))
+	for i, k := range keys {
+		k.Hash = strings.ToLower(strings.TrimSpace(k.Hash))
+		if _, err := hex.DecodeString(k.Hash); err != nil || len(k.Hash) != sha256.Size*2 {
+			return nil, fmt.Errorf("api key %d: hash must be a hex SHA-256", i)
+		}
+		if k.ID == "" {
+			k.ID = k.Hash[:12]
+		}
+		if _, ok := s.keys[k.Hash]; ok {
+			return nil, fmt.Errorf("api key %s: hash is listed twice", k.ID)
+		}
+		if ids[k.ID] {
+			return nil, fmt.Errorf("api key %s: id is listed twice", k.ID)
+		}
+		ids[k.ID] = true
+		s.keys[k.Hash] = k
+	}
+	return s, nil
+}
+
+func (s *memoryKeyStore) FindKey(ctx context.Context, hash string) (*APIKey, error) {
+	k, ok := s.keys[hash]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	return &k, nil
+}
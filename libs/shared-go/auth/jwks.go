@@ -0,0 +1,178 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	jwksRefreshInterval = time.Hour
+	// jwksMinRefresh limits refetches triggered by unknown key IDs, so
+	// tokens with made-up IDs cannot hammer the issuer.
+	jwksMinRefresh = time.Minute
+	jwksTimeout    = 10 * time.Second
+	maxJWKSBytes   = 1 << 20
+)
+
+var errJWKSUnavailable = errors.New("jwks is unavailable")
+
+// jwks caches the public keys of a JSON Web Key Set by key ID.
+type jwks struct {
+	url        string
+	httpClient *http.Client
+	logger     *zap.Logger
+
+	mu        sync.Mutex
+	byID      map[string]crypto.PublicKey
+	fetchedAt time.Time
+}
+
+func newJWKS(url string, logger *zap.Logger) *jwks {
+	return &jwks{
+		url:        url,
+		httpClient: &http.Client{Timeout: jwksTimeout},
+		logger:     logger,
+	}
+}
+
+// keys returns the key with ID kid, or every key when kid is empty,
+// fetching the set first when it is stale or does not have kid.
+func (s *jwks) keys(ctx context.Context, kid string) ([]crypto.PublicKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, known := s.byID[kid]
+	stale := time.Since(s.fetchedAt) > jwksRefreshInterval
+	missing := (s.byID == nil || kid != "" && !known) && time.Since(s.fetchedAt) > jwksMinRefresh
+	if stale || missing {
+		if err := s.fetch(ctx); err != nil {
+			// Keep verifying with the keys we have, if any.
+			s.logger.Warn("Failed to refresh jwks", zap.String("url", s.url), zap.Error(err))
+		}
+	}
+	if s.byID == nil {
+		return nil, errJWKSUnavailable
+	}
+
+	if kid == "" {
+		keys := make([]crypto.PublicKey, 0, len(s.byID))
+		for _, k := range s.byID {
+			keys = append(keys, k)
+		}
+		return keys, nil
+	}
+	if k, ok := s.byID[kid]; ok {
+		return []crypto.PublicKey{k}, nil
+	}
+	return nil, fmt.Errorf("%w: unknown key %q", ErrInvalidToken, kid)
+}
+
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// fetch replaces the cached keys. Callers hold s.mu.
+func (s *jwks) fetch(ctx context.Context) error {
+	// Record the attempt even if it fails, so failures are retried at the
+	// refresh rate rather than on every request.
+	s.fetchedAt = time.Now()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create jwks request: %w", err)
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch jwks: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch jwks: %s", resp.Status)
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxJWKSBytes)).Decode(&set); err != nil {
+		return fmt.Errorf("failed to parse jwks: %w", err)
+	}
+
+	byID := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		pub, err := k.publicKey()
+		if err != nil {
+			s.logger.Warn("Skipping jwks key", zap.String("kid", k.Kid), zap.Error(err))
+			continue
+		}
+		byID[k.Kid] = pub
+	}
+	if len(byID) == 0 {
+		return errors.New("jwks has no usable signing keys")
+	}
+	s.byID = byID
+	return nil
+}
+
+func (k *jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("modulus: %w", err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil || len(e) == 0 || len(e) > 4 {
+			return nil, errors.New("invalid exponent")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, errX := base64.RawURLEncoding.DecodeString(k.X)
+		y, errY := base64.RawURLEncoding.DecodeString(k.Y)
+		size := (curve.Params().BitSize + 7) / 8
+		if errX != nil || errY != nil || len(x) > size || len(y) > size {
+			return nil, errors.New("invalid coordinates")
+		}
+		// Uncompressed point: 0x04, then x and y left-padded to the curve size.
+		point := make([]byte, 1+2*size)
+		point[0] = 4
+		copy(point[1+size-len(x):1+size], x)
+		copy(point[1+2*size-len(y):], y)
+		return ecdsa.ParseUncompressedPublicKey(curve, point)
+	}
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
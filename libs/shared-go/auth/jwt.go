@@ -0,0 +1,283 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rsa"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const defaultLeeway = time.Minute
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrTokenExpired = errors.New("token has expired")
+)
+
+type JWTConfig struct {
+	Secret   string        // HMAC key for HS256/384/512 tokens
+	JWKSURL  string        // key set for RS, PS and ES tokens, e.g. https://issuer/.well-known/jwks.json
+	Issuer   string        // required "iss" claim; empty accepts any
+	Audience string        // required in the "aud" claim; empty accepts any
+	Leeway   time.Duration // allowed clock skew for "exp" and "nbf" (default: 1m)
+}
+
+// Claims are the registered claims of a verified token, plus the common
+// profile claims and the user's role and tenant.
+type Claims struct {
+	Subject   string   `json:"sub"`
+	Issuer    string   `json:"iss,omitempty"`
+	Audience  audience `json:"aud,omitempty"`
+	ExpiresAt int64    `json:"exp"`
+	NotBefore int64    `json:"nbf,omitempty"`
+	IssuedAt  int64    `json:"iat,omitempty"`
+	Email     string   `json:"email,omitempty"`
+	Name      string   `json:"name,omitempty"`
+	Role      string   `json:"role,omitempty"`
+	Tenant    string   `json:"tenant,omitempty"`
+}
+
+// audience decodes "aud", which is either a string or an array of them.
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*a = audience{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*a = many
+	return nil
+}
+
+type JWTVerifier struct {
+	secret   []byte
+	jwks     *jwks // nil without JWKSURL
+	issuer   string
+	audience string
+	leeway   time.Duration
+}
+
+// NewJWTVerifier verifies tokens signed with cfg.Secret, with a key from
+// cfg.JWKSURL, or either when both are set. The key set is fetched on
+// first use and refreshed hourly or when a token names an unknown key.
+func NewJWTVerifier(cfg JWTConfig, logger *zap.Logger) (*JWTVerifier, error) {
+	if cfg.Secret == "" && cfg.JWKSURL == "" {
+		return nil, errors.New("a jwt secret or jwks url is required")
+	}
+	v := &JWTVerifier{
+		secret:   []byte(cfg.Secret),
+		issuer:   cfg.Issuer,
+		audience: cfg.Audience,
+		leeway:   cfg.Leeway,
+	}
+	if v.leeway <= 0 {
+		v.leeway = defaultLeeway
+	}
+	if cfg.JWKSURL != "" {
+		url := strings.TrimSpace(cfg.JWKSURL)
+		if !strings.HasPrefix(url, "https://") && !strings.HasPrefix(url, "http://") {
+			return nil, fmt.Errorf("unsupported jwks url: %s", cfg.JWKSURL)
+		}
+		v.jwks = newJWKS(url, logger)
+	}
+	return v, nil
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid,omitempty"`
+	Typ string `json:"typ,omitempty"`
+}
+
+// SignHS256 returns claims as an HS256 token signed with secret, which a
+// JWTVerifier with the same secret accepts.
+func SignHS256(secret []byte, claims *Claims) (string, error) {
+	if len(secret) == 0 {
+		return "", errors.New("a jwt secret is required")
+	}
+	header, err := json.Marshal(jwtHeader{Alg: "HS256", Typ: "JWT"})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode claims: %w", err)
+	}
+
+	input := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	mac := hmac.New(crypto.SHA256.New, secret)
+	mac.Write([]byte(input))
+	return input + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// Verify checks token's signature and claims and returns the claims. It
+// returns ErrTokenExpired for an expired token and wraps ErrInvalidToken
+// for any other rejection.
+func (v *JWTVerifier) Verify(ctx context.Context, token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed", ErrInvalidToken)
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("%w: header: %v", ErrInvalidToken, err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: signature encoding", ErrInvalidToken)
+	}
+	if err := v.verifySignature(ctx, &header, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("%w: claims: %v", ErrInvalidToken, err)
+	}
+	if err := v.checkClaims(&claims); err != nil {
+		return nil, err
+	}
+	return &claims, nil
+}
+
+func (v *JWTVerifier) verifySignature(ctx context.Context, header *jwtHeader, input string, signature []byte) error {
+	alg, ok := algorithms[header.Alg]
+	if !ok {
+		return fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidToken, header.Alg)
+	}
+	digest := func() []byte {
+		h := alg.hash.New()
+		h.Write([]byte(input))
+		return h.Sum(nil)
+	}
+
+	if alg.family == "HS" {
+		if len(v.secret) == 0 {
+			return fmt.Errorf("%w: %s tokens are not accepted", ErrInvalidToken, header.Alg)
+		}
+		mac := hmac.New(alg.hash.New, v.secret)
+		mac.Write([]byte(input))
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return fmt.Errorf("%w: bad signature", ErrInvalidToken)
+		}
+		return nil
+	}
+
+	if v.jwks == nil {
+		return fmt.Errorf("%w: %s tokens are not accepted", ErrInvalidToken, header.Alg)
+	}
+	keys, err := v.jwks.keys(ctx, header.Kid)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if verifyWithKey(alg, key, digest(), signature) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: bad signature", ErrInvalidToken)
+}
+
+func verifyWithKey(alg algorithm, key crypto.PublicKey, digest, signature []byte) bool {
+	switch alg.family {
+	case "RS":
+		pub, ok := key.(*rsa.PublicKey)
+		return ok && rsa.VerifyPKCS1v15(pub, alg.hash, digest, signature) == nil
+	case "PS":
+		pub, ok := key.(*rsa.PublicKey)
+		return ok && rsa.VerifyPSS(pub, alg.hash, digest, signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}) == nil
+	case "ES":
+		// The signature is r and s concatenated, each the curve's size.
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok || pub.Curve.Params().Name != alg.curve {
+			return false
+		}
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return false
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		return ecdsa.Verify(pub, digest, r, s)
+	}
+	return false
+}
+
+func (v *JWTVerifier) checkClaims(c *Claims) error {
+	now := time.Now()
+	if c.Subject == "" {
+		return fmt.Errorf("%w: no subject", ErrInvalidToken)
+	}
+	if c.ExpiresAt == 0 {
+		return fmt.Errorf("%w: no expiry", ErrInvalidToken)
+	}
+	if now.After(time.Unix(c.ExpiresAt, 0).Add(v.leeway)) {
+		return ErrTokenExpired
+	}
+	if c.NotBefore != 0 && now.Add(v.leeway).Before(time.Unix(c.NotBefore, 0)) {
+		return fmt.Errorf("%w: not valid yet", ErrInvalidToken)
+	}
+	if v.issuer != "" && c.Issuer != v.issuer {
+		return fmt.Errorf("%w: unexpected issuer", ErrInvalidToken)
+	}
+	if v.audience != "" && !contains(c.Audience, v.audience) {
+		return fmt.Errorf("%w: unexpected audience", ErrInvalidToken)
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+type algorithm struct {
+	family string // HS, RS, PS or ES
+	hash   crypto.Hash
+	curve  string // for ES
+}
+
+var algorithms = map[string]algorithm{
+	"HS256": {family: "HS", hash: crypto.SHA256},
+	"HS384": {family: "HS", hash: crypto.SHA384},
+	"HS512": {family: "HS", hash: crypto.SHA512},
+	"RS256": {family: "RS", hash: crypto.SHA256},
+	"RS384": {family: "RS", hash: crypto.SHA384},
+	"RS512": {family: "RS", hash: crypto.SHA512},
+	"PS256": {family: "PS", hash: crypto.SHA256},
+	"PS384": {family: "PS", hash: crypto.SHA384},
+	"PS512": {family: "PS", hash: crypto.SHA512},
+	"ES256": {family: "ES", hash: crypto.SHA256, curve: "P-256"},
+	"ES384": {family: "ES", hash: crypto.SHA384, curve: "P-384"},
+	"ES512": {family: "ES", hash: crypto.SHA512, curve: "P-521"},
+}
@@ -0,0 +1,148 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// APIKeyHeader carries the caller's API key.
+const APIKeyHeader = "X-API-Key"
+
+var (
+	ErrMissingKey = errors.New("X-API-Key header is required")
+	ErrInvalidKey = errors.New("invalid api key")
+
+	ErrMissingToken = errors.New("bearer token is required")
+)
+
+// Identity is the authenticated caller of a request.
+type Identity struct {
+	KeyID  string `json:"key_id"`
+	Name   string `json:"name"`
+	Tenant string `json:"tenant,omitempty"`
+}
+
+type identityKey struct{}
+
+// WithIdentity returns a copy of ctx carrying id.
+func WithIdentity(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, id)
+}
+
+// FromContext returns the caller identity attached by APIKeyAuth. It works
+// on both c.Context() and c.UserContext() of an authenticated request.
+func FromContext(ctx context.Context) (Identity, bool) {
+	id, ok := ctx.Value(identityKey{}).(Identity)
+	return id, ok
+}
+
+// User is the end user a request acts for, from a verified JWT.
+type User struct {
+	ID    string `json:"id"` // the token's subject
+	Email string `json:"email,omitempty"`
+	Name  string `json:"name,omitempty"`
+	Role  Role   `json:"role"`
+	// Tenant is the customer the user belongs to, from the "tenant" claim;
+	// tokens without one are for DefaultTenant.
+	Tenant string `json:"tenant,omitempty"`
+}
+
+type userKey struct{}
+
+// WithUser returns a copy of ctx carrying u.
+func WithUser(ctx context.Context, u User) context.Context {
+	return context.WithValue(ctx, userKey{}, u)
+}
+
+// CurrentUser returns the user attached by JWTAuth, or false when the
+// request was not made for a user (or ctx is not a request's).
+func CurrentUser(ctx context.Context) (User, bool) {
+	u, ok := ctx.Value(userKey{}).(User)
+	return u, ok
+}
+
+// APIKeyAuth rejects requests without a valid X-API-Key header with 401
+// and attaches the caller's Identity to the others.
+func APIKeyAuth(store KeyStore, logger *zap.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := strings.TrimSpace(c.Get(APIKeyHeader))
+		if key == "" {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": ErrMissingKey.Error(),
+			})
+		}
+
+		k, err := store.FindKey(c.Context(), HashKey(key))
+		if errors.Is(err, ErrKeyNotFound) {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": ErrInvalidKey.Error(),
+			})
+		}
+		if err != nil {
+			logger.Error("Failed to look up api key", zap.Error(err))
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "failed to authenticate request",
+			})
+		}
+
+		id := Identity{KeyID: k.ID, Name: k.Name, Tenant: k.Tenant}
+		// Locals are the fasthttp user values, which c.Context().Value
+		// reads, so services called with c.Context() see the identity too.
+		c.Locals(identityKey{}, id)
+		c.SetUserContext(WithIdentity(c.UserContext(), id))
+		return c.Next()
+	}
+}
+
+// JWTAuth rejects requests without a valid "Authorization: Bearer" token
+// with 401 and attaches the token's User to the others.
+func JWTAuth(verifier *JWTVerifier, logger *zap.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		scheme, token, _ := strings.Cut(c.Get(fiber.HeaderAuthorization), " ")
+		if !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+			c.Set(fiber.HeaderWWWAuthenticate, "Bearer")
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": ErrMissingToken.Error(),
+			})
+		}
+
+		claims, err := verifier.Verify(c.Context(), strings.TrimSpace(token))
+		if errors.Is(err, ErrInvalidToken) || errors.Is(err, ErrTokenExpired) {
+			logger.Debug("Rejected bearer token", zap.Error(err))
+			c.Set(fiber.HeaderWWWAuthenticate, `Bearer error="invalid_token"`)
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": tokenError(err),
+			})
+		}
+		if err != nil {
+			logger.Error("Failed to verify bearer token", zap.Error(err))
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+				"error": "failed to authenticate request",
+			})
+		}
+
+		u := User{ID: claims.Subject, Email: claims.Email, Name: claims.Name, Role: ParseRole(claims.Role), Tenant: claims.Tenant}
+		// A customer's api key only admits its own users.
+		if id, ok := FromContext(c.Context()); ok && id.Tenant != "" && id.Tenant != tenantOrDefault(u.Tenant) {
+			logger.Debug("Rejected bearer token for another tenant", zap.String("key_id", id.KeyID), zap.String("tenant", u.Tenant))
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": ErrTenantMismatch.Error(),
+			})
+		}
+		c.Locals(userKey{}, u)
+		c.SetUserContext(WithUser(c.UserContext(), u))
+		return c.Next()
+	}
+}
+
+// tokenError keeps the reason a token was rejected out of the response.
+func tokenError(err error) string {
+	if errors.Is(err, ErrTokenExpired) {
+		return ErrTokenExpired.Error()
+	}
+	return ErrInvalidToken.Error()
+}
@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Role is a user's role, from the "role" claim of their token.
+type Role string
+
+const (
+	RoleAdmin  Role = "admin"
+	RoleMember Role = "member"
+	RoleViewer Role = "viewer"
+)
+
+// Permission is an action a role may take.
+type Permission string
+
+const (
+	// PermRead views conversations, documents and search results.
+	PermRead Permission = "read"
+	// PermWrite chats, changes the user's own conversations and feedback,
+	// shares conversations and ingests documents.
+	PermWrite Permission = "write"
+	// PermManage manages providers, system prompts, users and other
+	// users' data.
+	PermManage Permission = "manage"
+)
+
+var ErrForbidden = errors.New("permission denied")
+
+var rolePermissions = map[Role][]Permission{
+	RoleAdmin:  {PermRead, PermWrite, PermManage},
+	RoleMember: {PermRead, PermWrite},
+	RoleViewer: {PermRead},
+}
+
+// ParseRole returns the role named s. Tokens without a role are members;
+// an unknown role gets the least privilege, viewer.
+func ParseRole(s string) Role {
+	if s == "" {
+		return RoleMember
+	}
+	if _, ok := rolePermissions[Role(s)]; ok {
+		return Role(s)
+	}
+	return RoleViewer
+}
+
+// Valid reports whether r is a known role.
+func (r Role) Valid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+// Can reports whether r grants p.
+func (r Role) Can(p Permission) bool {
+	return slices.Contains(rolePermissions[r], p)
+}
+
+// Can reports whether the user of ctx may take p. Without a user, as when
+// user authentication is off or for background jobs, reading and writing
+// are allowed, since the API key, if any, has already admitted the caller,
+// but managing is not: it always takes a signed-in admin.
+func Can(ctx context.Context, p Permission) bool {
+	u, ok := CurrentUser(ctx)
+	if !ok {
+		return p != PermManage
+	}
+	return u.Role.Can(p)
+}
+
+// Require rejects requests whose user lacks p with 403. Add it to a route
+// after JWTAuth has run, e.g. group.Put("/:id", auth.Require(auth.PermManage), h.update).
+func Require(p Permission) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !Can(c.Context(), p) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": ErrForbidden.Error(),
+			})
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,32 @@
+package auth
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrTenantMismatch is returned for a user token presented with another
+// tenant's api key.
+var ErrTenantMismatch = errors.New("token is not valid for this api key's tenant")
+
+// DefaultTenant is the tenant of keys, tokens and requests that name
+// none, so a single-tenant deployment keeps all its data in it.
+const DefaultTenant = "default"
+
+// Tenant is the customer whose data a request may reach: the user's
+// tenant, else the api key's, else DefaultTenant. It is never empty, so
+// services always filter by it.
+func Tenant(ctx context.Context) string {
+	if u, ok := CurrentUser(ctx); ok {
+		return tenantOrDefault(u.Tenant)
+	}
+	id, _ := FromContext(ctx)
+	return tenantOrDefault(id.Tenant)
+}
+
+func tenantOrDefault(tenant string) string {
+	if tenant == "" {
+		return DefaultTenant
+	}
+	return tenant
+}
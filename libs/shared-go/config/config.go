@@ -10,6 +10,7 @@ import (
 
 var (
 	configInstance *Config
+	configErr      error
 	configOnce     sync.Once
 )
 
@@ -32,27 +33,103 @@ func parseEnv() error {
 	return nil
 }
 
-func loadConfig() *Config {
-	parseEnv()
-
+func readConfig() *Config {
 	return &Config{
-		ScribeQueryPort:  os.Getenv("SCRIBE_QUERY_PORT"),
-		WeaviateScheme:   os.Getenv("WEAVIATE_SCHEME"),
-		WeaviateHost:     os.Getenv("WEAVIATE_HOST"),
-		WeaviateAPIKey:   os.Getenv("WEAVIATE_API_KEY"),
-		WeaviateGrpcHost: os.Getenv("WEAVIATE_GRPC_HOST"),
-		ORIGINS:          os.Getenv("ORIGINS"),
-		OpenAIAPIKey:     os.Getenv("OPENAI_API_KEY"),
-		OpenAIModel:      os.Getenv("OPENAI_MODEL"),
-		LocalHost:        os.Getenv("LOCAL_HOST"),
-		LocalModel:       os.Getenv("LOCAL_MODEL"),
-		Provider:         os.Getenv("PROVIDER"),
+		ScribeQueryPort:         os.Getenv("SCRIBE_QUERY_PORT"),
+		WeaviateScheme:          os.Getenv("WEAVIATE_SCHEME"),
+		WeaviateHost:            os.Getenv("WEAVIATE_HOST"),
+		WeaviateAPIKey:          os.Getenv("WEAVIATE_API_KEY"),
+		WeaviateGrpcHost:        os.Getenv("WEAVIATE_GRPC_HOST"),
+		WeaviateDryRun:          os.Getenv("WEAVIATE_SCHEMA_DRY_RUN"),
+		ORIGINS:                 os.Getenv("ORIGINS"),
+		APIKeysFile:             os.Getenv("API_KEYS_FILE"),
+		AuthDisabled:            os.Getenv("AUTH_DISABLED"),
+		JWTSecret:               os.Getenv("JWT_SECRET"),
+		JWTJWKSURL:              os.Getenv("JWT_JWKS_URL"),
+		JWTIssuer:               os.Getenv("JWT_ISSUER"),
+		JWTAudience:             os.Getenv("JWT_AUDIENCE"),
+		UsersFile:               os.Getenv("USERS_FILE"),
+		AccessTokenMinutes:      os.Getenv("ACCESS_TOKEN_MINUTES"),
+		RefreshTokenDays:        os.Getenv("REFRESH_TOKEN_DAYS"),
+		RateLimits:              os.Getenv("RATE_LIMITS"),
+		EncryptionKeys:          os.Getenv("ENCRYPTION_KEYS"),
+		CredentialsFile:         os.Getenv("CREDENTIALS_FILE"),
+		OpenAIAPIKey:            os.Getenv("OPENAI_API_KEY"),
+		OpenAIModel:             os.Getenv("OPENAI_MODEL"),
+		LocalHost:               os.Getenv("LOCAL_HOST"),
+		LocalModel:              os.Getenv("LOCAL_MODEL"),
+		Provider:                os.Getenv("PROVIDER"),
+		ModelAliases:            os.Getenv("MODEL_ALIASES"),
+		AIProviders:             os.Getenv("AI_PROVIDERS"),
+		AIProvidersFile:         os.Getenv("AI_PROVIDERS_FILE"),
+		EmbeddingProvider:       os.Getenv("EMBEDDING_PROVIDER"),
+		EmbeddingModel:          os.Getenv("EMBEDDING_MODEL"),
+		VectorStore:             os.Getenv("VECTOR_STORE"),
+		QdrantURL:               os.Getenv("QDRANT_URL"),
+		QdrantAPIKey:            os.Getenv("QDRANT_API_KEY"),
+		MilvusURL:               os.Getenv("MILVUS_URL"),
+		MilvusToken:             os.Getenv("MILVUS_TOKEN"),
+		MilvusDB:                os.Getenv("MILVUS_DB"),
+		MilvusPartition:         os.Getenv("MILVUS_PARTITION"),
+		MilvusConsistency:       os.Getenv("MILVUS_CONSISTENCY_LEVEL"),
+		RerankProvider:          os.Getenv("RERANK_PROVIDER"),
+		RerankModel:             os.Getenv("RERANK_MODEL"),
+		RerankHost:              os.Getenv("RERANK_HOST"),
+		CohereAPIKey:            os.Getenv("COHERE_API_KEY"),
+		QueryExpansion:          os.Getenv("QUERY_EXPANSION"),
+		ContextTokenBudget:      os.Getenv("CONTEXT_TOKEN_BUDGET"),
+		ContextMaxPerDocument:   os.Getenv("CONTEXT_MAX_PER_DOCUMENT"),
+		CrawlerUserAgent:        os.Getenv("CRAWLER_USER_AGENT"),
+		CrawlerAllowedHosts:     os.Getenv("CRAWLER_ALLOWED_HOSTS"),
+		DatabaseType:            os.Getenv("DATABASE_TYPE"),
+		DatabaseURL:             os.Getenv("DATABASE_URL"),
+		DatabaseDriver:          os.Getenv("DATABASE_DRIVER"),
+		DatabaseMaxOpenConns:    os.Getenv("DATABASE_MAX_OPEN_CONNS"),
+		DatabaseMaxIdleConns:    os.Getenv("DATABASE_MAX_IDLE_CONNS"),
+		DatabaseIdleTimeoutSecs: os.Getenv("DATABASE_IDLE_TIMEOUT_SECS"),
+		DatabaseMaxConnAgeMins:  os.Getenv("DATABASE_MAX_CONN_AGE_MINS"),
+		DatabaseStmtTimeoutMs:   os.Getenv("DATABASE_STMT_TIMEOUT_MS"),
+		ConversationsFile:       os.Getenv("CONVERSATIONS_FILE"),
+		RedisURL:                os.Getenv("REDIS_URL"),
+		RedisMode:               os.Getenv("REDIS_MODE"),
+		ConversationCacheMins:   os.Getenv("CONVERSATION_CACHE_MINUTES"),
+		ConversationPurgeDays:   os.Getenv("CONVERSATION_PURGE_DAYS"),
+		RetentionDays:           os.Getenv("RETENTION_DAYS"),
+		RetentionTenantDays:     os.Getenv("RETENTION_TENANT_DAYS"),
+		RetentionDryRun:         os.Getenv("RETENTION_DRY_RUN"),
+		FeedbackFile:            os.Getenv("FEEDBACK_FILE"),
+		SharesFile:              os.Getenv("SHARES_FILE"),
+		ShareSecret:             os.Getenv("SHARE_SECRET"),
+		SystemPrompt:            os.Getenv("SYSTEM_PROMPT"),
+		HistoryMaxMessages:      os.Getenv("HISTORY_MAX_MESSAGES"),
+		HistoryTokenBudget:      os.Getenv("HISTORY_TOKEN_BUDGET"),
+		HistoryMaxMessageTokens: os.Getenv("HISTORY_MAX_MESSAGE_TOKENS"),
+		AttachmentTokenBudget:   os.Getenv("ATTACHMENT_TOKEN_BUDGET"),
+		IdempotencyTTLHours:     os.Getenv("IDEMPOTENCY_TTL_HOURS"),
+		EventBus:                os.Getenv("EVENT_BUS"),
+		EventWebhookURL:         os.Getenv("EVENT_WEBHOOK_URL"),
+		SecretsProvider:         os.Getenv("SECRETS_PROVIDER"),
+		SecretsRefreshMins:      os.Getenv("SECRETS_REFRESH_MINUTES"),
+		SecretsKeys:             os.Getenv("SECRETS_KEYS"),
+		VaultAddr:               os.Getenv("VAULT_ADDR"),
+		VaultToken:              os.Getenv("VAULT_TOKEN"),
+		VaultNamespace:          os.Getenv("VAULT_NAMESPACE"),
+		VaultSecretPath:         os.Getenv("VAULT_SECRET_PATH"),
+		AWSRegion:               os.Getenv("AWS_REGION"),
+		AWSSecretID:             os.Getenv("AWS_SECRET_ID"),
 	}
 }
 
+// LoadConfig reads the configuration from the environment and .env, after
+// applying the SECRETS_PROVIDER secret, if any, over them.
 func LoadConfig() (*Config, error) {
 	configOnce.Do(func() {
-		configInstance = loadConfig()
+		parseEnv()
+		configInstance = readConfig()
+		configErr = applySecrets(configInstance)
+		if configErr == nil {
+			configInstance = readConfig()
+		}
 	})
-	return configInstance, nil
+	return configInstance, configErr
 }
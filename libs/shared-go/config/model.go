@@ -1,15 +1,86 @@
 package config
 
 type Config struct {
-	ScribeQueryPort  string `mapstructure:"SCRIBE_QUERY_PORT"`
-	WeaviateScheme   string `mapstructure:"WEAVIATE_SCHEME"`
-	WeaviateHost     string `mapstructure:"WEAVIATE_HOST"`
-	WeaviateAPIKey   string `mapstructure:"WEAVIATE_API_KEY"`
-	WeaviateGrpcHost string `mapstructure:"WEAVIATE_GRPC_HOST"`
-	ORIGINS          string `mapstructure:"ORIGINS"`
-	OpenAIAPIKey     string `mapstructure:"OPENAI_API_KEY"`
-	OpenAIModel      string `mapstructure:"OPENAI_MODEL"`
-	LocalHost        string `mapstructure:"LOCAL_HOST"`
-	LocalModel       string `mapstructure:"LOCAL_MODEL"`
-	Provider         string `mapstructure:"PROVIDER"`
+	ScribeQueryPort         string `mapstructure:"SCRIBE_QUERY_PORT"`
+	WeaviateScheme          string `mapstructure:"WEAVIATE_SCHEME"`
+	WeaviateHost            string `mapstructure:"WEAVIATE_HOST"`
+	WeaviateAPIKey          string `mapstructure:"WEAVIATE_API_KEY"`
+	WeaviateGrpcHost        string `mapstructure:"WEAVIATE_GRPC_HOST"`
+	WeaviateDryRun          string `mapstructure:"WEAVIATE_SCHEMA_DRY_RUN"` // "true" logs schema migrations without applying them
+	ORIGINS                 string `mapstructure:"ORIGINS"`
+	APIKeysFile             string `mapstructure:"API_KEYS_FILE"` // JSON array of {id, name, hash} allowed to call /api; required unless AUTH_DISABLED
+	AuthDisabled            string `mapstructure:"AUTH_DISABLED"` // "true" serves /api without api keys, for local development
+	JWTSecret               string `mapstructure:"JWT_SECRET"`    // HMAC key of user tokens (HS256/384/512)
+	JWTJWKSURL              string `mapstructure:"JWT_JWKS_URL"`  // key set of user tokens (RS, PS and ES algorithms)
+	JWTIssuer               string `mapstructure:"JWT_ISSUER"`    // required "iss" of user tokens; empty accepts any
+	JWTAudience             string `mapstructure:"JWT_AUDIENCE"`  // required "aud" of user tokens; empty accepts any
+	UsersFile               string `mapstructure:"USERS_FILE"`
+	AccessTokenMinutes      string `mapstructure:"ACCESS_TOKEN_MINUTES"`
+	RefreshTokenDays        string `mapstructure:"REFRESH_TOKEN_DAYS"`
+	RateLimits              string `mapstructure:"RATE_LIMITS"`
+	EncryptionKeys          string `mapstructure:"ENCRYPTION_KEYS"`  // "id:base64key,..." encrypting stored provider keys; the first seals new ones
+	CredentialsFile         string `mapstructure:"CREDENTIALS_FILE"` // JSON file of users' sealed provider keys when there is no SQL database
+	OpenAIAPIKey            string `mapstructure:"OPENAI_API_KEY"`
+	OpenAIModel             string `mapstructure:"OPENAI_MODEL"`
+	LocalHost               string `mapstructure:"LOCAL_HOST"`
+	LocalModel              string `mapstructure:"LOCAL_MODEL"`
+	Provider                string `mapstructure:"PROVIDER"`
+	ModelAliases            string `mapstructure:"MODEL_ALIASES"`      // e.g. "fast=gpt-4o-mini,private=llama3:8b"
+	AIProviders             string `mapstructure:"AI_PROVIDERS"`       // JSON array of named provider specs
+	AIProvidersFile         string `mapstructure:"AI_PROVIDERS_FILE"`  // path to a JSON file of provider specs
+	EmbeddingProvider       string `mapstructure:"EMBEDDING_PROVIDER"` // "openai" or "local"; defaults to PROVIDER
+	EmbeddingModel          string `mapstructure:"EMBEDDING_MODEL"`
+	VectorStore             string `mapstructure:"VECTOR_STORE"` // "weaviate" (default), "qdrant" or "milvus"
+	QdrantURL               string `mapstructure:"QDRANT_URL"`
+	QdrantAPIKey            string `mapstructure:"QDRANT_API_KEY"`
+	MilvusURL               string `mapstructure:"MILVUS_URL"`
+	MilvusToken             string `mapstructure:"MILVUS_TOKEN"`
+	MilvusDB                string `mapstructure:"MILVUS_DB"`
+	MilvusPartition         string `mapstructure:"MILVUS_PARTITION"`
+	MilvusConsistency       string `mapstructure:"MILVUS_CONSISTENCY_LEVEL"` // Strong, Session, Bounded or Eventually
+	RerankProvider          string `mapstructure:"RERANK_PROVIDER"`          // "cohere" or "local"; empty disables reranking
+	RerankModel             string `mapstructure:"RERANK_MODEL"`
+	RerankHost              string `mapstructure:"RERANK_HOST"` // local cross-encoder server, e.g. http://localhost:8080
+	CohereAPIKey            string `mapstructure:"COHERE_API_KEY"`
+	QueryExpansion          string `mapstructure:"QUERY_EXPANSION"`            // "true" rewrites and paraphrases queries before retrieval by default
+	ContextTokenBudget      string `mapstructure:"CONTEXT_TOKEN_BUDGET"`       // tokens of retrieved chunks per prompt (default: 3000)
+	ContextMaxPerDocument   string `mapstructure:"CONTEXT_MAX_PER_DOCUMENT"`   // chunks per document before other sources (default: 3)
+	CrawlerUserAgent        string `mapstructure:"CRAWLER_USER_AGENT"`         // used for URL ingestion and robots.txt matching
+	CrawlerAllowedHosts     string `mapstructure:"CRAWLER_ALLOWED_HOSTS"`      // comma-separated; empty allows any public host
+	DatabaseType            string `mapstructure:"DATABASE_TYPE"`              // postgres (default), sqlite or mongo
+	DatabaseURL             string `mapstructure:"DATABASE_URL"`               // Postgres URL, SQLite file or MongoDB URI; when set, conversations are stored there instead of CONVERSATIONS_FILE
+	DatabaseDriver          string `mapstructure:"DATABASE_DRIVER"`            // database/sql driver name linked into the binary (default: pgx, or sqlite)
+	DatabaseMaxOpenConns    string `mapstructure:"DATABASE_MAX_OPEN_CONNS"`    // Postgres pool size (default: 25)
+	DatabaseMaxIdleConns    string `mapstructure:"DATABASE_MAX_IDLE_CONNS"`    // idle Postgres connections kept open (default: pool size)
+	DatabaseIdleTimeoutSecs string `mapstructure:"DATABASE_IDLE_TIMEOUT_SECS"` // idle connections are closed after this (default: 300)
+	DatabaseMaxConnAgeMins  string `mapstructure:"DATABASE_MAX_CONN_AGE_MINS"` // connections are replaced after this (default: 30)
+	DatabaseStmtTimeoutMs   string `mapstructure:"DATABASE_STMT_TIMEOUT_MS"`   // Postgres statement_timeout; empty keeps the server's
+	ConversationsFile       string `mapstructure:"CONVERSATIONS_FILE"`         // JSON file for conversation history; empty keeps it in memory
+	RedisURL                string `mapstructure:"REDIS_URL"`                  // e.g. redis://:password@localhost:6379/0; caches active conversations and shares stop requests between instances
+	RedisMode               string `mapstructure:"REDIS_MODE"`                 // empty for one server, cluster or sentinel
+	ConversationCacheMins   string `mapstructure:"CONVERSATION_CACHE_MINUTES"` // minutes an idle conversation stays in Redis (default: 60)
+	ConversationPurgeDays   string `mapstructure:"CONVERSATION_PURGE_DAYS"`    // days deleted conversations can be restored before purge (default: 30)
+	RetentionDays           string `mapstructure:"RETENTION_DAYS"`             // days after its last update a conversation is permanently deleted; empty keeps conversations
+	RetentionTenantDays     string `mapstructure:"RETENTION_TENANT_DAYS"`      // per-tenant RETENTION_DAYS, e.g. acme=30,globex=0
+	RetentionDryRun         string `mapstructure:"RETENTION_DRY_RUN"`          // "true" logs what retention would delete without deleting it
+	FeedbackFile            string `mapstructure:"FEEDBACK_FILE"`              // JSON Lines file for message feedback without a database; empty keeps it in memory
+	SharesFile              string `mapstructure:"SHARES_FILE"`                // JSON file for conversation share links without a database; empty keeps them in memory
+	ShareSecret             string `mapstructure:"SHARE_SECRET"`               // HMAC key for share link tokens
+	SystemPrompt            string `mapstructure:"SYSTEM_PROMPT"`              // sent first on every chat request
+	HistoryMaxMessages      string `mapstructure:"HISTORY_MAX_MESSAGES"`       // stored messages considered per request (default: 20)
+	HistoryTokenBudget      string `mapstructure:"HISTORY_TOKEN_BUDGET"`       // tokens of history per request (default: 4000)
+	HistoryMaxMessageTokens string `mapstructure:"HISTORY_MAX_MESSAGE_TOKENS"` // longer stored messages are truncated (default: 1000)
+	AttachmentTokenBudget   string `mapstructure:"ATTACHMENT_TOKEN_BUDGET"`    // tokens of uploaded file text per turn (default: 3000)
+	IdempotencyTTLHours     string `mapstructure:"IDEMPOTENCY_TTL_HOURS"`      // hours a chat response is replayed for its Idempotency-Key (default: 24)
+	EventBus                string `mapstructure:"EVENT_BUS"`                  // where chat events are published: redis (a stream per topic on REDIS_URL), webhook, or empty to log them
+	EventWebhookURL         string `mapstructure:"EVENT_WEBHOOK_URL"`          // receives a POST per event when EVENT_BUS=webhook
+	SecretsProvider         string `mapstructure:"SECRETS_PROVIDER"`
+	SecretsRefreshMins      string `mapstructure:"SECRETS_REFRESH_MINUTES"`
+	SecretsKeys             string `mapstructure:"SECRETS_KEYS"` // extra comma-separated variables the secret may set
+	VaultAddr               string `mapstructure:"VAULT_ADDR"`
+	VaultToken              string `mapstructure:"VAULT_TOKEN"`
+	VaultNamespace          string `mapstructure:"VAULT_NAMESPACE"`
+	VaultSecretPath         string `mapstructure:"VAULT_SECRET_PATH"`
+	AWSRegion               string `mapstructure:"AWS_REGION"`
+	AWSSecretID             string `mapstructure:"AWS_SECRET_ID"`
 }
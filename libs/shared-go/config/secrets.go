@@ -0,0 +1,79 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/Joepolymath/DaVinci/libs/shared-go/secrets"
+)
+
+// credentialNames are the variables a secret may set: the credentials
+// read by the configuration.
+var credentialNames = []string{
+	"OPENAI_API_KEY",
+	"COHERE_API_KEY",
+	"PINECONE_API_KEY",
+	"WEAVIATE_API_KEY",
+	"QDRANT_API_KEY",
+	"MILVUS_TOKEN",
+	"DATABASE_URL",
+	"REDIS_URL",
+	"JWT_SECRET",
+	"SHARE_SECRET",
+	"ENCRYPTION_KEYS",
+}
+
+// SecretNames are the variables the SECRETS_PROVIDER secret may set: the
+// known credentials and the extra names listed in SECRETS_KEYS, such as
+// the variables AI_PROVIDERS keys reference.
+func SecretNames(cfg *Config) []string {
+	names := append([]string(nil), credentialNames...)
+	for _, name := range strings.Split(cfg.SecretsKeys, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// NewSecretsSource returns the secrets manager selected by
+// SECRETS_PROVIDER ("vault" or "aws"), or nil without error when it is
+// unset.
+func NewSecretsSource(cfg *Config) (secrets.Source, error) {
+	switch cfg.SecretsProvider {
+	case "":
+		return nil, nil
+	case "vault":
+		return secrets.NewVaultSource(secrets.VaultConfig{
+			Address:   cfg.VaultAddr,
+			Token:     cfg.VaultToken,
+			Namespace: cfg.VaultNamespace,
+			Path:      cfg.VaultSecretPath,
+		})
+	case "aws":
+		return secrets.NewAWSSource(secrets.AWSConfig{
+			Region:   cfg.AWSRegion,
+			SecretID: cfg.AWSSecretID,
+		})
+	default:
+		return nil, fmt.Errorf("unsupported secrets provider: %q (supported: %q, %q)", cfg.SecretsProvider, "vault", "aws")
+	}
+}
+
+// applySecrets sets the allowed values of the configured secret as
+// environment variables, so that they override those of the environment
+// and .env.
+func applySecrets(cfg *Config) error {
+	source, err := NewSecretsSource(cfg)
+	if err != nil || source == nil {
+		return err
+	}
+	changed, err := secrets.Apply(context.Background(), source, SecretNames(cfg))
+	if err != nil {
+		return fmt.Errorf("failed to load secrets from %s: %w", cfg.SecretsProvider, err)
+	}
+	log.Printf("Loaded %d secrets from %s", len(changed), cfg.SecretsProvider)
+	return nil
+}
@@ -0,0 +1,74 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ModelAliases maps stable logical model names (e.g. "fast", "private") to
+// provider-specific model IDs (e.g. "gpt-4o-mini", "llama3:8b").
+type ModelAliases map[string]string
+
+// ParseModelAliases parses an alias table in the form
+// "fast=gpt-4o-mini,private=llama3:8b", as used in environment variables.
+func ParseModelAliases(s string) (ModelAliases, error) {
+	aliases := make(ModelAliases)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, model, ok := strings.Cut(pair, "=")
+		name, model = strings.TrimSpace(name), strings.TrimSpace(model)
+		if !ok || name == "" || model == "" {
+			return nil, fmt.Errorf("invalid model alias %q (want name=model)", pair)
+		}
+		aliases[name] = model
+	}
+	return aliases, nil
+}
+
+// Resolve returns the model an alias points to, or name unchanged if it is not an alias.
+func (a ModelAliases) Resolve(name string) string {
+	if model, ok := a[name]; ok {
+		return model
+	}
+	return name
+}
+
+// WithModelAliases wraps provider so that ChatOptions.Model is resolved
+// through aliases before each request.
+func WithModelAliases(provider ChatProvider, aliases ModelAliases) ChatProvider {
+	if len(aliases) == 0 {
+		return provider
+	}
+	return &aliasProvider{ChatProvider: provider, aliases: aliases}
+}
+
+type aliasProvider struct {
+	ChatProvider
+	aliases ModelAliases
+}
+
+func (p *aliasProvider) Completion(ctx context.Context, messages []Message, opts *ChatOptions) (*ChatResponse, error) {
+	return p.ChatProvider.Completion(ctx, messages, p.resolve(opts))
+}
+
+func (p *aliasProvider) CompletionStream(ctx context.Context, messages []Message, opts *ChatOptions, onDelta func(delta ChatStreamDelta) error) error {
+	return p.ChatProvider.CompletionStream(ctx, messages, p.resolve(opts), onDelta)
+}
+
+// resolve returns opts with Model resolved, copying rather than mutating the caller's options.
+func (p *aliasProvider) resolve(opts *ChatOptions) *ChatOptions {
+	if opts == nil || opts.Model == "" {
+		return opts
+	}
+	model := p.aliases.Resolve(opts.Model)
+	if model == opts.Model {
+		return opts
+	}
+	resolved := *opts
+	resolved.Model = model
+	return &resolved
+}
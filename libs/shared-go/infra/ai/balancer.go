@@ -0,0 +1,135 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// BalanceStrategy selects how BalancedProvider spreads requests over its backends.
+type BalanceStrategy string
+
+const (
+	// BalanceRoundRobin cycles through backends in order.
+	BalanceRoundRobin BalanceStrategy = "round_robin"
+	// BalanceLeastPending picks the backend with the fewest in-flight requests,
+	// which adapts to slow backends and long streams.
+	BalanceLeastPending BalanceStrategy = "least_pending"
+)
+
+// BalancedProvider spreads requests over several equivalent backends, such as
+// one adapter per OpenAI API key or per Ollama host, to get past per-key rate
+// limits and single-host throughput. All backends should serve the same models.
+type BalancedProvider struct {
+	backends []ChatProvider
+	strategy BalanceStrategy
+	pending  []atomic.Int64
+	next     atomic.Uint64
+}
+
+// NewBalancedProvider wraps backends with the given strategy (default: round robin).
+func NewBalancedProvider(strategy BalanceStrategy, backends ...ChatProvider) (*BalancedProvider, error) {
+	if len(backends) == 0 {
+		return nil, errors.New("at least one backend is required")
+	}
+	switch strategy {
+	case "":
+		strategy = BalanceRoundRobin
+	case BalanceRoundRobin, BalanceLeastPending:
+	default:
+		return nil, fmt.Errorf("unsupported balance strategy: %q", strategy)
+	}
+
+	return &BalancedProvider{
+		backends: backends,
+		strategy: strategy,
+		pending:  make([]atomic.Int64, len(backends)),
+	}, nil
+}
+
+func (b *BalancedProvider) Completion(ctx context.Context, messages []Message, opts *ChatOptions) (*ChatResponse, error) {
+	i := b.acquire()
+	defer b.release(i)
+	return b.backends[i].Completion(ctx, messages, opts)
+}
+
+func (b *BalancedProvider) CompletionStream(ctx context.Context, messages []Message, opts *ChatOptions, onDelta func(delta ChatStreamDelta) error) error {
+	i := b.acquire()
+	defer b.release(i)
+	return b.backends[i].CompletionStream(ctx, messages, opts, onDelta)
+}
+
+// Health succeeds if at least one backend is healthy.
+func (b *BalancedProvider) Health(ctx context.Context) error {
+	var errs []error
+	for i, backend := range b.backends {
+		err := backend.Health(ctx)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, fmt.Errorf("backend %d: %w", i, err))
+	}
+	return errors.Join(errs...)
+}
+
+// ListModels returns the models of the first backend that answers.
+func (b *BalancedProvider) ListModels(ctx context.Context) ([]ModelInfo, error) {
+	var errs []error
+	for _, backend := range b.backends {
+		models, err := backend.ListModels(ctx)
+		if err == nil {
+			return models, nil
+		}
+		errs = append(errs, err)
+	}
+	return nil, errors.Join(errs...)
+}
+
+func (b *BalancedProvider) IsEnabled() bool {
+	for _, backend := range b.backends {
+		if backend.IsEnabled() {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *BalancedProvider) GetModel() string {
+	return b.backends[0].GetModel()
+}
+
+// Pending returns the number of in-flight requests per backend.
+func (b *BalancedProvider) Pending() []int64 {
+	out := make([]int64, len(b.pending))
+	for i := range b.pending {
+		out[i] = b.pending[i].Load()
+	}
+	return out
+}
+
+// acquire picks a backend and counts the request as in flight on it.
+func (b *BalancedProvider) acquire() int {
+	var i int
+	switch b.strategy {
+	case BalanceLeastPending:
+		// Start the scan at a rotating offset so ties don't always favour backend 0.
+		start := int(b.next.Add(1)-1) % len(b.backends)
+		i = start
+		best := b.pending[start].Load()
+		for n := 1; n < len(b.backends); n++ {
+			j := (start + n) % len(b.backends)
+			if p := b.pending[j].Load(); p < best {
+				i, best = j, p
+			}
+		}
+	default:
+		i = int(b.next.Add(1)-1) % len(b.backends)
+	}
+	b.pending[i].Add(1)
+	return i
+}
+
+func (b *BalancedProvider) release(i int) {
+	b.pending[i].Add(-1)
+}
@@ -0,0 +1,139 @@
+package rerank
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultBaseURL = "https://api.cohere.com"
+	defaultModel   = "rerank-v3.5"
+	defaultTimeout = 30 * time.Second
+	rerankEndpoint = "/v2/rerank"
+)
+
+type Client struct {
+	apiKey     string
+	model      string
+	baseURL    string
+	httpClient *http.Client
+	logger     *zap.Logger
+	enabled    bool
+}
+
+func NewClient(cfg *Config, logger *zap.Logger) (*Client, error) {
+	if cfg == nil {
+		return nil, errors.New("config is required")
+	}
+	if !cfg.IsValid() {
+		return nil, errors.New("cohere API key is required")
+	}
+
+	model := cfg.Model
+	if model == "" {
+		model = defaultModel
+	}
+
+	baseURL := strings.TrimRight(cfg.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	logger.Info("Cohere rerank client initialized", zap.String("model", model))
+
+	return &Client{
+		apiKey:  cfg.APIKey,
+		model:   model,
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+		logger:  logger,
+		enabled: true,
+	}, nil
+}
+
+// Rerank scores documents against query with model (the configured model if
+// empty) and returns the topN most relevant (all if topN <= 0).
+func (c *Client) Rerank(ctx context.Context, model, query string, documents []string, topN int) (*RerankResponse, error) {
+	if len(documents) == 0 {
+		return &RerankResponse{}, nil
+	}
+	if model == "" {
+		model = c.model
+	}
+
+	jsonData, err := json.Marshal(RerankRequest{Model: model, Query: query, Documents: documents, TopN: topN})
+	if err != nil {
+		c.logger.Error("Failed to marshal rerank request", zap.Error(err))
+		return nil, fmt.Errorf("failed to marshal rerank request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+rerankEndpoint, bytes.NewBuffer(jsonData))
+	if err != nil {
+		c.logger.Error("Failed to create HTTP request", zap.Error(err))
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		c.logger.Error("Failed to send HTTP request", zap.Error(err))
+		return nil, fmt.Errorf("failed to send HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		c.logger.Error("Failed to read response body", zap.Error(err))
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr APIError
+		if err := json.Unmarshal(body, &apiErr); err == nil && apiErr.Message != "" {
+			c.logger.Error("Cohere API error",
+				zap.Int("status", resp.StatusCode),
+				zap.String("message", apiErr.Message))
+			return nil, fmt.Errorf("cohere API error (status %d): %s", resp.StatusCode, apiErr.Message)
+		}
+		return nil, fmt.Errorf("cohere API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var rerankResp RerankResponse
+	if err := json.Unmarshal(body, &rerankResp); err != nil {
+		c.logger.Error("Failed to unmarshal rerank response", zap.Error(err))
+		return nil, fmt.Errorf("failed to unmarshal rerank response: %w", err)
+	}
+
+	c.logger.Debug("Documents reranked",
+		zap.String("model", model),
+		zap.Int("documents", len(documents)),
+		zap.Int("search_units", rerankResp.Meta.BilledUnits.SearchUnits))
+
+	return &rerankResp, nil
+}
+
+func (c *Client) IsEnabled() bool {
+	return c.enabled
+}
+
+// GetModel returns the configured default model name.
+func (c *Client) GetModel() string {
+	return c.model
+}
@@ -0,0 +1,41 @@
+package rerank
+
+import "time"
+
+type Config struct {
+	APIKey  string
+	Model   string        // default: rerank-v3.5
+	BaseURL string        // default: https://api.cohere.com
+	Timeout time.Duration // per request (default: 30s)
+}
+
+func (c *Config) IsValid() bool {
+	return c.APIKey != ""
+}
+
+// RerankRequest is the body of POST /v2/rerank.
+type RerankRequest struct {
+	Model           string   `json:"model"`
+	Query           string   `json:"query"`
+	Documents       []string `json:"documents"`
+	TopN            int      `json:"top_n,omitempty"`
+	MaxTokensPerDoc int      `json:"max_tokens_per_doc,omitempty"`
+}
+
+// RerankResponse lists documents by descending relevance.
+type RerankResponse struct {
+	ID      string `json:"id"`
+	Results []struct {
+		Index          int     `json:"index"`
+		RelevanceScore float64 `json:"relevance_score"`
+	} `json:"results"`
+	Meta struct {
+		BilledUnits struct {
+			SearchUnits int `json:"search_units"`
+		} `json:"billed_units"`
+	} `json:"meta"`
+}
+
+type APIError struct {
+	Message string `json:"message"`
+}
@@ -0,0 +1,145 @@
+package ai
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	// defaultContextWindow is assumed for models missing from the catalog.
+	defaultContextWindow = 8192
+
+	// messageOverheadTokens approximates the per-message framing tokens
+	// (role markers, separators) added by chat templates.
+	messageOverheadTokens = 4
+)
+
+// ErrContextExceeded is returned by FitToContext when the messages that must be
+// kept (system prompts and the latest message) do not fit on their own.
+var ErrContextExceeded = errors.New("messages exceed the model context window")
+
+// modelContextWindows maps model names (or name prefixes) to their context size in tokens.
+// Lookups use the longest matching prefix, so "gpt-4o-mini-2024-07-18" resolves
+// via "gpt-4o-mini" and "llama3.1:8b" via "llama3.1".
+var modelContextWindows = map[string]int{
+	// OpenAI
+	"gpt-3.5-turbo": 16385,
+	"gpt-4":         8192,
+	"gpt-4-32k":     32768,
+	"gpt-4-turbo":   128000,
+	"gpt-4o":        128000,
+	"gpt-4o-mini":   128000,
+	"gpt-4.1":       1047576,
+	"gpt-5":         400000,
+	"o1":            200000,
+	"o1-mini":       128000,
+	"o3":            200000,
+	"o3-mini":       200000,
+	"o4-mini":       200000,
+
+	// Ollama defaults (the effective window also depends on num_ctx)
+	"llama2":   4096,
+	"llama3":   8192,
+	"llama3.1": 131072,
+	"llama3.2": 131072,
+	"mistral":  32768,
+	"mixtral":  32768,
+	"gemma2":   8192,
+	"gemma3":   131072,
+	"qwen2.5":  32768,
+	"phi3":     4096,
+}
+
+// ContextWindow returns the context size in tokens for model, falling back to
+// a conservative default when the model is not in the catalog.
+func ContextWindow(model string) int {
+	if size, ok := lookupModel(modelContextWindows, model); ok {
+		return size
+	}
+	return defaultContextWindow
+}
+
+// lookupModel finds model in a catalog keyed by model name prefixes, using
+// the longest matching prefix.
+func lookupModel[V any](catalog map[string]V, model string) (V, bool) {
+	model = strings.ToLower(strings.TrimSpace(model))
+
+	var (
+		best  string
+		value V
+		found bool
+	)
+	for prefix, v := range catalog {
+		if strings.HasPrefix(model, prefix) && len(prefix) > len(best) {
+			best, value, found = prefix, v, true
+		}
+	}
+	return value, found
+}
+
+// EstimateTokens approximates the token count of text using the common
+// heuristic of ~4 characters per token. It errs on the high side for short strings.
+func EstimateTokens(text string) int {
+	if text == "" {
+		return 0
+	}
+	return (len(text) + 3) / 4
+}
+
+// EstimateMessageTokens approximates the prompt tokens used by messages.
+func EstimateMessageTokens(messages []Message) int {
+	total := 0
+	for _, m := range messages {
+		total += EstimateTokens(m.Content) + messageOverheadTokens
+	}
+	return total
+}
+
+// FitToContext drops the oldest non-system messages until the conversation fits
+// in model's context window with reserveForOutput tokens left for the reply.
+// System messages and the most recent message are always kept, and the original
+// order is preserved. The input slice is not modified.
+func FitToContext(messages []Message, model string, reserveForOutput int) ([]Message, error) {
+	if len(messages) == 0 {
+		return messages, nil
+	}
+
+	budget := ContextWindow(model) - reserveForOutput
+	if EstimateMessageTokens(messages) <= budget {
+		return messages, nil
+	}
+
+	last := len(messages) - 1
+	keep := make([]bool, len(messages))
+	used := 0
+	for i, m := range messages {
+		if m.Role == RoleSystem || i == last {
+			keep[i] = true
+			used += EstimateTokens(m.Content) + messageOverheadTokens
+		}
+	}
+	if used > budget {
+		return nil, ErrContextExceeded
+	}
+
+	// Walk backwards so the most recent turns win the remaining budget.
+	for i := last - 1; i >= 0; i-- {
+		if keep[i] {
+			continue
+		}
+		cost := EstimateTokens(messages[i].Content) + messageOverheadTokens
+		if used+cost > budget {
+			break
+		}
+		keep[i] = true
+		used += cost
+	}
+
+	out := make([]Message, 0, len(messages))
+	for i, m := range messages {
+		if keep[i] {
+			out = append(out, m)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,164 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+
+	localembeddings "github.com/Joepolymath/DaVinci/libs/shared-go/infra/ai/local/embeddings"
+	openaiembeddings "github.com/Joepolymath/DaVinci/libs/shared-go/infra/ai/openai/embeddings"
+	"go.uber.org/zap"
+)
+
+// EmbeddingsProvider turns texts into vectors, one per input and in input order.
+type EmbeddingsProvider interface {
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+
+	IsEnabled() bool
+
+	GetModel() string
+}
+
+type EmbeddingsProviderConfig struct {
+	Provider ProviderType
+
+	// OpenAI-specific
+	OpenAIAPIKey     string
+	OpenAIModel      string // default: text-embedding-3-small
+	OpenAIDimensions int    // optional: shorten text-embedding-3-* vectors
+
+	// Local (Ollama)-specific
+	LocalHost  string
+	LocalModel string // default: nomic-embed-text
+
+	// Batch overrides the per-provider batching defaults.
+	Batch EmbeddingsBatchConfig
+}
+
+// defaultEmbeddingsBatch holds batching defaults per provider. OpenAI accepts up
+// to 2048 inputs and 300k tokens per request; Ollama embeds sequentially, so
+// large batches only add latency to each request.
+var defaultEmbeddingsBatch = map[ProviderType]EmbeddingsBatchConfig{
+	ProviderOpenAI: {BatchSize: 2048, MaxBatchTokens: 250000, Concurrency: 4},
+	ProviderLocal:  {BatchSize: 64, Concurrency: 1},
+}
+
+// NewEmbeddingsProvider creates the configured provider wrapped in a
+// BatchedEmbeddingsProvider, so Embed accepts any number of inputs.
+func NewEmbeddingsProvider(cfg *EmbeddingsProviderConfig, logger *zap.Logger) (EmbeddingsProvider, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("embeddings provider config is required")
+	}
+
+	provider, err := newEmbeddingsProvider(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	batch := defaultEmbeddingsBatch[cfg.Provider]
+	if cfg.Batch.BatchSize > 0 {
+		batch.BatchSize = cfg.Batch.BatchSize
+	}
+	if cfg.Batch.MaxBatchTokens > 0 {
+		batch.MaxBatchTokens = cfg.Batch.MaxBatchTokens
+	}
+	if cfg.Batch.Concurrency > 0 {
+		batch.Concurrency = cfg.Batch.Concurrency
+	}
+	return NewBatchedEmbeddingsProvider(provider, batch), nil
+}
+
+func newEmbeddingsProvider(cfg *EmbeddingsProviderConfig, logger *zap.Logger) (EmbeddingsProvider, error) {
+	switch cfg.Provider {
+	case ProviderOpenAI:
+		client, err := openaiembeddings.NewClient(&openaiembeddings.Config{
+			APIKey:     cfg.OpenAIAPIKey,
+			Model:      cfg.OpenAIModel,
+			Dimensions: cfg.OpenAIDimensions,
+		}, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OpenAI embeddings client: %w", err)
+		}
+		return &openAIEmbeddingsAdapter{client: client}, nil
+	case ProviderLocal:
+		client, err := localembeddings.NewClient(&localembeddings.Config{
+			Host:  cfg.LocalHost,
+			Model: cfg.LocalModel,
+		}, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create local embeddings client: %w", err)
+		}
+		return &localEmbeddingsAdapter{client: client}, nil
+	default:
+		return nil, fmt.Errorf("unsupported embeddings provider: %q (supported: %q, %q)", cfg.Provider, ProviderOpenAI, ProviderLocal)
+	}
+}
+
+type openAIEmbeddingsAdapter struct {
+	client *openaiembeddings.Client
+}
+
+func (a *openAIEmbeddingsAdapter) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	result, err := a.client.CreateEmbeddings(ctx, "", texts)
+	if err != nil {
+		return nil, err
+	}
+	return result.Embeddings, nil
+}
+
+func (a *openAIEmbeddingsAdapter) EmbedWithUsage(ctx context.Context, texts []string) (*EmbeddingsResult, error) {
+	result, err := a.client.CreateEmbeddings(ctx, "", texts)
+	if err != nil {
+		return nil, err
+	}
+	return &EmbeddingsResult{
+		Model:      result.Model,
+		Embeddings: result.Embeddings,
+		Usage: EmbeddingsUsage{
+			PromptTokens: result.Usage.PromptTokens,
+			TotalTokens:  result.Usage.TotalTokens,
+		},
+	}, nil
+}
+
+func (a *openAIEmbeddingsAdapter) IsEnabled() bool {
+	return a.client.IsEnabled()
+}
+
+func (a *openAIEmbeddingsAdapter) GetModel() string {
+	return a.client.GetModel()
+}
+
+type localEmbeddingsAdapter struct {
+	client *localembeddings.Client
+}
+
+func (a *localEmbeddingsAdapter) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	result, err := a.client.CreateEmbeddings(ctx, "", texts)
+	if err != nil {
+		return nil, err
+	}
+	return result.Embeddings, nil
+}
+
+func (a *localEmbeddingsAdapter) EmbedWithUsage(ctx context.Context, texts []string) (*EmbeddingsResult, error) {
+	result, err := a.client.CreateEmbeddings(ctx, "", texts)
+	if err != nil {
+		return nil, err
+	}
+	return &EmbeddingsResult{
+		Model:      result.Model,
+		Embeddings: result.Embeddings,
+		Usage: EmbeddingsUsage{
+			PromptTokens: result.PromptTokens,
+			TotalTokens:  result.PromptTokens,
+		},
+	}, nil
+}
+
+func (a *localEmbeddingsAdapter) IsEnabled() bool {
+	return a.client.IsEnabled()
+}
+
+func (a *localEmbeddingsAdapter) GetModel() string {
+	return a.client.GetModel()
+}
@@ -0,0 +1,165 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// EmbeddingsUsage is the token usage of one or more embeddings calls.
+type EmbeddingsUsage struct {
+	PromptTokens int `json:"prompt_tokens"`
+	TotalTokens  int `json:"total_tokens"`
+}
+
+// EmbeddingsResult holds one embedding per input, in input order, and the
+// usage summed over every request made to produce them.
+type EmbeddingsResult struct {
+	Model      string          `json:"model"`
+	Embeddings [][]float32     `json:"embeddings"`
+	Usage      EmbeddingsUsage `json:"usage"`
+}
+
+// UsageEmbedder is implemented by providers that report token usage.
+type UsageEmbedder interface {
+	EmbedWithUsage(ctx context.Context, texts []string) (*EmbeddingsResult, error)
+}
+
+// EmbeddingsBatchConfig controls how BatchedEmbeddingsProvider splits inputs.
+// Zero values take the provider defaults chosen by NewEmbeddingsProvider.
+type EmbeddingsBatchConfig struct {
+	BatchSize      int // max inputs per request
+	MaxBatchTokens int // max estimated tokens per request; 0 means no limit
+	Concurrency    int // max requests in flight (default: 1)
+}
+
+// BatchedEmbeddingsProvider splits large inputs into batches, embeds them with
+// bounded concurrency and reassembles the results in input order. The first
+// failing batch cancels the rest.
+type BatchedEmbeddingsProvider struct {
+	EmbeddingsProvider
+	cfg EmbeddingsBatchConfig
+}
+
+// NewBatchedEmbeddingsProvider wraps provider with batching.
+func NewBatchedEmbeddingsProvider(provider EmbeddingsProvider, cfg EmbeddingsBatchConfig) *BatchedEmbeddingsProvider {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 1
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 1
+	}
+	return &BatchedEmbeddingsProvider{EmbeddingsProvider: provider, cfg: cfg}
+}
+
+func (p *BatchedEmbeddingsProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	result, err := p.EmbedWithUsage(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+	return result.Embeddings, nil
+}
+
+func (p *BatchedEmbeddingsProvider) EmbedWithUsage(ctx context.Context, texts []string) (*EmbeddingsResult, error) {
+	if len(texts) == 0 {
+		return nil, errors.New("input cannot be empty")
+	}
+
+	batches := p.split(texts)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		sem      = make(chan struct{}, p.cfg.Concurrency)
+	)
+	result := &EmbeddingsResult{
+		Model:      p.GetModel(),
+		Embeddings: make([][]float32, len(texts)),
+	}
+
+	for _, b := range batches {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			batch, err := p.embedBatch(ctx, texts[b.start:b.end])
+			if err == nil && len(batch.Embeddings) != b.end-b.start {
+				err = fmt.Errorf("expected %d embeddings, got %d", b.end-b.start, len(batch.Embeddings))
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("failed to embed inputs %d-%d: %w", b.start, b.end-1, err)
+					cancel()
+				}
+				return
+			}
+			copy(result.Embeddings[b.start:b.end], batch.Embeddings)
+			if batch.Model != "" {
+				result.Model = batch.Model
+			}
+			result.Usage.PromptTokens += batch.Usage.PromptTokens
+			result.Usage.TotalTokens += batch.Usage.TotalTokens
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (p *BatchedEmbeddingsProvider) embedBatch(ctx context.Context, texts []string) (*EmbeddingsResult, error) {
+	if u, ok := p.EmbeddingsProvider.(UsageEmbedder); ok {
+		return u.EmbedWithUsage(ctx, texts)
+	}
+	embeddings, err := p.EmbeddingsProvider.Embed(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+	return &EmbeddingsResult{Embeddings: embeddings}, nil
+}
+
+type batchRange struct{ start, end int }
+
+// split cuts texts into consecutive batches bounded by BatchSize inputs and
+// MaxBatchTokens estimated tokens. An input larger than MaxBatchTokens gets
+// a batch of its own; the provider decides whether to truncate or reject it.
+func (p *BatchedEmbeddingsProvider) split(texts []string) []batchRange {
+	var (
+		batches []batchRange
+		start   int
+		tokens  int
+	)
+	for i, text := range texts {
+		n := EstimateTokens(text)
+		full := i-start >= p.cfg.BatchSize ||
+			(p.cfg.MaxBatchTokens > 0 && i > start && tokens+n > p.cfg.MaxBatchTokens)
+		if full {
+			batches = append(batches, batchRange{start, i})
+			start, tokens = i, 0
+		}
+		tokens += n
+	}
+	return append(batches, batchRange{start, len(texts)})
+}
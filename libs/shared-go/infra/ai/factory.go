@@ -2,7 +2,12 @@ package ai
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"time"
 
 	localchats "github.com/Joepolymath/DaVinci/libs/shared-go/infra/ai/local/chats"
 	openaichats "github.com/Joepolymath/DaVinci/libs/shared-go/infra/ai/openai/chats"
@@ -12,13 +17,54 @@ import (
 type ChatProviderConfig struct {
 	Provider ProviderType
 
+	// Timeout overrides the underlying HTTP client timeout for non-streaming requests.
+	Timeout time.Duration
+
+	// Headers and QueryParams are added to every provider request (gateways, proxies).
+	Headers     map[string]string
+	QueryParams map[string]string
+
+	// HTTPClient or Transport replace the default HTTP stack (proxies, mTLS, instrumentation).
+	HTTPClient *http.Client
+	Transport  http.RoundTripper
+
+	// ModelAliases maps logical model names used in ChatOptions.Model onto
+	// provider model IDs, e.g. {"fast": "gpt-4o-mini"}.
+	ModelAliases ModelAliases
+
+	// Middleware wraps the provider returned by NewChatProvider; the first
+	// entry is the outermost. Middleware sees ChatOptions.Model before alias resolution.
+	Middleware []ChatMiddleware
+
+	// ModelsCacheTTL is how long ListModels results are cached (default: 5m).
+	ModelsCacheTTL time.Duration
+
 	// OpenAI-specific
-	OpenAIAPIKey string
-	OpenAIModel  string
+	OpenAIAPIKey  string
+	OpenAIModel   string
+	OpenAIBaseURL string
+	// OpenAIAPIKeyFunc, if set, is read on every request instead of
+	// OpenAIAPIKey, e.g. to follow a key rotated in a secrets manager.
+	OpenAIAPIKeyFunc func() string
+
+	// OpenAIAPIKeys and LocalHosts configure several equivalent backends that are
+	// load balanced with Balancing. They take precedence over OpenAIAPIKey/LocalHost.
+	OpenAIAPIKeys []string
+	LocalHosts    []string
+	Balancing     BalanceStrategy
+	// OpenAIAPIKeyFuncs, if set, has an entry per OpenAIAPIKeys entry; a
+	// non-nil one is read on every request instead of that key.
+	OpenAIAPIKeyFuncs []func() string
+
+	// OpenAIUseResponsesAPI routes requests through /v1/responses instead of chat completions.
+	OpenAIUseResponsesAPI bool
 
 	// Local (Ollama)-specific
-	LocalHost  string
-	LocalModel string
+	LocalHost        string
+	LocalModel       string
+	LocalKeepAlive   time.Duration // how long Ollama keeps the model loaded; negative means forever
+	LocalWarmup      bool          // load the model in the background at startup
+	LocalHealthProbe bool          // Health also runs a one-token generation
 }
 
 func NewChatProvider(cfg *ChatProviderConfig, logger *zap.Logger) (ChatProvider, error) {
@@ -26,36 +72,116 @@ func NewChatProvider(cfg *ChatProviderConfig, logger *zap.Logger) (ChatProvider,
 		return nil, fmt.Errorf("chat provider config is required")
 	}
 
+	provider, err := newChatProvider(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+	return Chain(WithModelAliases(provider, cfg.ModelAliases), cfg.Middleware...), nil
+}
+
+func newChatProvider(cfg *ChatProviderConfig, logger *zap.Logger) (ChatProvider, error) {
 	switch cfg.Provider {
 	case ProviderOpenAI:
+		if len(cfg.OpenAIAPIKeys) > 0 {
+			return newBalanced(cfg, logger, cfg.OpenAIAPIKeys, func(c *ChatProviderConfig, i int, key string) (ChatProvider, error) {
+				c.OpenAIAPIKey, c.OpenAIAPIKeyFunc = key, nil
+				if i < len(cfg.OpenAIAPIKeyFuncs) {
+					c.OpenAIAPIKeyFunc = cfg.OpenAIAPIKeyFuncs[i]
+				}
+				return newOpenAIAdapter(c, logger)
+			})
+		}
 		return newOpenAIAdapter(cfg, logger)
 	case ProviderLocal:
+		if len(cfg.LocalHosts) > 0 {
+			return newBalanced(cfg, logger, cfg.LocalHosts, func(c *ChatProviderConfig, _ int, host string) (ChatProvider, error) {
+				c.LocalHost = host
+				return newLocalAdapter(c, logger)
+			})
+		}
 		return newLocalAdapter(cfg, logger)
 	default:
 		return nil, fmt.Errorf("unsupported chat provider: %q (supported: %q, %q)", cfg.Provider, ProviderOpenAI, ProviderLocal)
 	}
 }
 
+// newBalanced builds one backend per value (API key or host) from a copy of
+// cfg and wraps them in a BalancedProvider.
+func newBalanced(cfg *ChatProviderConfig, logger *zap.Logger, values []string, build func(c *ChatProviderConfig, i int, value string) (ChatProvider, error)) (ChatProvider, error) {
+	backends := make([]ChatProvider, 0, len(values))
+	for i, v := range values {
+		c := *cfg
+		backend, err := build(&c, i, v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create backend %d: %w", i, err)
+		}
+		backends = append(backends, backend)
+	}
+
+	logger.Info("Load balancing chat provider",
+		zap.String("provider", string(cfg.Provider)),
+		zap.Int("backends", len(backends)),
+		zap.String("strategy", string(cfg.Balancing)))
+
+	return NewBalancedProvider(cfg.Balancing, backends...)
+}
+
+// KeyedProviders are the providers whose requests can carry a caller's own
+// API key, set with WithAPIKey.
+var KeyedProviders = []ProviderType{ProviderOpenAI}
+
+// WithAPIKey makes providers of type provider answer requests made with
+// ctx using key instead of their configured key. It returns ctx unchanged
+// for providers not in KeyedProviders.
+func WithAPIKey(ctx context.Context, provider ProviderType, key string) context.Context {
+	switch provider {
+	case ProviderOpenAI:
+		return WithOpenAIAPIKey(ctx, key)
+	default:
+		return ctx
+	}
+}
+
 // ---------------------------------------------------------------------------
 // OpenAI adapter
 // ---------------------------------------------------------------------------
 
+// WithOpenAIAPIKey makes OpenAI providers answer requests made with ctx
+// using key instead of their configured key; see openaichats.WithAPIKey.
+func WithOpenAIAPIKey(ctx context.Context, key string) context.Context {
+	return openaichats.WithAPIKey(ctx, key)
+}
+
 type openAIAdapter struct {
 	client *openaichats.Client
+	models *modelCache
 }
 
 func newOpenAIAdapter(cfg *ChatProviderConfig, logger *zap.Logger) (*openAIAdapter, error) {
 	client, err := openaichats.NewClient(&openaichats.Config{
-		APIKey: cfg.OpenAIAPIKey,
-		Model:  cfg.OpenAIModel,
+		APIKey:          cfg.OpenAIAPIKey,
+		APIKeyFunc:      cfg.OpenAIAPIKeyFunc,
+		Model:           cfg.OpenAIModel,
+		Timeout:         cfg.Timeout,
+		BaseURL:         cfg.OpenAIBaseURL,
+		Headers:         cfg.Headers,
+		QueryParams:     cfg.QueryParams,
+		HTTPClient:      cfg.HTTPClient,
+		Transport:       cfg.Transport,
+		UseResponsesAPI: cfg.OpenAIUseResponsesAPI,
 	}, logger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create OpenAI chat client: %w", err)
 	}
-	return &openAIAdapter{client: client}, nil
+	a := &openAIAdapter{client: client}
+	a.models = newModelCache(cfg.ModelsCacheTTL, a.fetchModels)
+	return a, nil
 }
 
 func (a *openAIAdapter) Completion(ctx context.Context, messages []Message, opts *ChatOptions) (*ChatResponse, error) {
+	ctx, cancel := withTimeout(ctx, opts)
+	defer cancel()
+
 	oaiMsgs := toOpenAIMessages(messages)
 	oaiOpts := toOpenAIOptions(opts)
 
@@ -64,47 +190,110 @@ func (a *openAIAdapter) Completion(ctx context.Context, messages []Message, opts
 		return nil, err
 	}
 
-	content := ""
-	if len(resp.Choices) > 0 {
-		content = resp.Choices[0].Message.Content
-	}
-
-	return &ChatResponse{
-		Model:   resp.Model,
-		Content: content,
+	out := &ChatResponse{
+		Model:             resp.Model,
+		SystemFingerprint: resp.SystemFingerprint,
+		RequestID:         resp.RequestID,
 		Usage: ChatUsage{
 			PromptTokens:     resp.Usage.PromptTokens,
 			CompletionTokens: resp.Usage.CompletionTokens,
 			TotalTokens:      resp.Usage.TotalTokens,
 		},
-	}, nil
+	}
+
+	out.EstimatedCost = EstimateCost(resp.Model, out.Usage)
+
+	if len(resp.Choices) > 0 {
+		out.Content = resp.Choices[0].Message.Content
+		out.FinishReason = resp.Choices[0].FinishReason
+		out.Logprobs = fromOpenAILogprobs(resp.Choices[0].Logprobs)
+	}
+
+	if len(resp.Choices) > 1 {
+		out.Choices = make([]ChatChoice, len(resp.Choices))
+		for i, c := range resp.Choices {
+			out.Choices[i] = ChatChoice{
+				Index:        c.Index,
+				Content:      c.Message.Content,
+				FinishReason: c.FinishReason,
+				Logprobs:     fromOpenAILogprobs(c.Logprobs),
+			}
+		}
+	}
+
+	return out, nil
 }
 
 func (a *openAIAdapter) CompletionStream(ctx context.Context, messages []Message, opts *ChatOptions, onDelta func(delta ChatStreamDelta) error) error {
+	ctx, cancel := withTimeout(ctx, opts)
+	defer cancel()
+
 	oaiMsgs := toOpenAIMessages(messages)
 	oaiOpts := toOpenAIOptions(opts)
 
 	return a.client.CompletionStream(ctx, oaiMsgs, oaiOpts, func(chunk openaichats.StreamChunk) error {
-		content := ""
-		finishReason := ""
-		done := false
-
-		if len(chunk.Choices) > 0 {
-			content = chunk.Choices[0].Delta.Content
-			finishReason = chunk.Choices[0].FinishReason
-			done = finishReason == "stop"
+		// With stream_options.include_usage the final chunk has no choices
+		// and carries the usage for the whole request. It comes after the
+		// chunk that finishes the answer, which is the Done delta.
+		if chunk.Usage != nil {
+			return onDelta(ChatStreamDelta{
+				RequestID: chunk.RequestID,
+				Usage: &ChatUsage{
+					PromptTokens:     chunk.Usage.PromptTokens,
+					CompletionTokens: chunk.Usage.CompletionTokens,
+					TotalTokens:      chunk.Usage.TotalTokens,
+				},
+			})
+		}
+		if len(chunk.Choices) == 0 {
+			return onDelta(ChatStreamDelta{})
 		}
 
-		return onDelta(ChatStreamDelta{
-			Content:      content,
-			Done:         done,
-			FinishReason: finishReason,
-		})
+		// With N > 1 each chunk may carry deltas for several candidates.
+		for _, choice := range chunk.Choices {
+			if err := onDelta(ChatStreamDelta{
+				Index:        choice.Index,
+				Content:      choice.Delta.Content,
+				Done:         choice.FinishReason != "",
+				FinishReason: choice.FinishReason,
+				Logprobs:     fromOpenAILogprobs(choice.Logprobs),
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
 	})
 }
 
 func (a *openAIAdapter) Health(ctx context.Context) error {
-	return a.client.Health(ctx)
+	err := a.client.Health(ctx)
+	if errors.Is(err, openaichats.ErrModelNotAvailable) {
+		return fmt.Errorf("%w: %w", ErrModelNotAvailable, err)
+	}
+	return err
+}
+
+func (a *openAIAdapter) ListModels(ctx context.Context) ([]ModelInfo, error) {
+	return a.models.get(ctx)
+}
+
+func (a *openAIAdapter) fetchModels(ctx context.Context) ([]ModelInfo, error) {
+	models, err := a.client.ListModels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]ModelInfo, len(models))
+	for i, m := range models {
+		out[i] = ModelInfo{
+			ID:       m.ID,
+			Provider: ProviderOpenAI,
+			OwnedBy:  m.OwnedBy,
+		}
+		if m.Created > 0 {
+			out[i].Created = time.Unix(m.Created, 0).UTC()
+		}
+	}
+	return out, nil
 }
 
 func (a *openAIAdapter) IsEnabled() bool {
@@ -119,22 +308,48 @@ func (a *openAIAdapter) GetModel() string {
 // Local (Ollama) adapter
 // ---------------------------------------------------------------------------
 
+// localWarmupTimeout bounds the background model load started by LocalWarmup.
+const localWarmupTimeout = 10 * time.Minute
+
 type localAdapter struct {
 	client *localchats.Client
+	models *modelCache
 }
 
 func newLocalAdapter(cfg *ChatProviderConfig, logger *zap.Logger) (*localAdapter, error) {
 	client, err := localchats.NewClient(&localchats.Config{
-		Host:  cfg.LocalHost,
-		Model: cfg.LocalModel,
+		Host:        cfg.LocalHost,
+		Model:       cfg.LocalModel,
+		Timeout:     cfg.Timeout,
+		Headers:     cfg.Headers,
+		QueryParams: cfg.QueryParams,
+		HTTPClient:  cfg.HTTPClient,
+		Transport:   cfg.Transport,
+		KeepAlive:   cfg.LocalKeepAlive,
+		HealthProbe: cfg.LocalHealthProbe,
 	}, logger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create local LLM chat client: %w", err)
 	}
-	return &localAdapter{client: client}, nil
+	a := &localAdapter{client: client}
+	a.models = newModelCache(cfg.ModelsCacheTTL, a.fetchModels)
+
+	if cfg.LocalWarmup {
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), localWarmupTimeout)
+			defer cancel()
+			// Failures are logged by the client; the first request will load the model instead.
+			_ = client.Warmup(ctx)
+		}()
+	}
+
+	return a, nil
 }
 
 func (a *localAdapter) Completion(ctx context.Context, messages []Message, opts *ChatOptions) (*ChatResponse, error) {
+	ctx, cancel := withTimeout(ctx, opts)
+	defer cancel()
+
 	localMsgs := toLocalMessages(messages)
 	localOpts := toLocalOptions(opts)
 
@@ -144,31 +359,74 @@ func (a *localAdapter) Completion(ctx context.Context, messages []Message, opts
 	}
 
 	// Ollama doesn't report standard token counts; approximate from eval counts.
-	return &ChatResponse{
-		Model:   resp.Model,
-		Content: resp.Message.Content,
+	out := &ChatResponse{
+		Model:        resp.Model,
+		Content:      resp.Message.Content,
+		FinishReason: resp.DoneReason,
 		Usage: ChatUsage{
 			PromptTokens:     resp.PromptEvalCount,
 			CompletionTokens: resp.EvalCount,
 			TotalTokens:      resp.PromptEvalCount + resp.EvalCount,
 		},
-	}, nil
+	}
+	out.EstimatedCost = EstimateCost(resp.Model, out.Usage)
+	return out, nil
 }
 
 func (a *localAdapter) CompletionStream(ctx context.Context, messages []Message, opts *ChatOptions, onDelta func(delta ChatStreamDelta) error) error {
+	ctx, cancel := withTimeout(ctx, opts)
+	defer cancel()
+
 	localMsgs := toLocalMessages(messages)
 	localOpts := toLocalOptions(opts)
 
 	return a.client.CompletionStream(ctx, localMsgs, localOpts, func(chunk localchats.StreamChunk) error {
-		return onDelta(ChatStreamDelta{
-			Content: chunk.Message.Content,
-			Done:    chunk.Done,
-		})
+		delta := ChatStreamDelta{
+			Content:      chunk.Message.Content,
+			Done:         chunk.Done,
+			FinishReason: chunk.DoneReason,
+		}
+		if chunk.Done {
+			delta.Usage = &ChatUsage{
+				PromptTokens:     chunk.PromptEvalCount,
+				CompletionTokens: chunk.EvalCount,
+				TotalTokens:      chunk.PromptEvalCount + chunk.EvalCount,
+			}
+		}
+		return onDelta(delta)
 	})
 }
 
 func (a *localAdapter) Health(ctx context.Context) error {
-	return a.client.Health(ctx)
+	err := a.client.Health(ctx)
+	if errors.Is(err, localchats.ErrModelNotFound) {
+		return fmt.Errorf("%w: %w", ErrModelNotAvailable, err)
+	}
+	return err
+}
+
+func (a *localAdapter) ListModels(ctx context.Context) ([]ModelInfo, error) {
+	return a.models.get(ctx)
+}
+
+func (a *localAdapter) fetchModels(ctx context.Context) ([]ModelInfo, error) {
+	models, err := a.client.ListModels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]ModelInfo, len(models))
+	for i, m := range models {
+		out[i] = ModelInfo{
+			ID:       m.Name,
+			Provider: ProviderLocal,
+			Size:     m.Size,
+			Family:   m.Details.Family,
+		}
+		if t, err := time.Parse(time.RFC3339Nano, m.ModifiedAt); err == nil {
+			out[i].Created = t
+		}
+	}
+	return out, nil
 }
 
 func (a *localAdapter) IsEnabled() bool {
@@ -183,6 +441,14 @@ func (a *localAdapter) GetModel() string {
 // Type conversion helpers
 // ---------------------------------------------------------------------------
 
+// withTimeout derives a context bounded by opts.Timeout, if one is set.
+func withTimeout(ctx context.Context, opts *ChatOptions) (context.Context, context.CancelFunc) {
+	if opts == nil || opts.Timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, opts.Timeout)
+}
+
 func toOpenAIMessages(msgs []Message) []openaichats.Message {
 	out := make([]openaichats.Message, len(msgs))
 	for i, m := range msgs {
@@ -196,17 +462,80 @@ func toOpenAIOptions(opts *ChatOptions) *openaichats.Options {
 		return nil
 	}
 	return &openaichats.Options{
+		Model:       opts.Model,
 		Temperature: opts.Temperature,
 		TopP:        opts.TopP,
 		MaxTokens:   opts.MaxTokens,
 		Stop:        opts.Stop,
+		Logprobs:    opts.Logprobs,
+		TopLogprobs: opts.TopLogprobs,
+		N:           opts.N,
+
+		FrequencyPenalty: opts.FrequencyPenalty,
+		PresencePenalty:  opts.PresencePenalty,
+		Seed:             opts.Seed,
+
+		ReasoningEffort:     opts.ReasoningEffort,
+		MaxCompletionTokens: opts.MaxCompletionTokens,
+
+		Tools:          toOpenAITools(opts.BuiltinTools),
+		ResponseFormat: toOpenAIResponseFormat(opts.ResponseFormat),
 	}
 }
 
+func toOpenAIResponseFormat(rf *ResponseFormat) *openaichats.ResponseFormat {
+	if rf == nil {
+		return nil
+	}
+	out := &openaichats.ResponseFormat{Type: rf.Type}
+	if rf.Type == ResponseFormatJSONSchema {
+		name := rf.Name
+		if name == "" {
+			name = "response"
+		}
+		out.JSONSchema = &openaichats.JSONSchema{Name: name, Schema: rf.Schema, Strict: rf.Strict}
+	}
+	return out
+}
+
+func toOpenAITools(types []string) []openaichats.Tool {
+	if len(types) == 0 {
+		return nil
+	}
+	out := make([]openaichats.Tool, len(types))
+	for i, t := range types {
+		out[i] = openaichats.Tool{Type: t}
+	}
+	return out
+}
+
+func fromOpenAILogprobs(lp *openaichats.Logprobs) []TokenLogprob {
+	if lp == nil || len(lp.Content) == 0 {
+		return nil
+	}
+	out := make([]TokenLogprob, len(lp.Content))
+	for i, t := range lp.Content {
+		out[i] = TokenLogprob{Token: t.Token, Logprob: t.Logprob, Bytes: t.Bytes}
+		if len(t.TopLogprobs) > 0 {
+			out[i].TopLogprobs = make([]TokenLogprob, len(t.TopLogprobs))
+			for j, top := range t.TopLogprobs {
+				out[i].TopLogprobs[j] = TokenLogprob{Token: top.Token, Logprob: top.Logprob, Bytes: top.Bytes}
+			}
+		}
+	}
+	return out
+}
+
 func toLocalMessages(msgs []Message) []localchats.Message {
 	out := make([]localchats.Message, len(msgs))
 	for i, m := range msgs {
 		out[i] = localchats.Message{Role: m.Role, Content: m.Content}
+		if len(m.Images) > 0 {
+			out[i].Images = make([]string, len(m.Images))
+			for j, img := range m.Images {
+				out[i].Images[j] = base64.StdEncoding.EncodeToString(img)
+			}
+		}
 	}
 	return out
 }
@@ -215,10 +544,52 @@ func toLocalOptions(opts *ChatOptions) *localchats.Options {
 	if opts == nil {
 		return nil
 	}
+	maxTokens := opts.MaxTokens
+	if maxTokens == 0 {
+		maxTokens = opts.MaxCompletionTokens
+	}
 	return &localchats.Options{
+		Model:       opts.Model,
 		Temperature: opts.Temperature,
 		TopP:        opts.TopP,
-		MaxTokens:   opts.MaxTokens,
+		MaxTokens:   maxTokens,
 		Stop:        opts.Stop,
+
+		FrequencyPenalty: opts.FrequencyPenalty,
+		PresencePenalty:  opts.PresencePenalty,
+		RepeatPenalty:    toRepeatPenalty(opts.FrequencyPenalty),
+		Seed:             opts.Seed,
+
+		Format: toLocalFormat(opts.ResponseFormat),
+	}
+}
+
+// toLocalFormat maps the unified response format onto Ollama's format field:
+// "json" for JSON mode, or the schema itself for structured outputs.
+func toLocalFormat(rf *ResponseFormat) json.RawMessage {
+	if rf == nil {
+		return nil
+	}
+	switch rf.Type {
+	case ResponseFormatJSONObject:
+		return localchats.FormatJSON
+	case ResponseFormatJSONSchema:
+		if len(rf.Schema) > 0 {
+			return rf.Schema
+		}
+		return localchats.FormatJSON
+	default:
+		return nil
+	}
+}
+
+// toRepeatPenalty maps an OpenAI-style frequency penalty (-2.0 to 2.0, neutral 0)
+// onto Ollama's multiplicative repeat_penalty (0.0 to 2.0, neutral 1.0).
+// Not every Ollama runner honours frequency_penalty, but all honour repeat_penalty.
+func toRepeatPenalty(frequencyPenalty *float64) *float64 {
+	if frequencyPenalty == nil {
+		return nil
 	}
+	p := 1 + *frequencyPenalty/2
+	return &p
 }
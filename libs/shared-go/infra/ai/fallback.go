@@ -0,0 +1,165 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// openAIMaxStop is the number of stop sequences the OpenAI API accepts.
+const openAIMaxStop = 4
+
+// FallbackBackend is one provider in a FallbackProvider chain. Type tells
+// AdaptOptions which option constraints apply to the backend.
+type FallbackBackend struct {
+	Name     string
+	Type     ProviderType
+	Provider ChatProvider
+}
+
+// FallbackProvider sends each request to the first backend and, if it fails,
+// retries on the next one with options adapted to that backend. Streams only
+// fail over if nothing has been delivered to the caller yet.
+type FallbackProvider struct {
+	backends []FallbackBackend
+	logger   *zap.Logger
+}
+
+// NewFallbackProvider creates a fallback chain; backends are tried in order.
+func NewFallbackProvider(backends []FallbackBackend, logger *zap.Logger) (*FallbackProvider, error) {
+	if len(backends) == 0 {
+		return nil, errors.New("fallback provider requires at least one backend")
+	}
+	for _, b := range backends {
+		if b.Provider == nil {
+			return nil, fmt.Errorf("fallback backend %q has no provider", b.Name)
+		}
+	}
+	return &FallbackProvider{backends: backends, logger: logger}, nil
+}
+
+func (p *FallbackProvider) Completion(ctx context.Context, messages []Message, opts *ChatOptions) (*ChatResponse, error) {
+	var errs []error
+	for i, b := range p.backends {
+		resp, err := b.Provider.Completion(ctx, messages, p.optionsFor(i, messages, opts))
+		if err == nil {
+			return resp, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", b.Name, err))
+		if !p.shouldFailOver(ctx, i, err) {
+			break
+		}
+	}
+	return nil, errors.Join(errs...)
+}
+
+func (p *FallbackProvider) CompletionStream(ctx context.Context, messages []Message, opts *ChatOptions, onDelta func(delta ChatStreamDelta) error) error {
+	var errs []error
+	for i, b := range p.backends {
+		delivered := false
+		err := b.Provider.CompletionStream(ctx, messages, p.optionsFor(i, messages, opts), func(delta ChatStreamDelta) error {
+			delivered = true
+			return onDelta(delta)
+		})
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", b.Name, err))
+		if delivered || !p.shouldFailOver(ctx, i, err) {
+			break
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Health succeeds if any backend is healthy.
+func (p *FallbackProvider) Health(ctx context.Context) error {
+	var errs []error
+	for _, b := range p.backends {
+		err := b.Provider.Health(ctx)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", b.Name, err))
+	}
+	return errors.Join(errs...)
+}
+
+// ListModels returns the models of the primary backend.
+func (p *FallbackProvider) ListModels(ctx context.Context) ([]ModelInfo, error) {
+	return p.backends[0].Provider.ListModels(ctx)
+}
+
+func (p *FallbackProvider) IsEnabled() bool {
+	for _, b := range p.backends {
+		if b.Provider.IsEnabled() {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *FallbackProvider) GetModel() string {
+	return p.backends[0].Provider.GetModel()
+}
+
+// optionsFor returns opts unchanged for the primary and adapted for fallbacks.
+func (p *FallbackProvider) optionsFor(i int, messages []Message, opts *ChatOptions) *ChatOptions {
+	if i == 0 {
+		return opts
+	}
+	b := p.backends[i]
+	return AdaptOptions(opts, messages, b.Type, b.Provider.GetModel())
+}
+
+// shouldFailOver reports whether the request should move on from backend i.
+// Cancellation by the caller is never retried.
+func (p *FallbackProvider) shouldFailOver(ctx context.Context, i int, err error) bool {
+	if ctx.Err() != nil || i == len(p.backends)-1 {
+		return false
+	}
+	p.logger.Warn("Chat backend failed, falling back",
+		zap.String("backend", p.backends[i].Name),
+		zap.String("fallback", p.backends[i+1].Name),
+		zap.Error(err))
+	return true
+}
+
+// AdaptOptions rewrites opts so a request that was built for another backend
+// is valid for a backend of type target serving model. It never mutates opts.
+//   - Model is cleared so the target uses its own configured model.
+//   - MaxTokens and MaxCompletionTokens are capped to what fits in the target's
+//     context window after the prompt.
+//   - OpenAI accepts at most four stop sequences; extra ones are dropped.
+//   - Ollama has no logprobs, multiple candidates or hosted tools, so those are dropped.
+func AdaptOptions(opts *ChatOptions, messages []Message, target ProviderType, model string) *ChatOptions {
+	if opts == nil {
+		return nil
+	}
+	adapted := *opts
+	adapted.Model = ""
+
+	limit := max(ContextWindow(model)-EstimateMessageTokens(messages), 1)
+	adapted.MaxTokens = min(adapted.MaxTokens, limit)
+	adapted.MaxCompletionTokens = min(adapted.MaxCompletionTokens, limit)
+
+	switch target {
+	case ProviderOpenAI:
+		if len(adapted.Stop) > openAIMaxStop {
+			adapted.Stop = adapted.Stop[:openAIMaxStop]
+		}
+	case ProviderLocal:
+		if adapted.MaxTokens == 0 {
+			adapted.MaxTokens = adapted.MaxCompletionTokens
+		}
+		adapted.MaxCompletionTokens = 0
+		adapted.ReasoningEffort = ""
+		adapted.Logprobs = false
+		adapted.TopLogprobs = nil
+		adapted.N = 0
+		adapted.BuiltinTools = nil
+	}
+	return &adapted
+}
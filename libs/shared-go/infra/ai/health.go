@@ -0,0 +1,186 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultHealthInterval         = 30 * time.Second
+	defaultHealthTimeout          = 10 * time.Second
+	defaultHealthFailureThreshold = 2
+)
+
+// HealthSupervisorConfig tunes background health probing.
+type HealthSupervisorConfig struct {
+	Interval time.Duration // time between probes (default: 30s)
+	Timeout  time.Duration // bound on each Health call (default: 10s)
+
+	// FailureThreshold is the number of consecutive failures before a provider
+	// is marked unhealthy (default: 2). ErrModelNotAvailable marks it
+	// unhealthy immediately. One success marks it healthy again.
+	FailureThreshold int
+}
+
+// ProviderStatus is the last known health of a provider.
+type ProviderStatus struct {
+	Name                string    `json:"name"`
+	Healthy             bool      `json:"healthy"`
+	ModelAvailable      bool      `json:"model_available"`
+	LastChecked         time.Time `json:"last_checked,omitzero"`
+	LastError           string    `json:"last_error,omitempty"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+}
+
+// HealthSupervisor periodically calls Health on a set of providers and tracks
+// their status. RouterProvider consults it to skip unhealthy backends.
+type HealthSupervisor struct {
+	providers map[string]ChatProvider
+	cfg       HealthSupervisorConfig
+	logger    *zap.Logger
+
+	mu     sync.RWMutex
+	status map[string]*ProviderStatus
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewHealthSupervisor creates a supervisor. Providers are assumed healthy
+// until the first probe says otherwise. Call Start to begin probing.
+func NewHealthSupervisor(providers map[string]ChatProvider, cfg HealthSupervisorConfig, logger *zap.Logger) *HealthSupervisor {
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultHealthInterval
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultHealthTimeout
+	}
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = defaultHealthFailureThreshold
+	}
+
+	status := make(map[string]*ProviderStatus, len(providers))
+	for name := range providers {
+		status[name] = &ProviderStatus{Name: name, Healthy: true, ModelAvailable: true}
+	}
+
+	return &HealthSupervisor{
+		providers: providers,
+		cfg:       cfg,
+		logger:    logger,
+		status:    status,
+	}
+}
+
+// Start probes all providers immediately and then every Interval until ctx is
+// done or Stop is called.
+func (h *HealthSupervisor) Start(ctx context.Context) {
+	ctx, h.cancel = context.WithCancel(ctx)
+	h.done = make(chan struct{})
+
+	go func() {
+		defer close(h.done)
+
+		ticker := time.NewTicker(h.cfg.Interval)
+		defer ticker.Stop()
+
+		for {
+			h.CheckNow(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	h.logger.Info("Provider health supervisor started",
+		zap.Int("providers", len(h.providers)),
+		zap.Duration("interval", h.cfg.Interval))
+}
+
+// Stop ends background probing and waits for the current round to finish.
+func (h *HealthSupervisor) Stop() {
+	if h.cancel == nil {
+		return
+	}
+	h.cancel()
+	<-h.done
+}
+
+// CheckNow probes every provider concurrently and updates their status.
+func (h *HealthSupervisor) CheckNow(ctx context.Context) {
+	var wg sync.WaitGroup
+	for name, provider := range h.providers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			probeCtx, cancel := context.WithTimeout(ctx, h.cfg.Timeout)
+			defer cancel()
+			h.record(name, provider.Health(probeCtx))
+		}()
+	}
+	wg.Wait()
+}
+
+// IsHealthy reports the last known health of name. Unknown names are
+// considered healthy so that routing never blocks on missing data.
+func (h *HealthSupervisor) IsHealthy(name string) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	s, ok := h.status[name]
+	return !ok || s.Healthy
+}
+
+// ProvidersStatus returns a snapshot of every provider's status, sorted by name.
+func (h *HealthSupervisor) ProvidersStatus() []ProviderStatus {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	out := make([]ProviderStatus, 0, len(h.status))
+	for _, s := range h.status {
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+func (h *HealthSupervisor) record(name string, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s := h.status[name]
+	wasHealthy := s.Healthy
+	s.LastChecked = time.Now()
+
+	if err == nil {
+		s.Healthy = true
+		s.ModelAvailable = true
+		s.LastError = ""
+		s.ConsecutiveFailures = 0
+		if !wasHealthy {
+			h.logger.Info("Provider recovered", zap.String("provider", name))
+		}
+		return
+	}
+
+	s.LastError = err.Error()
+	s.ConsecutiveFailures++
+	s.ModelAvailable = !errors.Is(err, ErrModelNotAvailable)
+	if !s.ModelAvailable || s.ConsecutiveFailures >= h.cfg.FailureThreshold {
+		s.Healthy = false
+	}
+	if wasHealthy && !s.Healthy {
+		h.logger.Warn("Provider marked unhealthy",
+			zap.String("provider", name),
+			zap.Int("consecutive_failures", s.ConsecutiveFailures),
+			zap.Error(err))
+	}
+}
@@ -1,15 +1,28 @@
 package ai
 
-import "context"
+import (
+	"context"
+	"errors"
+)
 
 type ChatProvider interface {
 	Completion(ctx context.Context, messages []Message, opts *ChatOptions) (*ChatResponse, error)
 
 	CompletionStream(ctx context.Context, messages []Message, opts *ChatOptions, onDelta func(delta ChatStreamDelta) error) error
 
+	// Health reports whether the provider can serve requests for its configured
+	// model. Errors wrap ErrModelNotAvailable when the host is up but the model is missing.
 	Health(ctx context.Context) error
 
+	// ListModels returns the models the provider can serve. Results are cached briefly.
+	ListModels(ctx context.Context) ([]ModelInfo, error)
+
 	IsEnabled() bool
 
 	GetModel() string
 }
+
+// ErrModelNotAvailable is wrapped by Health errors when the provider is
+// reachable but the configured model is missing, so routers can fail over
+// instead of retrying.
+var ErrModelNotAvailable = errors.New("model not available")
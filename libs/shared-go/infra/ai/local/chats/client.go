@@ -16,18 +16,30 @@ import (
 )
 
 const (
-	defaultHost    = "http://localhost:11434"
-	defaultModel   = "llama3:8b"
-	defaultTimeout = 5 * time.Minute
-	chatEndpoint   = "/api/chat"
+	defaultHost             = "http://localhost:11434"
+	defaultModel            = "llama3:8b"
+	defaultTimeout          = 5 * time.Minute
+	defaultStreamBufferSize = 64 * 1024
+	chatEndpoint            = "/api/chat"
+	tagsEndpoint            = "/api/tags"
+	pullEndpoint            = "/api/pull"
+	deleteEndpoint          = "/api/delete"
+	showEndpoint            = "/api/show"
+	generateEndpoint        = "/api/generate"
 )
 
 type Client struct {
-	host       string
-	model      string
-	httpClient *http.Client
-	logger     *zap.Logger
-	enabled    bool
+	host             string
+	model            string
+	headers          map[string]string
+	queryParams      map[string]string
+	httpClient       *http.Client
+	timeout          time.Duration
+	streamBufferSize int
+	keepAlive        time.Duration
+	healthProbe      bool
+	logger           *zap.Logger
+	enabled          bool
 }
 
 func NewClient(cfg *Config, logger *zap.Logger) (*Client, error) {
@@ -45,14 +57,23 @@ func NewClient(cfg *Config, logger *zap.Logger) (*Client, error) {
 		model = defaultModel
 	}
 
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
 	client := &Client{
-		host:  host,
-		model: model,
-		httpClient: &http.Client{
-			Timeout: defaultTimeout,
-		},
-		logger:  logger,
-		enabled: true,
+		host:             host,
+		model:            model,
+		headers:          cfg.Headers,
+		queryParams:      cfg.QueryParams,
+		httpClient:       newHTTPClient(cfg, timeout),
+		timeout:          timeout,
+		streamBufferSize: streamBufferSize(cfg.StreamBufferSize),
+		keepAlive:        cfg.KeepAlive,
+		healthProbe:      cfg.HealthProbe,
+		logger:           logger,
+		enabled:          true,
 	}
 
 	logger.Info("Local LLM chat client initialized",
@@ -63,6 +84,9 @@ func NewClient(cfg *Config, logger *zap.Logger) (*Client, error) {
 }
 
 func (c *Client) Completion(ctx context.Context, messages []Message, opts *Options) (*CompletionResponse, error) {
+	ctx, cancel := c.withRequestTimeout(ctx)
+	defer cancel()
+
 	if !c.enabled {
 		return nil, errors.New("local LLM client is not enabled")
 	}
@@ -71,17 +95,19 @@ func (c *Client) Completion(ctx context.Context, messages []Message, opts *Optio
 	}
 
 	reqBody := CompletionRequest{
-		Model:    c.model,
-		Messages: messages,
-		Stream:   false,
-		Options:  opts,
+		Model:     c.modelFor(opts),
+		Messages:  messages,
+		Stream:    false,
+		Options:   opts,
+		KeepAlive: c.keepAliveFor(opts),
+		Format:    formatFor(opts),
 	}
 
 	c.logger.Debug("Sending completion request",
-		zap.String("model", c.model),
+		zap.String("model", reqBody.Model),
 		zap.Int("message_count", len(messages)))
 
-	body, err := c.doRequest(ctx, reqBody)
+	body, err := c.doRequest(ctx, http.MethodPost, chatEndpoint, reqBody)
 	if err != nil {
 		return nil, err
 	}
@@ -122,65 +148,80 @@ func (c *Client) CompletionStream(ctx context.Context, messages []Message, opts
 	}
 
 	reqBody := CompletionRequest{
-		Model:    c.model,
-		Messages: messages,
-		Stream:   true,
-		Options:  opts,
+		Model:     c.modelFor(opts),
+		Messages:  messages,
+		Stream:    true,
+		Options:   opts,
+		KeepAlive: c.keepAliveFor(opts),
+		Format:    formatFor(opts),
 	}
 
 	c.logger.Debug("Sending streaming completion request",
-		zap.String("model", c.model),
+		zap.String("model", reqBody.Model),
 		zap.Int("message_count", len(messages)))
 
-	body, err := c.doRequest(ctx, reqBody)
+	body, err := c.doRequest(ctx, http.MethodPost, chatEndpoint, reqBody)
 	if err != nil {
 		return err
 	}
 	defer body.Close()
 
-	scanner := bufio.NewScanner(body)
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" {
-			continue
+	// ReadBytes grows as needed, so very large chunks are not truncated.
+	reader := bufio.NewReaderSize(body, c.streamBufferSize)
+	for {
+		raw, readErr := reader.ReadBytes('\n')
+
+		if line := bytes.TrimSpace(raw); len(line) > 0 {
+			var chunk StreamChunk
+			if err := json.Unmarshal(line, &chunk); err != nil {
+				c.logger.Error("Failed to unmarshal stream chunk",
+					zap.Error(err),
+					zap.ByteString("raw", line))
+				return fmt.Errorf("failed to unmarshal stream chunk: %w", err)
+			}
+
+			if err := onChunk(chunk); err != nil {
+				c.logger.Debug("Streaming stopped by callback", zap.Error(err))
+				return err
+			}
+
+			if chunk.Done {
+				c.logger.Debug("Stream completed",
+					zap.String("model", chunk.Model),
+					zap.Int("eval_count", chunk.EvalCount))
+				break
+			}
 		}
 
-		var chunk StreamChunk
-		if err := json.Unmarshal([]byte(line), &chunk); err != nil {
-			c.logger.Error("Failed to unmarshal stream chunk",
-				zap.Error(err),
-				zap.String("raw", line))
-			return fmt.Errorf("failed to unmarshal stream chunk: %w", err)
-		}
-
-		if err := onChunk(chunk); err != nil {
-			c.logger.Debug("Streaming stopped by callback", zap.Error(err))
-			return err
-		}
-
-		if chunk.Done {
-			c.logger.Debug("Stream completed",
-				zap.String("model", chunk.Model),
-				zap.Int("eval_count", chunk.EvalCount))
-			break
+		if readErr != nil {
+			if errors.Is(readErr, io.EOF) {
+				break
+			}
+			// A cancelled context aborts the in-flight read; report the cancellation, not the I/O error.
+			if ctx.Err() != nil {
+				c.logger.Debug("Stream cancelled", zap.Error(ctx.Err()))
+				return ctx.Err()
+			}
+			c.logger.Error("Error reading stream", zap.Error(readErr))
+			return fmt.Errorf("error reading stream: %w", readErr)
 		}
 	}
 
-	if err := scanner.Err(); err != nil {
-		c.logger.Error("Error reading stream", zap.Error(err))
-		return fmt.Errorf("error reading stream: %w", err)
-	}
-
 	return nil
 }
 
-// Health checks if the local LLM is reachable.
+// Health checks that the local LLM is reachable and that the configured model
+// has been pulled, returning ErrModelNotFound if it has not. With
+// Config.HealthProbe set it also runs a one-token generation.
 func (c *Client) Health(ctx context.Context) error {
+	ctx, cancel := c.withRequestTimeout(ctx)
+	defer cancel()
+
 	if !c.enabled {
 		return errors.New("local LLM client is not enabled")
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.host, nil)
+	req, err := c.newRequest(ctx, http.MethodGet, "", nil)
 	if err != nil {
 		return fmt.Errorf("failed to create health check request: %w", err)
 	}
@@ -195,45 +236,131 @@ func (c *Client) Health(ctx context.Context) error {
 		return fmt.Errorf("local LLM health check returned status %d", resp.StatusCode)
 	}
 
-	c.logger.Info("Local LLM health check passed", zap.String("host", c.host))
+	models, err := c.ListModels(ctx)
+	if err != nil {
+		return fmt.Errorf("local LLM health check failed to list models: %w", err)
+	}
+	found := false
+	for _, m := range models {
+		if modelNameMatches(m.Name, c.model) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		c.logger.Warn("Local LLM model not found", zap.String("host", c.host), zap.String("model", c.model))
+		return fmt.Errorf("%w: %s", ErrModelNotFound, c.model)
+	}
+
+	if c.healthProbe {
+		if _, err := c.Generate(ctx, "ping", &GenerateOptions{Options: &Options{MaxTokens: 1}}); err != nil {
+			return fmt.Errorf("local LLM health probe failed: %w", err)
+		}
+	}
+
+	c.logger.Info("Local LLM health check passed", zap.String("host", c.host), zap.String("model", c.model))
 	return nil
 }
 
+// ListModels returns the models available on the local server (GET /api/tags).
+func (c *Client) ListModels(ctx context.Context) ([]ModelInfo, error) {
+	ctx, cancel := c.withRequestTimeout(ctx)
+	defer cancel()
+
+	if !c.enabled {
+		return nil, errors.New("local LLM client is not enabled")
+	}
+
+	req, err := c.newRequest(ctx, http.MethodGet, tagsEndpoint, nil)
+	if err != nil {
+		c.logger.Error("Failed to create HTTP request", zap.Error(err))
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.logger.Error("Failed to send HTTP request", zap.Error(err))
+		return nil, fmt.Errorf("failed to send HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		c.logger.Error("Failed to read response body", zap.Error(err))
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		c.logger.Error("LLM API error",
+			zap.Int("status", resp.StatusCode),
+			zap.String("body", string(raw)))
+		return nil, fmt.Errorf("LLM API error (status %d): %s", resp.StatusCode, string(raw))
+	}
+
+	var list ModelList
+	if err := json.Unmarshal(raw, &list); err != nil {
+		c.logger.Error("Failed to unmarshal model list", zap.Error(err))
+		return nil, fmt.Errorf("failed to unmarshal model list: %w", err)
+	}
+
+	return list.Models, nil
+}
+
 // IsEnabled returns whether the client is enabled.
 func (c *Client) IsEnabled() bool {
 	return c.enabled
 }
 
-// GetModel returns the configured model name.
+// GetModel returns the configured default model name.
 func (c *Client) GetModel() string {
 	return c.model
 }
 
-// doRequest marshals the request body and sends the HTTP POST to the chat endpoint.
+// modelFor returns the per-request model override, falling back to the configured model.
+func (c *Client) modelFor(opts *Options) string {
+	if opts != nil && opts.Model != "" {
+		return opts.Model
+	}
+	return c.model
+}
+
+// formatFor returns the requested output format, if any.
+func formatFor(opts *Options) json.RawMessage {
+	if opts == nil {
+		return nil
+	}
+	return opts.Format
+}
+
+// keepAliveFor returns the keep_alive value for a request: the per-request
+// override, then the configured default, or "" to leave it to the server.
+func (c *Client) keepAliveFor(opts *Options) string {
+	if opts != nil && opts.KeepAlive != nil {
+		return opts.KeepAlive.String()
+	}
+	if c.keepAlive != 0 {
+		return c.keepAlive.String()
+	}
+	return ""
+}
+
+// doRequest marshals the request body and sends it to endpoint.
 // Returns the response body (caller must close it).
-func (c *Client) doRequest(ctx context.Context, reqBody CompletionRequest) (io.ReadCloser, error) {
+func (c *Client) doRequest(ctx context.Context, method, endpoint string, reqBody any) (io.ReadCloser, error) {
 	jsonData, err := json.Marshal(reqBody)
 	if err != nil {
 		c.logger.Error("Failed to marshal request", zap.Error(err))
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	url := c.host + chatEndpoint
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(jsonData))
+	httpReq, err := c.newRequest(ctx, method, endpoint, bytes.NewBuffer(jsonData))
 	if err != nil {
 		c.logger.Error("Failed to create HTTP request", zap.Error(err))
 		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
 
-	// For streaming requests, use a client without a timeout
-	// so the connection stays open for the duration of generation.
-	httpClient := c.httpClient
-	if reqBody.Stream {
-		httpClient = &http.Client{} // no timeout for streaming
-	}
-
-	resp, err := httpClient.Do(httpReq)
+	resp, err := c.httpClient.Do(httpReq)
 	if err != nil {
 		c.logger.Error("Failed to send HTTP request", zap.Error(err))
 		return nil, fmt.Errorf("failed to send HTTP request: %w", err)
@@ -250,3 +377,59 @@ func (c *Client) doRequest(ctx context.Context, reqBody CompletionRequest) (io.R
 
 	return resp.Body, nil
 }
+
+// newRequest builds a request against the configured host, applying custom
+// headers and default query parameters.
+func (c *Client) newRequest(ctx context.Context, method, endpoint string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.host+endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(c.queryParams) > 0 {
+		q := req.URL.Query()
+		for k, v := range c.queryParams {
+			q.Set(k, v)
+		}
+		req.URL.RawQuery = q.Encode()
+	}
+
+	for k, v := range c.headers {
+		req.Header.Set(k, v)
+	}
+
+	return req, nil
+}
+
+// newHTTPClient returns the caller-supplied client, or a shared client around the
+// configured transport. The client itself has no overall timeout so streams can
+// run for as long as generation takes; non-streaming calls are bounded by a
+// context deadline instead (see withRequestTimeout), and the default transport
+// bounds the wait for response headers.
+func newHTTPClient(cfg *Config, timeout time.Duration) *http.Client {
+	if cfg.HTTPClient != nil {
+		return cfg.HTTPClient
+	}
+
+	transport := cfg.Transport
+	if transport == nil {
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		t.ResponseHeaderTimeout = timeout
+		transport = t
+	}
+	return &http.Client{Transport: transport}
+}
+
+// streamBufferSize returns the configured initial read buffer size for streams.
+func streamBufferSize(size int) int {
+	if size <= 0 {
+		return defaultStreamBufferSize
+	}
+	return size
+}
+
+// withRequestTimeout bounds a non-streaming call, including reading the body,
+// by the configured timeout.
+func (c *Client) withRequestTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, c.timeout)
+}
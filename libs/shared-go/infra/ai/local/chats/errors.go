@@ -0,0 +1,7 @@
+package chats
+
+import "errors"
+
+// ErrModelNotFound is returned by Health when the configured model has not
+// been pulled to the local server. Use PullModel or EnsureModel to fetch it.
+var ErrModelNotFound = errors.New("model not found on local LLM server")
@@ -0,0 +1,166 @@
+package chats
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+// Generate sends a raw (non-chat) completion request to /api/generate.
+// With opts.Raw the prompt is passed to the model verbatim, which is required
+// for fill-in-the-middle and for prompts that already contain template tokens.
+func (c *Client) Generate(ctx context.Context, prompt string, opts *GenerateOptions) (*GenerateResponse, error) {
+	ctx, cancel := c.withRequestTimeout(ctx)
+	defer cancel()
+
+	if !c.enabled {
+		return nil, errors.New("local LLM client is not enabled")
+	}
+
+	reqBody := c.buildGenerateRequest(prompt, false, opts)
+
+	c.logger.Debug("Sending generate request",
+		zap.String("model", reqBody.Model),
+		zap.Bool("raw", reqBody.Raw),
+		zap.Int("prompt_length", len(prompt)))
+
+	body, err := c.doRequest(ctx, http.MethodPost, generateEndpoint, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	raw, err := io.ReadAll(body)
+	if err != nil {
+		c.logger.Error("Failed to read response body", zap.Error(err))
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	var resp GenerateResponse
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		c.logger.Error("Failed to unmarshal generate response", zap.Error(err))
+		return nil, fmt.Errorf("failed to unmarshal generate response: %w", err)
+	}
+
+	c.logger.Debug("Generate response received",
+		zap.String("model", resp.Model),
+		zap.Int("eval_count", resp.EvalCount))
+
+	return &resp, nil
+}
+
+// GenerateStream is the streaming form of Generate. Each chunk is delivered to
+// onChunk, which can return an error to stop early; the final chunk has Done=true.
+func (c *Client) GenerateStream(ctx context.Context, prompt string, opts *GenerateOptions, onChunk func(chunk GenerateResponse) error) error {
+	if !c.enabled {
+		return errors.New("local LLM client is not enabled")
+	}
+	if onChunk == nil {
+		return errors.New("onChunk callback is required")
+	}
+
+	reqBody := c.buildGenerateRequest(prompt, true, opts)
+
+	c.logger.Debug("Sending streaming generate request",
+		zap.String("model", reqBody.Model),
+		zap.Bool("raw", reqBody.Raw),
+		zap.Int("prompt_length", len(prompt)))
+
+	body, err := c.doRequest(ctx, http.MethodPost, generateEndpoint, reqBody)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	reader := bufio.NewReaderSize(body, c.streamBufferSize)
+	for {
+		raw, readErr := reader.ReadBytes('\n')
+
+		if line := bytes.TrimSpace(raw); len(line) > 0 {
+			var chunk GenerateResponse
+			if err := json.Unmarshal(line, &chunk); err != nil {
+				c.logger.Error("Failed to unmarshal stream chunk",
+					zap.Error(err),
+					zap.ByteString("raw", line))
+				return fmt.Errorf("failed to unmarshal stream chunk: %w", err)
+			}
+
+			if err := onChunk(chunk); err != nil {
+				c.logger.Debug("Streaming stopped by callback", zap.Error(err))
+				return err
+			}
+
+			if chunk.Done {
+				break
+			}
+		}
+
+		if readErr != nil {
+			if errors.Is(readErr, io.EOF) {
+				break
+			}
+			if ctx.Err() != nil {
+				c.logger.Debug("Stream cancelled", zap.Error(ctx.Err()))
+				return ctx.Err()
+			}
+			c.logger.Error("Error reading stream", zap.Error(readErr))
+			return fmt.Errorf("error reading stream: %w", readErr)
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) buildGenerateRequest(prompt string, stream bool, opts *GenerateOptions) GenerateRequest {
+	if opts == nil {
+		opts = &GenerateOptions{}
+	}
+	return GenerateRequest{
+		Model:     c.modelFor(opts.Options),
+		Prompt:    prompt,
+		Suffix:    opts.Suffix,
+		System:    opts.System,
+		Template:  opts.Template,
+		Raw:       opts.Raw,
+		Context:   opts.Context,
+		Stream:    stream,
+		Options:   opts.Options,
+		KeepAlive: c.keepAliveFor(opts.Options),
+		Format:    formatFor(opts.Options),
+	}
+}
+
+// Warmup loads the configured model into memory by sending an empty prompt,
+// so the first real request doesn't pay the load cost. The configured
+// KeepAlive controls how long the model then stays resident.
+func (c *Client) Warmup(ctx context.Context) error {
+	if !c.enabled {
+		return errors.New("local LLM client is not enabled")
+	}
+
+	c.logger.Info("Warming up local model", zap.String("model", c.model))
+
+	// An empty prompt makes Ollama load the model and return immediately.
+	// Loading can take longer than a normal request, so only ctx bounds it.
+	body, err := c.doRequest(ctx, http.MethodPost, generateEndpoint, GenerateRequest{
+		Model:     c.model,
+		Stream:    false,
+		KeepAlive: c.keepAliveFor(nil),
+	})
+	if err != nil {
+		c.logger.Warn("Local model warmup failed", zap.String("model", c.model), zap.Error(err))
+		return fmt.Errorf("failed to warm up model %s: %w", c.model, err)
+	}
+	defer body.Close()
+	_, _ = io.Copy(io.Discard, body)
+
+	c.logger.Info("Local model warmed up", zap.String("model", c.model))
+	return nil
+}
@@ -1,5 +1,12 @@
 package chats
 
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
 // Role constants for chat messages.
 const (
 	RoleSystem    = "system"
@@ -7,10 +14,35 @@ const (
 	RoleAssistant = "assistant"
 )
 
+// FormatJSON requests free-form JSON output; pass a schema instead for structured outputs.
+var FormatJSON = json.RawMessage(`"json"`)
+
 // Config holds the configuration for the local LLM client.
 type Config struct {
-	Host  string // e.g. "http://localhost:11434" (Ollama default)
-	Model string // e.g. "llama3:8b"
+	Host    string        // e.g. "http://localhost:11434" (Ollama default)
+	Model   string        // e.g. "llama3:8b"
+	Timeout time.Duration // bounds non-streaming requests and the wait for response headers (default: 5m)
+
+	// KeepAlive is how long Ollama keeps the model loaded after a request.
+	// Zero uses the server default (5m); negative keeps it loaded indefinitely.
+	KeepAlive time.Duration
+
+	// HealthProbe makes Health also run a one-token generation, catching models
+	// that are present but fail to load (e.g. out of memory).
+	HealthProbe bool
+
+	// StreamBufferSize is the initial read buffer for streaming responses
+	// (default: 64KB). Lines longer than this are still read in full.
+	StreamBufferSize int
+
+	Headers     map[string]string // added to every request, e.g. auth for a reverse proxy
+	QueryParams map[string]string // added to every request URL
+
+	// HTTPClient, if set, is shared by all requests; its own Timeout also applies
+	// to streams, so leave it zero. Otherwise a single client is built around
+	// Transport, or a clone of http.DefaultTransport.
+	HTTPClient *http.Client
+	Transport  http.RoundTripper
 }
 
 // IsValid returns true if the configuration has the minimum required fields.
@@ -20,8 +52,9 @@ func (c *Config) IsValid() bool {
 
 // Message represents a single chat message.
 type Message struct {
-	Role    string `json:"role"`    // "system", "user", or "assistant"
-	Content string `json:"content"` // The message content
+	Role    string   `json:"role"`             // "system", "user", or "assistant"
+	Content string   `json:"content"`          // The message content
+	Images  []string `json:"images,omitempty"` // Base64-encoded images for multimodal models (llava, llama3.2-vision)
 }
 
 // CompletionRequest is the payload sent to the local LLM for a chat completion.
@@ -30,15 +63,58 @@ type CompletionRequest struct {
 	Messages []Message `json:"messages"`
 	Stream   bool      `json:"stream"`
 	Options  *Options  `json:"options,omitempty"`
+
+	KeepAlive string          `json:"keep_alive,omitempty"` // duration string, e.g. "30m"; "0s" unloads immediately
+	Format    json.RawMessage `json:"format,omitempty"`     // "json" or a JSON schema object
 }
 
 // Options are optional model-level parameters.
+// Pointer fields are sent only when non-nil, so an explicit zero is honoured.
 type Options struct {
-	Temperature float64 `json:"temperature,omitempty"`
-	TopP        float64 `json:"top_p,omitempty"`
-	TopK        int     `json:"top_k,omitempty"`
-	MaxTokens   int     `json:"num_predict,omitempty"` // Ollama uses "num_predict"
+	Model       string   `json:"-"` // overrides Config.Model for this request; not an Ollama option
+	Temperature *float64 `json:"temperature,omitempty"`
+	TopP        *float64 `json:"top_p,omitempty"`
+	TopK        int      `json:"top_k,omitempty"`
+	MaxTokens   int      `json:"num_predict,omitempty"` // Ollama uses "num_predict"
 	Stop        []string `json:"stop,omitempty"`
+
+	RepeatPenalty    *float64 `json:"repeat_penalty,omitempty"`    // 1.0 is neutral, higher penalises repetition
+	FrequencyPenalty *float64 `json:"frequency_penalty,omitempty"` // honoured by the llama.cpp runner only
+	PresencePenalty  *float64 `json:"presence_penalty,omitempty"`  // honoured by the llama.cpp runner only
+	Seed             *int64   `json:"seed,omitempty"`              // fixed seed for reproducible sampling
+
+	// Sampling
+	MinP            *float64 `json:"min_p,omitempty"`         // minimum probability relative to the most likely token
+	TypicalP        *float64 `json:"typical_p,omitempty"`     // locally typical sampling, 1.0 disables
+	RepeatLastN     *int     `json:"repeat_last_n,omitempty"` // tokens to look back for repeat_penalty; 0 disables, -1 = num_ctx
+	Mirostat        *int     `json:"mirostat,omitempty"`      // 0 disabled, 1 Mirostat, 2 Mirostat 2.0
+	MirostatTau     *float64 `json:"mirostat_tau,omitempty"`  // target entropy; lower is more focused
+	MirostatEta     *float64 `json:"mirostat_eta,omitempty"`  // learning rate
+	NumKeep         *int     `json:"num_keep,omitempty"`      // prompt tokens kept when the context overflows
+	PenalizeNewline *bool    `json:"penalize_newline,omitempty"`
+
+	// Runtime. Changing these forces the model to reload.
+	NumCtx    int   `json:"num_ctx,omitempty"`    // context window size in tokens
+	NumBatch  int   `json:"num_batch,omitempty"`  // prompt processing batch size
+	NumGPU    *int  `json:"num_gpu,omitempty"`    // layers offloaded to the GPU; 0 forces CPU
+	MainGPU   *int  `json:"main_gpu,omitempty"`   // GPU used for small tensors in multi-GPU setups
+	NumThread int   `json:"num_thread,omitempty"` // CPU threads; defaults to the physical core count
+	UseMMap   *bool `json:"use_mmap,omitempty"`
+	UseMLock  *bool `json:"use_mlock,omitempty"`
+	LowVRAM   *bool `json:"low_vram,omitempty"`
+	NUMA      *bool `json:"numa,omitempty"`
+
+	// Extra holds options not modelled above; they are merged into the
+	// options object, and typed fields take precedence on conflict.
+	Extra map[string]any `json:"-"`
+
+	// KeepAlive overrides Config.KeepAlive for this request; zero unloads the
+	// model once the response is done. Sent at the top level, not in options.
+	KeepAlive *time.Duration `json:"-"`
+
+	// Format constrains the output: FormatJSON for any JSON, or a JSON schema
+	// object for structured outputs. Sent at the top level, not in options.
+	Format json.RawMessage `json:"-"`
 }
 
 // CompletionResponse is the full (non-streaming) response from the local LLM.
@@ -48,6 +124,8 @@ type CompletionResponse struct {
 	Message   Message `json:"message"`
 	Done      bool    `json:"done"`
 
+	DoneReason string `json:"done_reason,omitempty"` // "stop", "length" or "load"
+
 	// Usage statistics (populated when done=true)
 	TotalDuration      int64 `json:"total_duration,omitempty"`
 	LoadDuration       int64 `json:"load_duration,omitempty"`
@@ -66,6 +144,8 @@ type StreamChunk struct {
 	Message   Message `json:"message"`
 	Done      bool    `json:"done"`
 
+	DoneReason string `json:"done_reason,omitempty"` // final chunk only: "stop", "length" or "load"
+
 	// Only present in the final chunk (Done=true)
 	TotalDuration      int64 `json:"total_duration,omitempty"`
 	LoadDuration       int64 `json:"load_duration,omitempty"`
@@ -75,3 +155,130 @@ type StreamChunk struct {
 	EvalDuration       int64 `json:"eval_duration,omitempty"`
 }
 
+// ModelList is the response from the /api/tags endpoint.
+type ModelList struct {
+	Models []ModelInfo `json:"models"`
+}
+
+// ModelInfo describes a model that has been pulled to the local server.
+type ModelInfo struct {
+	Name       string       `json:"name"`  // e.g. "llama3:8b"
+	Model      string       `json:"model"` // usually the same as Name
+	ModifiedAt string       `json:"modified_at"`
+	Size       int64        `json:"size"` // bytes on disk
+	Digest     string       `json:"digest"`
+	Details    ModelDetails `json:"details"`
+}
+
+// ModelDetails holds model metadata reported by Ollama.
+type ModelDetails struct {
+	Format            string   `json:"format,omitempty"` // e.g. "gguf"
+	Family            string   `json:"family,omitempty"`
+	Families          []string `json:"families,omitempty"`
+	ParameterSize     string   `json:"parameter_size,omitempty"`     // e.g. "8.0B"
+	QuantizationLevel string   `json:"quantization_level,omitempty"` // e.g. "Q4_0"
+}
+
+// ModelRequest identifies a model for the delete and show endpoints.
+type ModelRequest struct {
+	Model string `json:"model"`
+}
+
+// PullRequest is the payload sent to the /api/pull endpoint.
+type PullRequest struct {
+	Model    string `json:"model"`
+	Insecure bool   `json:"insecure,omitempty"`
+	Stream   bool   `json:"stream"`
+}
+
+// PullProgress is a single progress update streamed while pulling a model.
+// Total and Completed are set while a layer is downloading.
+type PullProgress struct {
+	Status    string `json:"status"` // e.g. "pulling manifest", "downloading", "success"
+	Digest    string `json:"digest,omitempty"`
+	Total     int64  `json:"total,omitempty"`
+	Completed int64  `json:"completed,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// ShowResponse is the response from the /api/show endpoint.
+type ShowResponse struct {
+	Modelfile    string         `json:"modelfile"`
+	Parameters   string         `json:"parameters"`
+	Template     string         `json:"template"`
+	License      string         `json:"license,omitempty"`
+	Details      ModelDetails   `json:"details"`
+	ModelInfo    map[string]any `json:"model_info,omitempty"`
+	Capabilities []string       `json:"capabilities,omitempty"` // e.g. "completion", "vision", "tools"
+	ModifiedAt   string         `json:"modified_at,omitempty"`
+}
+
+// MarshalJSON flattens Extra into the options object alongside the typed fields.
+func (o Options) MarshalJSON() ([]byte, error) {
+	type options Options
+	data, err := json.Marshal(options(o))
+	if err != nil || len(o.Extra) == 0 {
+		return data, err
+	}
+
+	merged := make(map[string]json.RawMessage, len(o.Extra))
+	for k, v := range o.Extra {
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal extra option %q: %w", k, err)
+		}
+		merged[k] = raw
+	}
+	var typed map[string]json.RawMessage
+	if err := json.Unmarshal(data, &typed); err != nil {
+		return nil, err
+	}
+	for k, v := range typed {
+		merged[k] = v
+	}
+	return json.Marshal(merged)
+}
+
+// GenerateOptions are optional parameters for Generate.
+type GenerateOptions struct {
+	System   string // overrides the Modelfile system prompt; ignored when Raw is set
+	Template string // overrides the Modelfile prompt template; ignored when Raw is set
+	Suffix   string // text after the insertion point, for fill-in-the-middle
+	Raw      bool   // send Prompt verbatim, without applying the prompt template
+	Context  []int  // context returned by a previous Generate call, for short conversational memory
+
+	Options *Options // model-level parameters; Options.Model and Options.KeepAlive also apply
+}
+
+// GenerateRequest is the payload sent to the /api/generate endpoint.
+type GenerateRequest struct {
+	Model     string          `json:"model"`
+	Prompt    string          `json:"prompt"`
+	Suffix    string          `json:"suffix,omitempty"`
+	System    string          `json:"system,omitempty"`
+	Template  string          `json:"template,omitempty"`
+	Raw       bool            `json:"raw,omitempty"`
+	Context   []int           `json:"context,omitempty"`
+	Stream    bool            `json:"stream"`
+	Options   *Options        `json:"options,omitempty"`
+	KeepAlive string          `json:"keep_alive,omitempty"`
+	Format    json.RawMessage `json:"format,omitempty"`
+}
+
+// GenerateResponse is a response, or a streamed chunk, from the /api/generate endpoint.
+// The final chunk has Done=true and includes Context and usage statistics.
+type GenerateResponse struct {
+	Model      string `json:"model"`
+	CreatedAt  string `json:"created_at"`
+	Response   string `json:"response"`
+	Done       bool   `json:"done"`
+	DoneReason string `json:"done_reason,omitempty"` // "stop", "length" or "load"
+	Context    []int  `json:"context,omitempty"`
+
+	TotalDuration      int64 `json:"total_duration,omitempty"`
+	LoadDuration       int64 `json:"load_duration,omitempty"`
+	PromptEvalCount    int   `json:"prompt_eval_count,omitempty"`
+	PromptEvalDuration int64 `json:"prompt_eval_duration,omitempty"`
+	EvalCount          int   `json:"eval_count,omitempty"`
+	EvalDuration       int64 `json:"eval_duration,omitempty"`
+}
@@ -0,0 +1,156 @@
+package chats
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// PullModel downloads a model from the Ollama library. Progress updates are
+// delivered to onProgress, which may be nil. Pulls can take minutes, so only
+// ctx bounds the call, not the client timeout.
+func (c *Client) PullModel(ctx context.Context, name string, onProgress func(p PullProgress) error) error {
+	if !c.enabled {
+		return errors.New("local LLM client is not enabled")
+	}
+	if name == "" {
+		return errors.New("model name is required")
+	}
+
+	c.logger.Info("Pulling model", zap.String("model", name))
+
+	body, err := c.doRequest(ctx, http.MethodPost, pullEndpoint, PullRequest{Model: name, Stream: true})
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	reader := bufio.NewReaderSize(body, c.streamBufferSize)
+	for {
+		raw, readErr := reader.ReadBytes('\n')
+
+		if line := bytes.TrimSpace(raw); len(line) > 0 {
+			var progress PullProgress
+			if err := json.Unmarshal(line, &progress); err != nil {
+				c.logger.Error("Failed to unmarshal pull progress",
+					zap.Error(err),
+					zap.ByteString("raw", line))
+				return fmt.Errorf("failed to unmarshal pull progress: %w", err)
+			}
+			if progress.Error != "" {
+				c.logger.Error("Model pull failed",
+					zap.String("model", name),
+					zap.String("error", progress.Error))
+				return fmt.Errorf("failed to pull model %s: %s", name, progress.Error)
+			}
+
+			if onProgress != nil {
+				if err := onProgress(progress); err != nil {
+					return err
+				}
+			}
+
+			if progress.Status == "success" {
+				c.logger.Info("Model pulled", zap.String("model", name))
+				return nil
+			}
+		}
+
+		if readErr != nil {
+			if errors.Is(readErr, io.EOF) {
+				break
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			c.logger.Error("Error reading pull progress", zap.Error(readErr))
+			return fmt.Errorf("error reading pull progress: %w", readErr)
+		}
+	}
+
+	return fmt.Errorf("pull of model %s ended without success", name)
+}
+
+// DeleteModel removes a model and its data from the local server.
+func (c *Client) DeleteModel(ctx context.Context, name string) error {
+	ctx, cancel := c.withRequestTimeout(ctx)
+	defer cancel()
+
+	if !c.enabled {
+		return errors.New("local LLM client is not enabled")
+	}
+	if name == "" {
+		return errors.New("model name is required")
+	}
+
+	body, err := c.doRequest(ctx, http.MethodDelete, deleteEndpoint, ModelRequest{Model: name})
+	if err != nil {
+		return err
+	}
+	body.Close()
+
+	c.logger.Info("Model deleted", zap.String("model", name))
+	return nil
+}
+
+// ShowModel returns details about a local model: its Modelfile, parameters,
+// prompt template and capabilities.
+func (c *Client) ShowModel(ctx context.Context, name string) (*ShowResponse, error) {
+	ctx, cancel := c.withRequestTimeout(ctx)
+	defer cancel()
+
+	if !c.enabled {
+		return nil, errors.New("local LLM client is not enabled")
+	}
+	if name == "" {
+		return nil, errors.New("model name is required")
+	}
+
+	body, err := c.doRequest(ctx, http.MethodPost, showEndpoint, ModelRequest{Model: name})
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var out ShowResponse
+	if err := json.NewDecoder(body).Decode(&out); err != nil {
+		c.logger.Error("Failed to unmarshal show response", zap.Error(err))
+		return nil, fmt.Errorf("failed to unmarshal show response: %w", err)
+	}
+	return &out, nil
+}
+
+// EnsureModel pulls name if it is not already present on the server, so
+// required models can be provisioned at startup.
+func (c *Client) EnsureModel(ctx context.Context, name string, onProgress func(p PullProgress) error) error {
+	models, err := c.ListModels(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list local models: %w", err)
+	}
+	for _, m := range models {
+		if modelNameMatches(m.Name, name) {
+			return nil
+		}
+	}
+	return c.PullModel(ctx, name, onProgress)
+}
+
+// modelNameMatches compares Ollama model names, treating a missing tag as ":latest".
+func modelNameMatches(a, b string) bool {
+	return withDefaultTag(a) == withDefaultTag(b)
+}
+
+func withDefaultTag(name string) string {
+	if !strings.Contains(name, ":") {
+		return name + ":latest"
+	}
+	return name
+}
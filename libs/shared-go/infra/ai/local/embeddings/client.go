@@ -0,0 +1,193 @@
+package embeddings
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultHost      = "http://localhost:11434"
+	defaultModel     = "nomic-embed-text"
+	defaultTimeout   = 2 * time.Minute
+	defaultBatchSize = 512
+	embedEndpoint    = "/api/embed"
+)
+
+type Client struct {
+	host       string
+	model      string
+	batchSize  int
+	headers    map[string]string
+	httpClient *http.Client
+	logger     *zap.Logger
+	enabled    bool
+}
+
+func NewClient(cfg *Config, logger *zap.Logger) (*Client, error) {
+	if cfg == nil {
+		return nil, errors.New("config is required")
+	}
+
+	host := strings.TrimRight(cfg.Host, "/")
+	if host == "" {
+		host = defaultHost
+	}
+
+	model := cfg.Model
+	if model == "" {
+		model = defaultModel
+	}
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	batchSize := cfg.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+
+	client := &Client{
+		host:      host,
+		model:     model,
+		batchSize: batchSize,
+		headers:   cfg.Headers,
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+		logger:  logger,
+		enabled: true,
+	}
+
+	logger.Info("Local embeddings client initialized",
+		zap.String("host", host),
+		zap.String("model", model))
+
+	return client, nil
+}
+
+// CreateEmbeddings embeds every input with model (the configured model if empty),
+// splitting the inputs into batches of at most BatchSize.
+func (c *Client) CreateEmbeddings(ctx context.Context, model string, inputs []string) (*EmbeddingsResult, error) {
+	if len(inputs) == 0 {
+		c.logger.Error("Input cannot be empty")
+		return nil, errors.New("input cannot be empty")
+	}
+
+	if !c.enabled {
+		c.logger.Error("Embedding provider is not enabled")
+		return nil, errors.New("embedding provider is not enabled")
+	}
+
+	if model == "" {
+		model = c.model
+	}
+
+	c.logger.Debug("Creating embeddings",
+		zap.String("model", model),
+		zap.Int("input_count", len(inputs)))
+
+	result := &EmbeddingsResult{
+		Model:      model,
+		Embeddings: make([][]float32, 0, len(inputs)),
+	}
+
+	for start := 0; start < len(inputs); start += c.batchSize {
+		end := min(start+c.batchSize, len(inputs))
+
+		resp, err := c.embed(ctx, model, inputs[start:end])
+		if err != nil {
+			return nil, err
+		}
+		if len(resp.Embeddings) != end-start {
+			c.logger.Error("Unexpected embedding count",
+				zap.Int("want", end-start),
+				zap.Int("got", len(resp.Embeddings)))
+			return nil, fmt.Errorf("expected %d embeddings, got %d", end-start, len(resp.Embeddings))
+		}
+
+		result.Embeddings = append(result.Embeddings, resp.Embeddings...)
+		result.Model = resp.Model
+		result.PromptTokens += resp.PromptEvalCount
+	}
+
+	c.logger.Debug("Embeddings created",
+		zap.String("model", result.Model),
+		zap.Int("count", len(result.Embeddings)),
+		zap.Int("prompt_tokens", result.PromptTokens))
+
+	return result, nil
+}
+
+func (c *Client) CreateEmbedding(ctx context.Context, input string) ([]float32, error) {
+	result, err := c.CreateEmbeddings(ctx, "", []string{input})
+	if err != nil {
+		return nil, err
+	}
+	return result.Embeddings[0], nil
+}
+
+func (c *Client) IsEnabled() bool {
+	return c.enabled
+}
+
+// GetModel returns the configured default model name.
+func (c *Client) GetModel() string {
+	return c.model
+}
+
+func (c *Client) embed(ctx context.Context, model string, inputs []string) (*EmbedResponse, error) {
+	jsonData, err := json.Marshal(EmbedRequest{Model: model, Input: inputs})
+	if err != nil {
+		c.logger.Error("Failed to marshal embed request", zap.Error(err))
+		return nil, fmt.Errorf("failed to marshal embed request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.host+embedEndpoint, bytes.NewBuffer(jsonData))
+	if err != nil {
+		c.logger.Error("Failed to create HTTP request", zap.Error(err))
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	for k, v := range c.headers {
+		httpReq.Header.Set(k, v)
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		c.logger.Error("Failed to send HTTP request", zap.Error(err))
+		return nil, fmt.Errorf("failed to send HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		c.logger.Error("Failed to read response body", zap.Error(err))
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		c.logger.Error("LLM API error",
+			zap.Int("status", resp.StatusCode),
+			zap.String("body", string(body)))
+		return nil, fmt.Errorf("LLM API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var embedResp EmbedResponse
+	if err := json.Unmarshal(body, &embedResp); err != nil {
+		c.logger.Error("Failed to unmarshal embed response", zap.Error(err))
+		return nil, fmt.Errorf("failed to unmarshal embed response: %w", err)
+	}
+
+	return &embedResp, nil
+}
@@ -0,0 +1,35 @@
+package embeddings
+
+import "time"
+
+type Config struct {
+	Host      string            // e.g. "http://localhost:11434" (Ollama default)
+	Model     string            // e.g. "nomic-embed-text"
+	Timeout   time.Duration     // per request (default: 2m)
+	BatchSize int               // Optional: max inputs per request (default: 512)
+	Headers   map[string]string // added to every request, e.g. auth for a reverse proxy
+}
+
+// EmbedRequest is the body of POST /api/embed.
+type EmbedRequest struct {
+	Model    string   `json:"model"`
+	Input    []string `json:"input"`
+	Truncate *bool    `json:"truncate,omitempty"` // truncate inputs that exceed the context length (server default: true)
+}
+
+// EmbedResponse holds one embedding per input, in input order.
+type EmbedResponse struct {
+	Model           string      `json:"model"`
+	Embeddings      [][]float32 `json:"embeddings"`
+	TotalDuration   int64       `json:"total_duration,omitempty"`
+	LoadDuration    int64       `json:"load_duration,omitempty"`
+	PromptEvalCount int         `json:"prompt_eval_count,omitempty"`
+}
+
+// EmbeddingsResult holds one embedding per input, in input order, along with
+// the prompt tokens summed over all batches.
+type EmbeddingsResult struct {
+	Model        string
+	Embeddings   [][]float32
+	PromptTokens int
+}
@@ -0,0 +1,135 @@
+package rerank
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultHost    = "http://localhost:8080"
+	defaultModel   = "cross-encoder"
+	defaultTimeout = time.Minute
+	rerankEndpoint = "/rerank"
+)
+
+// Client calls a local cross-encoder served over HTTP (Hugging Face Text
+// Embeddings Inference or a compatible server).
+type Client struct {
+	host       string
+	model      string
+	headers    map[string]string
+	httpClient *http.Client
+	logger     *zap.Logger
+	enabled    bool
+}
+
+func NewClient(cfg *Config, logger *zap.Logger) (*Client, error) {
+	if cfg == nil {
+		return nil, errors.New("config is required")
+	}
+
+	host := strings.TrimRight(cfg.Host, "/")
+	if host == "" {
+		host = defaultHost
+	}
+
+	model := cfg.Model
+	if model == "" {
+		model = defaultModel
+	}
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	logger.Info("Local rerank client initialized",
+		zap.String("host", host),
+		zap.String("model", model))
+
+	return &Client{
+		host:    host,
+		model:   model,
+		headers: cfg.Headers,
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+		logger:  logger,
+		enabled: true,
+	}, nil
+}
+
+// Rerank scores texts against query and returns them by descending score,
+// keeping the topN best (all if topN <= 0). Scores are normalised to 0–1.
+func (c *Client) Rerank(ctx context.Context, query string, texts []string, topN int) ([]RerankResult, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+
+	jsonData, err := json.Marshal(RerankRequest{Query: query, Texts: texts, Truncate: true})
+	if err != nil {
+		c.logger.Error("Failed to marshal rerank request", zap.Error(err))
+		return nil, fmt.Errorf("failed to marshal rerank request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.host+rerankEndpoint, bytes.NewBuffer(jsonData))
+	if err != nil {
+		c.logger.Error("Failed to create HTTP request", zap.Error(err))
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	for k, v := range c.headers {
+		httpReq.Header.Set(k, v)
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		c.logger.Error("Failed to send HTTP request", zap.Error(err))
+		return nil, fmt.Errorf("failed to send HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		c.logger.Error("Failed to read response body", zap.Error(err))
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		c.logger.Error("Rerank API error",
+			zap.Int("status", resp.StatusCode),
+			zap.String("body", string(body)))
+		return nil, fmt.Errorf("rerank API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var results []RerankResult
+	if err := json.Unmarshal(body, &results); err != nil {
+		c.logger.Error("Failed to unmarshal rerank response", zap.Error(err))
+		return nil, fmt.Errorf("failed to unmarshal rerank response: %w", err)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	if topN > 0 && len(results) > topN {
+		results = results[:topN]
+	}
+	return results, nil
+}
+
+func (c *Client) IsEnabled() bool {
+	return c.enabled
+}
+
+// GetModel returns the configured model name.
+func (c *Client) GetModel() string {
+	return c.model
+}
@@ -0,0 +1,25 @@
+package rerank
+
+import "time"
+
+type Config struct {
+	Host    string            // e.g. "http://localhost:8080" (Text Embeddings Inference default)
+	Model   string            // informational; the server serves a single cross-encoder
+	Timeout time.Duration     // per request (default: 1m)
+	Headers map[string]string // added to every request, e.g. auth for a reverse proxy
+}
+
+// RerankRequest is the body of POST /rerank on a Text Embeddings Inference
+// server running a cross-encoder such as BAAI/bge-reranker-base.
+type RerankRequest struct {
+	Query     string   `json:"query"`
+	Texts     []string `json:"texts"`
+	RawScores bool     `json:"raw_scores"`
+	Truncate  bool     `json:"truncate"`
+}
+
+// RerankResult scores one text; the server returns them by descending score.
+type RerankResult struct {
+	Index int     `json:"index"`
+	Score float64 `json:"score"`
+}
@@ -0,0 +1,84 @@
+package ai
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ChatMiddleware decorates a ChatProvider with cross-cutting behaviour such as
+// logging, metrics, caching or guardrails. Middleware usually embeds the
+// provider it wraps and overrides only the methods it cares about.
+type ChatMiddleware func(next ChatProvider) ChatProvider
+
+// Chain wraps provider with middleware. The first middleware is the outermost,
+// so it sees each request first and each response last.
+func Chain(provider ChatProvider, middleware ...ChatMiddleware) ChatProvider {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		provider = middleware[i](provider)
+	}
+	return provider
+}
+
+// ModelAliasMiddleware is WithModelAliases as a ChatMiddleware.
+func ModelAliasMiddleware(aliases ModelAliases) ChatMiddleware {
+	return func(next ChatProvider) ChatProvider {
+		return WithModelAliases(next, aliases)
+	}
+}
+
+// LoggingMiddleware logs the model, latency and token usage of every request.
+func LoggingMiddleware(logger *zap.Logger) ChatMiddleware {
+	return func(next ChatProvider) ChatProvider {
+		return &loggingProvider{ChatProvider: next, logger: logger}
+	}
+}
+
+type loggingProvider struct {
+	ChatProvider
+	logger *zap.Logger
+}
+
+func (p *loggingProvider) Completion(ctx context.Context, messages []Message, opts *ChatOptions) (*ChatResponse, error) {
+	start := time.Now()
+	resp, err := p.ChatProvider.Completion(ctx, messages, opts)
+	if err != nil {
+		p.logger.Error("Chat completion failed",
+			zap.String("model", p.model(opts)),
+			zap.Duration("duration", time.Since(start)),
+			zap.Error(err))
+		return nil, err
+	}
+
+	p.logger.Info("Chat completion",
+		zap.String("model", resp.Model),
+		zap.Duration("duration", time.Since(start)),
+		zap.Int("prompt_tokens", resp.Usage.PromptTokens),
+		zap.Int("completion_tokens", resp.Usage.CompletionTokens))
+	return resp, nil
+}
+
+func (p *loggingProvider) CompletionStream(ctx context.Context, messages []Message, opts *ChatOptions, onDelta func(delta ChatStreamDelta) error) error {
+	start := time.Now()
+	err := p.ChatProvider.CompletionStream(ctx, messages, opts, onDelta)
+	if err != nil {
+		p.logger.Error("Chat completion stream failed",
+			zap.String("model", p.model(opts)),
+			zap.Duration("duration", time.Since(start)),
+			zap.Error(err))
+		return err
+	}
+
+	p.logger.Info("Chat completion stream",
+		zap.String("model", p.model(opts)),
+		zap.Duration("duration", time.Since(start)))
+	return nil
+}
+
+func (p *loggingProvider) model(opts *ChatOptions) string {
+	if opts != nil && opts.Model != "" {
+		return opts.Model
+	}
+	return p.GetModel()
+}
@@ -1,10 +1,16 @@
 package ai
 
+import (
+	"encoding/json"
+	"time"
+)
+
 type ProviderType string
 
 const (
 	ProviderOpenAI ProviderType = "openai"
 	ProviderLocal  ProviderType = "local"
+	ProviderCohere ProviderType = "cohere" // reranking only
 )
 
 const (
@@ -16,19 +22,107 @@ const (
 type Message struct {
 	Role    string `json:"role"`
 	Content string `json:"content"`
+
+	// Images are raw image bytes (PNG, JPEG) attached to the message for
+	// multimodal models. Currently sent by the local provider only.
+	Images [][]byte `json:"images,omitempty"`
 }
 
+// ChatOptions are optional generation parameters. Pointer fields distinguish
+// "unset" (nil, provider default) from an explicit zero such as Temperature 0
+// for greedy decoding; use Float64, Int and Int64 to set them inline.
 type ChatOptions struct {
-	Temperature float64  `json:"temperature,omitempty"`
-	TopP        float64  `json:"top_p,omitempty"`
+	Model       string   `json:"model,omitempty"` // overrides the provider's default model
+	Temperature *float64 `json:"temperature,omitempty"`
+	TopP        *float64 `json:"top_p,omitempty"`
 	MaxTokens   int      `json:"max_tokens,omitempty"`
 	Stop        []string `json:"stop,omitempty"`
+	Logprobs    bool     `json:"logprobs,omitempty"`
+	TopLogprobs *int     `json:"top_logprobs,omitempty"` // 0-20, requires Logprobs
+	N           int      `json:"n,omitempty"`            // number of candidates to generate
+
+	FrequencyPenalty *float64 `json:"frequency_penalty,omitempty"` // -2.0 to 2.0
+	PresencePenalty  *float64 `json:"presence_penalty,omitempty"`  // -2.0 to 2.0
+
+	Seed *int64 `json:"seed,omitempty"` // best-effort deterministic sampling
+
+	// Reasoning models (OpenAI o-series) reject MaxTokens and sampling
+	// parameters; adapters translate or drop them based on the model family.
+	ReasoningEffort     string `json:"reasoning_effort,omitempty"` // "low", "medium" or "high"
+	MaxCompletionTokens int    `json:"max_completion_tokens,omitempty"`
+
+	// ResponseFormat constrains the output to JSON, optionally matching a schema.
+	ResponseFormat *ResponseFormat `json:"response_format,omitempty"`
+
+	// QualityTier is the minimum tier required by this request when routed
+	// through a cost-aware RouterProvider; zero uses the policy default.
+	QualityTier QualityTier `json:"quality_tier,omitempty"`
+
+	// BuiltinTools enables provider-hosted tools by type, e.g. "web_search_preview".
+	// Only honoured by the OpenAI provider in Responses API mode.
+	BuiltinTools []string `json:"builtin_tools,omitempty"`
+
+	// Timeout bounds the whole request, including the stream, when non-zero.
+	Timeout time.Duration `json:"-"`
+}
+
+// Response format types.
+const (
+	ResponseFormatText       = "text"
+	ResponseFormatJSONObject = "json_object"
+	ResponseFormatJSONSchema = "json_schema"
+)
+
+// ResponseFormat requests JSON output. With ResponseFormatJSONSchema, Schema is a
+// JSON schema the output must match (OpenAI structured outputs, Ollama format).
+type ResponseFormat struct {
+	Type   string          `json:"type"`
+	Name   string          `json:"name,omitempty"` // schema name; required by OpenAI
+	Schema json.RawMessage `json:"schema,omitempty"`
+	Strict bool            `json:"strict,omitempty"` // OpenAI only: reject outputs that deviate from Schema
 }
 
+// Float64 returns a pointer to v, for setting optional ChatOptions fields.
+func Float64(v float64) *float64 { return &v }
+
+// Int returns a pointer to v, for setting optional ChatOptions fields.
+func Int(v int) *int { return &v }
+
+// Int64 returns a pointer to v, for setting optional ChatOptions fields.
+func Int64(v int64) *int64 { return &v }
+
 type ChatResponse struct {
-	Model   string    `json:"model"`
-	Content string    `json:"content"`
-	Usage   ChatUsage `json:"usage"`
+	Model    string         `json:"model"`
+	Content  string         `json:"content"` // content of the first candidate
+	Usage    ChatUsage      `json:"usage"`
+	Logprobs []TokenLogprob `json:"logprobs,omitempty"`
+	Choices  []ChatChoice   `json:"choices,omitempty"` // all candidates, populated when N > 1
+
+	// FinishReason is why the first candidate ended: "stop", "length", ...
+	FinishReason string `json:"finish_reason,omitempty"`
+
+	// SystemFingerprint identifies the backend configuration that served the
+	// request; together with Seed it indicates whether outputs are comparable.
+	SystemFingerprint string `json:"system_fingerprint,omitempty"`
+
+	// RequestID is the provider's identifier for the request (OpenAI x-request-id),
+	// to quote when filing support tickets.
+	RequestID string `json:"request_id,omitempty"`
+
+	// EstimatedCost is the list-price cost of the request in USD, from the
+	// built-in pricing table; zero for local and unknown models.
+	EstimatedCost float64 `json:"estimated_cost,omitempty"`
+
+	// Arm names the SplitProvider arm that served the request.
+	Arm string `json:"arm,omitempty"`
+}
+
+// ChatChoice is a single candidate completion.
+type ChatChoice struct {
+	Index        int            `json:"index"`
+	Content      string         `json:"content"`
+	FinishReason string         `json:"finish_reason,omitempty"`
+	Logprobs     []TokenLogprob `json:"logprobs,omitempty"`
 }
 
 type ChatUsage struct {
@@ -37,8 +131,35 @@ type ChatUsage struct {
 	TotalTokens      int `json:"total_tokens"`
 }
 
+// ChatStreamDelta is one piece of a streamed answer. Done marks the delta
+// that finishes a candidate, with its FinishReason ("stop", "length", ...).
+// Usage may come with it or, as with OpenAI, in a later delta of its own.
 type ChatStreamDelta struct {
-	Content      string `json:"content"`
-	Done         bool   `json:"done"`
-	FinishReason string `json:"finish_reason,omitempty"`
+	Index        int            `json:"index,omitempty"` // candidate index when N > 1
+	Content      string         `json:"content"`
+	Done         bool           `json:"done"`
+	FinishReason string         `json:"finish_reason,omitempty"`
+	Logprobs     []TokenLogprob `json:"logprobs,omitempty"`
+	Usage        *ChatUsage     `json:"usage,omitempty"`      // set once, at the end of the stream
+	RequestID    string         `json:"request_id,omitempty"` // set once, at the end of the stream
+	Arm          string         `json:"arm,omitempty"`        // SplitProvider arm serving the stream
+}
+
+// TokenLogprob is the log probability of a single generated token,
+// optionally with the most likely alternatives at that position.
+type TokenLogprob struct {
+	Token       string         `json:"token"`
+	Logprob     float64        `json:"logprob"`
+	Bytes       []int          `json:"bytes,omitempty"`
+	TopLogprobs []TokenLogprob `json:"top_logprobs,omitempty"`
+}
+
+// ModelInfo describes a model offered by a provider, for model pickers.
+type ModelInfo struct {
+	ID       string       `json:"id"` // name to pass as ChatOptions.Model
+	Provider ProviderType `json:"provider"`
+	OwnedBy  string       `json:"owned_by,omitempty"` // OpenAI only
+	Size     int64        `json:"size,omitempty"`     // bytes on disk, local only
+	Family   string       `json:"family,omitempty"`   // local only
+	Created  time.Time    `json:"created,omitzero"`
 }
@@ -0,0 +1,65 @@
+package ai
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// defaultModelsCacheTTL bounds how long ListModels results are reused.
+const defaultModelsCacheTTL = 5 * time.Minute
+
+// modelCache memoises a provider's model list for a short time so model
+// pickers don't hit the provider on every page load.
+type modelCache struct {
+	ttl   time.Duration
+	fetch func(ctx context.Context) ([]ModelInfo, error)
+
+	mu        sync.Mutex
+	models    []ModelInfo
+	fetchedAt time.Time
+}
+
+func newModelCache(ttl time.Duration, fetch func(ctx context.Context) ([]ModelInfo, error)) *modelCache {
+	if ttl <= 0 {
+		ttl = defaultModelsCacheTTL
+	}
+	return &modelCache{ttl: ttl, fetch: fetch}
+}
+
+// get returns the cached list if it is fresh, fetching it otherwise.
+// Failed fetches are not cached. The returned slice must not be modified.
+func (m *modelCache) get(ctx context.Context) ([]ModelInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.models != nil && time.Since(m.fetchedAt) < m.ttl {
+		return m.models, nil
+	}
+
+	models, err := m.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if models == nil {
+		models = []ModelInfo{}
+	}
+	m.models = models
+	m.fetchedAt = time.Now()
+	return models, nil
+}
+
+// Serves reports whether p lists model among its models. Local model tags
+// default to "latest", so "llama3" matches "llama3:latest".
+func Serves(ctx context.Context, p ChatProvider, model string) (bool, error) {
+	models, err := p.ListModels(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, m := range models {
+		if m.ID == model || m.ID == model+":latest" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
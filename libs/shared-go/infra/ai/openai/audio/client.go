@@ -0,0 +1,199 @@
+package audio
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultModel        = "whisper-1"
+	defaultTimeout      = 5 * time.Minute
+	defaultFilename     = "audio.mp3"
+	transcriptionAPIURL = "https://api.openai.com/v1/audio/transcriptions"
+)
+
+type Client struct {
+	apiKey     string
+	model      string
+	httpClient *http.Client
+	logger     *zap.Logger
+	enabled    bool
+}
+
+func NewClient(cfg *Config, logger *zap.Logger) (*Client, error) {
+	if cfg == nil {
+		return nil, errors.New("config is required")
+	}
+	if !cfg.IsValid() {
+		return nil, errors.New("invalid OpenAI configuration: API key is required")
+	}
+
+	model := cfg.Model
+	if model == "" {
+		model = defaultModel
+	}
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	client := &Client{
+		apiKey: cfg.APIKey,
+		model:  model,
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+		logger:  logger,
+		enabled: true,
+	}
+
+	logger.Info("OpenAI audio client initialized",
+		zap.String("model", model))
+
+	return client, nil
+}
+
+// Transcribe uploads the audio read from r and returns its transcript.
+// The audio is streamed to OpenAI as multipart form data without being buffered in memory.
+func (c *Client) Transcribe(ctx context.Context, r io.Reader, opts *TranscriptionOptions) (*Transcription, error) {
+	if !c.enabled {
+		return nil, errors.New("OpenAI audio client is not enabled")
+	}
+	if r == nil {
+		return nil, errors.New("audio reader is required")
+	}
+	if opts == nil {
+		opts = &TranscriptionOptions{}
+	}
+
+	model := opts.Model
+	if model == "" {
+		model = c.model
+	}
+	format := opts.ResponseFormat
+	if format == "" {
+		format = FormatJSON
+	}
+	filename := opts.Filename
+	if filename == "" {
+		filename = defaultFilename
+	}
+
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+
+	go func() {
+		pw.CloseWithError(writeTranscriptionForm(mw, r, filename, model, format, opts))
+	}()
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, transcriptionAPIURL, pr)
+	if err != nil {
+		pr.CloseWithError(err)
+		c.logger.Error("Failed to create HTTP request", zap.Error(err))
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", mw.FormDataContentType())
+	httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+
+	c.logger.Debug("Sending transcription request",
+		zap.String("model", model),
+		zap.String("filename", filename),
+		zap.String("response_format", format))
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		pr.CloseWithError(err)
+		c.logger.Error("Failed to send HTTP request", zap.Error(err))
+		return nil, fmt.Errorf("failed to send HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		c.logger.Error("Failed to read response body", zap.Error(err))
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var apiErr APIError
+		if jsonErr := json.Unmarshal(raw, &apiErr); jsonErr == nil && apiErr.Error.Message != "" {
+			c.logger.Error("OpenAI API error",
+				zap.Int("status", resp.StatusCode),
+				zap.String("type", apiErr.Error.Type),
+				zap.String("message", apiErr.Error.Message))
+			return nil, fmt.Errorf("OpenAI API error (status %d): %s", resp.StatusCode, apiErr.Error.Message)
+		}
+		c.logger.Error("OpenAI API error",
+			zap.Int("status", resp.StatusCode),
+			zap.String("body", string(raw)))
+		return nil, fmt.Errorf("OpenAI API error (status %d): %s", resp.StatusCode, string(raw))
+	}
+
+	switch format {
+	case FormatText, FormatSRT, FormatVTT:
+		return &Transcription{Text: string(raw)}, nil
+	}
+
+	var out Transcription
+	if err := json.Unmarshal(raw, &out); err != nil {
+		c.logger.Error("Failed to unmarshal transcription response", zap.Error(err))
+		return nil, fmt.Errorf("failed to unmarshal transcription response: %w", err)
+	}
+
+	c.logger.Debug("Transcription received",
+		zap.String("language", out.Language),
+		zap.Float64("duration", out.Duration),
+		zap.Int("segments", len(out.Segments)))
+
+	return &out, nil
+}
+
+// IsEnabled returns whether the client is enabled.
+func (c *Client) IsEnabled() bool {
+	return c.enabled
+}
+
+// writeTranscriptionForm writes the multipart body: scalar fields first, then the file.
+func writeTranscriptionForm(mw *multipart.Writer, r io.Reader, filename, model, format string, opts *TranscriptionOptions) error {
+	fields := [][2]string{
+		{"model", model},
+		{"response_format", format},
+	}
+	if opts.Language != "" {
+		fields = append(fields, [2]string{"language", opts.Language})
+	}
+	if opts.Prompt != "" {
+		fields = append(fields, [2]string{"prompt", opts.Prompt})
+	}
+	if opts.Temperature != nil {
+		fields = append(fields, [2]string{"temperature", strconv.FormatFloat(*opts.Temperature, 'f', -1, 64)})
+	}
+	for _, g := range opts.TimestampGranularities {
+		fields = append(fields, [2]string{"timestamp_granularities[]", g})
+	}
+
+	for _, f := range fields {
+		if err := mw.WriteField(f[0], f[1]); err != nil {
+			return fmt.Errorf("failed to write form field %q: %w", f[0], err)
+		}
+	}
+
+	part, err := mw.CreateFormFile("file", filename)
+	if err != nil {
+		return fmt.Errorf("failed to create form file: %w", err)
+	}
+	if _, err := io.Copy(part, r); err != nil {
+		return fmt.Errorf("failed to write audio data: %w", err)
+	}
+	return mw.Close()
+}
@@ -0,0 +1,78 @@
+package audio
+
+import "time"
+
+// Response formats supported by the transcription endpoint.
+const (
+	FormatJSON        = "json"
+	FormatText        = "text"
+	FormatSRT         = "srt"
+	FormatVTT         = "vtt"
+	FormatVerboseJSON = "verbose_json" // includes language, duration and timestamps
+)
+
+// Timestamp granularities, only honoured with FormatVerboseJSON.
+const (
+	GranularityWord    = "word"
+	GranularitySegment = "segment"
+)
+
+// Config holds the configuration for the OpenAI audio client.
+type Config struct {
+	APIKey  string        // Required: OpenAI API key
+	Model   string        // e.g. "whisper-1", "gpt-4o-transcribe"
+	Timeout time.Duration // HTTP timeout (default: 5m, uploads can be large)
+}
+
+// IsValid returns true if the configuration has the minimum required fields.
+func (c *Config) IsValid() bool {
+	return c.APIKey != ""
+}
+
+// TranscriptionOptions are optional parameters for Transcribe.
+type TranscriptionOptions struct {
+	Model                  string   // Overrides Config.Model
+	Filename               string   // Upload filename; its extension tells OpenAI the format (default: "audio.mp3")
+	Language               string   // ISO-639-1 hint, e.g. "en"; improves accuracy and latency
+	Prompt                 string   // Optional context or spelling hints
+	Temperature            *float64 // 0-1 sampling temperature
+	ResponseFormat         string   // One of the Format constants (default: FormatJSON)
+	TimestampGranularities []string // GranularityWord and/or GranularitySegment
+}
+
+// Transcription is the result of a transcription request. For the text, srt
+// and vtt formats only Text is populated, holding the raw response.
+type Transcription struct {
+	Text     string    `json:"text"`
+	Language string    `json:"language,omitempty"`
+	Duration float64   `json:"duration,omitempty"` // seconds
+	Segments []Segment `json:"segments,omitempty"`
+	Words    []Word    `json:"words,omitempty"`
+}
+
+// Segment is a timestamped span of the transcript (verbose_json only).
+type Segment struct {
+	ID               int     `json:"id"`
+	Start            float64 `json:"start"` // seconds
+	End              float64 `json:"end"`   // seconds
+	Text             string  `json:"text"`
+	AvgLogprob       float64 `json:"avg_logprob"`
+	CompressionRatio float64 `json:"compression_ratio"`
+	NoSpeechProb     float64 `json:"no_speech_prob"`
+}
+
+// Word is a single timestamped word (verbose_json with word granularity only).
+type Word struct {
+	Word  string  `json:"word"`
+	Start float64 `json:"start"` // seconds
+	End   float64 `json:"end"`   // seconds
+}
+
+// APIError represents an error response from the OpenAI API.
+type APIError struct {
+	Error struct {
+		Message string `json:"message"`
+		Type    string `json:"type"`
+		Code    string `json:"code"`
+	} `json:"error"`
+}
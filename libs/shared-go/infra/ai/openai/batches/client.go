@@ -0,0 +1,298 @@
+package batches
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/ai/openai/files"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultTimeout      = 10 * time.Minute
+	defaultPollInterval = 30 * time.Second
+	batchesAPIURL       = "https://api.openai.com/v1/batches"
+)
+
+type Client struct {
+	apiKey       string
+	httpClient   *http.Client
+	files        *files.Client
+	pollInterval time.Duration
+	logger       *zap.Logger
+	enabled      bool
+}
+
+func NewClient(cfg *Config, logger *zap.Logger) (*Client, error) {
+	if cfg == nil {
+		return nil, errors.New("config is required")
+	}
+	if !cfg.IsValid() {
+		return nil, errors.New("invalid OpenAI configuration: API key is required")
+	}
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	pollInterval := cfg.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = defaultPollInterval
+	}
+
+	filesClient, err := files.NewClient(&files.Config{APIKey: cfg.APIKey, Timeout: timeout}, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create files client: %w", err)
+	}
+
+	client := &Client{
+		apiKey: cfg.APIKey,
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+		files:        filesClient,
+		pollInterval: pollInterval,
+		logger:       logger,
+		enabled:      true,
+	}
+
+	logger.Info("OpenAI batch client initialized",
+		zap.Duration("poll_interval", pollInterval))
+
+	return client, nil
+}
+
+// Create uploads a JSONL input file and starts a batch against endpoint.
+func (c *Client) Create(ctx context.Context, input io.Reader, endpoint string, metadata map[string]string) (*Batch, error) {
+	if !c.enabled {
+		return nil, errors.New("OpenAI batch client is not enabled")
+	}
+
+	file, err := c.files.Upload(ctx, input, "batch_input.jsonl", files.PurposeBatch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload batch input: %w", err)
+	}
+
+	return c.CreateFromFile(ctx, file.ID, endpoint, metadata)
+}
+
+// CreateFromLines encodes lines as JSONL and starts a batch. The lines are
+// streamed to the upload, so the encoded file is not held in memory.
+func (c *Client) CreateFromLines(ctx context.Context, lines []RequestLine, metadata map[string]string) (*Batch, error) {
+	if len(lines) == 0 {
+		return nil, errors.New("at least one batch line is required")
+	}
+	endpoint := lines[0].URL
+	for _, line := range lines {
+		if line.URL != endpoint {
+			return nil, fmt.Errorf("batch lines must share one endpoint, got %s and %s", endpoint, line.URL)
+		}
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(WriteJSONL(pw, lines))
+	}()
+	defer pr.Close()
+
+	return c.Create(ctx, pr, endpoint, metadata)
+}
+
+// CreateFromFile starts a batch from an input file that has already been uploaded.
+func (c *Client) CreateFromFile(ctx context.Context, inputFileID, endpoint string, metadata map[string]string) (*Batch, error) {
+	if !c.enabled {
+		return nil, errors.New("OpenAI batch client is not enabled")
+	}
+	if inputFileID == "" {
+		return nil, errors.New("input file ID is required")
+	}
+	if endpoint == "" {
+		endpoint = EndpointChatCompletions
+	}
+
+	req := CreateRequest{
+		InputFileID:      inputFileID,
+		Endpoint:         endpoint,
+		CompletionWindow: CompletionWindow24h,
+		Metadata:         metadata,
+	}
+
+	var out Batch
+	if err := c.doJSON(ctx, http.MethodPost, batchesAPIURL, req, &out); err != nil {
+		return nil, err
+	}
+
+	c.logger.Info("Batch created",
+		zap.String("batch_id", out.ID),
+		zap.String("input_file_id", inputFileID),
+		zap.String("endpoint", endpoint))
+
+	return &out, nil
+}
+
+// Retrieve returns the current state of a batch.
+func (c *Client) Retrieve(ctx context.Context, batchID string) (*Batch, error) {
+	if !c.enabled {
+		return nil, errors.New("OpenAI batch client is not enabled")
+	}
+	if batchID == "" {
+		return nil, errors.New("batch ID is required")
+	}
+
+	var out Batch
+	if err := c.doJSON(ctx, http.MethodGet, batchesAPIURL+"/"+url.PathEscape(batchID), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Cancel requests cancellation of an in-progress batch. The batch moves to
+// "cancelling" and then "cancelled"; partial results remain downloadable.
+func (c *Client) Cancel(ctx context.Context, batchID string) (*Batch, error) {
+	if !c.enabled {
+		return nil, errors.New("OpenAI batch client is not enabled")
+	}
+	if batchID == "" {
+		return nil, errors.New("batch ID is required")
+	}
+
+	var out Batch
+	if err := c.doJSON(ctx, http.MethodPost, batchesAPIURL+"/"+url.PathEscape(batchID)+"/cancel", nil, &out); err != nil {
+		return nil, err
+	}
+
+	c.logger.Info("Batch cancellation requested", zap.String("batch_id", batchID))
+	return &out, nil
+}
+
+// Wait polls the batch until it reaches a terminal status or ctx is done.
+func (c *Client) Wait(ctx context.Context, batchID string) (*Batch, error) {
+	ticker := time.NewTicker(c.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		batch, err := c.Retrieve(ctx, batchID)
+		if err != nil {
+			return nil, err
+		}
+
+		c.logger.Debug("Batch status",
+			zap.String("batch_id", batchID),
+			zap.String("status", batch.Status),
+			zap.Int("completed", batch.RequestCounts.Completed),
+			zap.Int("failed", batch.RequestCounts.Failed),
+			zap.Int("total", batch.RequestCounts.Total))
+
+		if batch.IsTerminal() {
+			return batch, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return batch, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Results downloads and parses the output file of a batch. Requests that could
+// not be executed are in the error file; fetch them with Errors.
+func (c *Client) Results(ctx context.Context, batch *Batch) ([]ResultLine, error) {
+	if batch == nil || batch.OutputFileID == "" {
+		return nil, nil
+	}
+	return c.download(ctx, batch.OutputFileID)
+}
+
+// Errors downloads and parses the error file of a batch, if any.
+func (c *Client) Errors(ctx context.Context, batch *Batch) ([]ResultLine, error) {
+	if batch == nil || batch.ErrorFileID == "" {
+		return nil, nil
+	}
+	return c.download(ctx, batch.ErrorFileID)
+}
+
+// IsEnabled returns whether the client is enabled.
+func (c *Client) IsEnabled() bool {
+	return c.enabled
+}
+
+func (c *Client) download(ctx context.Context, fileID string) ([]ResultLine, error) {
+	body, err := c.files.Content(ctx, fileID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download batch file %s: %w", fileID, err)
+	}
+	defer body.Close()
+
+	lines, err := ReadResults(body)
+	if err != nil {
+		c.logger.Error("Failed to parse batch file", zap.String("file_id", fileID), zap.Error(err))
+		return nil, err
+	}
+	return lines, nil
+}
+
+// doJSON sends an optional JSON body and decodes a successful JSON response into out.
+func (c *Client) doJSON(ctx context.Context, method, endpoint string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		jsonData, err := json.Marshal(body)
+		if err != nil {
+			c.logger.Error("Failed to marshal request", zap.Error(err))
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(jsonData)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		c.logger.Error("Failed to create HTTP request", zap.Error(err))
+		return fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		c.logger.Error("Failed to send HTTP request", zap.Error(err))
+		return fmt.Errorf("failed to send HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		c.logger.Error("Failed to read response body", zap.Error(err))
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var apiErr APIError
+		if jsonErr := json.Unmarshal(raw, &apiErr); jsonErr == nil && apiErr.Error.Message != "" {
+			c.logger.Error("OpenAI API error",
+				zap.Int("status", resp.StatusCode),
+				zap.String("type", apiErr.Error.Type),
+				zap.String("message", apiErr.Error.Message))
+			return fmt.Errorf("OpenAI API error (status %d): %s", resp.StatusCode, apiErr.Error.Message)
+		}
+		c.logger.Error("OpenAI API error",
+			zap.Int("status", resp.StatusCode),
+			zap.String("body", string(raw)))
+		return fmt.Errorf("OpenAI API error (status %d): %s", resp.StatusCode, string(raw))
+	}
+
+	if err := json.Unmarshal(raw, out); err != nil {
+		c.logger.Error("Failed to unmarshal response", zap.Error(err))
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package batches
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/ai/openai/chats"
+)
+
+// ChatRequestLines converts chat completion requests into batch input lines.
+// Custom IDs are idPrefix followed by the request index, so results can be
+// mapped back with ParseIndex. Streaming is disabled, as batches do not support it.
+func ChatRequestLines(reqs []chats.CompletionRequest, idPrefix string) []RequestLine {
+	lines := make([]RequestLine, len(reqs))
+	for i, req := range reqs {
+		req.Stream = false
+		req.StreamOptions = nil
+		lines[i] = RequestLine{
+			CustomID: fmt.Sprintf("%s%d", idPrefix, i),
+			Method:   "POST",
+			URL:      EndpointChatCompletions,
+			Body:     req,
+		}
+	}
+	return lines
+}
+
+// ParseIndex recovers the request index from a custom ID produced by ChatRequestLines.
+func ParseIndex(customID, idPrefix string) (int, error) {
+	rest, ok := strings.CutPrefix(customID, idPrefix)
+	if !ok {
+		return 0, fmt.Errorf("custom ID %q does not have prefix %q", customID, idPrefix)
+	}
+	idx, err := strconv.Atoi(rest)
+	if err != nil {
+		return 0, fmt.Errorf("invalid custom ID %q: %w", customID, err)
+	}
+	return idx, nil
+}
+
+// WriteJSONL writes lines to w in the batch input file format.
+func WriteJSONL(w io.Writer, lines []RequestLine) error {
+	seen := make(map[string]struct{}, len(lines))
+	enc := json.NewEncoder(w)
+	for _, line := range lines {
+		if line.CustomID == "" {
+			return errors.New("custom ID is required on every batch line")
+		}
+		if _, dup := seen[line.CustomID]; dup {
+			return fmt.Errorf("duplicate custom ID %q", line.CustomID)
+		}
+		seen[line.CustomID] = struct{}{}
+
+		if err := enc.Encode(line); err != nil {
+			return fmt.Errorf("failed to encode batch line %q: %w", line.CustomID, err)
+		}
+	}
+	return nil
+}
+
+// ReadResults parses a batch output or error file.
+func ReadResults(r io.Reader) ([]ResultLine, error) {
+	var out []ResultLine
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		raw := scanner.Bytes()
+		if len(raw) == 0 {
+			continue
+		}
+		var line ResultLine
+		if err := json.Unmarshal(raw, &line); err != nil {
+			return nil, fmt.Errorf("failed to parse result line %d: %w", n, err)
+		}
+		out = append(out, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read results: %w", err)
+	}
+	return out, nil
+}
+
+// ChatResponse decodes the body of a chat completion result.
+func (l *ResultLine) ChatResponse() (*chats.CompletionResponse, error) {
+	if l.Error != nil {
+		return nil, fmt.Errorf("batch request %s failed: %s", l.CustomID, l.Error.Message)
+	}
+	if l.Response == nil {
+		return nil, fmt.Errorf("batch request %s has no response", l.CustomID)
+	}
+	if l.Response.StatusCode < 200 || l.Response.StatusCode >= 300 {
+		var apiErr APIError
+		if err := json.Unmarshal(l.Response.Body, &apiErr); err == nil && apiErr.Error.Message != "" {
+			return nil, fmt.Errorf("OpenAI API error (status %d): %s", l.Response.StatusCode, apiErr.Error.Message)
+		}
+		return nil, fmt.Errorf("OpenAI API error (status %d): %s", l.Response.StatusCode, string(l.Response.Body))
+	}
+
+	var resp chats.CompletionResponse
+	if err := json.Unmarshal(l.Response.Body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal chat response: %w", err)
+	}
+	resp.RequestID = l.Response.RequestID
+	return &resp, nil
+}
@@ -0,0 +1,137 @@
+package batches
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Endpoints a batch can target.
+const (
+	EndpointChatCompletions = "/v1/chat/completions"
+	EndpointEmbeddings      = "/v1/embeddings"
+	EndpointResponses       = "/v1/responses"
+)
+
+// CompletionWindow24h is currently the only completion window OpenAI accepts.
+const CompletionWindow24h = "24h"
+
+// Batch statuses.
+const (
+	StatusValidating = "validating"
+	StatusFailed     = "failed"
+	StatusInProgress = "in_progress"
+	StatusFinalizing = "finalizing"
+	StatusCompleted  = "completed"
+	StatusExpired    = "expired"
+	StatusCancelling = "cancelling"
+	StatusCancelled  = "cancelled"
+)
+
+// Config holds the configuration for the OpenAI batch client.
+type Config struct {
+	APIKey       string        // Required: OpenAI API key
+	Timeout      time.Duration // HTTP timeout per call (default: 10m, covers input upload and result download)
+	PollInterval time.Duration // Interval used by Wait (default: 30s)
+}
+
+// IsValid returns true if the configuration has the minimum required fields.
+func (c *Config) IsValid() bool {
+	return c.APIKey != ""
+}
+
+// RequestLine is one line of a batch input file.
+type RequestLine struct {
+	CustomID string `json:"custom_id"` // Unique within the batch; used to match results to requests
+	Method   string `json:"method"`    // Always "POST"
+	URL      string `json:"url"`       // One of the Endpoint constants
+	Body     any    `json:"body"`
+}
+
+// CreateRequest is the payload sent to the create batch endpoint.
+type CreateRequest struct {
+	InputFileID      string            `json:"input_file_id"`
+	Endpoint         string            `json:"endpoint"`
+	CompletionWindow string            `json:"completion_window"`
+	Metadata         map[string]string `json:"metadata,omitempty"`
+}
+
+// Batch is a batch object as returned by the API.
+type Batch struct {
+	ID               string            `json:"id"`
+	Object           string            `json:"object"`
+	Endpoint         string            `json:"endpoint"`
+	Errors           *BatchErrors      `json:"errors,omitempty"`
+	InputFileID      string            `json:"input_file_id"`
+	CompletionWindow string            `json:"completion_window"`
+	Status           string            `json:"status"`
+	OutputFileID     string            `json:"output_file_id,omitempty"`
+	ErrorFileID      string            `json:"error_file_id,omitempty"`
+	CreatedAt        int64             `json:"created_at"`
+	InProgressAt     int64             `json:"in_progress_at,omitempty"`
+	ExpiresAt        int64             `json:"expires_at,omitempty"`
+	CompletedAt      int64             `json:"completed_at,omitempty"`
+	FailedAt         int64             `json:"failed_at,omitempty"`
+	CancelledAt      int64             `json:"cancelled_at,omitempty"`
+	RequestCounts    RequestCounts     `json:"request_counts"`
+	Metadata         map[string]string `json:"metadata,omitempty"`
+}
+
+// IsTerminal reports whether the batch has stopped changing state.
+func (b *Batch) IsTerminal() bool {
+	switch b.Status {
+	case StatusCompleted, StatusFailed, StatusExpired, StatusCancelled:
+		return true
+	}
+	return false
+}
+
+// RequestCounts tracks progress through the batch.
+type RequestCounts struct {
+	Total     int `json:"total"`
+	Completed int `json:"completed"`
+	Failed    int `json:"failed"`
+}
+
+// BatchErrors holds validation errors for a failed batch.
+type BatchErrors struct {
+	Object string       `json:"object"`
+	Data   []BatchError `json:"data"`
+}
+
+// BatchError is a single validation error, usually pointing at an input line.
+type BatchError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Param   string `json:"param,omitempty"`
+	Line    int    `json:"line,omitempty"`
+}
+
+// ResultLine is one line of a batch output or error file.
+type ResultLine struct {
+	ID       string          `json:"id"`
+	CustomID string          `json:"custom_id"`
+	Response *ResultResponse `json:"response,omitempty"`
+	Error    *ResultError    `json:"error,omitempty"`
+}
+
+// ResultResponse is the HTTP response recorded for a batched request.
+type ResultResponse struct {
+	StatusCode int             `json:"status_code"`
+	RequestID  string          `json:"request_id"`
+	Body       json.RawMessage `json:"body"`
+}
+
+// ResultError describes a request that could not be executed.
+type ResultError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// APIError represents an error response from the OpenAI API.
+type APIError struct {
+	Error struct {
+		Message string `json:"message"`
+		Type    string `json:"type"`
+		Code    string `json:"code"`
+	} `json:"error"`
+}
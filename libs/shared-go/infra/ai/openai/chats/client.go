@@ -1,7 +1,6 @@
 package chats
 
 import (
-	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -9,24 +8,38 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/ai/sse"
 	"go.uber.org/zap"
 )
 
 const (
-	defaultModel   = "gpt-4o-mini"
-	defaultTimeout = 2 * time.Minute
-	chatAPIURL     = "https://api.openai.com/v1/chat/completions"
+	defaultModel            = "gpt-4o-mini"
+	defaultTimeout          = 2 * time.Minute
+	defaultStreamBufferSize = 64 * 1024
+	defaultBaseURL          = "https://api.openai.com/v1"
+	chatEndpoint            = "/chat/completions"
+	modelsEndpoint          = "/models"
+	responsesEndpoint       = "/responses"
 )
 
 type Client struct {
-	apiKey     string
-	model      string
-	httpClient *http.Client
-	logger     *zap.Logger
-	enabled    bool
+	apiKey           string
+	apiKeyFunc       func() string
+	model            string
+	baseURL          string
+	headers          map[string]string
+	queryParams      map[string]string
+	httpClient       *http.Client
+	timeout          time.Duration
+	streamBufferSize int
+	retry            RetryPolicy
+	useResponses     bool
+	logger           *zap.Logger
+	enabled          bool
 }
 
 func NewClient(cfg *Config, logger *zap.Logger) (*Client, error) {
@@ -42,43 +55,72 @@ func NewClient(cfg *Config, logger *zap.Logger) (*Client, error) {
 		model = defaultModel
 	}
 
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	baseURL := strings.TrimRight(cfg.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+
+	retry := DefaultRetryPolicy()
+	if cfg.Retry != nil {
+		retry = cfg.Retry.withDefaults()
+	}
+
 	client := &Client{
-		apiKey: cfg.APIKey,
-		model:  model,
-		httpClient: &http.Client{
-			Timeout: defaultTimeout,
-		},
-		logger:  logger,
-		enabled: true,
+		apiKey:           cfg.APIKey,
+		apiKeyFunc:       cfg.APIKeyFunc,
+		model:            model,
+		baseURL:          baseURL,
+		headers:          cfg.Headers,
+		queryParams:      cfg.QueryParams,
+		httpClient:       newHTTPClient(cfg, timeout),
+		timeout:          timeout,
+		streamBufferSize: streamBufferSize(cfg.StreamBufferSize),
+		retry:            retry,
+		useResponses:     cfg.UseResponsesAPI,
+		logger:           logger,
+		enabled:          true,
 	}
 
 	logger.Info("OpenAI chat client initialized",
-		zap.String("model", model))
+		zap.String("model", model),
+		zap.Bool("responses_api", cfg.UseResponsesAPI))
 
 	return client, nil
 }
 
 func (c *Client) Completion(ctx context.Context, messages []Message, opts *Options) (*CompletionResponse, error) {
+	ctx, cancel := c.withRequestTimeout(ctx)
+	defer cancel()
+
 	if !c.enabled {
 		return nil, errors.New("OpenAI chat client is not enabled")
 	}
 	if len(messages) == 0 {
 		return nil, errors.New("at least one message is required")
 	}
+	if c.useResponses {
+		return c.responsesCompletion(ctx, messages, opts)
+	}
 
 	reqBody := c.buildRequest(messages, false, opts)
 
 	c.logger.Debug("Sending completion request",
-		zap.String("model", c.model),
+		zap.String("model", reqBody.Model),
 		zap.Int("message_count", len(messages)))
 
-	body, err := c.doRequest(ctx, reqBody)
+	httpResp, err := c.doRequest(ctx, chatEndpoint, reqBody)
 	if err != nil {
 		return nil, err
 	}
-	defer body.Close()
+	defer httpResp.Body.Close()
+	requestID := httpResp.Header.Get(requestIDHeader)
 
-	raw, err := io.ReadAll(body)
+	raw, err := io.ReadAll(httpResp.Body)
 	if err != nil {
 		c.logger.Error("Failed to read response body", zap.Error(err))
 		return nil, fmt.Errorf("failed to read response body: %w", err)
@@ -86,15 +128,20 @@ func (c *Client) Completion(ctx context.Context, messages []Message, opts *Optio
 
 	var resp CompletionResponse
 	if err := json.Unmarshal(raw, &resp); err != nil {
-		c.logger.Error("Failed to unmarshal completion response", zap.Error(err))
-		return nil, fmt.Errorf("failed to unmarshal completion response: %w", err)
+		c.logger.Error("Failed to unmarshal completion response",
+			zap.String("request_id", requestID),
+			zap.Error(err))
+		return nil, fmt.Errorf("failed to unmarshal completion response (request %s): %w", requestID, err)
 	}
+	resp.RequestID = requestID
 
 	c.logger.Debug("Completion response received",
+		zap.String("request_id", requestID),
 		zap.String("model", resp.Model),
 		zap.Int("prompt_tokens", resp.Usage.PromptTokens),
 		zap.Int("completion_tokens", resp.Usage.CompletionTokens),
-		zap.Int("total_tokens", resp.Usage.TotalTokens))
+		zap.Int("total_tokens", resp.Usage.TotalTokens),
+		zap.String("system_fingerprint", resp.SystemFingerprint))
 
 	return &resp, nil
 }
@@ -112,44 +159,75 @@ func (c *Client) CompletionStream(ctx context.Context, messages []Message, opts
 	if onChunk == nil {
 		return errors.New("onChunk callback is required")
 	}
+	if c.useResponses {
+		return c.responsesCompletionStream(ctx, messages, opts, onChunk)
+	}
 
 	reqBody := c.buildRequest(messages, true, opts)
 
 	c.logger.Debug("Sending streaming completion request",
-		zap.String("model", c.model),
+		zap.String("model", reqBody.Model),
 		zap.Int("message_count", len(messages)))
 
-	body, err := c.doRequest(ctx, reqBody)
+	httpResp, err := c.doRequest(ctx, chatEndpoint, reqBody)
 	if err != nil {
 		return err
 	}
-	defer body.Close()
+	defer httpResp.Body.Close()
+	requestID := httpResp.Header.Get(requestIDHeader)
 
-	scanner := bufio.NewScanner(body)
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" {
-			continue
+	reader := sse.NewReaderSize(httpResp.Body, c.streamBufferSize)
+	for {
+		event, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
 		}
-
-		// OpenAI streaming uses SSE format: "data: {...}" or "data: [DONE]"
-		if !strings.HasPrefix(line, "data: ") {
-			continue
+		if err != nil {
+			// A cancelled context aborts the in-flight read; report the cancellation, not the I/O error.
+			if ctx.Err() != nil {
+				c.logger.Debug("Stream cancelled", zap.Error(ctx.Err()))
+				return ctx.Err()
+			}
+			c.logger.Error("Error reading stream",
+				zap.String("request_id", requestID),
+				zap.Error(err))
+			return fmt.Errorf("error reading stream (request %s): %w", requestID, err)
 		}
 
-		data := strings.TrimPrefix(line, "data: ")
-		if data == "[DONE]" {
-			c.logger.Debug("Stream completed")
+		// OpenAI ends the stream with "data: [DONE]"
+		if event.Data == "[DONE]" {
+			c.logger.Debug("Stream completed", zap.String("request_id", requestID))
 			break
 		}
 
+		// Errors raised mid-stream arrive as an "error" event or an error payload.
+		if event.Event == "error" || strings.HasPrefix(event.Data, `{"error"`) {
+			var apiErr APIError
+			if err := json.Unmarshal([]byte(event.Data), &apiErr); err != nil || apiErr.Error.Message == "" {
+				apiErr.Error.Message = event.Data
+			}
+			c.logger.Error("OpenAI stream error",
+				zap.String("request_id", requestID),
+				zap.String("type", apiErr.Error.Type),
+				zap.String("message", apiErr.Error.Message))
+			return &StatusError{
+				StatusCode: httpResp.StatusCode,
+				Type:       apiErr.Error.Type,
+				Code:       apiErr.Error.Code,
+				Message:    apiErr.Error.Message,
+				RequestID:  requestID,
+			}
+		}
+
 		var chunk StreamChunk
-		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+		if err := json.Unmarshal([]byte(event.Data), &chunk); err != nil {
 			c.logger.Error("Failed to unmarshal stream chunk",
+				zap.String("request_id", requestID),
 				zap.Error(err),
-				zap.String("raw", data))
-			return fmt.Errorf("failed to unmarshal stream chunk: %w", err)
+				zap.String("raw", event.Data))
+			return fmt.Errorf("failed to unmarshal stream chunk (request %s): %w", requestID, err)
 		}
+		chunk.RequestID = requestID
 
 		if err := onChunk(chunk); err != nil {
 			c.logger.Debug("Streaming stopped by callback", zap.Error(err))
@@ -157,25 +235,24 @@ func (c *Client) CompletionStream(ctx context.Context, messages []Message, opts
 		}
 	}
 
-	if err := scanner.Err(); err != nil {
-		c.logger.Error("Error reading stream", zap.Error(err))
-		return fmt.Errorf("error reading stream: %w", err)
-	}
-
 	return nil
 }
 
-// Health checks if the OpenAI API is reachable by listing models.
+// Health checks that the OpenAI API is reachable and the configured model is
+// available to the API key (GET /models/{model}). A missing model is reported
+// as ErrModelNotAvailable.
 func (c *Client) Health(ctx context.Context) error {
+	ctx, cancel := c.withRequestTimeout(ctx)
+	defer cancel()
+
 	if !c.enabled {
 		return errors.New("OpenAI chat client is not enabled")
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.openai.com/v1/models", nil)
+	req, err := c.newRequest(ctx, http.MethodGet, modelsEndpoint+"/"+url.PathEscape(c.model), nil)
 	if err != nil {
 		return fmt.Errorf("failed to create health check request: %w", err)
 	}
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -183,98 +260,331 @@ func (c *Client) Health(ctx context.Context) error {
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		c.logger.Warn("OpenAI model not available", zap.String("model", c.model))
+		return fmt.Errorf("%w: %s", ErrModelNotAvailable, c.model)
+	default:
 		return fmt.Errorf("OpenAI health check returned status %d", resp.StatusCode)
 	}
 
-	c.logger.Info("OpenAI health check passed")
+	c.logger.Info("OpenAI health check passed", zap.String("model", c.model))
 	return nil
 }
 
+// ListModels returns the models available to the API key (GET /models).
+func (c *Client) ListModels(ctx context.Context) ([]ModelInfo, error) {
+	ctx, cancel := c.withRequestTimeout(ctx)
+	defer cancel()
+
+	if !c.enabled {
+		return nil, errors.New("OpenAI chat client is not enabled")
+	}
+
+	req, err := c.newRequest(ctx, http.MethodGet, modelsEndpoint, nil)
+	if err != nil {
+		c.logger.Error("Failed to create HTTP request", zap.Error(err))
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.logger.Error("Failed to send HTTP request", zap.Error(err))
+		return nil, fmt.Errorf("failed to send HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		c.logger.Error("Failed to read response body", zap.Error(err))
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		requestID := resp.Header.Get(requestIDHeader)
+		var apiErr APIError
+		if jsonErr := json.Unmarshal(raw, &apiErr); jsonErr != nil || apiErr.Error.Message == "" {
+			apiErr.Error.Message = string(raw)
+		}
+		c.logger.Error("OpenAI API error",
+			zap.Int("status", resp.StatusCode),
+			zap.String("request_id", requestID),
+			zap.String("message", apiErr.Error.Message))
+		return nil, &StatusError{
+			StatusCode: resp.StatusCode,
+			Type:       apiErr.Error.Type,
+			Code:       apiErr.Error.Code,
+			Message:    apiErr.Error.Message,
+			RequestID:  requestID,
+		}
+	}
+
+	var list ModelList
+	if err := json.Unmarshal(raw, &list); err != nil {
+		c.logger.Error("Failed to unmarshal model list", zap.Error(err))
+		return nil, fmt.Errorf("failed to unmarshal model list: %w", err)
+	}
+
+	return list.Data, nil
+}
+
 // IsEnabled returns whether the client is enabled.
 func (c *Client) IsEnabled() bool {
 	return c.enabled
 }
 
-// GetModel returns the configured model name.
+// GetModel returns the configured default model name.
 func (c *Client) GetModel() string {
 	return c.model
 }
 
 // buildRequest constructs the CompletionRequest, flattening Options into the top-level fields.
+// For reasoning models it switches max_tokens to max_completion_tokens and drops
+// the sampling parameters those models reject.
 func (c *Client) buildRequest(messages []Message, stream bool, opts *Options) CompletionRequest {
 	req := CompletionRequest{
 		Model:    c.model,
 		Messages: messages,
 		Stream:   stream,
 	}
+	if opts != nil && opts.Model != "" {
+		req.Model = opts.Model
+	}
+	if stream {
+		req.StreamOptions = &StreamOptions{IncludeUsage: true}
+	}
 
-	if opts != nil {
-		if opts.Temperature != 0 {
-			req.Temperature = &opts.Temperature
-		}
-		if opts.TopP != 0 {
-			req.TopP = &opts.TopP
-		}
-		if opts.MaxTokens != 0 {
-			req.MaxTokens = &opts.MaxTokens
+	if opts == nil {
+		return req
+	}
+
+	if len(opts.Stop) > 0 {
+		req.Stop = opts.Stop
+	}
+	if opts.N > 1 {
+		req.N = &opts.N
+	}
+	req.Seed = opts.Seed
+	if opts.MaxCompletionTokens != 0 {
+		req.MaxCompletionTokens = &opts.MaxCompletionTokens
+	}
+	req.ResponseFormat = opts.ResponseFormat
+
+	if isReasoningModel(req.Model) {
+		if req.MaxCompletionTokens == nil && opts.MaxTokens != 0 {
+			req.MaxCompletionTokens = &opts.MaxTokens
 		}
-		if len(opts.Stop) > 0 {
-			req.Stop = opts.Stop
+		req.ReasoningEffort = opts.ReasoningEffort
+		if opts.Temperature != nil || opts.TopP != nil || opts.Logprobs ||
+			opts.FrequencyPenalty != nil || opts.PresencePenalty != nil {
+			c.logger.Debug("Dropping sampling options unsupported by reasoning model",
+				zap.String("model", req.Model))
 		}
+		return req
+	}
+
+	req.Temperature = opts.Temperature
+	req.TopP = opts.TopP
+	if opts.MaxTokens != 0 && req.MaxCompletionTokens == nil {
+		req.MaxTokens = &opts.MaxTokens
+	}
+	if opts.Logprobs {
+		req.Logprobs = &opts.Logprobs
+		req.TopLogprobs = opts.TopLogprobs
 	}
+	req.FrequencyPenalty = opts.FrequencyPenalty
+	req.PresencePenalty = opts.PresencePenalty
 
 	return req
 }
 
-// doRequest marshals the request body and sends the HTTP POST to the OpenAI API.
-// Returns the response body (caller must close it).
-func (c *Client) doRequest(ctx context.Context, reqBody CompletionRequest) (io.ReadCloser, error) {
+// isReasoningModel reports whether model belongs to the o-series reasoning
+// family (o1, o3, o4-mini, ...), which uses a different parameter set.
+func isReasoningModel(model string) bool {
+	return len(model) >= 2 && model[0] == 'o' && model[1] >= '0' && model[1] <= '9'
+}
+
+// doRequest marshals the request body and sends the HTTP POST to endpoint,
+// retrying 429, 5xx and transport failures according to the retry policy.
+// Returns the successful response (caller must close its body).
+func (c *Client) doRequest(ctx context.Context, endpoint string, reqBody any) (*http.Response, error) {
 	jsonData, err := json.Marshal(reqBody)
 	if err != nil {
 		c.logger.Error("Failed to marshal request", zap.Error(err))
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, chatAPIURL, bytes.NewBuffer(jsonData))
+	var waited time.Duration
+	for attempt := 1; ; attempt++ {
+		resp, status, err := c.sendRequest(ctx, endpoint, jsonData)
+		if err == nil {
+			return resp, nil
+		}
+
+		retryable := status == 0 || isRetryableStatus(status)
+		if !retryable || ctx.Err() != nil || attempt >= c.retry.MaxAttempts {
+			return nil, err
+		}
+
+		// Prefer the server's own schedule over blind backoff when it gives one.
+		delay := c.retry.backoff(attempt)
+		var rlErr *RateLimitError
+		if errors.As(err, &rlErr) {
+			if hint := rlErr.RateLimit.RetryDelay(); hint > 0 {
+				delay = hint
+			}
+		}
+		if c.retry.Budget > 0 && waited+delay > c.retry.Budget {
+			c.logger.Warn("Retry budget exhausted",
+				zap.Int("attempt", attempt),
+				zap.Duration("waited", waited))
+			return nil, err
+		}
+
+		c.logger.Warn("Retrying OpenAI request",
+			zap.Int("attempt", attempt),
+			zap.Int("status", status),
+			zap.Duration("backoff", delay),
+			zap.Error(err))
+
+		if err := sleepContext(ctx, delay); err != nil {
+			return nil, err
+		}
+		waited += delay
+	}
+}
+
+// sendRequest performs a single HTTP attempt. On failure it returns the HTTP
+// status (0 for transport errors) so the caller can decide whether to retry.
+func (c *Client) sendRequest(ctx context.Context, endpoint string, jsonData []byte) (*http.Response, int, error) {
+	httpReq, err := c.newRequest(ctx, http.MethodPost, endpoint, bytes.NewReader(jsonData))
 	if err != nil {
 		c.logger.Error("Failed to create HTTP request", zap.Error(err))
-		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+		return nil, 0, fmt.Errorf("failed to create HTTP request: %w", err)
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
-
-	// For streaming requests, use a client without a timeout
-	// so the connection stays open for the duration of generation.
-	httpClient := c.httpClient
-	if reqBody.Stream {
-		httpClient = &http.Client{} // no timeout for streaming
-	}
 
-	resp, err := httpClient.Do(httpReq)
+	resp, err := c.httpClient.Do(httpReq)
 	if err != nil {
 		c.logger.Error("Failed to send HTTP request", zap.Error(err))
-		return nil, fmt.Errorf("failed to send HTTP request: %w", err)
+		return nil, 0, fmt.Errorf("failed to send HTTP request: %w", err)
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		defer resp.Body.Close()
 		body, _ := io.ReadAll(resp.Body)
+		requestID := resp.Header.Get(requestIDHeader)
 
 		var apiErr APIError
-		if jsonErr := json.Unmarshal(body, &apiErr); jsonErr == nil && apiErr.Error.Message != "" {
-			c.logger.Error("OpenAI API error",
-				zap.Int("status", resp.StatusCode),
-				zap.String("type", apiErr.Error.Type),
-				zap.String("message", apiErr.Error.Message))
-			return nil, fmt.Errorf("OpenAI API error (status %d): %s", resp.StatusCode, apiErr.Error.Message)
+		if jsonErr := json.Unmarshal(body, &apiErr); jsonErr != nil || apiErr.Error.Message == "" {
+			apiErr.Error.Message = string(body)
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests {
+			rlErr := &RateLimitError{
+				StatusCode: resp.StatusCode,
+				Type:       apiErr.Error.Type,
+				Message:    apiErr.Error.Message,
+				RequestID:  requestID,
+				RateLimit:  parseRateLimitHeaders(resp.Header),
+			}
+			c.logger.Warn("OpenAI rate limit exceeded",
+				zap.String("request_id", requestID),
+				zap.String("type", rlErr.Type),
+				zap.Int("remaining_requests", rlErr.RateLimit.RemainingRequests),
+				zap.Int("remaining_tokens", rlErr.RateLimit.RemainingTokens),
+				zap.Duration("retry_after", rlErr.RateLimit.RetryDelay()))
+			return nil, resp.StatusCode, rlErr
 		}
 
 		c.logger.Error("OpenAI API error",
 			zap.Int("status", resp.StatusCode),
-			zap.String("body", string(body)))
-		return nil, fmt.Errorf("OpenAI API error (status %d): %s", resp.StatusCode, string(body))
+			zap.String("request_id", requestID),
+			zap.String("type", apiErr.Error.Type),
+			zap.String("message", apiErr.Error.Message))
+		return nil, resp.StatusCode, &StatusError{
+			StatusCode: resp.StatusCode,
+			Type:       apiErr.Error.Type,
+			Code:       apiErr.Error.Code,
+			Message:    apiErr.Error.Message,
+			RequestID:  requestID,
+		}
+	}
+
+	return resp, resp.StatusCode, nil
+}
+
+type apiKeyCtxKey struct{}
+
+// WithAPIKey makes requests to api.openai.com made with ctx authenticate
+// with key, e.g. a user's own key, instead of the configured one. Clients
+// with a custom BaseURL (gateways, proxies) keep their own key, so a user's
+// key is never sent to a third party.
+func WithAPIKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, apiKeyCtxKey{}, key)
+}
+
+// newRequest builds a request against the configured base URL, applying
+// authentication, custom headers, and default query parameters.
+func (c *Client) newRequest(ctx context.Context, method, endpoint string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(c.queryParams) > 0 {
+		q := req.URL.Query()
+		for k, v := range c.queryParams {
+			q.Set(k, v)
+		}
+		req.URL.RawQuery = q.Encode()
 	}
 
-	return resp.Body, nil
+	apiKey := c.apiKey
+	if c.apiKeyFunc != nil {
+		apiKey = c.apiKeyFunc()
+	}
+	if key, ok := ctx.Value(apiKeyCtxKey{}).(string); ok && key != "" && c.baseURL == defaultBaseURL {
+		apiKey = key
+	}
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+	for k, v := range c.headers {
+		req.Header.Set(k, v)
+	}
+
+	return req, nil
+}
+
+// newHTTPClient returns the caller-supplied client, or a shared client around the
+// configured transport. The client itself has no overall timeout so streams can
+// run for as long as generation takes; non-streaming calls are bounded by a
+// context deadline instead (see withRequestTimeout), and the default transport
+// bounds the wait for response headers.
+func newHTTPClient(cfg *Config, timeout time.Duration) *http.Client {
+	if cfg.HTTPClient != nil {
+		return cfg.HTTPClient
+	}
+
+	transport := cfg.Transport
+	if transport == nil {
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		t.ResponseHeaderTimeout = timeout
+		transport = t
+	}
+	return &http.Client{Transport: transport}
+}
+
+// streamBufferSize returns the configured initial read buffer size for streams.
+func streamBufferSize(size int) int {
+	if size <= 0 {
+		return defaultStreamBufferSize
+	}
+	return size
+}
+
+// withRequestTimeout bounds a non-streaming call, including reading the body,
+// by the configured timeout.
+func (c *Client) withRequestTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, c.timeout)
 }
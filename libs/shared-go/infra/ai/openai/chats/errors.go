@@ -0,0 +1,31 @@
+package chats
+
+import (
+	"errors"
+	"fmt"
+)
+
+// requestIDHeader is the response header OpenAI uses to identify a request;
+// quote it when contacting OpenAI support.
+const requestIDHeader = "x-request-id"
+
+// ErrModelNotAvailable is returned by Health when the configured model does not
+// exist or the API key has no access to it.
+var ErrModelNotAvailable = errors.New("model not available to this API key")
+
+// StatusError is returned when the OpenAI API responds with a non-2xx status
+// other than 429 (see RateLimitError).
+type StatusError struct {
+	StatusCode int
+	Type       string
+	Code       string
+	Message    string
+	RequestID  string
+}
+
+func (e *StatusError) Error() string {
+	if e.RequestID != "" {
+		return fmt.Sprintf("OpenAI API error (status %d, request %s): %s", e.StatusCode, e.RequestID, e.Message)
+	}
+	return fmt.Sprintf("OpenAI API error (status %d): %s", e.StatusCode, e.Message)
+}
@@ -1,5 +1,18 @@
 package chats
 
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// Reasoning effort levels for o-series models.
+const (
+	ReasoningEffortLow    = "low"
+	ReasoningEffortMedium = "medium"
+	ReasoningEffortHigh   = "high"
+)
+
 // Role constants for chat messages.
 const (
 	RoleSystem    = "system"
@@ -9,13 +22,38 @@ const (
 
 // Config holds the configuration for the OpenAI chat completion client.
 type Config struct {
-	APIKey string // Required: OpenAI API key
-	Model  string // e.g. "gpt-4o", "gpt-4o-mini", "gpt-3.5-turbo"
+	APIKey  string        // Required: OpenAI API key
+	Model   string        // e.g. "gpt-4o", "gpt-4o-mini", "gpt-3.5-turbo"
+	Timeout time.Duration // bounds non-streaming requests and the wait for response headers (default: 2m)
+
+	// APIKeyFunc, if set, is called for every request instead of using
+	// APIKey, so that a rotated key is used without a restart.
+	APIKeyFunc func() string
+
+	// StreamBufferSize is the initial read buffer for streaming responses
+	// (default: 64KB). Lines longer than this are still read in full.
+	StreamBufferSize int
+	Retry            *RetryPolicy // nil uses DefaultRetryPolicy; MaxAttempts 1 disables retries
+
+	// UseResponsesAPI sends requests to /responses instead of /chat/completions.
+	// Results are still returned as CompletionResponse and StreamChunk values.
+	UseResponsesAPI bool
+
+	// Gateway/proxy support (Cloudflare AI Gateway, Kong, internal proxies).
+	BaseURL     string            // default: "https://api.openai.com/v1"
+	Headers     map[string]string // added to every request; may override Authorization
+	QueryParams map[string]string // added to every request URL
+
+	// HTTPClient, if set, is shared by all requests; its own Timeout also applies
+	// to streams, so leave it zero. Otherwise a single client is built around
+	// Transport, or a clone of http.DefaultTransport.
+	HTTPClient *http.Client
+	Transport  http.RoundTripper
 }
 
 // IsValid returns true if the configuration has the minimum required fields.
 func (c *Config) IsValid() bool {
-	return c.APIKey != ""
+	return c.APIKey != "" || c.APIKeyFunc != nil
 }
 
 // Message represents a single chat message.
@@ -26,22 +64,72 @@ type Message struct {
 
 // CompletionRequest is the payload sent to the OpenAI chat completion API.
 type CompletionRequest struct {
-	Model       string    `json:"model"`
-	Messages    []Message `json:"messages"`
-	Stream      bool      `json:"stream"`
-	Options     *Options  `json:"-"` // flattened into the request during marshalling
-	Temperature *float64  `json:"temperature,omitempty"`
-	TopP        *float64  `json:"top_p,omitempty"`
-	MaxTokens   *int      `json:"max_tokens,omitempty"`
-	Stop        []string  `json:"stop,omitempty"`
+	Model         string         `json:"model"`
+	Messages      []Message      `json:"messages"`
+	Stream        bool           `json:"stream"`
+	StreamOptions *StreamOptions `json:"stream_options,omitempty"`
+	Options       *Options       `json:"-"` // flattened into the request during marshalling
+	Temperature   *float64       `json:"temperature,omitempty"`
+	TopP          *float64       `json:"top_p,omitempty"`
+	MaxTokens     *int           `json:"max_tokens,omitempty"`
+	Stop          []string       `json:"stop,omitempty"`
+	Logprobs      *bool          `json:"logprobs,omitempty"`
+	TopLogprobs   *int           `json:"top_logprobs,omitempty"`
+	N             *int           `json:"n,omitempty"`
+
+	FrequencyPenalty *float64 `json:"frequency_penalty,omitempty"`
+	PresencePenalty  *float64 `json:"presence_penalty,omitempty"`
+	Seed             *int64   `json:"seed,omitempty"`
+
+	MaxCompletionTokens *int   `json:"max_completion_tokens,omitempty"`
+	ReasoningEffort     string `json:"reasoning_effort,omitempty"`
+
+	ResponseFormat *ResponseFormat `json:"response_format,omitempty"`
+}
+
+// ResponseFormat constrains the model output to JSON.
+type ResponseFormat struct {
+	Type       string      `json:"type"` // "text", "json_object" or "json_schema"
+	JSONSchema *JSONSchema `json:"json_schema,omitempty"`
+}
+
+// JSONSchema describes the expected output for the "json_schema" response format.
+type JSONSchema struct {
+	Name   string          `json:"name"`
+	Schema json.RawMessage `json:"schema"`
+	Strict bool            `json:"strict,omitempty"`
+}
+
+// StreamOptions controls extra data sent on streaming requests.
+type StreamOptions struct {
+	IncludeUsage bool `json:"include_usage"` // send a final chunk with token usage
 }
 
 // Options are optional model-level parameters.
+// Pointer fields are sent only when non-nil, so an explicit zero is honoured.
 type Options struct {
-	Temperature float64  `json:"temperature,omitempty"`
-	TopP        float64  `json:"top_p,omitempty"`
+	Model       string   `json:"-"` // Overrides Config.Model for this request when set
+	Temperature *float64 `json:"temperature,omitempty"`
+	TopP        *float64 `json:"top_p,omitempty"`
 	MaxTokens   int      `json:"max_tokens,omitempty"`
 	Stop        []string `json:"stop,omitempty"`
+	Logprobs    bool     `json:"logprobs,omitempty"`     // Return log probabilities of the output tokens
+	TopLogprobs *int     `json:"top_logprobs,omitempty"` // 0-20 most likely tokens per position, requires Logprobs
+	N           int      `json:"n,omitempty"`            // Number of choices to generate
+
+	FrequencyPenalty *float64 `json:"frequency_penalty,omitempty"` // -2.0 to 2.0, penalises tokens by how often they appeared
+	PresencePenalty  *float64 `json:"presence_penalty,omitempty"`  // -2.0 to 2.0, penalises tokens that appeared at all
+	Seed             *int64   `json:"seed,omitempty"`              // Best-effort deterministic sampling
+
+	// Reasoning models (o1, o3, o4-mini, ...) only accept these; buildRequest
+	// maps MaxTokens onto MaxCompletionTokens and drops unsupported sampling fields.
+	MaxCompletionTokens int    `json:"max_completion_tokens,omitempty"`
+	ReasoningEffort     string `json:"reasoning_effort,omitempty"` // "low", "medium" or "high"
+
+	// Tools enables built-in tools such as web search. Only used with UseResponsesAPI.
+	Tools []Tool `json:"-"`
+
+	ResponseFormat *ResponseFormat `json:"-"` // JSON mode or structured outputs
 }
 
 // CompletionResponse is the full (non-streaming) response from the OpenAI API.
@@ -52,13 +140,38 @@ type CompletionResponse struct {
 	Model   string   `json:"model"`
 	Choices []Choice `json:"choices"`
 	Usage   Usage    `json:"usage"`
+
+	SystemFingerprint string `json:"system_fingerprint,omitempty"` // backend configuration, compare alongside Seed
+
+	RequestID string `json:"-"` // from the x-request-id response header, for support tickets
 }
 
 // Choice represents a single completion choice.
 type Choice struct {
-	Index        int     `json:"index"`
-	Message      Message `json:"message"`
-	FinishReason string  `json:"finish_reason"`
+	Index        int       `json:"index"`
+	Message      Message   `json:"message"`
+	FinishReason string    `json:"finish_reason"`
+	Logprobs     *Logprobs `json:"logprobs,omitempty"` // present when logprobs were requested
+}
+
+// Logprobs holds the per-token log probability information for a choice.
+type Logprobs struct {
+	Content []TokenLogprob `json:"content"`
+}
+
+// TokenLogprob is the log probability of a single token.
+type TokenLogprob struct {
+	Token       string       `json:"token"`
+	Logprob     float64      `json:"logprob"`
+	Bytes       []int        `json:"bytes,omitempty"`
+	TopLogprobs []TopLogprob `json:"top_logprobs,omitempty"`
+}
+
+// TopLogprob is one of the most likely tokens at a given position.
+type TopLogprob struct {
+	Token   string  `json:"token"`
+	Logprob float64 `json:"logprob"`
+	Bytes   []int   `json:"bytes,omitempty"`
 }
 
 // Usage contains token usage statistics.
@@ -76,13 +189,18 @@ type StreamChunk struct {
 	Model   string        `json:"model"`
 	Choices []StreamDelta `json:"choices"`
 	Usage   *Usage        `json:"usage,omitempty"` // present in the final chunk if requested
+
+	SystemFingerprint string `json:"system_fingerprint,omitempty"`
+
+	RequestID string `json:"-"` // from the x-request-id response header
 }
 
 // StreamDelta represents a single delta in a streaming response.
 type StreamDelta struct {
-	Index        int    `json:"index"`
-	Delta        Delta  `json:"delta"`
-	FinishReason string `json:"finish_reason,omitempty"`
+	Index        int       `json:"index"`
+	Delta        Delta     `json:"delta"`
+	FinishReason string    `json:"finish_reason,omitempty"`
+	Logprobs     *Logprobs `json:"logprobs,omitempty"` // present when logprobs were requested
 }
 
 // Delta is the incremental content in a streaming chunk.
@@ -100,3 +218,16 @@ type APIError struct {
 	} `json:"error"`
 }
 
+// ModelList is the response from the /models endpoint.
+type ModelList struct {
+	Object string      `json:"object"`
+	Data   []ModelInfo `json:"data"`
+}
+
+// ModelInfo describes a model available to the API key.
+type ModelInfo struct {
+	ID      string `json:"id"`
+	Object  string `json:"object"`
+	Created int64  `json:"created"`
+	OwnedBy string `json:"owned_by"`
+}
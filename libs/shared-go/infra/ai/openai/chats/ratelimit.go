@@ -0,0 +1,108 @@
+package chats
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimitInfo is the rate-limit state reported by OpenAI response headers.
+// Zero values mean the header was absent.
+type RateLimitInfo struct {
+	LimitRequests     int
+	RemainingRequests int
+	ResetRequests     time.Duration // time until the request quota resets
+	LimitTokens       int
+	RemainingTokens   int
+	ResetTokens       time.Duration // time until the token quota resets
+	RetryAfter        time.Duration // from Retry-After / retry-after-ms
+}
+
+// RateLimitError is returned when OpenAI rejects a request with HTTP 429.
+type RateLimitError struct {
+	StatusCode int
+	Type       string
+	Message    string
+	RequestID  string
+	RateLimit  RateLimitInfo
+}
+
+func (e *RateLimitError) Error() string {
+	detail := fmt.Sprintf("status %d", e.StatusCode)
+	if e.RequestID != "" {
+		detail += ", request " + e.RequestID
+	}
+	if e.RateLimit.RetryAfter > 0 {
+		detail += ", retry after " + e.RateLimit.RetryAfter.String()
+	}
+	return fmt.Sprintf("OpenAI rate limit exceeded (%s): %s", detail, e.Message)
+}
+
+// RetryDelay returns the server-suggested wait before retrying, or 0 if none was given.
+// Retry-After wins; otherwise the reset time of whichever quota is exhausted is used.
+func (r RateLimitInfo) RetryDelay() time.Duration {
+	if r.RetryAfter > 0 {
+		return r.RetryAfter
+	}
+	var delay time.Duration
+	if r.LimitRequests > 0 && r.RemainingRequests == 0 {
+		delay = r.ResetRequests
+	}
+	if r.LimitTokens > 0 && r.RemainingTokens == 0 && r.ResetTokens > delay {
+		delay = r.ResetTokens
+	}
+	return delay
+}
+
+// parseRateLimitHeaders extracts rate-limit information from response headers.
+func parseRateLimitHeaders(h http.Header) RateLimitInfo {
+	return RateLimitInfo{
+		LimitRequests:     headerInt(h, "x-ratelimit-limit-requests"),
+		RemainingRequests: headerInt(h, "x-ratelimit-remaining-requests"),
+		ResetRequests:     parseResetDuration(h.Get("x-ratelimit-reset-requests")),
+		LimitTokens:       headerInt(h, "x-ratelimit-limit-tokens"),
+		RemainingTokens:   headerInt(h, "x-ratelimit-remaining-tokens"),
+		ResetTokens:       parseResetDuration(h.Get("x-ratelimit-reset-tokens")),
+		RetryAfter:        parseRetryAfter(h),
+	}
+}
+
+// parseRetryAfter reads retry-after-ms, then Retry-After as seconds or an HTTP date.
+func parseRetryAfter(h http.Header) time.Duration {
+	if ms, err := strconv.ParseFloat(strings.TrimSpace(h.Get("retry-after-ms")), 64); err == nil && ms > 0 {
+		return time.Duration(ms * float64(time.Millisecond))
+	}
+
+	v := strings.TrimSpace(h.Get("Retry-After"))
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.ParseFloat(v, 64); err == nil && secs > 0 {
+		return time.Duration(secs * float64(time.Second))
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// parseResetDuration parses OpenAI reset values such as "1s", "6m0s" or "20ms".
+func parseResetDuration(v string) time.Duration {
+	d, err := time.ParseDuration(strings.TrimSpace(v))
+	if err != nil || d < 0 {
+		return 0
+	}
+	return d
+}
+
+func headerInt(h http.Header, key string) int {
+	n, err := strconv.Atoi(strings.TrimSpace(h.Get(key)))
+	if err != nil {
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,348 @@
+package chats
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/ai/sse"
+	"go.uber.org/zap"
+)
+
+// Tool is a built-in Responses API tool, e.g. {Type: "web_search_preview"}
+// or {Type: "file_search", VectorStoreIDs: [...]}.
+type Tool struct {
+	Type              string   `json:"type"`
+	VectorStoreIDs    []string `json:"vector_store_ids,omitempty"`    // file_search
+	MaxNumResults     int      `json:"max_num_results,omitempty"`     // file_search
+	SearchContextSize string   `json:"search_context_size,omitempty"` // web_search_preview: "low", "medium" or "high"
+}
+
+// ResponsesRequest is the payload sent to the /responses endpoint.
+type ResponsesRequest struct {
+	Model           string              `json:"model"`
+	Input           []Message           `json:"input"`
+	Stream          bool                `json:"stream,omitempty"`
+	Temperature     *float64            `json:"temperature,omitempty"`
+	TopP            *float64            `json:"top_p,omitempty"`
+	MaxOutputTokens *int                `json:"max_output_tokens,omitempty"`
+	Reasoning       *ResponsesReasoning `json:"reasoning,omitempty"`
+	Tools           []Tool              `json:"tools,omitempty"`
+	Store           *bool               `json:"store,omitempty"` // false keeps requests stateless, like chat completions
+	Text            *ResponsesText      `json:"text,omitempty"`
+}
+
+// ResponsesText configures the text output of a response.
+type ResponsesText struct {
+	Format ResponsesTextFormat `json:"format"`
+}
+
+// ResponsesTextFormat is the Responses API form of ResponseFormat, with the
+// schema fields inlined rather than nested under json_schema.
+type ResponsesTextFormat struct {
+	Type   string          `json:"type"`
+	Name   string          `json:"name,omitempty"`
+	Schema json.RawMessage `json:"schema,omitempty"`
+	Strict bool            `json:"strict,omitempty"`
+}
+
+// ResponsesReasoning configures reasoning models on the Responses API.
+type ResponsesReasoning struct {
+	Effort string `json:"effort,omitempty"`
+}
+
+// ResponsesResponse is a response object from the /responses endpoint.
+type ResponsesResponse struct {
+	ID                string                `json:"id"`
+	Object            string                `json:"object"`
+	CreatedAt         int64                 `json:"created_at"`
+	Model             string                `json:"model"`
+	Status            string                `json:"status"` // "completed", "incomplete", "failed", ...
+	Output            []ResponsesOutputItem `json:"output"`
+	Usage             *ResponsesUsage       `json:"usage,omitempty"`
+	Error             *ResponsesError       `json:"error,omitempty"`
+	IncompleteDetails *struct {
+		Reason string `json:"reason"` // e.g. "max_output_tokens"
+	} `json:"incomplete_details,omitempty"`
+}
+
+// ResponsesOutputItem is one item of a response's output: a message, or a
+// record of a built-in tool call such as "web_search_call".
+type ResponsesOutputItem struct {
+	ID      string                 `json:"id"`
+	Type    string                 `json:"type"`
+	Role    string                 `json:"role,omitempty"`
+	Status  string                 `json:"status,omitempty"`
+	Content []ResponsesContentPart `json:"content,omitempty"`
+}
+
+// ResponsesContentPart is a piece of message content.
+type ResponsesContentPart struct {
+	Type        string            `json:"type"` // "output_text" or "refusal"
+	Text        string            `json:"text,omitempty"`
+	Refusal     string            `json:"refusal,omitempty"`
+	Annotations []json.RawMessage `json:"annotations,omitempty"` // url and file citations from tools
+}
+
+// ResponsesUsage contains token usage statistics for a response.
+type ResponsesUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+	TotalTokens  int `json:"total_tokens"`
+}
+
+// ResponsesError is the error attached to a failed response or error event.
+type ResponsesError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// OutputText concatenates the text of every message in the output.
+func (r *ResponsesResponse) OutputText() string {
+	var sb strings.Builder
+	for _, item := range r.Output {
+		if item.Type != "message" {
+			continue
+		}
+		for _, part := range item.Content {
+			if part.Type == "output_text" {
+				sb.WriteString(part.Text)
+			}
+		}
+	}
+	return sb.String()
+}
+
+// responsesStreamEvent covers the fields used from Responses API stream events.
+type responsesStreamEvent struct {
+	Type     string             `json:"type"`
+	Delta    string             `json:"delta,omitempty"`
+	Response *ResponsesResponse `json:"response,omitempty"`
+	Code     string             `json:"code,omitempty"`
+	Message  string             `json:"message,omitempty"`
+}
+
+func (c *Client) responsesCompletion(ctx context.Context, messages []Message, opts *Options) (*CompletionResponse, error) {
+	reqBody := c.buildResponsesRequest(messages, false, opts)
+
+	c.logger.Debug("Sending responses request",
+		zap.String("model", reqBody.Model),
+		zap.Int("message_count", len(messages)),
+		zap.Int("tool_count", len(reqBody.Tools)))
+
+	httpResp, err := c.doRequest(ctx, responsesEndpoint, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+	requestID := httpResp.Header.Get(requestIDHeader)
+
+	raw, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		c.logger.Error("Failed to read response body", zap.Error(err))
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	var resp ResponsesResponse
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		c.logger.Error("Failed to unmarshal responses response",
+			zap.String("request_id", requestID),
+			zap.Error(err))
+		return nil, fmt.Errorf("failed to unmarshal responses response (request %s): %w", requestID, err)
+	}
+	if resp.Error != nil {
+		return nil, c.responsesError(resp.Error, httpResp.StatusCode, requestID)
+	}
+
+	out := fromResponsesResponse(&resp)
+	out.RequestID = requestID
+
+	c.logger.Debug("Responses response received",
+		zap.String("request_id", requestID),
+		zap.String("model", out.Model),
+		zap.String("status", resp.Status),
+		zap.Int("total_tokens", out.Usage.TotalTokens))
+
+	return out, nil
+}
+
+func (c *Client) responsesCompletionStream(ctx context.Context, messages []Message, opts *Options, onChunk func(chunk StreamChunk) error) error {
+	reqBody := c.buildResponsesRequest(messages, true, opts)
+
+	c.logger.Debug("Sending streaming responses request",
+		zap.String("model", reqBody.Model),
+		zap.Int("message_count", len(messages)),
+		zap.Int("tool_count", len(reqBody.Tools)))
+
+	httpResp, err := c.doRequest(ctx, responsesEndpoint, reqBody)
+	if err != nil {
+		return err
+	}
+	defer httpResp.Body.Close()
+	requestID := httpResp.Header.Get(requestIDHeader)
+
+	reader := sse.NewReaderSize(httpResp.Body, c.streamBufferSize)
+	for {
+		event, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				c.logger.Debug("Stream cancelled", zap.Error(ctx.Err()))
+				return ctx.Err()
+			}
+			c.logger.Error("Error reading stream",
+				zap.String("request_id", requestID),
+				zap.Error(err))
+			return fmt.Errorf("error reading stream (request %s): %w", requestID, err)
+		}
+
+		var ev responsesStreamEvent
+		if err := json.Unmarshal([]byte(event.Data), &ev); err != nil {
+			c.logger.Error("Failed to unmarshal stream event",
+				zap.String("request_id", requestID),
+				zap.Error(err),
+				zap.String("raw", event.Data))
+			return fmt.Errorf("failed to unmarshal stream event (request %s): %w", requestID, err)
+		}
+
+		var chunks []StreamChunk
+		switch ev.Type {
+		case "response.output_text.delta":
+			chunks = []StreamChunk{{
+				Choices: []StreamDelta{{Delta: Delta{Content: ev.Delta}}},
+			}}
+
+		case "response.completed", "response.incomplete":
+			if ev.Response == nil {
+				continue
+			}
+			final := fromResponsesResponse(ev.Response)
+			chunks = []StreamChunk{
+				{ID: final.ID, Model: final.Model, Choices: []StreamDelta{{FinishReason: final.Choices[0].FinishReason}}},
+				{ID: final.ID, Model: final.Model, Usage: &final.Usage},
+			}
+
+		case "response.failed":
+			if ev.Response != nil && ev.Response.Error != nil {
+				return c.responsesError(ev.Response.Error, httpResp.StatusCode, requestID)
+			}
+			return c.responsesError(&ResponsesError{Message: "response failed"}, httpResp.StatusCode, requestID)
+
+		case "error":
+			return c.responsesError(&ResponsesError{Code: ev.Code, Message: ev.Message}, httpResp.StatusCode, requestID)
+
+		default:
+			// Lifecycle and tool-call progress events carry no text.
+			continue
+		}
+
+		for _, chunk := range chunks {
+			chunk.RequestID = requestID
+			if err := onChunk(chunk); err != nil {
+				c.logger.Debug("Streaming stopped by callback", zap.Error(err))
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// buildResponsesRequest maps chat options onto the Responses API. Parameters
+// with no Responses equivalent (Stop, N, Logprobs, penalties, Seed) are dropped.
+func (c *Client) buildResponsesRequest(messages []Message, stream bool, opts *Options) ResponsesRequest {
+	store := false
+	req := ResponsesRequest{
+		Model:  c.model,
+		Input:  messages,
+		Stream: stream,
+		Store:  &store,
+	}
+	if opts == nil {
+		return req
+	}
+	if opts.Model != "" {
+		req.Model = opts.Model
+	}
+
+	maxTokens := opts.MaxCompletionTokens
+	if maxTokens == 0 {
+		maxTokens = opts.MaxTokens
+	}
+	if maxTokens != 0 {
+		req.MaxOutputTokens = &maxTokens
+	}
+	req.Tools = opts.Tools
+	if rf := opts.ResponseFormat; rf != nil {
+		req.Text = &ResponsesText{Format: ResponsesTextFormat{Type: rf.Type}}
+		if rf.JSONSchema != nil {
+			req.Text.Format.Name = rf.JSONSchema.Name
+			req.Text.Format.Schema = rf.JSONSchema.Schema
+			req.Text.Format.Strict = rf.JSONSchema.Strict
+		}
+	}
+
+	if isReasoningModel(req.Model) {
+		if opts.ReasoningEffort != "" {
+			req.Reasoning = &ResponsesReasoning{Effort: opts.ReasoningEffort}
+		}
+	} else {
+		req.Temperature = opts.Temperature
+		req.TopP = opts.TopP
+	}
+
+	if len(opts.Stop) > 0 || opts.N > 1 || opts.Logprobs || opts.Seed != nil ||
+		opts.FrequencyPenalty != nil || opts.PresencePenalty != nil {
+		c.logger.Debug("Dropping options unsupported by the Responses API",
+			zap.String("model", req.Model))
+	}
+
+	return req
+}
+
+// fromResponsesResponse converts a Responses API result into the chat
+// completion shape, with the output text as a single assistant choice.
+func fromResponsesResponse(r *ResponsesResponse) *CompletionResponse {
+	finishReason := "stop"
+	if r.Status == "incomplete" && r.IncompleteDetails != nil && r.IncompleteDetails.Reason == "max_output_tokens" {
+		finishReason = "length"
+	}
+
+	out := &CompletionResponse{
+		ID:      r.ID,
+		Object:  r.Object,
+		Created: r.CreatedAt,
+		Model:   r.Model,
+		Choices: []Choice{{
+			Message:      Message{Role: "assistant", Content: r.OutputText()},
+			FinishReason: finishReason,
+		}},
+	}
+	if r.Usage != nil {
+		out.Usage = Usage{
+			PromptTokens:     r.Usage.InputTokens,
+			CompletionTokens: r.Usage.OutputTokens,
+			TotalTokens:      r.Usage.TotalTokens,
+		}
+	}
+	return out
+}
+
+// responsesError reports an error carried in a successful HTTP response body.
+func (c *Client) responsesError(e *ResponsesError, statusCode int, requestID string) error {
+	c.logger.Error("OpenAI responses error",
+		zap.String("request_id", requestID),
+		zap.String("code", e.Code),
+		zap.String("message", e.Message))
+	return &StatusError{
+		StatusCode: statusCode,
+		Code:       e.Code,
+		Message:    e.Message,
+		RequestID:  requestID,
+	}
+}
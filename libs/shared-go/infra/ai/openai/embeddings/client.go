@@ -13,11 +13,17 @@ import (
 	"go.uber.org/zap"
 )
 
-const embeddingsAPIURL = "https://api.openai.com/v1/embeddings"
+const (
+	embeddingsAPIURL = "https://api.openai.com/v1/embeddings"
+	defaultModel     = "text-embedding-3-small"
+	defaultBatchSize = 2048 // OpenAI limit on inputs per request
+)
 
 type Config struct {
-	APIKey string
-	Model  string
+	APIKey     string
+	Model      string
+	Dimensions int // Optional: shorten embeddings (text-embedding-3-* only)
+	BatchSize  int // Optional: max inputs per request (default: 2048)
 }
 
 func (c *Config) IsValid() bool {
@@ -27,14 +33,17 @@ func (c *Config) IsValid() bool {
 type Client struct {
 	apiKey     string
 	model      string
+	dimensions int
+	batchSize  int
 	httpClient *http.Client
 	logger     *zap.Logger
 	enabled    bool
 }
 
 type EmbeddingRequest struct {
-	Input []string `json:"input"`
-	Model string   `json:"model"`
+	Input      []string `json:"input"`
+	Model      string   `json:"model"`
+	Dimensions int      `json:"dimensions,omitempty"`
 }
 
 type EmbeddingResponse struct {
@@ -45,10 +54,21 @@ type EmbeddingResponse struct {
 		Index     int       `json:"index"`
 	} `json:"data"`
 	Model string `json:"model"`
-	Usage struct {
-		PromptTokens int `json:"prompt_tokens"`
-		TotalTokens  int `json:"total_tokens"`
-	} `json:"usage"`
+	Usage Usage  `json:"usage"`
+}
+
+// Usage contains token usage statistics for an embeddings call.
+type Usage struct {
+	PromptTokens int `json:"prompt_tokens"`
+	TotalTokens  int `json:"total_tokens"`
+}
+
+// EmbeddingsResult holds one embedding per input, in input order, along with
+// the usage summed over all batches.
+type EmbeddingsResult struct {
+	Model      string
+	Embeddings [][]float32
+	Usage      Usage
 }
 
 type APIError struct {
@@ -67,12 +87,19 @@ func NewClient(config *Config, logger *zap.Logger) (*Client, error) {
 
 	model := config.Model
 	if model == "" {
-		model = "text-embedding-3-small"
+		model = defaultModel
+	}
+
+	batchSize := config.BatchSize
+	if batchSize <= 0 || batchSize > defaultBatchSize {
+		batchSize = defaultBatchSize
 	}
 
 	client := &Client{
-		apiKey: config.APIKey,
-		model:  model,
+		apiKey:     config.APIKey,
+		model:      model,
+		dimensions: config.Dimensions,
+		batchSize:  batchSize,
 		httpClient: &http.Client{
 			Timeout: 60 * time.Second,
 		},
@@ -86,8 +113,10 @@ func NewClient(config *Config, logger *zap.Logger) (*Client, error) {
 	return client, nil
 }
 
-func (c *Client) CreateEmbeddings(ctx context.Context, input []string) ([]float32, error) {
-	if len(input) == 0 {
+// CreateEmbeddings embeds every input with model (the configured model if empty),
+// splitting the inputs into batches that respect the per-request limit.
+func (c *Client) CreateEmbeddings(ctx context.Context, model string, inputs []string) (*EmbeddingsResult, error) {
+	if len(inputs) == 0 {
 		c.logger.Error("Input cannot be empty")
 		return nil, errors.New("input cannot be empty")
 	}
@@ -97,12 +126,75 @@ func (c *Client) CreateEmbeddings(ctx context.Context, input []string) ([]float3
 		return nil, errors.New("embedding provider is not enabled")
 	}
 
-	c.logger.Debug("Creating embedding",
-		zap.Int("input_length", len(input)))
+	if model == "" {
+		model = c.model
+	}
+
+	c.logger.Debug("Creating embeddings",
+		zap.String("model", model),
+		zap.Int("input_count", len(inputs)))
+
+	result := &EmbeddingsResult{
+		Model:      model,
+		Embeddings: make([][]float32, len(inputs)),
+	}
+
+	for start := 0; start < len(inputs); start += c.batchSize {
+		end := min(start+c.batchSize, len(inputs))
+
+		resp, err := c.createBatch(ctx, model, inputs[start:end])
+		if err != nil {
+			return nil, err
+		}
+
+		for _, d := range resp.Data {
+			if d.Index < 0 || start+d.Index >= end {
+				return nil, fmt.Errorf("embedding index %d out of range for batch of %d", d.Index, end-start)
+			}
+			result.Embeddings[start+d.Index] = d.Embedding
+		}
+		result.Model = resp.Model
+		result.Usage.PromptTokens += resp.Usage.PromptTokens
+		result.Usage.TotalTokens += resp.Usage.TotalTokens
+	}
+
+	for i, e := range result.Embeddings {
+		if len(e) == 0 {
+			c.logger.Error("No embedding returned", zap.Int("index", i))
+			return nil, fmt.Errorf("no embedding returned from open ai api for input %d", i)
+		}
+	}
+
+	c.logger.Debug("Embeddings created",
+		zap.String("model", result.Model),
+		zap.Int("count", len(result.Embeddings)),
+		zap.Int("total_tokens", result.Usage.TotalTokens))
+
+	return result, nil
+}
 
+func (c *Client) CreateEmbedding(ctx context.Context, input string) ([]float32, error) {
+	result, err := c.CreateEmbeddings(ctx, "", []string{input})
+	if err != nil {
+		return nil, err
+	}
+	return result.Embeddings[0], nil
+}
+
+func (c *Client) IsEnabled() bool {
+	return c.enabled
+}
+
+// GetModel returns the configured default model name.
+func (c *Client) GetModel() string {
+	return c.model
+}
+
+func (c *Client) createBatch(ctx context.Context, model string, inputs []string) (*EmbeddingResponse, error) {
 	request := EmbeddingRequest{
-		Input: input,
-		Model: c.model,
+		Input:      inputs,
+		Model:      model,
+		Dimensions: c.dimensions,
 	}
 
 	jsonData, err := json.Marshal(request)
@@ -131,15 +223,6 @@ func (c *Client) CreateEmbeddings(ctx context.Context, input []string) ([]float3
 
 	defer response.Body.Close()
 
-	if response.StatusCode < 200 || response.StatusCode >= 300 {
-		apiError := APIError{}
-		if err := json.NewDecoder(response.Body).Decode(&apiError); err != nil {
-			c.logger.Error("Failed to decode API error response",
-				zap.Error(err))
-			return nil, fmt.Errorf("failed to decode API error response: %w", err)
-		}
-	}
-
 	body, err := io.ReadAll(response.Body)
 	if err != nil {
 		c.logger.Error("Failed to read response body",
@@ -147,6 +230,20 @@ func (c *Client) CreateEmbeddings(ctx context.Context, input []string) ([]float3
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		apiError := APIError{}
+		if err := json.Unmarshal(body, &apiError); err == nil && apiError.Error.Message != "" {
+			c.logger.Error("OpenAI API error",
+				zap.Int("status", response.StatusCode),
+				zap.String("message", apiError.Error.Message))
+			return nil, fmt.Errorf("OpenAI API error (status %d): %s", response.StatusCode, apiError.Error.Message)
+		}
+		c.logger.Error("OpenAI API error",
+			zap.Int("status", response.StatusCode),
+			zap.String("body", string(body)))
+		return nil, fmt.Errorf("OpenAI API error (status %d): %s", response.StatusCode, string(body))
+	}
+
 	var embeddingResponse EmbeddingResponse
 	if err := json.Unmarshal(body, &embeddingResponse); err != nil {
 		c.logger.Error("Failed to unmarshal embedding response",
@@ -159,20 +256,5 @@ func (c *Client) CreateEmbeddings(ctx context.Context, input []string) ([]float3
 		return nil, fmt.Errorf("no embedding data returned from open ai api")
 	}
 
-	embedding := embeddingResponse.Data[0].Embedding
-	if len(embedding) == 0 {
-		c.logger.Error("No embedding returned")
-		return nil, fmt.Errorf("no embedding returned from open ai api")
-	}
-
-	return embedding, nil
+	return &embeddingResponse, nil
 }
-
-func (c *Client) CreateEmbedding(ctx context.Context, input string) ([]float32, error) {
-	return c.CreateEmbeddings(ctx, []string{input})
-}
-
-func (c *Client) IsEnabled() bool {
-	return c.enabled
-}
-
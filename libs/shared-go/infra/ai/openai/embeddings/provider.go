@@ -18,11 +18,16 @@ func (p *EmbeddingProvider) CreateEmbedding(ctx context.Context, text string) ([
 	return p.client.CreateEmbedding(ctx, text)
 }
 
+// CreateEmbeddings returns the embedding of the first text, as required by embedding.Provider.
+// Use Client.CreateEmbeddings directly to get one embedding per input.
 func (p *EmbeddingProvider) CreateEmbeddings(ctx context.Context, texts []string) ([]float32, error) {
-	return p.client.CreateEmbeddings(ctx, texts)
+	result, err := p.client.CreateEmbeddings(ctx, "", texts)
+	if err != nil {
+		return nil, err
+	}
+	return result.Embeddings[0], nil
 }
 
 func (p *EmbeddingProvider) IsEnabled() bool {
 	return p.client != nil && p.client.IsEnabled()
 }
-
@@ -0,0 +1,287 @@
+package files
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultTimeout = 10 * time.Minute
+	filesAPIURL    = "https://api.openai.com/v1/files"
+)
+
+type Client struct {
+	apiKey     string
+	httpClient *http.Client
+	logger     *zap.Logger
+	enabled    bool
+}
+
+func NewClient(cfg *Config, logger *zap.Logger) (*Client, error) {
+	if cfg == nil {
+		return nil, errors.New("config is required")
+	}
+	if !cfg.IsValid() {
+		return nil, errors.New("invalid OpenAI configuration: API key is required")
+	}
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	client := &Client{
+		apiKey: cfg.APIKey,
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+		logger:  logger,
+		enabled: true,
+	}
+
+	logger.Info("OpenAI files client initialized")
+
+	return client, nil
+}
+
+// Upload streams the contents of r to OpenAI as a new file with the given purpose.
+// The body is written through a pipe, so large JSONL files are never held in memory.
+func (c *Client) Upload(ctx context.Context, r io.Reader, filename, purpose string) (*File, error) {
+	if !c.enabled {
+		return nil, errors.New("OpenAI files client is not enabled")
+	}
+	if r == nil {
+		return nil, errors.New("file reader is required")
+	}
+	if filename == "" {
+		return nil, errors.New("filename is required")
+	}
+	if purpose == "" {
+		return nil, errors.New("purpose is required")
+	}
+
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+
+	go func() {
+		pw.CloseWithError(writeUploadForm(mw, r, filename, purpose))
+	}()
+
+	httpReq, err := c.newRequest(ctx, http.MethodPost, filesAPIURL, pr)
+	if err != nil {
+		pr.CloseWithError(err)
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", mw.FormDataContentType())
+
+	c.logger.Debug("Uploading file",
+		zap.String("filename", filename),
+		zap.String("purpose", purpose))
+
+	var out File
+	if err := c.do(httpReq, &out); err != nil {
+		pr.CloseWithError(err)
+		return nil, err
+	}
+
+	c.logger.Info("File uploaded",
+		zap.String("file_id", out.ID),
+		zap.Int64("bytes", out.Bytes))
+
+	return &out, nil
+}
+
+// List returns uploaded files, optionally filtered by purpose.
+func (c *Client) List(ctx context.Context, opts *ListOptions) (*FileList, error) {
+	if !c.enabled {
+		return nil, errors.New("OpenAI files client is not enabled")
+	}
+
+	endpoint := filesAPIURL
+	if opts != nil {
+		q := url.Values{}
+		if opts.Purpose != "" {
+			q.Set("purpose", opts.Purpose)
+		}
+		if opts.Limit > 0 {
+			q.Set("limit", strconv.Itoa(opts.Limit))
+		}
+		if opts.After != "" {
+			q.Set("after", opts.After)
+		}
+		if opts.Order != "" {
+			q.Set("order", opts.Order)
+		}
+		if len(q) > 0 {
+			endpoint += "?" + q.Encode()
+		}
+	}
+
+	httpReq, err := c.newRequest(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var out FileList
+	if err := c.do(httpReq, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Retrieve returns the metadata of a single file.
+func (c *Client) Retrieve(ctx context.Context, fileID string) (*File, error) {
+	if !c.enabled {
+		return nil, errors.New("OpenAI files client is not enabled")
+	}
+	if fileID == "" {
+		return nil, errors.New("file ID is required")
+	}
+
+	httpReq, err := c.newRequest(ctx, http.MethodGet, filesAPIURL+"/"+url.PathEscape(fileID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var out File
+	if err := c.do(httpReq, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Content returns a reader over the raw contents of a file. The caller must close it.
+func (c *Client) Content(ctx context.Context, fileID string) (io.ReadCloser, error) {
+	if !c.enabled {
+		return nil, errors.New("OpenAI files client is not enabled")
+	}
+	if fileID == "" {
+		return nil, errors.New("file ID is required")
+	}
+
+	httpReq, err := c.newRequest(ctx, http.MethodGet, filesAPIURL+"/"+url.PathEscape(fileID)+"/content", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		c.logger.Error("Failed to send HTTP request", zap.Error(err))
+		return nil, fmt.Errorf("failed to send HTTP request: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		raw, _ := io.ReadAll(resp.Body)
+		return nil, c.apiError(resp.StatusCode, raw)
+	}
+
+	return resp.Body, nil
+}
+
+// Delete removes a file.
+func (c *Client) Delete(ctx context.Context, fileID string) error {
+	if !c.enabled {
+		return errors.New("OpenAI files client is not enabled")
+	}
+	if fileID == "" {
+		return errors.New("file ID is required")
+	}
+
+	httpReq, err := c.newRequest(ctx, http.MethodDelete, filesAPIURL+"/"+url.PathEscape(fileID), nil)
+	if err != nil {
+		return err
+	}
+
+	var out DeleteResponse
+	if err := c.do(httpReq, &out); err != nil {
+		return err
+	}
+	if !out.Deleted {
+		return fmt.Errorf("file %s was not deleted", fileID)
+	}
+
+	c.logger.Info("File deleted", zap.String("file_id", fileID))
+	return nil
+}
+
+// IsEnabled returns whether the client is enabled.
+func (c *Client) IsEnabled() bool {
+	return c.enabled
+}
+
+func (c *Client) newRequest(ctx context.Context, method, endpoint string, body io.Reader) (*http.Request, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		c.logger.Error("Failed to create HTTP request", zap.Error(err))
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+	return httpReq, nil
+}
+
+// do sends the request and decodes a successful JSON response into out.
+func (c *Client) do(httpReq *http.Request, out any) error {
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		c.logger.Error("Failed to send HTTP request", zap.Error(err))
+		return fmt.Errorf("failed to send HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		c.logger.Error("Failed to read response body", zap.Error(err))
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return c.apiError(resp.StatusCode, raw)
+	}
+
+	if err := json.Unmarshal(raw, out); err != nil {
+		c.logger.Error("Failed to unmarshal response", zap.Error(err))
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) apiError(status int, raw []byte) error {
+	var apiErr APIError
+	if jsonErr := json.Unmarshal(raw, &apiErr); jsonErr == nil && apiErr.Error.Message != "" {
+		c.logger.Error("OpenAI API error",
+			zap.Int("status", status),
+			zap.String("type", apiErr.Error.Type),
+			zap.String("message", apiErr.Error.Message))
+		return fmt.Errorf("OpenAI API error (status %d): %s", status, apiErr.Error.Message)
+	}
+	c.logger.Error("OpenAI API error",
+		zap.Int("status", status),
+		zap.String("body", string(raw)))
+	return fmt.Errorf("OpenAI API error (status %d): %s", status, string(raw))
+}
+
+// writeUploadForm writes the multipart body: the purpose field first, then the file.
+func writeUploadForm(mw *multipart.Writer, r io.Reader, filename, purpose string) error {
+	if err := mw.WriteField("purpose", purpose); err != nil {
+		return fmt.Errorf("failed to write form field %q: %w", "purpose", err)
+	}
+
+	part, err := mw.CreateFormFile("file", filename)
+	if err != nil {
+		return fmt.Errorf("failed to create form file: %w", err)
+	}
+	if _, err := io.Copy(part, r); err != nil {
+		return fmt.Errorf("failed to write file data: %w", err)
+	}
+	return mw.Close()
+}
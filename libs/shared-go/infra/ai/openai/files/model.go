@@ -0,0 +1,67 @@
+package files
+
+import "time"
+
+// File purposes accepted by the OpenAI Files API.
+const (
+	PurposeBatch      = "batch"
+	PurposeFineTune   = "fine-tune"
+	PurposeAssistants = "assistants"
+	PurposeVision     = "vision"
+	PurposeUserData   = "user_data"
+)
+
+// Config holds the configuration for the OpenAI files client.
+type Config struct {
+	APIKey  string        // Required: OpenAI API key
+	Timeout time.Duration // HTTP timeout (default: 10m, uploads can be large)
+}
+
+// IsValid returns true if the configuration has the minimum required fields.
+func (c *Config) IsValid() bool {
+	return c.APIKey != ""
+}
+
+// File is an uploaded file object.
+type File struct {
+	ID        string `json:"id"`
+	Object    string `json:"object"`
+	Bytes     int64  `json:"bytes"`
+	CreatedAt int64  `json:"created_at"`
+	Filename  string `json:"filename"`
+	Purpose   string `json:"purpose"`
+	Status    string `json:"status,omitempty"` // Deprecated by OpenAI but still returned
+}
+
+// FileList is the response from the list files endpoint.
+type FileList struct {
+	Object  string `json:"object"`
+	Data    []File `json:"data"`
+	HasMore bool   `json:"has_more"`
+	FirstID string `json:"first_id,omitempty"`
+	LastID  string `json:"last_id,omitempty"`
+}
+
+// ListOptions are optional parameters for List.
+type ListOptions struct {
+	Purpose string // Only return files with this purpose
+	Limit   int    // Page size, 1-10000 (default: 10000)
+	After   string // Cursor: return files after this file ID
+	Order   string // "asc" or "desc" by created_at (default: "desc")
+}
+
+// DeleteResponse is the response from the delete file endpoint.
+type DeleteResponse struct {
+	ID      string `json:"id"`
+	Object  string `json:"object"`
+	Deleted bool   `json:"deleted"`
+}
+
+// APIError represents an error response from the OpenAI API.
+type APIError struct {
+	Error struct {
+		Message string `json:"message"`
+		Type    string `json:"type"`
+		Code    string `json:"code"`
+	} `json:"error"`
+}
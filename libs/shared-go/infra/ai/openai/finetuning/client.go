@@ -0,0 +1,260 @@
+package finetuning
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/ai/openai/files"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultTimeout = 10 * time.Minute
+	jobsAPIURL     = "https://api.openai.com/v1/fine_tuning/jobs"
+)
+
+type Client struct {
+	apiKey     string
+	httpClient *http.Client
+	files      *files.Client
+	logger     *zap.Logger
+	enabled    bool
+}
+
+func NewClient(cfg *Config, logger *zap.Logger) (*Client, error) {
+	if cfg == nil {
+		return nil, errors.New("config is required")
+	}
+	if !cfg.IsValid() {
+		return nil, errors.New("invalid OpenAI configuration: API key is required")
+	}
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	filesClient, err := files.NewClient(&files.Config{APIKey: cfg.APIKey, Timeout: timeout}, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create files client: %w", err)
+	}
+
+	client := &Client{
+		apiKey: cfg.APIKey,
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+		files:   filesClient,
+		logger:  logger,
+		enabled: true,
+	}
+
+	logger.Info("OpenAI fine-tuning client initialized")
+
+	return client, nil
+}
+
+// UploadTrainingFile uploads a JSONL training or validation file and returns its file ID.
+func (c *Client) UploadTrainingFile(ctx context.Context, r io.Reader, filename string) (string, error) {
+	if !c.enabled {
+		return "", errors.New("OpenAI fine-tuning client is not enabled")
+	}
+
+	file, err := c.files.Upload(ctx, r, filename, files.PurposeFineTune)
+	if err != nil {
+		return "", fmt.Errorf("failed to upload training file: %w", err)
+	}
+	return file.ID, nil
+}
+
+// CreateJob starts a fine-tuning job.
+func (c *Client) CreateJob(ctx context.Context, req *CreateJobRequest) (*Job, error) {
+	if !c.enabled {
+		return nil, errors.New("OpenAI fine-tuning client is not enabled")
+	}
+	if req == nil || req.Model == "" || req.TrainingFile == "" {
+		return nil, errors.New("model and training file are required")
+	}
+
+	var out Job
+	if err := c.doJSON(ctx, http.MethodPost, jobsAPIURL, req, &out); err != nil {
+		return nil, err
+	}
+
+	c.logger.Info("Fine-tuning job created",
+		zap.String("job_id", out.ID),
+		zap.String("model", out.Model),
+		zap.String("training_file", out.TrainingFile))
+
+	return &out, nil
+}
+
+// RetrieveJob returns the current state of a job.
+func (c *Client) RetrieveJob(ctx context.Context, jobID string) (*Job, error) {
+	if !c.enabled {
+		return nil, errors.New("OpenAI fine-tuning client is not enabled")
+	}
+	if jobID == "" {
+		return nil, errors.New("job ID is required")
+	}
+
+	var out Job
+	if err := c.doJSON(ctx, http.MethodGet, jobsAPIURL+"/"+url.PathEscape(jobID), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListJobs returns the organization's fine-tuning jobs, newest first.
+func (c *Client) ListJobs(ctx context.Context, opts *ListOptions) (*JobList, error) {
+	if !c.enabled {
+		return nil, errors.New("OpenAI fine-tuning client is not enabled")
+	}
+
+	var out JobList
+	if err := c.doJSON(ctx, http.MethodGet, withListOptions(jobsAPIURL, opts), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListEvents returns status and metric events for a job, newest first.
+func (c *Client) ListEvents(ctx context.Context, jobID string, opts *ListOptions) (*EventList, error) {
+	if !c.enabled {
+		return nil, errors.New("OpenAI fine-tuning client is not enabled")
+	}
+	if jobID == "" {
+		return nil, errors.New("job ID is required")
+	}
+
+	endpoint := withListOptions(jobsAPIURL+"/"+url.PathEscape(jobID)+"/events", opts)
+
+	var out EventList
+	if err := c.doJSON(ctx, http.MethodGet, endpoint, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CancelJob stops a running or queued job.
+func (c *Client) CancelJob(ctx context.Context, jobID string) (*Job, error) {
+	if !c.enabled {
+		return nil, errors.New("OpenAI fine-tuning client is not enabled")
+	}
+	if jobID == "" {
+		return nil, errors.New("job ID is required")
+	}
+
+	var out Job
+	if err := c.doJSON(ctx, http.MethodPost, jobsAPIURL+"/"+url.PathEscape(jobID)+"/cancel", nil, &out); err != nil {
+		return nil, err
+	}
+
+	c.logger.Info("Fine-tuning job cancelled", zap.String("job_id", jobID))
+	return &out, nil
+}
+
+// ListCheckpoints returns the epoch checkpoints saved by a job.
+func (c *Client) ListCheckpoints(ctx context.Context, jobID string, opts *ListOptions) (*CheckpointList, error) {
+	if !c.enabled {
+		return nil, errors.New("OpenAI fine-tuning client is not enabled")
+	}
+	if jobID == "" {
+		return nil, errors.New("job ID is required")
+	}
+
+	endpoint := withListOptions(jobsAPIURL+"/"+url.PathEscape(jobID)+"/checkpoints", opts)
+
+	var out CheckpointList
+	if err := c.doJSON(ctx, http.MethodGet, endpoint, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// IsEnabled returns whether the client is enabled.
+func (c *Client) IsEnabled() bool {
+	return c.enabled
+}
+
+func withListOptions(endpoint string, opts *ListOptions) string {
+	if opts == nil {
+		return endpoint
+	}
+	q := url.Values{}
+	if opts.After != "" {
+		q.Set("after", opts.After)
+	}
+	if opts.Limit > 0 {
+		q.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if len(q) == 0 {
+		return endpoint
+	}
+	return endpoint + "?" + q.Encode()
+}
+
+// doJSON sends an optional JSON body and decodes a successful JSON response into out.
+func (c *Client) doJSON(ctx context.Context, method, endpoint string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		jsonData, err := json.Marshal(body)
+		if err != nil {
+			c.logger.Error("Failed to marshal request", zap.Error(err))
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(jsonData)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		c.logger.Error("Failed to create HTTP request", zap.Error(err))
+		return fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		c.logger.Error("Failed to send HTTP request", zap.Error(err))
+		return fmt.Errorf("failed to send HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		c.logger.Error("Failed to read response body", zap.Error(err))
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var apiErr APIError
+		if jsonErr := json.Unmarshal(raw, &apiErr); jsonErr == nil && apiErr.Error.Message != "" {
+			c.logger.Error("OpenAI API error",
+				zap.Int("status", resp.StatusCode),
+				zap.String("type", apiErr.Error.Type),
+				zap.String("message", apiErr.Error.Message))
+			return fmt.Errorf("OpenAI API error (status %d): %s", resp.StatusCode, apiErr.Error.Message)
+		}
+		c.logger.Error("OpenAI API error",
+			zap.Int("status", resp.StatusCode),
+			zap.String("body", string(raw)))
+		return fmt.Errorf("OpenAI API error (status %d): %s", resp.StatusCode, string(raw))
+	}
+
+	if err := json.Unmarshal(raw, out); err != nil {
+		c.logger.Error("Failed to unmarshal response", zap.Error(err))
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package finetuning
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Job statuses.
+const (
+	StatusValidatingFiles = "validating_files"
+	StatusQueued          = "queued"
+	StatusRunning         = "running"
+	StatusSucceeded       = "succeeded"
+	StatusFailed          = "failed"
+	StatusCancelled       = "cancelled"
+)
+
+// Config holds the configuration for the OpenAI fine-tuning client.
+type Config struct {
+	APIKey  string        // Required: OpenAI API key
+	Timeout time.Duration // HTTP timeout (default: 10m, covers training file upload)
+}
+
+// IsValid returns true if the configuration has the minimum required fields.
+func (c *Config) IsValid() bool {
+	return c.APIKey != ""
+}
+
+// CreateJobRequest is the payload sent to the create fine-tuning job endpoint.
+type CreateJobRequest struct {
+	Model           string            `json:"model"`         // Base model, e.g. "gpt-4o-mini-2024-07-18"
+	TrainingFile    string            `json:"training_file"` // File ID uploaded with purpose "fine-tune"
+	ValidationFile  string            `json:"validation_file,omitempty"`
+	Suffix          string            `json:"suffix,omitempty"` // Up to 64 characters added to the model name
+	Seed            *int64            `json:"seed,omitempty"`
+	Hyperparameters *Hyperparameters  `json:"hyperparameters,omitempty"`
+	Metadata        map[string]string `json:"metadata,omitempty"`
+}
+
+// Hyperparameters tune the training run. Nil fields let OpenAI choose ("auto").
+type Hyperparameters struct {
+	NEpochs                *int     `json:"n_epochs,omitempty"`
+	BatchSize              *int     `json:"batch_size,omitempty"`
+	LearningRateMultiplier *float64 `json:"learning_rate_multiplier,omitempty"`
+}
+
+// Job is a fine-tuning job as returned by the API.
+type Job struct {
+	ID              string            `json:"id"`
+	Object          string            `json:"object"`
+	Model           string            `json:"model"`
+	FineTunedModel  string            `json:"fine_tuned_model,omitempty"` // Set once the job succeeds
+	Status          string            `json:"status"`
+	TrainingFile    string            `json:"training_file"`
+	ValidationFile  string            `json:"validation_file,omitempty"`
+	ResultFiles     []string          `json:"result_files,omitempty"`
+	TrainedTokens   int               `json:"trained_tokens,omitempty"`
+	Seed            int64             `json:"seed,omitempty"`
+	Hyperparameters json.RawMessage   `json:"hyperparameters,omitempty"` // values may be numbers or "auto"
+	Error           *JobError         `json:"error,omitempty"`
+	CreatedAt       int64             `json:"created_at"`
+	FinishedAt      int64             `json:"finished_at,omitempty"`
+	EstimatedFinish int64             `json:"estimated_finish,omitempty"`
+	Metadata        map[string]string `json:"metadata,omitempty"`
+}
+
+// IsTerminal reports whether the job has stopped changing state.
+func (j *Job) IsTerminal() bool {
+	switch j.Status {
+	case StatusSucceeded, StatusFailed, StatusCancelled:
+		return true
+	}
+	return false
+}
+
+// JobError describes why a job failed.
+type JobError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Param   string `json:"param,omitempty"`
+}
+
+// JobList is the response from the list jobs endpoint.
+type JobList struct {
+	Object  string `json:"object"`
+	Data    []Job  `json:"data"`
+	HasMore bool   `json:"has_more"`
+}
+
+// Event is a progress or status message emitted by a job.
+type Event struct {
+	ID        string          `json:"id"`
+	Object    string          `json:"object"`
+	CreatedAt int64           `json:"created_at"`
+	Level     string          `json:"level"` // "info", "warn" or "error"
+	Message   string          `json:"message"`
+	Type      string          `json:"type,omitempty"` // "message" or "metrics"
+	Data      json.RawMessage `json:"data,omitempty"`
+}
+
+// EventList is the response from the list events endpoint.
+type EventList struct {
+	Object  string  `json:"object"`
+	Data    []Event `json:"data"`
+	HasMore bool    `json:"has_more"`
+}
+
+// Checkpoint is an intermediate model saved at the end of a training epoch.
+type Checkpoint struct {
+	ID                       string             `json:"id"`
+	Object                   string             `json:"object"`
+	CreatedAt                int64              `json:"created_at"`
+	FineTunedModelCheckpoint string             `json:"fine_tuned_model_checkpoint"` // Usable as a model name
+	FineTuningJobID          string             `json:"fine_tuning_job_id"`
+	StepNumber               int                `json:"step_number"`
+	Metrics                  map[string]float64 `json:"metrics,omitempty"`
+}
+
+// CheckpointList is the response from the list checkpoints endpoint.
+type CheckpointList struct {
+	Object  string       `json:"object"`
+	Data    []Checkpoint `json:"data"`
+	HasMore bool         `json:"has_more"`
+	FirstID string       `json:"first_id,omitempty"`
+	LastID  string       `json:"last_id,omitempty"`
+}
+
+// ListOptions are optional cursor pagination parameters.
+type ListOptions struct {
+	After string // Return items after this ID
+	Limit int    // Page size (API default: 20)
+}
+
+// APIError represents an error response from the OpenAI API.
+type APIError struct {
+	Error struct {
+		Message string `json:"message"`
+		Type    string `json:"type"`
+		Code    string `json:"code"`
+	} `json:"error"`
+}
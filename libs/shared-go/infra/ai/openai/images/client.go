@@ -0,0 +1,180 @@
+package images
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultModel      = "dall-e-3"
+	defaultTimeout    = 2 * time.Minute
+	generationsAPIURL = "https://api.openai.com/v1/images/generations"
+)
+
+type Client struct {
+	apiKey     string
+	model      string
+	httpClient *http.Client
+	logger     *zap.Logger
+	enabled    bool
+}
+
+func NewClient(cfg *Config, logger *zap.Logger) (*Client, error) {
+	if cfg == nil {
+		return nil, errors.New("config is required")
+	}
+	if !cfg.IsValid() {
+		return nil, errors.New("invalid OpenAI configuration: API key is required")
+	}
+
+	model := cfg.Model
+	if model == "" {
+		model = defaultModel
+	}
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	client := &Client{
+		apiKey: cfg.APIKey,
+		model:  model,
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+		logger:  logger,
+		enabled: true,
+	}
+
+	logger.Info("OpenAI images client initialized",
+		zap.String("model", model))
+
+	return client, nil
+}
+
+// Generate creates images from a text prompt.
+func (c *Client) Generate(ctx context.Context, prompt string, opts *GenerateOptions) (*ImageResponse, error) {
+	if !c.enabled {
+		return nil, errors.New("OpenAI images client is not enabled")
+	}
+	if prompt == "" {
+		return nil, errors.New("prompt is required")
+	}
+
+	req := c.buildRequest(prompt, opts)
+
+	jsonData, err := json.Marshal(req)
+	if err != nil {
+		c.logger.Error("Failed to marshal request", zap.Error(err))
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, generationsAPIURL, bytes.NewReader(jsonData))
+	if err != nil {
+		c.logger.Error("Failed to create HTTP request", zap.Error(err))
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+
+	c.logger.Debug("Sending image generation request",
+		zap.String("model", req.Model),
+		zap.String("size", req.Size),
+		zap.Int("n", req.N))
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		c.logger.Error("Failed to send HTTP request", zap.Error(err))
+		return nil, fmt.Errorf("failed to send HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		c.logger.Error("Failed to read response body", zap.Error(err))
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var apiErr APIError
+		if jsonErr := json.Unmarshal(raw, &apiErr); jsonErr == nil && apiErr.Error.Message != "" {
+			c.logger.Error("OpenAI API error",
+				zap.Int("status", resp.StatusCode),
+				zap.String("type", apiErr.Error.Type),
+				zap.String("message", apiErr.Error.Message))
+			return nil, fmt.Errorf("OpenAI API error (status %d): %s", resp.StatusCode, apiErr.Error.Message)
+		}
+		c.logger.Error("OpenAI API error",
+			zap.Int("status", resp.StatusCode),
+			zap.String("body", string(raw)))
+		return nil, fmt.Errorf("OpenAI API error (status %d): %s", resp.StatusCode, string(raw))
+	}
+
+	var out ImageResponse
+	if err := json.Unmarshal(raw, &out); err != nil {
+		c.logger.Error("Failed to unmarshal image response", zap.Error(err))
+		return nil, fmt.Errorf("failed to unmarshal image response: %w", err)
+	}
+	if len(out.Data) == 0 {
+		return nil, errors.New("no images in response")
+	}
+
+	c.logger.Debug("Image generation response received",
+		zap.Int("image_count", len(out.Data)))
+
+	return &out, nil
+}
+
+// IsEnabled returns whether the client is enabled.
+func (c *Client) IsEnabled() bool {
+	return c.enabled
+}
+
+// GetModel returns the default model used by this client.
+func (c *Client) GetModel() string {
+	return c.model
+}
+
+// buildRequest maps options onto the request, dropping the fields the chosen
+// model family rejects (gpt-image has no response_format, DALL-E no output_format).
+func (c *Client) buildRequest(prompt string, opts *GenerateOptions) GenerateRequest {
+	req := GenerateRequest{
+		Model:  c.model,
+		Prompt: prompt,
+	}
+	if opts == nil {
+		return req
+	}
+
+	if opts.Model != "" {
+		req.Model = opts.Model
+	}
+	req.N = opts.N
+	req.Size = opts.Size
+	req.Quality = opts.Quality
+	req.User = opts.User
+
+	if isGPTImageModel(req.Model) {
+		req.OutputFormat = opts.OutputFormat
+		req.Background = opts.Background
+	} else {
+		req.ResponseFormat = opts.ResponseFormat
+		req.Style = opts.Style
+	}
+
+	return req
+}
+
+func isGPTImageModel(model string) bool {
+	return strings.HasPrefix(model, "gpt-image")
+}
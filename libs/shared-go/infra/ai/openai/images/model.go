@@ -0,0 +1,103 @@
+package images
+
+import (
+	"encoding/base64"
+	"errors"
+	"time"
+)
+
+// Image sizes. DALL-E 3 supports the square, landscape and portrait 1024/1792 sizes;
+// gpt-image-1 supports 1024x1024, 1536x1024, 1024x1536 and "auto".
+const (
+	Size256       = "256x256"
+	Size512       = "512x512"
+	Size1024      = "1024x1024"
+	Size1792x1024 = "1792x1024"
+	Size1024x1792 = "1024x1792"
+	Size1536x1024 = "1536x1024"
+	Size1024x1536 = "1024x1536"
+	SizeAuto      = "auto"
+)
+
+// Response formats for DALL-E models. gpt-image models always return base64.
+const (
+	ResponseFormatURL    = "url"
+	ResponseFormatBase64 = "b64_json"
+)
+
+// Config holds the configuration for the OpenAI images client.
+type Config struct {
+	APIKey  string        // Required: OpenAI API key
+	Model   string        // e.g. "dall-e-3", "gpt-image-1"
+	Timeout time.Duration // HTTP timeout (default: 2m, generation is slow)
+}
+
+// IsValid returns true if the configuration has the minimum required fields.
+func (c *Config) IsValid() bool {
+	return c.APIKey != ""
+}
+
+// GenerateOptions are optional parameters for Generate.
+type GenerateOptions struct {
+	Model          string // Overrides Config.Model
+	N              int    // Number of images (dall-e-3 only supports 1)
+	Size           string // One of the Size constants
+	Quality        string // "standard"/"hd" for dall-e-3, "low"/"medium"/"high"/"auto" for gpt-image-1
+	Style          string // "vivid" or "natural" (dall-e-3 only)
+	ResponseFormat string // ResponseFormatURL or ResponseFormatBase64 (DALL-E only)
+	OutputFormat   string // "png", "jpeg" or "webp" (gpt-image only)
+	Background     string // "transparent", "opaque" or "auto" (gpt-image only)
+	User           string // End-user identifier for abuse monitoring
+}
+
+// GenerateRequest is the payload sent to the OpenAI image generation API.
+type GenerateRequest struct {
+	Model          string `json:"model,omitempty"`
+	Prompt         string `json:"prompt"`
+	N              int    `json:"n,omitempty"`
+	Size           string `json:"size,omitempty"`
+	Quality        string `json:"quality,omitempty"`
+	Style          string `json:"style,omitempty"`
+	ResponseFormat string `json:"response_format,omitempty"`
+	OutputFormat   string `json:"output_format,omitempty"`
+	Background     string `json:"background,omitempty"`
+	User           string `json:"user,omitempty"`
+}
+
+// ImageResponse is the response from the OpenAI image generation API.
+type ImageResponse struct {
+	Created int64   `json:"created"`
+	Data    []Image `json:"data"`
+	Usage   *Usage  `json:"usage,omitempty"` // gpt-image only
+}
+
+// Image is a single generated image. Exactly one of URL or B64JSON is set.
+type Image struct {
+	URL           string `json:"url,omitempty"`      // Expires after about an hour
+	B64JSON       string `json:"b64_json,omitempty"` // Base64-encoded image bytes
+	RevisedPrompt string `json:"revised_prompt,omitempty"`
+}
+
+// Bytes decodes the base64 image data. It returns an error for URL results.
+func (i Image) Bytes() ([]byte, error) {
+	if i.B64JSON == "" {
+		return nil, errors.New("image has no base64 data; request ResponseFormatBase64 or download the URL")
+	}
+	return base64.StdEncoding.DecodeString(i.B64JSON)
+}
+
+// Usage reports token usage for gpt-image models.
+type Usage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+	TotalTokens  int `json:"total_tokens"`
+}
+
+// APIError represents an error response from the OpenAI API.
+type APIError struct {
+	Error struct {
+		Message string `json:"message"`
+		Type    string `json:"type"`
+		Code    string `json:"code"`
+	} `json:"error"`
+}
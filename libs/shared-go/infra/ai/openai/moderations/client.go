@@ -0,0 +1,147 @@
+package moderations
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultModel      = "omni-moderation-latest"
+	defaultTimeout    = 30 * time.Second
+	moderationsAPIURL = "https://api.openai.com/v1/moderations"
+)
+
+type Client struct {
+	apiKey     string
+	model      string
+	httpClient *http.Client
+	logger     *zap.Logger
+	enabled    bool
+}
+
+func NewClient(cfg *Config, logger *zap.Logger) (*Client, error) {
+	if cfg == nil {
+		return nil, errors.New("config is required")
+	}
+	if !cfg.IsValid() {
+		return nil, errors.New("invalid OpenAI configuration: API key is required")
+	}
+
+	model := cfg.Model
+	if model == "" {
+		model = defaultModel
+	}
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	client := &Client{
+		apiKey: cfg.APIKey,
+		model:  model,
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+		logger:  logger,
+		enabled: true,
+	}
+
+	logger.Info("OpenAI moderation client initialized",
+		zap.String("model", model))
+
+	return client, nil
+}
+
+// Moderate classifies each input against OpenAI's usage policies.
+// Use it to screen user prompts before sending them to a model, and model
+// output before returning it to the user.
+func (c *Client) Moderate(ctx context.Context, inputs ...string) (*ModerationResponse, error) {
+	if !c.enabled {
+		return nil, errors.New("OpenAI moderation client is not enabled")
+	}
+	if len(inputs) == 0 {
+		return nil, errors.New("at least one input is required")
+	}
+
+	jsonData, err := json.Marshal(ModerationRequest{Model: c.model, Input: inputs})
+	if err != nil {
+		c.logger.Error("Failed to marshal request", zap.Error(err))
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, moderationsAPIURL, bytes.NewReader(jsonData))
+	if err != nil {
+		c.logger.Error("Failed to create HTTP request", zap.Error(err))
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		c.logger.Error("Failed to send HTTP request", zap.Error(err))
+		return nil, fmt.Errorf("failed to send HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		c.logger.Error("Failed to read response body", zap.Error(err))
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var apiErr APIError
+		if jsonErr := json.Unmarshal(raw, &apiErr); jsonErr == nil && apiErr.Error.Message != "" {
+			c.logger.Error("OpenAI API error",
+				zap.Int("status", resp.StatusCode),
+				zap.String("type", apiErr.Error.Type),
+				zap.String("message", apiErr.Error.Message))
+			return nil, fmt.Errorf("OpenAI API error (status %d): %s", resp.StatusCode, apiErr.Error.Message)
+		}
+		c.logger.Error("OpenAI API error",
+			zap.Int("status", resp.StatusCode),
+			zap.String("body", string(raw)))
+		return nil, fmt.Errorf("OpenAI API error (status %d): %s", resp.StatusCode, string(raw))
+	}
+
+	var out ModerationResponse
+	if err := json.Unmarshal(raw, &out); err != nil {
+		c.logger.Error("Failed to unmarshal moderation response", zap.Error(err))
+		return nil, fmt.Errorf("failed to unmarshal moderation response: %w", err)
+	}
+	if len(out.Results) != len(inputs) {
+		return nil, fmt.Errorf("moderation returned %d results for %d inputs", len(out.Results), len(inputs))
+	}
+
+	c.logger.Debug("Moderation response received",
+		zap.String("model", out.Model),
+		zap.Int("input_count", len(inputs)),
+		zap.Bool("flagged", out.Flagged()))
+
+	return &out, nil
+}
+
+// Check moderates a single input and returns whether it was flagged and which categories triggered.
+func (c *Client) Check(ctx context.Context, input string) (bool, []string, error) {
+	resp, err := c.Moderate(ctx, input)
+	if err != nil {
+		return false, nil, err
+	}
+	result := resp.Results[0]
+	return result.Flagged, result.FlaggedCategories(), nil
+}
+
+// IsEnabled returns whether the client is enabled.
+func (c *Client) IsEnabled() bool {
+	return c.enabled
+}
@@ -0,0 +1,70 @@
+package moderations
+
+import (
+	"sort"
+	"time"
+)
+
+// Config holds the configuration for the OpenAI moderation client.
+type Config struct {
+	APIKey  string        // Required: OpenAI API key
+	Model   string        // e.g. "omni-moderation-latest", "text-moderation-latest"
+	Timeout time.Duration // HTTP timeout (default: 30s)
+}
+
+// IsValid returns true if the configuration has the minimum required fields.
+func (c *Config) IsValid() bool {
+	return c.APIKey != ""
+}
+
+// ModerationRequest is the payload sent to the OpenAI moderation API.
+type ModerationRequest struct {
+	Model string   `json:"model,omitempty"`
+	Input []string `json:"input"`
+}
+
+// ModerationResponse is the response from the OpenAI moderation API.
+// Results are in the same order as the inputs.
+type ModerationResponse struct {
+	ID      string   `json:"id"`
+	Model   string   `json:"model"`
+	Results []Result `json:"results"`
+}
+
+// Result is the moderation verdict for a single input.
+type Result struct {
+	Flagged        bool               `json:"flagged"`
+	Categories     map[string]bool    `json:"categories"`      // e.g. "hate", "self-harm/intent"
+	CategoryScores map[string]float64 `json:"category_scores"` // 0-1 confidence per category
+}
+
+// FlaggedCategories returns the names of the categories that were flagged, sorted.
+func (r Result) FlaggedCategories() []string {
+	var out []string
+	for name, flagged := range r.Categories {
+		if flagged {
+			out = append(out, name)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Flagged reports whether any result in the response was flagged.
+func (r *ModerationResponse) Flagged() bool {
+	for _, res := range r.Results {
+		if res.Flagged {
+			return true
+		}
+	}
+	return false
+}
+
+// APIError represents an error response from the OpenAI API.
+type APIError struct {
+	Error struct {
+		Message string `json:"message"`
+		Type    string `json:"type"`
+		Code    string `json:"code"`
+	} `json:"error"`
+}
@@ -0,0 +1,69 @@
+package ai
+
+// QualityTier ranks models by capability for cost-aware routing.
+type QualityTier int
+
+const (
+	TierBasic    QualityTier = 1 // small/local models: classification, extraction, short answers
+	TierStandard QualityTier = 2 // general chat and summarization
+	TierPremium  QualityTier = 3 // complex reasoning and long-form generation
+)
+
+// ModelPrice is the list price of a model in USD per 1K tokens.
+type ModelPrice struct {
+	InputPer1K  float64
+	OutputPer1K float64
+	Tier        QualityTier
+}
+
+// modelPrices is the built-in pricing table. Like modelContextWindows, lookups
+// use the longest matching prefix. Local models are free to run.
+var modelPrices = map[string]ModelPrice{
+	// OpenAI
+	"gpt-3.5-turbo": {InputPer1K: 0.0005, OutputPer1K: 0.0015, Tier: TierBasic},
+	"gpt-4":         {InputPer1K: 0.03, OutputPer1K: 0.06, Tier: TierPremium},
+	"gpt-4-turbo":   {InputPer1K: 0.01, OutputPer1K: 0.03, Tier: TierPremium},
+	"gpt-4o":        {InputPer1K: 0.0025, OutputPer1K: 0.01, Tier: TierPremium},
+	"gpt-4o-mini":   {InputPer1K: 0.00015, OutputPer1K: 0.0006, Tier: TierStandard},
+	"gpt-4.1":       {InputPer1K: 0.002, OutputPer1K: 0.008, Tier: TierPremium},
+	"gpt-4.1-mini":  {InputPer1K: 0.0004, OutputPer1K: 0.0016, Tier: TierStandard},
+	"gpt-4.1-nano":  {InputPer1K: 0.0001, OutputPer1K: 0.0004, Tier: TierBasic},
+	"gpt-5":         {InputPer1K: 0.00125, OutputPer1K: 0.01, Tier: TierPremium},
+	"gpt-5-mini":    {InputPer1K: 0.00025, OutputPer1K: 0.002, Tier: TierStandard},
+	"gpt-5-nano":    {InputPer1K: 0.00005, OutputPer1K: 0.0004, Tier: TierBasic},
+	"o1":            {InputPer1K: 0.015, OutputPer1K: 0.06, Tier: TierPremium},
+	"o3":            {InputPer1K: 0.002, OutputPer1K: 0.008, Tier: TierPremium},
+	"o3-mini":       {InputPer1K: 0.0011, OutputPer1K: 0.0044, Tier: TierPremium},
+	"o4-mini":       {InputPer1K: 0.0011, OutputPer1K: 0.0044, Tier: TierPremium},
+
+	// Ollama
+	"llama3":   {Tier: TierBasic},
+	"llama3.1": {Tier: TierBasic},
+	"llama3.2": {Tier: TierBasic},
+	"llama3.3": {Tier: TierStandard},
+	"mistral":  {Tier: TierBasic},
+	"mixtral":  {Tier: TierStandard},
+	"gemma2":   {Tier: TierBasic},
+	"gemma3":   {Tier: TierBasic},
+	"qwen2.5":  {Tier: TierBasic},
+	"phi3":     {Tier: TierBasic},
+}
+
+// PriceFor returns the list price of model, and false if it is not in the table.
+func PriceFor(model string) (ModelPrice, bool) {
+	return lookupModel(modelPrices, model)
+}
+
+// EstimateCost returns the estimated USD cost of a request with the given
+// usage, or 0 if the model is not in the pricing table.
+func EstimateCost(model string, usage ChatUsage) float64 {
+	price, ok := PriceFor(model)
+	if !ok {
+		return 0
+	}
+	return price.cost(usage.PromptTokens, usage.CompletionTokens)
+}
+
+func (p ModelPrice) cost(promptTokens, completionTokens int) float64 {
+	return float64(promptTokens)/1000*p.InputPer1K + float64(completionTokens)/1000*p.OutputPer1K
+}
@@ -0,0 +1,238 @@
+package ai
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ProviderSpec is the serialisable form of a named ChatProviderConfig, for
+// loading several providers from JSON. String values may reference
+// environment variables as $VAR or ${VAR}, so secrets stay out of the file.
+type ProviderSpec struct {
+	Name     string       `json:"name"`
+	Provider ProviderType `json:"provider"`
+	Default  bool         `json:"default,omitempty"`
+
+	Model   string `json:"model,omitempty"`
+	Timeout string `json:"timeout,omitempty"` // Go duration, e.g. "90s"
+
+	Headers      map[string]string `json:"headers,omitempty"`
+	QueryParams  map[string]string `json:"query_params,omitempty"`
+	ModelAliases map[string]string `json:"model_aliases,omitempty"`
+	Balancing    BalanceStrategy   `json:"balancing,omitempty"`
+
+	// OpenAI
+	APIKey          string   `json:"api_key,omitempty"`
+	APIKeys         []string `json:"api_keys,omitempty"`
+	BaseURL         string   `json:"base_url,omitempty"`
+	UseResponsesAPI bool     `json:"use_responses_api,omitempty"`
+
+	// Local (Ollama)
+	Host        string   `json:"host,omitempty"`
+	Hosts       []string `json:"hosts,omitempty"`
+	KeepAlive   string   `json:"keep_alive,omitempty"` // Go duration; negative keeps the model loaded
+	Warmup      bool     `json:"warmup,omitempty"`
+	HealthProbe bool     `json:"health_probe,omitempty"`
+}
+
+// Config converts the spec into a ChatProviderConfig, expanding environment variables.
+func (s *ProviderSpec) Config() (*ChatProviderConfig, error) {
+	cfg := &ChatProviderConfig{
+		Provider:     s.Provider,
+		Headers:      expandMap(s.Headers),
+		QueryParams:  expandMap(s.QueryParams),
+		ModelAliases: ModelAliases(s.ModelAliases),
+		Balancing:    s.Balancing,
+	}
+
+	if s.Timeout != "" {
+		d, err := time.ParseDuration(s.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("provider %s: invalid timeout: %w", s.Name, err)
+		}
+		cfg.Timeout = d
+	}
+
+	switch s.Provider {
+	case ProviderOpenAI:
+		// Keys referencing variables are expanded again per request, so
+		// keys refreshed from a secrets manager are picked up.
+		cfg.OpenAIAPIKey = os.ExpandEnv(s.APIKey)
+		cfg.OpenAIAPIKeyFunc = expandFunc(s.APIKey)
+		cfg.OpenAIAPIKeys = expandSlice(s.APIKeys)
+		for i, key := range s.APIKeys {
+			if f := expandFunc(key); f != nil {
+				if cfg.OpenAIAPIKeyFuncs == nil {
+					cfg.OpenAIAPIKeyFuncs = make([]func() string, len(s.APIKeys))
+				}
+				cfg.OpenAIAPIKeyFuncs[i] = f
+			}
+		}
+		cfg.OpenAIModel = s.Model
+		cfg.OpenAIBaseURL = os.ExpandEnv(s.BaseURL)
+		cfg.OpenAIUseResponsesAPI = s.UseResponsesAPI
+	case ProviderLocal:
+		cfg.LocalHost = os.ExpandEnv(s.Host)
+		cfg.LocalHosts = expandSlice(s.Hosts)
+		cfg.LocalModel = s.Model
+		cfg.LocalWarmup = s.Warmup
+		cfg.LocalHealthProbe = s.HealthProbe
+		if s.KeepAlive != "" {
+			d, err := time.ParseDuration(s.KeepAlive)
+			if err != nil {
+				return nil, fmt.Errorf("provider %s: invalid keep_alive: %w", s.Name, err)
+			}
+			cfg.LocalKeepAlive = d
+		}
+	default:
+		return nil, fmt.Errorf("provider %s: unsupported chat provider: %q", s.Name, s.Provider)
+	}
+
+	return cfg, nil
+}
+
+// ParseProviderSpecs decodes a JSON array of provider specs.
+func ParseProviderSpecs(data []byte) ([]ProviderSpec, error) {
+	var specs []ProviderSpec
+	if err := json.Unmarshal(data, &specs); err != nil {
+		return nil, fmt.Errorf("failed to parse provider specs: %w", err)
+	}
+	return specs, nil
+}
+
+// LoadProviderSpecs reads a JSON array of provider specs from path.
+func LoadProviderSpecs(path string) ([]ProviderSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read provider specs: %w", err)
+	}
+	return ParseProviderSpecs(data)
+}
+
+// ChatProviderRegistry holds named chat providers, e.g. one per assistant.
+type ChatProviderRegistry struct {
+	providers map[string]ChatProvider
+	def       string
+}
+
+// NewChatProviders builds every spec into a registry keyed by name. The
+// default provider is the spec marked Default, or the first one.
+func NewChatProviders(specs []ProviderSpec, logger *zap.Logger) (*ChatProviderRegistry, error) {
+	if len(specs) == 0 {
+		return nil, errors.New("at least one provider spec is required")
+	}
+
+	reg := &ChatProviderRegistry{providers: make(map[string]ChatProvider, len(specs))}
+	for i := range specs {
+		spec := &specs[i]
+		if spec.Name == "" {
+			return nil, fmt.Errorf("provider spec %d has no name", i)
+		}
+		if _, dup := reg.providers[spec.Name]; dup {
+			return nil, fmt.Errorf("duplicate provider name %q", spec.Name)
+		}
+
+		cfg, err := spec.Config()
+		if err != nil {
+			return nil, err
+		}
+		provider, err := NewChatProvider(cfg, logger.With(zap.String("provider_name", spec.Name)))
+		if err != nil {
+			return nil, fmt.Errorf("provider %s: %w", spec.Name, err)
+		}
+		reg.providers[spec.Name] = provider
+
+		if spec.Default {
+			if reg.def != "" {
+				return nil, fmt.Errorf("providers %q and %q are both marked default", reg.def, spec.Name)
+			}
+			reg.def = spec.Name
+		}
+	}
+	if reg.def == "" {
+		reg.def = specs[0].Name
+	}
+
+	logger.Info("Chat providers initialized",
+		zap.Strings("names", reg.Names()),
+		zap.String("default", reg.def))
+
+	return reg, nil
+}
+
+// NewChatProviderRegistry wraps already constructed providers.
+func NewChatProviderRegistry(providers map[string]ChatProvider, defaultName string) (*ChatProviderRegistry, error) {
+	if _, ok := providers[defaultName]; !ok {
+		return nil, fmt.Errorf("default provider %q is not registered", defaultName)
+	}
+	return &ChatProviderRegistry{providers: providers, def: defaultName}, nil
+}
+
+// Get returns the provider registered under name.
+func (r *ChatProviderRegistry) Get(name string) (ChatProvider, bool) {
+	p, ok := r.providers[name]
+	return p, ok
+}
+
+// Default returns the default provider.
+func (r *ChatProviderRegistry) Default() ChatProvider {
+	return r.providers[r.def]
+}
+
+// DefaultName returns the name of the default provider.
+func (r *ChatProviderRegistry) DefaultName() string {
+	return r.def
+}
+
+// Names returns the registered provider names, sorted.
+func (r *ChatProviderRegistry) Names() []string {
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// All returns the providers keyed by name. The map must not be modified.
+func (r *ChatProviderRegistry) All() map[string]ChatProvider {
+	return r.providers
+}
+
+func expandMap(m map[string]string) map[string]string {
+	if len(m) == 0 {
+		return m
+	}
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = os.ExpandEnv(v)
+	}
+	return out
+}
+
+// expandFunc returns a function expanding the environment variables in
+// s when called, or nil when s references none.
+func expandFunc(s string) func() string {
+	if !strings.Contains(s, "$") {
+		return nil
+	}
+	return func() string { return os.ExpandEnv(s) }
+}
+
+func expandSlice(s []string) []string {
+	if len(s) == 0 {
+		return s
+	}
+	out := make([]string, len(s))
+	for i, v := range s {
+		out[i] = os.ExpandEnv(v)
+	}
+	return out
+}
@@ -0,0 +1,107 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+
+	coherererank "github.com/Joepolymath/DaVinci/libs/shared-go/infra/ai/cohere/rerank"
+	localrerank "github.com/Joepolymath/DaVinci/libs/shared-go/infra/ai/local/rerank"
+	"go.uber.org/zap"
+)
+
+// RerankResult is a document's position in the reranked input and its
+// relevance score; higher is more relevant.
+type RerankResult struct {
+	Index int     `json:"index"`
+	Score float64 `json:"score"`
+}
+
+// Reranker reorders documents by relevance to a query, typically with a
+// cross-encoder, returning at most topN results (all if topN <= 0) by
+// descending score.
+type Reranker interface {
+	Rerank(ctx context.Context, query string, documents []string, topN int) ([]RerankResult, error)
+
+	GetModel() string
+}
+
+type RerankerConfig struct {
+	Provider ProviderType // ProviderCohere or ProviderLocal
+
+	// Cohere-specific
+	CohereAPIKey string
+	CohereModel  string // default: rerank-v3.5
+
+	// Local cross-encoder (Text Embeddings Inference)-specific
+	LocalHost  string
+	LocalModel string
+}
+
+func NewReranker(cfg *RerankerConfig, logger *zap.Logger) (Reranker, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("reranker config is required")
+	}
+
+	switch cfg.Provider {
+	case ProviderCohere:
+		client, err := coherererank.NewClient(&coherererank.Config{
+			APIKey: cfg.CohereAPIKey,
+			Model:  cfg.CohereModel,
+		}, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Cohere rerank client: %w", err)
+		}
+		return &cohereRerankAdapter{client: client}, nil
+	case ProviderLocal:
+		client, err := localrerank.NewClient(&localrerank.Config{
+			Host:  cfg.LocalHost,
+			Model: cfg.LocalModel,
+		}, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create local rerank client: %w", err)
+		}
+		return &localRerankAdapter{client: client}, nil
+	default:
+		return nil, fmt.Errorf("unsupported rerank provider: %q (supported: %q, %q)", cfg.Provider, ProviderCohere, ProviderLocal)
+	}
+}
+
+type cohereRerankAdapter struct {
+	client *coherererank.Client
+}
+
+func (a *cohereRerankAdapter) Rerank(ctx context.Context, query string, documents []string, topN int) ([]RerankResult, error) {
+	resp, err := a.client.Rerank(ctx, "", query, documents, topN)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]RerankResult, len(resp.Results))
+	for i, r := range resp.Results {
+		results[i] = RerankResult{Index: r.Index, Score: r.RelevanceScore}
+	}
+	return results, nil
+}
+
+func (a *cohereRerankAdapter) GetModel() string {
+	return a.client.GetModel()
+}
+
+type localRerankAdapter struct {
+	client *localrerank.Client
+}
+
+func (a *localRerankAdapter) Rerank(ctx context.Context, query string, documents []string, topN int) ([]RerankResult, error) {
+	resp, err := a.client.Rerank(ctx, query, documents, topN)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]RerankResult, len(resp))
+	for i, r := range resp {
+		results[i] = RerankResult{Index: r.Index, Score: r.Score}
+	}
+	return results, nil
+}
+
+func (a *localRerankAdapter) GetModel() string {
+	return a.client.GetModel()
+}
@@ -0,0 +1,339 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// defaultExpectedOutputTokens is the completion length assumed when comparing
+// backend costs for a request without MaxTokens.
+const defaultExpectedOutputTokens = 500
+
+// Capability is a feature a request needs from the backend that serves it.
+type Capability string
+
+const (
+	CapabilityVision           Capability = "vision"            // messages carry images
+	CapabilityTools            Capability = "tools"             // BuiltinTools requested
+	CapabilityStructuredOutput Capability = "structured_output" // ResponseFormat with a JSON schema
+)
+
+// RouteRule sends matching requests to a named backend. Every condition that
+// is set must hold; unset conditions match anything.
+type RouteRule struct {
+	Name    string `json:"name"`
+	Backend string `json:"backend"`
+
+	// Models matches ChatOptions.Model (typically an alias). A trailing "*"
+	// matches by prefix, e.g. "gpt-*".
+	Models []string `json:"models,omitempty"`
+
+	// MinPromptTokens and MaxPromptTokens bound the estimated prompt size.
+	MinPromptTokens int `json:"min_prompt_tokens,omitempty"`
+	MaxPromptTokens int `json:"max_prompt_tokens,omitempty"`
+
+	// Requires matches requests that need any of these capabilities.
+	Requires []Capability `json:"requires,omitempty"`
+
+	// Model, if set, replaces ChatOptions.Model before the request is forwarded,
+	// e.g. mapping the alias "gpt-class" onto "gpt-4o".
+	Model string `json:"model,omitempty"`
+}
+
+// RouterConfig is the declarative routing table for RouterProvider.
+// Rules are evaluated in order and the first match wins.
+type RouterConfig struct {
+	Rules   []RouteRule `json:"rules"`
+	Default string      `json:"default"` // backend used when no rule matches
+
+	// Cost, if set, picks among backends when no rule matches and the request
+	// does not name a model, instead of always using Default.
+	Cost *CostPolicy `json:"cost,omitempty"`
+}
+
+// CostPolicy routes to the cheapest backend whose model meets the required
+// quality tier, using the built-in pricing table.
+type CostPolicy struct {
+	Backends []string    `json:"backends"`           // candidates; empty means all backends
+	MinTier  QualityTier `json:"min_tier,omitempty"` // default tier when ChatOptions.QualityTier is zero
+
+	// ExpectedOutputTokens is assumed when MaxTokens is unset (default: 500).
+	ExpectedOutputTokens int `json:"expected_output_tokens,omitempty"`
+}
+
+// RouterProvider dispatches each request to one of several named backends
+// according to RouterConfig.
+type RouterProvider struct {
+	backends map[string]ChatProvider
+	rules    []RouteRule
+	def      string
+	cost     *CostPolicy
+	health   *HealthSupervisor
+}
+
+// NewRouterProvider validates cfg against the available backends.
+func NewRouterProvider(cfg RouterConfig, backends map[string]ChatProvider) (*RouterProvider, error) {
+	if len(backends) == 0 {
+		return nil, errors.New("at least one backend is required")
+	}
+	if _, ok := backends[cfg.Default]; !ok {
+		return nil, fmt.Errorf("default backend %q is not configured", cfg.Default)
+	}
+	for i, rule := range cfg.Rules {
+		if _, ok := backends[rule.Backend]; !ok {
+			return nil, fmt.Errorf("route rule %d (%s) references unknown backend %q", i, rule.Name, rule.Backend)
+		}
+	}
+	if cfg.Cost != nil {
+		for _, name := range cfg.Cost.Backends {
+			if _, ok := backends[name]; !ok {
+				return nil, fmt.Errorf("cost policy references unknown backend %q", name)
+			}
+		}
+	}
+
+	return &RouterProvider{
+		backends: backends,
+		rules:    cfg.Rules,
+		def:      cfg.Default,
+		cost:     cfg.Cost,
+	}, nil
+}
+
+// WithHealth makes the router skip backends that health reports as unhealthy.
+// Rules pointing at an unhealthy backend are passed over; if the default is
+// unhealthy, another healthy backend is used. When nothing is healthy the
+// default is used anyway.
+func (r *RouterProvider) WithHealth(health *HealthSupervisor) *RouterProvider {
+	r.health = health
+	return r
+}
+
+// ProvidersStatus returns the backend health tracked by the attached
+// HealthSupervisor, or nil if there is none.
+func (r *RouterProvider) ProvidersStatus() []ProviderStatus {
+	if r.health == nil {
+		return nil
+	}
+	return r.health.ProvidersStatus()
+}
+
+// Route returns the backend name, provider and (possibly rewritten) options
+// that a request would be sent to.
+func (r *RouterProvider) Route(messages []Message, opts *ChatOptions) (string, ChatProvider, *ChatOptions) {
+	model := ""
+	if opts != nil {
+		model = opts.Model
+	}
+	tokens := EstimateMessageTokens(messages)
+	caps := requiredCapabilities(messages, opts)
+
+	for _, rule := range r.rules {
+		if !rule.matches(model, tokens, caps) || !r.isHealthy(rule.Backend) {
+			continue
+		}
+		if rule.Model != "" {
+			routed := ChatOptions{}
+			if opts != nil {
+				routed = *opts
+			}
+			routed.Model = rule.Model
+			opts = &routed
+		}
+		return rule.Backend, r.backends[rule.Backend], opts
+	}
+
+	if r.cost != nil && model == "" {
+		if name, ok := r.cheapest(tokens, opts); ok {
+			return name, r.backends[name], opts
+		}
+	}
+
+	name := r.fallback()
+	return name, r.backends[name], opts
+}
+
+// fallback returns the default backend, or the first healthy one by name if
+// the default is unhealthy.
+func (r *RouterProvider) fallback() string {
+	if r.isHealthy(r.def) {
+		return r.def
+	}
+	names := make([]string, 0, len(r.backends))
+	for name := range r.backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if r.isHealthy(name) {
+			return name
+		}
+	}
+	return r.def
+}
+
+func (r *RouterProvider) isHealthy(name string) bool {
+	return r.health == nil || r.health.IsHealthy(name)
+}
+
+// cheapest returns the candidate backend with the lowest estimated cost whose
+// model meets the required tier. Backends with unpriced models are skipped.
+func (r *RouterProvider) cheapest(promptTokens int, opts *ChatOptions) (string, bool) {
+	tier := r.cost.MinTier
+	outputTokens := r.cost.ExpectedOutputTokens
+	if outputTokens <= 0 {
+		outputTokens = defaultExpectedOutputTokens
+	}
+	if opts != nil {
+		if opts.QualityTier != 0 {
+			tier = opts.QualityTier
+		}
+		if opts.MaxTokens > 0 {
+			outputTokens = opts.MaxTokens
+		}
+	}
+
+	candidates := r.cost.Backends
+	if len(candidates) == 0 {
+		candidates = make([]string, 0, len(r.backends))
+		for name := range r.backends {
+			candidates = append(candidates, name)
+		}
+		sort.Strings(candidates)
+	}
+
+	best, bestCost, found := "", 0.0, false
+	for _, name := range candidates {
+		backend := r.backends[name]
+		if !backend.IsEnabled() || !r.isHealthy(name) {
+			continue
+		}
+		price, ok := PriceFor(backend.GetModel())
+		if !ok || price.Tier < tier {
+			continue
+		}
+		cost := price.cost(promptTokens, outputTokens)
+		if !found || cost < bestCost {
+			best, bestCost, found = name, cost, true
+		}
+	}
+	return best, found
+}
+
+func (r *RouterProvider) Completion(ctx context.Context, messages []Message, opts *ChatOptions) (*ChatResponse, error) {
+	_, backend, opts := r.Route(messages, opts)
+	return backend.Completion(ctx, messages, opts)
+}
+
+func (r *RouterProvider) CompletionStream(ctx context.Context, messages []Message, opts *ChatOptions, onDelta func(delta ChatStreamDelta) error) error {
+	_, backend, opts := r.Route(messages, opts)
+	return backend.CompletionStream(ctx, messages, opts, onDelta)
+}
+
+// Health checks every backend and reports all failures.
+func (r *RouterProvider) Health(ctx context.Context) error {
+	var errs []error
+	for name, backend := range r.backends {
+		if err := backend.Health(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("backend %s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ListModels merges the model lists of all backends, skipping those that fail.
+func (r *RouterProvider) ListModels(ctx context.Context) ([]ModelInfo, error) {
+	var (
+		out  []ModelInfo
+		errs []error
+		seen = make(map[string]struct{})
+	)
+	for name, backend := range r.backends {
+		models, err := backend.ListModels(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("backend %s: %w", name, err))
+			continue
+		}
+		for _, m := range models {
+			key := string(m.Provider) + "/" + m.ID
+			if _, dup := seen[key]; dup {
+				continue
+			}
+			seen[key] = struct{}{}
+			out = append(out, m)
+		}
+	}
+	if out == nil && len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return out, nil
+}
+
+func (r *RouterProvider) IsEnabled() bool {
+	return r.backends[r.def].IsEnabled()
+}
+
+func (r *RouterProvider) GetModel() string {
+	return r.backends[r.def].GetModel()
+}
+
+func (rule *RouteRule) matches(model string, tokens int, caps map[Capability]bool) bool {
+	if len(rule.Models) > 0 && !matchesModel(rule.Models, model) {
+		return false
+	}
+	if rule.MinPromptTokens > 0 && tokens < rule.MinPromptTokens {
+		return false
+	}
+	if rule.MaxPromptTokens > 0 && tokens > rule.MaxPromptTokens {
+		return false
+	}
+	if len(rule.Requires) > 0 {
+		needed := false
+		for _, c := range rule.Requires {
+			if caps[c] {
+				needed = true
+				break
+			}
+		}
+		if !needed {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesModel(patterns []string, model string) bool {
+	for _, p := range patterns {
+		if prefix, ok := strings.CutSuffix(p, "*"); ok {
+			if strings.HasPrefix(model, prefix) {
+				return true
+			}
+		} else if p == model {
+			return true
+		}
+	}
+	return false
+}
+
+// requiredCapabilities inspects a request for features the backend must support.
+func requiredCapabilities(messages []Message, opts *ChatOptions) map[Capability]bool {
+	caps := make(map[Capability]bool)
+	for _, m := range messages {
+		if len(m.Images) > 0 {
+			caps[CapabilityVision] = true
+			break
+		}
+	}
+	if opts != nil {
+		if len(opts.BuiltinTools) > 0 {
+			caps[CapabilityTools] = true
+		}
+		if opts.ResponseFormat != nil && opts.ResponseFormat.Type == ResponseFormatJSONSchema {
+			caps[CapabilityStructuredOutput] = true
+		}
+	}
+	return caps
+}
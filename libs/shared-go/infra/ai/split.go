@@ -0,0 +1,103 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+)
+
+// SplitArm is one side of a traffic split.
+type SplitArm struct {
+	Name     string // reported in ChatResponse.Arm, e.g. "openai" or "llama3"
+	Provider ChatProvider
+}
+
+// SplitProvider sends a percentage of traffic to arm B and the rest to arm A,
+// tagging every response with the arm that served it, so a candidate model can
+// be evaluated against the incumbent on live traffic.
+type SplitProvider struct {
+	a, b     SplitArm
+	percentB float64
+}
+
+// NewSplitProvider routes percentB (0-100) percent of requests to b.
+func NewSplitProvider(a, b SplitArm, percentB float64) (*SplitProvider, error) {
+	if a.Provider == nil || b.Provider == nil {
+		return nil, errors.New("both split arms require a provider")
+	}
+	if a.Name == "" || b.Name == "" || a.Name == b.Name {
+		return nil, errors.New("split arms require distinct names")
+	}
+	if percentB < 0 || percentB > 100 {
+		return nil, fmt.Errorf("split percentage must be between 0 and 100, got %v", percentB)
+	}
+	return &SplitProvider{a: a, b: b, percentB: percentB}, nil
+}
+
+type splitKeyCtxKey struct{}
+
+// WithSplitKey pins requests made with ctx to an arm chosen by hashing key
+// (e.g. a user or conversation ID), so one user sees a consistent model.
+// Without a key each request is assigned at random.
+func WithSplitKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, splitKeyCtxKey{}, key)
+}
+
+func (s *SplitProvider) Completion(ctx context.Context, messages []Message, opts *ChatOptions) (*ChatResponse, error) {
+	arm := s.pick(ctx)
+	resp, err := arm.Provider.Completion(ctx, messages, opts)
+	if err != nil {
+		return nil, err
+	}
+	resp.Arm = arm.Name
+	return resp, nil
+}
+
+func (s *SplitProvider) CompletionStream(ctx context.Context, messages []Message, opts *ChatOptions, onDelta func(delta ChatStreamDelta) error) error {
+	arm := s.pick(ctx)
+	return arm.Provider.CompletionStream(ctx, messages, opts, func(delta ChatStreamDelta) error {
+		delta.Arm = arm.Name
+		return onDelta(delta)
+	})
+}
+
+// Health requires both arms to be healthy, since either may receive traffic.
+func (s *SplitProvider) Health(ctx context.Context) error {
+	var errs []error
+	for _, arm := range []SplitArm{s.a, s.b} {
+		if err := arm.Provider.Health(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("arm %s: %w", arm.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ListModels returns the models of arm A, the incumbent.
+func (s *SplitProvider) ListModels(ctx context.Context) ([]ModelInfo, error) {
+	return s.a.Provider.ListModels(ctx)
+}
+
+func (s *SplitProvider) IsEnabled() bool {
+	return s.a.Provider.IsEnabled() && s.b.Provider.IsEnabled()
+}
+
+func (s *SplitProvider) GetModel() string {
+	return s.a.Provider.GetModel()
+}
+
+func (s *SplitProvider) pick(ctx context.Context) SplitArm {
+	var roll float64
+	if key, ok := ctx.Value(splitKeyCtxKey{}).(string); ok && key != "" {
+		h := fnv.New64a()
+		h.Write([]byte(key))
+		roll = float64(h.Sum64()%10000) / 100
+	} else {
+		roll = rand.Float64() * 100
+	}
+	if roll < s.percentB {
+		return s.b
+	}
+	return s.a
+}
@@ -0,0 +1,132 @@
+package sse
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Event is a single server-sent event.
+type Event struct {
+	Event string // event type; empty means the default "message"
+	Data  string // data lines joined with "\n"
+	ID    string // last event ID
+	Retry int    // reconnection time in milliseconds, 0 if not sent
+}
+
+// Reader parses a text/event-stream body as described by the WHATWG
+// server-sent events specification. It accepts LF, CRLF and CR line endings,
+// multi-line data fields, named events and comments, and has no line length limit.
+type Reader struct {
+	r      *bufio.Reader
+	lastID string
+	// pendingCR is set after a line ended with CR, so an LF that follows
+	// (completing a CRLF) is skipped by the next read rather than looked
+	// for with a Peek that would block until more bytes arrive.
+	pendingCR bool
+}
+
+// NewReader returns a Reader that parses events from r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: bufio.NewReader(r)}
+}
+
+// NewReaderSize is like NewReader but uses a read buffer of at least size bytes.
+// The buffer size only affects read granularity, not the maximum line length.
+func NewReaderSize(r io.Reader, size int) *Reader {
+	return &Reader{r: bufio.NewReaderSize(r, size)}
+}
+
+// Next returns the next event. It returns io.EOF when the stream ends; as the
+// specification requires, a final event not followed by a blank line is
+// discarded, since the stream may have been cut off mid-event.
+func (r *Reader) Next() (*Event, error) {
+	var (
+		ev      Event
+		data    strings.Builder
+		hasData bool
+	)
+	ev.ID = r.lastID
+
+	for {
+		line, err := r.readLine()
+		if err != nil {
+			return nil, err
+		}
+
+		if len(line) == 0 {
+			// Blank line dispatches the event; events without data are skipped.
+			if hasData {
+				ev.Data = data.String()
+				return &ev, nil
+			}
+			ev = Event{ID: r.lastID}
+			continue
+		}
+
+		if line[0] == ':' {
+			continue // comment / keep-alive
+		}
+
+		field, value := line, []byte(nil)
+		if i := bytes.IndexByte(line, ':'); i >= 0 {
+			field, value = line[:i], line[i+1:]
+			if len(value) > 0 && value[0] == ' ' {
+				value = value[1:]
+			}
+		}
+
+		switch string(field) {
+		case "data":
+			if hasData {
+				data.WriteByte('\n')
+			}
+			data.Write(value)
+			hasData = true
+		case "event":
+			ev.Event = string(value)
+		case "id":
+			if bytes.IndexByte(value, 0) < 0 {
+				r.lastID = string(value)
+				ev.ID = r.lastID
+			}
+		case "retry":
+			if n, err := strconv.Atoi(string(value)); err == nil && n >= 0 {
+				ev.Retry = n
+			}
+		}
+	}
+}
+
+// readLine returns the next line without its terminator (LF, CRLF or CR).
+// It returns io.EOF only when no bytes remain.
+func (r *Reader) readLine() ([]byte, error) {
+	var line []byte
+	for {
+		b, err := r.r.ReadByte()
+		if err != nil {
+			if errors.Is(err, io.EOF) && len(line) > 0 {
+				return line, nil
+			}
+			return nil, err
+		}
+		if r.pendingCR {
+			r.pendingCR = false
+			if b == '\n' {
+				continue
+			}
+		}
+		switch b {
+		case '\n':
+			return line, nil
+		case '\r':
+			r.pendingCR = true
+			return line, nil
+		default:
+			line = append(line, b)
+		}
+	}
+}
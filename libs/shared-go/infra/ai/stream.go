@@ -0,0 +1,101 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrStreamClosed is reported by Stream.Err when the caller aborted the stream with Close.
+var ErrStreamClosed = errors.New("chat stream closed by caller")
+
+// Stream is a handle to a streaming completion running in the background.
+// Close aborts generation; the provider's HTTP response body is closed as soon
+// as the in-flight read returns, which stops the upstream from billing further tokens.
+type Stream struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu     sync.Mutex
+	closed bool
+	err    error
+}
+
+// CompletionStreamCancelable starts a streaming completion on provider and returns
+// immediately. Deltas are delivered to onDelta from a separate goroutine.
+func CompletionStreamCancelable(ctx context.Context, provider ChatProvider, messages []Message, opts *ChatOptions, onDelta func(delta ChatStreamDelta) error) *Stream {
+	ctx, cancel := context.WithCancel(ctx)
+	s := &Stream{
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	go func() {
+		defer close(s.done)
+		defer cancel()
+
+		err := provider.CompletionStream(ctx, messages, opts, func(delta ChatStreamDelta) error {
+			if s.isClosed() {
+				return ErrStreamClosed
+			}
+			return onDelta(delta)
+		})
+
+		s.mu.Lock()
+		if s.closed {
+			err = ErrStreamClosed
+		}
+		s.err = err
+		s.mu.Unlock()
+	}()
+
+	return s
+}
+
+// Close aborts the stream and waits for the provider call to return.
+// It is safe to call more than once and after the stream has finished.
+func (s *Stream) Close() error {
+	s.mu.Lock()
+	if !s.isDone() {
+		s.closed = true
+	}
+	s.mu.Unlock()
+
+	s.cancel()
+	<-s.done
+	return nil
+}
+
+// Done is closed once the stream has finished, failed, or been closed.
+func (s *Stream) Done() <-chan struct{} {
+	return s.done
+}
+
+// Wait blocks until the stream finishes and returns its error.
+func (s *Stream) Wait() error {
+	<-s.done
+	return s.Err()
+}
+
+// Err returns the error the stream ended with, ErrStreamClosed if it was aborted
+// by Close, or nil while it is still running or if it completed normally.
+func (s *Stream) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+func (s *Stream) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+func (s *Stream) isDone() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
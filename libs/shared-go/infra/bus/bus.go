@@ -0,0 +1,41 @@
+// Package bus publishes application events to a message bus: a Redis
+// stream, an HTTP webhook, or the log when neither is configured.
+// Delivery is at least once, so consumers should skip message IDs they
+// have already seen.
+package bus
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+type Message struct {
+	ID      string // unique per event; repeated when a publish is retried
+	Topic   string // e.g. "chat.message.completed"
+	Key     string // orders messages about one entity, e.g. a conversation ID
+	Payload []byte // JSON
+}
+
+type Publisher interface {
+	// Publish returns once the bus has accepted the message.
+	Publish(ctx context.Context, msg Message) error
+}
+
+type logPublisher struct {
+	logger *zap.Logger
+}
+
+// NewLogPublisher logs messages at debug level instead of publishing them.
+func NewLogPublisher(logger *zap.Logger) Publisher {
+	return &logPublisher{logger: logger}
+}
+
+func (p *logPublisher) Publish(ctx context.Context, msg Message) error {
+	p.logger.Debug("Event",
+		zap.String("id", msg.ID),
+		zap.String("topic", msg.Topic),
+		zap.String("key", msg.Key),
+		zap.ByteString("payload", msg.Payload))
+	return nil
+}
@@ -0,0 +1,42 @@
+package bus
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/redis"
+)
+
+// defaultStreamLength caps each stream, approximately, so an idle
+// consumer cannot exhaust Redis memory.
+const defaultStreamLength = 100_000
+
+type RedisStreamConfig struct {
+	Prefix    string // prepended to the topic to name the stream, e.g. "events:"
+	MaxLength int    // entries kept per stream (default: 100000)
+}
+
+type redisStreamPublisher struct {
+	client    *redis.Client
+	prefix    string
+	maxLength int
+}
+
+// NewRedisStreamPublisher appends each message to the stream named after
+// its topic, with id, key and payload fields.
+func NewRedisStreamPublisher(client *redis.Client, cfg RedisStreamConfig) Publisher {
+	maxLength := cfg.MaxLength
+	if maxLength <= 0 {
+		maxLength = defaultStreamLength
+	}
+	return &redisStreamPublisher{client: client, prefix: cfg.Prefix, maxLength: maxLength}
+}
+
+func (p *redisStreamPublisher) Publish(ctx context.Context, msg Message) error {
+	_, err := p.client.Do(ctx, "XADD", p.prefix+msg.Topic, "MAXLEN", "~", p.maxLength, "*",
+		"id", msg.ID, "key", msg.Key, "payload", msg.Payload)
+	if err != nil {
+		return fmt.Errorf("publish to redis stream: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package bus
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const defaultWebhookTimeout = 10 * time.Second
+
+// Headers set on every webhook request besides Content-Type.
+const (
+	HeaderEventID    = "X-Event-Id"
+	HeaderEventTopic = "X-Event-Topic"
+	HeaderEventKey   = "X-Event-Key"
+)
+
+type WebhookConfig struct {
+	URL     string            // receives a POST per message with the payload as its body
+	Headers map[string]string // e.g. Authorization
+	Timeout time.Duration     // per request (default: 10s)
+}
+
+type webhookPublisher struct {
+	url        string
+	headers    map[string]string
+	httpClient *http.Client
+}
+
+// NewWebhookPublisher posts each message to cfg.URL. Any 2xx response
+// counts as accepted.
+func NewWebhookPublisher(cfg WebhookConfig) (Publisher, error) {
+	url := strings.TrimSpace(cfg.URL)
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return nil, fmt.Errorf("unsupported webhook url: %s", cfg.URL)
+	}
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultWebhookTimeout
+	}
+
+	return &webhookPublisher{
+		url:        url,
+		headers:    cfg.Headers,
+		httpClient: &http.Client{Timeout: timeout},
+	}, nil
+}
+
+func (p *webhookPublisher) Publish(ctx context.Context, msg Message) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(msg.Payload))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	for k, v := range p.headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEventID, msg.ID)
+	req.Header.Set(HeaderEventTopic, msg.Topic)
+	req.Header.Set(HeaderEventKey, msg.Key)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// migrationLock is the advisory lock key held while migrating, so that
+// instances starting together apply each migration once.
+const migrationLock = 7_134_226_041
+
+// Migrate applies the migrations in fsys that are not yet recorded in the
+// schema_migrations table, in file name order, each in its own
+// transaction, and returns the versions it applied. Migrations are files
+// named "<version>_<description>.sql", e.g. "001_conversations.sql".
+func (db *DB) Migrate(ctx context.Context, fsys fs.FS) ([]string, error) {
+	names, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(names)
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("acquire migration connection: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLock); err != nil {
+		return nil, fmt.Errorf("acquire migration lock: %w", err)
+	}
+	defer conn.ExecContext(context.WithoutCancel(ctx), `SELECT pg_advisory_unlock($1)`, migrationLock)
+
+	if _, err := conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    TEXT PRIMARY KEY,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`); err != nil {
+		return nil, fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	applied := []string{}
+	for _, name := range names {
+		version, _, ok := strings.Cut(path.Base(name), "_")
+		if !ok {
+			return applied, fmt.Errorf("migration %s: name must be <version>_<description>.sql", name)
+		}
+
+		var exists bool
+		if err := conn.QueryRowContext(ctx,
+			`SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)`, version,
+		).Scan(&exists); err != nil {
+			return applied, fmt.Errorf("check migration %s: %w", version, err)
+		}
+		if exists {
+			continue
+		}
+
+		script, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return applied, err
+		}
+		if err := migrate(ctx, conn, version, string(script)); err != nil {
+			return applied, fmt.Errorf("migration %s: %w", name, err)
+		}
+		db.logger.Info("Applied migration", zap.String("version", version), zap.String("file", name))
+		applied = append(applied, version)
+	}
+	return applied, nil
+}
+
+// migrate runs script and records version in one transaction.
+func migrate(ctx context.Context, conn *sql.Conn, version, script string) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version) VALUES ($1)`, version); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// PoolStats reports how busy a connection pool is. Counts and durations
+// marked total are since the pool was opened.
+type PoolStats struct {
+	MaxOpen     int     `json:"max_open"` // 0 is unlimited
+	Open        int     `json:"open"`
+	InUse       int     `json:"in_use"`
+	Idle        int     `json:"idle"`
+	Utilization float64 `json:"utilization"` // InUse / MaxOpen, 0 when unlimited
+
+	WaitCount         int64 `json:"wait_count"` // total requests that waited for a connection
+	WaitMs            int64 `json:"wait_ms"`    // total time spent waiting
+	MaxIdleClosed     int64 `json:"max_idle_closed"`
+	MaxIdleTimeClosed int64 `json:"max_idle_time_closed"`
+	MaxLifetimeClosed int64 `json:"max_lifetime_closed"`
+}
+
+// ReadPoolStats reads the statistics of db's pool.
+func ReadPoolStats(db *sql.DB) PoolStats {
+	s := db.Stats()
+	stats := PoolStats{
+		MaxOpen:           s.MaxOpenConnections,
+		Open:              s.OpenConnections,
+		InUse:             s.InUse,
+		Idle:              s.Idle,
+		WaitCount:         s.WaitCount,
+		WaitMs:            s.WaitDuration.Milliseconds(),
+		MaxIdleClosed:     s.MaxIdleClosed,
+		MaxIdleTimeClosed: s.MaxIdleTimeClosed,
+		MaxLifetimeClosed: s.MaxLifetimeClosed,
+	}
+	if s.MaxOpenConnections > 0 {
+		stats.Utilization = float64(s.InUse) / float64(s.MaxOpenConnections)
+	}
+	return stats
+}
+
+// MonitorPool checks db's pool every interval until ctx ends and logs a
+// warning for each interval in which requests had to wait for a
+// connection, the sign that MaxOpenConns is too low for the load.
+func MonitorPool(ctx context.Context, db *sql.DB, interval time.Duration, logger *zap.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last := ReadPoolStats(db)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		stats := ReadPoolStats(db)
+		if waits := stats.WaitCount - last.WaitCount; waits > 0 {
+			logger.Warn("Database pool saturated",
+				zap.Int64("waits", waits),
+				zap.Int64("wait_ms", stats.WaitMs-last.WaitMs),
+				zap.Int("in_use", stats.InUse),
+				zap.Int("max_open", stats.MaxOpen))
+		}
+		last = stats
+	}
+}
@@ -0,0 +1,77 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// Migrate applies the migrations in fsys that are not yet recorded in the
+// schema_migrations table, in file name order, each in its own
+// transaction, and returns the versions it applied. Migrations are files
+// named "<version>_<description>.sql", e.g. "001_conversations.sql".
+func (db *DB) Migrate(ctx context.Context, fsys fs.FS) ([]string, error) {
+	names, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(names)
+
+	if _, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    TEXT PRIMARY KEY,
+		applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`); err != nil {
+		return nil, fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	applied := []string{}
+	for _, name := range names {
+		version, _, ok := strings.Cut(path.Base(name), "_")
+		if !ok {
+			return applied, fmt.Errorf("migration %s: name must be <version>_<description>.sql", name)
+		}
+
+		var exists bool
+		if err := db.QueryRowContext(ctx,
+			`SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = ?)`, version,
+		).Scan(&exists); err != nil {
+			return applied, fmt.Errorf("check migration %s: %w", version, err)
+		}
+		if exists {
+			continue
+		}
+
+		script, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return applied, err
+		}
+		if err := db.migrate(ctx, version, string(script)); err != nil {
+			return applied, fmt.Errorf("migration %s: %w", name, err)
+		}
+		db.logger.Info("Applied migration", zap.String("version", version), zap.String("file", name))
+		applied = append(applied, version)
+	}
+	return applied, nil
+}
+
+// migrate runs script and records version in one transaction.
+func (db *DB) migrate(ctx context.Context, version, script string) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version) VALUES (?)`, version); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
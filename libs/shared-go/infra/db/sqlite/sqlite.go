@@ -0,0 +1,72 @@
+// Package sqlite opens SQLite databases through database/sql and applies
+// schema migrations, for running without external services. The driver is
+// looked up by name, so binaries link it in, e.g. with
+// import _ "modernc.org/sqlite" for "sqlite", which needs no cgo.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+
+	"go.uber.org/zap"
+)
+
+const defaultDriver = "sqlite"
+
+var errPathRequired = errors.New("sqlite path is required")
+
+type SQLiteConfig struct {
+	Path   string // database file, e.g. "scribequery.db"
+	Driver string // registered database/sql driver name (default: "sqlite")
+}
+
+// DB is a database file. SQLite allows one writer at a time, so it is
+// used through a single connection.
+type DB struct {
+	*sql.DB
+	logger *zap.Logger
+}
+
+// NewSQLiteDB opens, creating it if needed, the database at cfg.Path with
+// foreign keys enforced and write-ahead logging.
+func NewSQLiteDB(ctx context.Context, cfg SQLiteConfig, logger *zap.Logger) (*DB, error) {
+	if cfg.Path == "" {
+		return nil, errPathRequired
+	}
+	driver := cfg.Driver
+	if driver == "" {
+		driver = defaultDriver
+	}
+	if !slices.Contains(sql.Drivers(), driver) {
+		return nil, fmt.Errorf("sqlite driver %q is not registered", driver)
+	}
+
+	pool, err := sql.Open(driver, cfg.Path)
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite: %w", err)
+	}
+	pool.SetMaxOpenConns(1)
+
+	for _, pragma := range []string{
+		`PRAGMA foreign_keys = ON`,
+		`PRAGMA journal_mode = WAL`,
+		`PRAGMA busy_timeout = 5000`,
+	} {
+		if _, err := pool.ExecContext(ctx, pragma); err != nil {
+			pool.Close()
+			return nil, fmt.Errorf("configure sqlite: %w", err)
+		}
+	}
+	return &DB{DB: pool, logger: logger}, nil
+}
+
+// Health reports whether the database file can be queried.
+func (db *DB) Health(ctx context.Context) error {
+	if err := db.PingContext(ctx); err != nil {
+		return fmt.Errorf("ping sqlite: %w", err)
+	}
+	return nil
+}
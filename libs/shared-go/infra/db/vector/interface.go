@@ -0,0 +1,23 @@
+package vector
+
+import "context"
+
+// Store is the provider-agnostic vector database used by domain code.
+// Implementations live next to each backend (e.g. weaviate.NewStore).
+type Store interface {
+	// Upsert inserts points or replaces those with the same ID.
+	Upsert(ctx context.Context, collection string, points []Point) error
+
+	// Query returns the points nearest to req.Vector (or req.Text, for
+	// backends that vectorize server-side), best match first.
+	Query(ctx context.Context, req *QueryRequest) ([]Match, error)
+
+	// Delete removes points by ID. Missing IDs are not an error.
+	Delete(ctx context.Context, collection string, ids []string) error
+
+	// Collections lists the collections in the store.
+	Collections(ctx context.Context) ([]Collection, error)
+
+	// CreateCollection creates a collection for vectors of the given dimension.
+	CreateCollection(ctx context.Context, collection Collection) error
+}
@@ -0,0 +1,292 @@
+// Package memory is an in-process vector.Store that searches by brute
+// force. It suits tests and small local setups; nothing is persisted.
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/db/vector"
+)
+
+const defaultLimit = 10
+
+var errCollectionRequired = errors.New("collection name is required")
+
+type collection struct {
+	vector.Collection
+	points map[string]vector.Point
+}
+
+type memoryStore struct {
+	mu          sync.RWMutex
+	collections map[string]*collection
+}
+
+// NewStore creates an empty store. Upsert creates missing collections with
+// cosine distance.
+func NewStore() vector.Store {
+	return &memoryStore{collections: make(map[string]*collection)}
+}
+
+func (s *memoryStore) Upsert(ctx context.Context, name string, points []vector.Point) error {
+	if strings.TrimSpace(name) == "" {
+		return errCollectionRequired
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := s.collections[name]
+	if c == nil {
+		c = &collection{
+			Collection: vector.Collection{Name: name, Distance: vector.DistanceCosine},
+			points:     make(map[string]vector.Point),
+		}
+		s.collections[name] = c
+	}
+	for _, p := range points {
+		if c.Dimension > 0 && len(p.Vector) != c.Dimension {
+			return fmt.Errorf("point %s has dimension %d, collection %s expects %d", p.ID, len(p.Vector), name, c.Dimension)
+		}
+	}
+	for _, p := range points {
+		if c.Dimension == 0 {
+			c.Dimension = len(p.Vector)
+		}
+		c.points[p.ID] = p
+	}
+	return nil
+}
+
+func (s *memoryStore) Query(ctx context.Context, req *vector.QueryRequest) ([]vector.Match, error) {
+	if req == nil {
+		return nil, errors.New("QueryRequest is required")
+	}
+	if strings.TrimSpace(req.Collection) == "" {
+		return nil, errCollectionRequired
+	}
+	if req.Hybrid != nil {
+		return nil, vector.ErrHybridUnsupported
+	}
+	if len(req.Vector) == 0 {
+		if req.Text != "" {
+			return nil, vector.ErrTextQueryUnsupported
+		}
+		return nil, errors.New("search vector is required")
+	}
+
+	limit := req.TopK
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	c := s.collections[req.Collection]
+	if c == nil {
+		return nil, fmt.Errorf("collection %s does not exist", req.Collection)
+	}
+
+	matches := []vector.Match{}
+	for _, p := range c.points {
+		if len(p.Vector) != len(req.Vector) || !keep(p.Payload, req.Filter, req.Where) {
+			continue
+		}
+		score := similarity(c.Distance, req.Vector, p.Vector)
+		if score < req.MinScore {
+			continue
+		}
+		m := vector.Match{ID: p.ID, Score: score}
+		if req.WithPayload || len(req.Fields) > 0 {
+			m.Payload = selectFields(p.Payload, req.Fields)
+		}
+		if req.WithVector {
+			m.Vector = p.Vector
+		}
+		matches = append(matches, m)
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].ID < matches[j].ID
+	})
+	return matches[:min(len(matches), limit)], nil
+}
+
+func (s *memoryStore) Delete(ctx context.Context, name string, ids []string) error {
+	if strings.TrimSpace(name) == "" {
+		return errCollectionRequired
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if c := s.collections[name]; c != nil {
+		for _, id := range ids {
+			delete(c.points, id)
+		}
+	}
+	return nil
+}
+
+func (s *memoryStore) Collections(ctx context.Context) ([]vector.Collection, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	collections := make([]vector.Collection, 0, len(s.collections))
+	for _, c := range s.collections {
+		collections = append(collections, c.Collection)
+	}
+	sort.Slice(collections, func(i, j int) bool { return collections[i].Name < collections[j].Name })
+	return collections, nil
+}
+
+func (s *memoryStore) CreateCollection(ctx context.Context, spec vector.Collection) error {
+	if strings.TrimSpace(spec.Name) == "" {
+		return errCollectionRequired
+	}
+	if spec.Distance == "" {
+		spec.Distance = vector.DistanceCosine
+	}
+	switch spec.Distance {
+	case vector.DistanceCosine, vector.DistanceEuclid, vector.DistanceDot:
+	default:
+		return fmt.Errorf("unsupported distance %q", spec.Distance)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.collections[spec.Name]; ok {
+		return fmt.Errorf("collection %s already exists", spec.Name)
+	}
+	s.collections[spec.Name] = &collection{Collection: spec, points: make(map[string]vector.Point)}
+	return nil
+}
+
+// similarity scores b against a so that higher is closer, whatever the
+// distance: euclidean distance is negated.
+func similarity(distance string, a, b []float32) float32 {
+	var dot, na, nb, sq float64
+	for i := range a {
+		x, y := float64(a[i]), float64(b[i])
+		dot += x * y
+		na += x * x
+		nb += y * y
+		sq += (x - y) * (x - y)
+	}
+	switch distance {
+	case vector.DistanceDot:
+		return float32(dot)
+	case vector.DistanceEuclid:
+		return float32(-math.Sqrt(sq))
+	default:
+		if na == 0 || nb == 0 {
+			return 0
+		}
+		return float32(dot / math.Sqrt(na*nb))
+	}
+}
+
+func selectFields(payload vector.Payload, fields []string) vector.Payload {
+	if len(fields) == 0 {
+		return payload
+	}
+	selected := make(vector.Payload, len(fields))
+	for _, f := range fields {
+		if v, ok := payload[f]; ok {
+			selected[f] = v
+		}
+	}
+	return selected
+}
+
+// keep reports whether payload equals every filter value and meets every
+// condition.
+func keep(payload, filter vector.Payload, where []vector.Condition) bool {
+	for k, v := range filter {
+		if !equal(payload[k], v) {
+			return false
+		}
+	}
+	for _, c := range where {
+		if !meets(payload[c.Field], c) {
+			return false
+		}
+	}
+	return true
+}
+
+func meets(value any, c vector.Condition) bool {
+	switch c.Operator {
+	case vector.OpEqual:
+		return equal(value, c.Value)
+	case vector.OpNotEqual:
+		return !equal(value, c.Value)
+	case vector.OpGreaterThan, vector.OpGreaterThanEqual, vector.OpLessThan, vector.OpLessThanEqual:
+		x, ok1 := number(value)
+		y, ok2 := number(c.Value)
+		if !ok1 || !ok2 {
+			return false
+		}
+		switch c.Operator {
+		case vector.OpGreaterThan:
+			return x > y
+		case vector.OpGreaterThanEqual:
+			return x >= y
+		case vector.OpLessThan:
+			return x < y
+		default:
+			return x <= y
+		}
+	case vector.OpIn:
+		values, _ := c.Values()
+		for _, v := range values {
+			if equal(value, v) {
+				return true
+			}
+		}
+		return false
+	case vector.OpLike:
+		s, ok1 := value.(string)
+		sub, ok2 := c.Value.(string)
+		return ok1 && ok2 && strings.Contains(strings.ToLower(s), strings.ToLower(sub))
+	}
+	return false
+}
+
+// equal compares numbers by value whatever their type, e.g. a payload int
+// against a float64 decoded from JSON.
+func equal(a, b any) bool {
+	if x, ok := number(a); ok {
+		y, ok := number(b)
+		return ok && x == y
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func number(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
@@ -0,0 +1,232 @@
+package milvus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultURL         = "http://localhost:19530"
+	defaultTimeout     = 30 * time.Second
+	defaultLimit       = 10
+	defaultIDMaxLength = 512
+	primaryField       = "id"
+	vectorField        = "vector"
+	distanceField      = "distance"
+	restPrefix         = "/v2/vectordb"
+)
+
+var errCollectionRequired = errors.New("collection name is required")
+
+type MilvusConfig struct {
+	URL     string        // e.g. "http://localhost:19530"
+	Token   string        // "user:password" or a Zilliz Cloud API key
+	DBName  string        // default database when empty
+	Timeout time.Duration // per request (default: 30s)
+
+	// Partition scopes store writes, deletes and searches to one partition.
+	// Empty uses the collection's default partition (and searches all).
+	Partition string
+
+	// ConsistencyLevel applies to searches and to collections created through
+	// the store: Strong, Session, Bounded or Eventually (server default: Bounded).
+	ConsistencyLevel string
+
+	// MetricType is used for collections created through the store (default: COSINE).
+	MetricType string
+
+	// SearchParams are passed through to searches, e.g. {"params": {"ef": 64}}.
+	SearchParams map[string]any
+}
+
+type Client struct {
+	url              string
+	token            string
+	dbName           string
+	partition        string
+	consistencyLevel string
+	metricType       string
+	searchParams     map[string]any
+	httpClient       *http.Client
+	logger           *zap.Logger
+}
+
+func NewMilvusClient(cfg MilvusConfig, logger *zap.Logger) (*Client, error) {
+	url := strings.TrimRight(strings.TrimSpace(cfg.URL), "/")
+	if url == "" {
+		url = defaultURL
+	}
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return nil, fmt.Errorf("unsupported milvus url: %s", cfg.URL)
+	}
+
+	switch cfg.ConsistencyLevel {
+	case "", ConsistencyStrong, ConsistencySession, ConsistencyBounded, ConsistencyEventually:
+	default:
+		return nil, fmt.Errorf("unsupported milvus consistency level: %s", cfg.ConsistencyLevel)
+	}
+
+	metricType := strings.ToUpper(cfg.MetricType)
+	if metricType == "" {
+		metricType = MetricCosine
+	}
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	return &Client{
+		url:              url,
+		token:            cfg.Token,
+		dbName:           cfg.DBName,
+		partition:        cfg.Partition,
+		consistencyLevel: cfg.ConsistencyLevel,
+		metricType:       metricType,
+		searchParams:     cfg.SearchParams,
+		httpClient:       &http.Client{Timeout: timeout},
+		logger:           logger,
+	}, nil
+}
+
+// Health reports whether Milvus answers API requests.
+func (c *Client) Health(ctx context.Context) error {
+	if _, err := c.ListCollections(ctx); err != nil {
+		return fmt.Errorf("check milvus health: %w", err)
+	}
+	return nil
+}
+
+// ListCollections returns the collection names in the configured database.
+func (c *Client) ListCollections(ctx context.Context) ([]string, error) {
+	var names []string
+	if err := c.do(ctx, "/collections/list", collectionRequest{DBName: c.dbName}, &names); err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// DropCollection deletes a collection and all of its data.
+func (c *Client) DropCollection(ctx context.Context, collection string) error {
+	if strings.TrimSpace(collection) == "" {
+		return errCollectionRequired
+	}
+	req := collectionRequest{DBName: c.dbName, CollectionName: collection}
+	if err := c.do(ctx, "/collections/drop", req, nil); err != nil {
+		return fmt.Errorf("drop collection %q: %w", collection, err)
+	}
+	c.logger.Info("dropped collection", zap.String("collection", collection))
+	return nil
+}
+
+// LoadCollection loads a collection into memory so it can be searched.
+func (c *Client) LoadCollection(ctx context.Context, collection string) error {
+	if strings.TrimSpace(collection) == "" {
+		return errCollectionRequired
+	}
+	req := collectionRequest{DBName: c.dbName, CollectionName: collection}
+	if err := c.do(ctx, "/collections/load", req, nil); err != nil {
+		return fmt.Errorf("load collection %q: %w", collection, err)
+	}
+	return nil
+}
+
+// CreatePartition creates a partition in collection.
+func (c *Client) CreatePartition(ctx context.Context, collection, partition string) error {
+	if strings.TrimSpace(collection) == "" {
+		return errCollectionRequired
+	}
+	if strings.TrimSpace(partition) == "" {
+		return errors.New("partition name is required")
+	}
+	req := partitionRequest{DBName: c.dbName, CollectionName: collection, PartitionName: partition}
+	if err := c.do(ctx, "/partitions/create", req, nil); err != nil {
+		return fmt.Errorf("create partition %q in %q: %w", partition, collection, err)
+	}
+	c.logger.Info("created partition", zap.String("collection", collection), zap.String("partition", partition))
+	return nil
+}
+
+// ListPartitions returns the partition names of collection.
+func (c *Client) ListPartitions(ctx context.Context, collection string) ([]string, error) {
+	if strings.TrimSpace(collection) == "" {
+		return nil, errCollectionRequired
+	}
+	var names []string
+	req := partitionRequest{DBName: c.dbName, CollectionName: collection}
+	if err := c.do(ctx, "/partitions/list", req, &names); err != nil {
+		return nil, fmt.Errorf("list partitions of %q: %w", collection, err)
+	}
+	return names, nil
+}
+
+// DropPartition deletes a partition and its data. Milvus requires the
+// partition to be released first.
+func (c *Client) DropPartition(ctx context.Context, collection, partition string) error {
+	if strings.TrimSpace(collection) == "" {
+		return errCollectionRequired
+	}
+	req := partitionRequest{DBName: c.dbName, CollectionName: collection, PartitionName: partition}
+	if err := c.do(ctx, "/partitions/drop", req, nil); err != nil {
+		return fmt.Errorf("drop partition %q in %q: %w", partition, collection, err)
+	}
+	c.logger.Info("dropped partition", zap.String("collection", collection), zap.String("partition", partition))
+	return nil
+}
+
+// do POSTs body to a REST v2 endpoint and decodes the data field into out.
+func (c *Client) do(ctx context.Context, endpoint string, body, out any) error {
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("marshal milvus request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url+restPrefix+endpoint, bytes.NewReader(jsonData))
+	if err != nil {
+		return fmt.Errorf("create milvus request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("milvus %s: %w", endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read milvus response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("milvus API error (status %d): %s", resp.StatusCode, string(raw))
+	}
+
+	var envelope response
+	if err := json.Unmarshal(raw, &envelope); err != nil {
+		return fmt.Errorf("unmarshal milvus response: %w", err)
+	}
+	if envelope.Code != 0 {
+		return fmt.Errorf("milvus API error (code %d): %s", envelope.Code, envelope.Message)
+	}
+
+	if out == nil || len(envelope.Data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(envelope.Data, out); err != nil {
+		return fmt.Errorf("unmarshal milvus response data: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package milvus
+
+import "encoding/json"
+
+// Consistency levels for reads, from strongest to weakest.
+const (
+	ConsistencyStrong     = "Strong"
+	ConsistencySession    = "Session"
+	ConsistencyBounded    = "Bounded"
+	ConsistencyEventually = "Eventually"
+)
+
+// Metric types for collections created through the store.
+const (
+	MetricCosine = "COSINE"
+	MetricL2     = "L2"
+	MetricIP     = "IP"
+)
+
+// response is the envelope of every Milvus REST v2 response. A non-zero Code
+// is an error even when the HTTP status is 200.
+type response struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message,omitempty"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+type createCollectionRequest struct {
+	DBName           string            `json:"dbName,omitempty"`
+	CollectionName   string            `json:"collectionName"`
+	Dimension        int               `json:"dimension"`
+	MetricType       string            `json:"metricType"`
+	IDType           string            `json:"idType"`
+	PrimaryFieldName string            `json:"primaryFieldName"`
+	VectorFieldName  string            `json:"vectorFieldName"`
+	Params           map[string]string `json:"params,omitempty"`
+}
+
+type collectionRequest struct {
+	DBName         string `json:"dbName,omitempty"`
+	CollectionName string `json:"collectionName,omitempty"`
+}
+
+type partitionRequest struct {
+	DBName         string `json:"dbName,omitempty"`
+	CollectionName string `json:"collectionName"`
+	PartitionName  string `json:"partitionName,omitempty"`
+}
+
+type upsertRequest struct {
+	DBName         string           `json:"dbName,omitempty"`
+	CollectionName string           `json:"collectionName"`
+	PartitionName  string           `json:"partitionName,omitempty"`
+	Data           []map[string]any `json:"data"`
+}
+
+type deleteRequest struct {
+	DBName         string `json:"dbName,omitempty"`
+	CollectionName string `json:"collectionName"`
+	PartitionName  string `json:"partitionName,omitempty"`
+	Filter         string `json:"filter"`
+}
+
+type searchRequest struct {
+	DBName           string         `json:"dbName,omitempty"`
+	CollectionName   string         `json:"collectionName"`
+	PartitionNames   []string       `json:"partitionNames,omitempty"`
+	Data             [][]float32    `json:"data"`
+	AnnsField        string         `json:"annsField"`
+	Limit            int            `json:"limit"`
+	Filter           string         `json:"filter,omitempty"`
+	OutputFields     []string       `json:"outputFields,omitempty"`
+	SearchParams     map[string]any `json:"searchParams,omitempty"`
+	ConsistencyLevel string         `json:"consistencyLevel,omitempty"`
+}
+
+type describeCollectionResponse struct {
+	CollectionName string `json:"collectionName"`
+	Fields         []struct {
+		Name   string `json:"name"`
+		Type   string `json:"type"`
+		Params []struct {
+			Key   string `json:"key"`
+			Value any    `json:"value"`
+		} `json:"params"`
+	} `json:"fields"`
+	Indexes []struct {
+		FieldName  string `json:"fieldName"`
+		MetricType string `json:"metricType"`
+	} `json:"indexes"`
+}
@@ -0,0 +1,385 @@
+package milvus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/db/vector"
+	"go.uber.org/zap"
+)
+
+type milvusStore struct {
+	client *Client
+}
+
+// NewStore exposes a Milvus client as a vector.Store. Collections created
+// through it use a VarChar "id" primary key, a "vector" field and dynamic
+// fields for the payload. Scores are similarities: COSINE and IP distances
+// are returned as-is and L2 distances are negated, so higher is always better.
+func NewStore(client *Client) vector.Store {
+	return &milvusStore{client: client}
+}
+
+func (s *milvusStore) Upsert(ctx context.Context, collection string, points []vector.Point) error {
+	if strings.TrimSpace(collection) == "" {
+		return errCollectionRequired
+	}
+	if len(points) == 0 {
+		return errors.New("at least one point is required")
+	}
+
+	req := upsertRequest{
+		DBName:         s.client.dbName,
+		CollectionName: collection,
+		PartitionName:  s.client.partition,
+		Data:           make([]map[string]any, len(points)),
+	}
+	for i, p := range points {
+		if strings.TrimSpace(p.ID) == "" {
+			return errors.New("point ID is required")
+		}
+		row := make(map[string]any, len(p.Payload)+2)
+		for k, v := range p.Payload {
+			row[k] = v
+		}
+		row[primaryField] = p.ID
+		row[vectorField] = p.Vector
+		req.Data[i] = row
+	}
+
+	if err := s.client.do(ctx, "/entities/upsert", req, nil); err != nil {
+		return fmt.Errorf("upsert points to %q: %w", collection, err)
+	}
+	s.client.logger.Debug("upserted points", zap.String("collection", collection), zap.Int("count", len(points)))
+	return nil
+}
+
+func (s *milvusStore) Query(ctx context.Context, req *vector.QueryRequest) ([]vector.Match, error) {
+	if req == nil {
+		return nil, errors.New("QueryRequest is required")
+	}
+	if strings.TrimSpace(req.Collection) == "" {
+		return nil, errCollectionRequired
+	}
+	if req.Hybrid != nil {
+		return nil, vector.ErrHybridUnsupported
+	}
+	if len(req.Vector) == 0 {
+		if req.Text != "" {
+			return nil, vector.ErrTextQueryUnsupported
+		}
+		return nil, errors.New("search vector is required")
+	}
+
+	limit := req.TopK
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+
+	expr, err := filterExpr(req.Filter, req.Where)
+	if err != nil {
+		return nil, err
+	}
+
+	searchReq := searchRequest{
+		DBName:           s.client.dbName,
+		CollectionName:   req.Collection,
+		Data:             [][]float32{req.Vector},
+		AnnsField:        vectorField,
+		Limit:            limit,
+		Filter:           expr,
+		OutputFields:     outputFields(req),
+		SearchParams:     s.client.searchParams,
+		ConsistencyLevel: s.client.consistencyLevel,
+	}
+	if s.client.partition != "" {
+		searchReq.PartitionNames = []string{s.client.partition}
+	}
+
+	var rows []map[string]any
+	if err := s.client.do(ctx, "/entities/search", searchReq, &rows); err != nil {
+		return nil, fmt.Errorf("search %q: %w", req.Collection, err)
+	}
+
+	matches := make([]vector.Match, 0, len(rows))
+	for _, row := range rows {
+		m := vector.Match{ID: fmt.Sprint(row[primaryField])}
+		if d, ok := row[distanceField].(float64); ok {
+			m.Score = float32(d)
+			if s.client.metricType == MetricL2 {
+				m.Score = -m.Score
+			}
+		}
+		if req.MinScore > 0 && m.Score < req.MinScore {
+			continue
+		}
+		if req.WithVector {
+			m.Vector = toFloat32s(row[vectorField])
+		}
+		if req.WithPayload {
+			m.Payload = make(vector.Payload)
+			for k, v := range row {
+				if k != primaryField && k != vectorField && k != distanceField {
+					m.Payload[k] = v
+				}
+			}
+		}
+		matches = append(matches, m)
+	}
+	return matches, nil
+}
+
+func (s *milvusStore) Delete(ctx context.Context, collection string, ids []string) error {
+	if strings.TrimSpace(collection) == "" {
+		return errCollectionRequired
+	}
+	if len(ids) == 0 {
+		return errors.New("at least one point id is required")
+	}
+
+	quoted := make([]string, len(ids))
+	for i, id := range ids {
+		quoted[i] = strconv.Quote(id)
+	}
+	req := deleteRequest{
+		DBName:         s.client.dbName,
+		CollectionName: collection,
+		PartitionName:  s.client.partition,
+		Filter:         fmt.Sprintf("%s in [%s]", primaryField, strings.Join(quoted, ",")),
+	}
+	if err := s.client.do(ctx, "/entities/delete", req, nil); err != nil {
+		return fmt.Errorf("delete points from %q: %w", collection, err)
+	}
+	s.client.logger.Debug("deleted points", zap.String("collection", collection), zap.Int("count", len(ids)))
+	return nil
+}
+
+func (s *milvusStore) Collections(ctx context.Context) ([]vector.Collection, error) {
+	names, err := s.client.ListCollections(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list collections: %w", err)
+	}
+
+	collections := make([]vector.Collection, 0, len(names))
+	for _, name := range names {
+		var desc describeCollectionResponse
+		req := collectionRequest{DBName: s.client.dbName, CollectionName: name}
+		if err := s.client.do(ctx, "/collections/describe", req, &desc); err != nil {
+			return nil, fmt.Errorf("describe collection %q: %w", name, err)
+		}
+		collections = append(collections, collectionFromDescription(name, &desc))
+	}
+	return collections, nil
+}
+
+func (s *milvusStore) CreateCollection(ctx context.Context, collection vector.Collection) error {
+	if strings.TrimSpace(collection.Name) == "" {
+		return errCollectionRequired
+	}
+	if collection.Dimension <= 0 {
+		return errors.New("vector size is required")
+	}
+
+	metricType := s.client.metricType
+	if collection.Distance != "" {
+		metricType = metricFor(collection.Distance)
+	}
+
+	params := map[string]string{"max_length": strconv.Itoa(defaultIDMaxLength)}
+	if s.client.consistencyLevel != "" {
+		params["consistencyLevel"] = s.client.consistencyLevel
+	}
+
+	req := createCollectionRequest{
+		DBName:           s.client.dbName,
+		CollectionName:   collection.Name,
+		Dimension:        collection.Dimension,
+		MetricType:       metricType,
+		IDType:           "VarChar",
+		PrimaryFieldName: primaryField,
+		VectorFieldName:  vectorField,
+		Params:           params,
+	}
+	if err := s.client.do(ctx, "/collections/create", req, nil); err != nil {
+		return fmt.Errorf("create collection %q: %w", collection.Name, err)
+	}
+	s.client.logger.Info("created collection", zap.String("collection", collection.Name))
+	return nil
+}
+
+// filterExpr builds a boolean expression from equality filters and where-clauses.
+func filterExpr(filter vector.Payload, conditions []vector.Condition) (string, error) {
+	keys := make([]string, 0, len(filter))
+	for k := range filter {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	all := make([]vector.Condition, 0, len(keys)+len(conditions))
+	for _, k := range keys {
+		all = append(all, vector.Condition{Field: k, Operator: vector.OpEqual, Value: filter[k]})
+	}
+	all = append(all, conditions...)
+
+	clauses := make([]string, len(all))
+	for i, c := range all {
+		clause, err := conditionExpr(c)
+		if err != nil {
+			return "", err
+		}
+		clauses[i] = clause
+	}
+	return strings.Join(clauses, " and "), nil
+}
+
+var exprOperators = map[string]string{
+	vector.OpEqual:            "==",
+	vector.OpNotEqual:         "!=",
+	vector.OpGreaterThan:      ">",
+	vector.OpGreaterThanEqual: ">=",
+	vector.OpLessThan:         "<",
+	vector.OpLessThanEqual:    "<=",
+}
+
+func conditionExpr(c vector.Condition) (string, error) {
+	if !isIdentifier(c.Field) {
+		return "", fmt.Errorf("unsupported filter field %q", c.Field)
+	}
+
+	switch c.Operator {
+	case vector.OpIn:
+		values, ok := c.Values()
+		if !ok {
+			return "", fmt.Errorf("%q condition on %q needs a non-empty list", c.Operator, c.Field)
+		}
+		literals := make([]string, len(values))
+		for i, v := range values {
+			lit, err := literal(c.Field, v)
+			if err != nil {
+				return "", err
+			}
+			literals[i] = lit
+		}
+		return fmt.Sprintf("%s in [%s]", c.Field, strings.Join(literals, ",")), nil
+	case vector.OpLike:
+		text, ok := c.Value.(string)
+		if !ok {
+			return "", fmt.Errorf("%q condition on %q needs a string", c.Operator, c.Field)
+		}
+		return fmt.Sprintf("%s like %s", c.Field, strconv.Quote("%"+text+"%")), nil
+	}
+
+	op, ok := exprOperators[c.Operator]
+	if !ok {
+		return "", fmt.Errorf("unsupported condition operator %q", c.Operator)
+	}
+	lit, err := literal(c.Field, c.Value)
+	if err != nil {
+		return "", err
+	}
+	return c.Field + " " + op + " " + lit, nil
+}
+
+func literal(field string, value any) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return strconv.Quote(v), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	default:
+		return "", fmt.Errorf("unsupported filter value for %q: %T", field, v)
+	}
+}
+
+func outputFields(req *vector.QueryRequest) []string {
+	var fields []string
+	switch {
+	case req.WithPayload && len(req.Fields) > 0:
+		fields = append(fields, req.Fields...)
+	case req.WithPayload:
+		fields = append(fields, "*")
+	}
+	if req.WithVector {
+		fields = append(fields, vectorField)
+	}
+	return fields
+}
+
+func collectionFromDescription(name string, desc *describeCollectionResponse) vector.Collection {
+	c := vector.Collection{Name: name, Distance: vector.DistanceCosine}
+	for _, f := range desc.Fields {
+		if f.Name != vectorField {
+			continue
+		}
+		for _, p := range f.Params {
+			if p.Key == "dim" {
+				c.Dimension, _ = strconv.Atoi(fmt.Sprint(p.Value))
+			}
+		}
+	}
+	for _, idx := range desc.Indexes {
+		if idx.FieldName == vectorField {
+			c.Distance = storeDistance(idx.MetricType)
+		}
+	}
+	return c
+}
+
+func metricFor(distance string) string {
+	switch strings.ToLower(strings.TrimSpace(distance)) {
+	case "euclid", "l2":
+		return MetricL2
+	case "dot", "ip":
+		return MetricIP
+	default:
+		return MetricCosine
+	}
+}
+
+// storeDistance maps a Milvus metric type onto the vector package's names.
+func storeDistance(metric string) string {
+	switch metric {
+	case MetricL2:
+		return vector.DistanceEuclid
+	case MetricIP:
+		return vector.DistanceDot
+	default:
+		return vector.DistanceCosine
+	}
+}
+
+func toFloat32s(v any) []float32 {
+	values, ok := v.([]any)
+	if !ok {
+		return nil
+	}
+	out := make([]float32, 0, len(values))
+	for _, f := range values {
+		if fl, ok := f.(float64); ok {
+			out = append(out, float32(fl))
+		}
+	}
+	return out
+}
+
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		if r != '_' && !(r >= 'a' && r <= 'z') && !(r >= 'A' && r <= 'Z') && !(i > 0 && r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
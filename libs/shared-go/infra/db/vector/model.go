@@ -0,0 +1,125 @@
+package vector
+
+import "errors"
+
+var (
+	// ErrTextQueryUnsupported is returned by Query when req.Text is set on a
+	// backend that cannot vectorize queries itself.
+	ErrTextQueryUnsupported = errors.New("text queries are not supported by this vector store")
+
+	// ErrHybridUnsupported is returned by Query when req.Hybrid is set on a
+	// backend without keyword (BM25) search.
+	ErrHybridUnsupported = errors.New("hybrid search is not supported by this vector store")
+)
+
+// Condition operators.
+const (
+	OpEqual            = "eq"
+	OpNotEqual         = "ne"
+	OpGreaterThan      = "gt"
+	OpGreaterThanEqual = "gte"
+	OpLessThan         = "lt"
+	OpLessThanEqual    = "lte"
+	OpIn               = "in"   // Value is a slice; the field equals any element
+	OpLike             = "like" // Value is a string the field contains
+)
+
+// Distance metrics.
+const (
+	DistanceCosine = "cosine"
+	DistanceEuclid = "euclid"
+	DistanceDot    = "dot"
+)
+
+type Payload map[string]interface{}
+
+type Point struct {
+	ID      string    `json:"id"`
+	Vector  []float32 `json:"vector"`
+	Payload Payload   `json:"payload,omitempty"`
+}
+
+type Collection struct {
+	Name      string `json:"name"`
+	Dimension int    `json:"dimension,omitempty"` // 0 when the backend does not report it
+	Distance  string `json:"distance,omitempty"`  // cosine, euclid or dot (default: cosine)
+}
+
+type QueryRequest struct {
+	Collection string    `json:"collection" validate:"required"`
+	Vector     []float32 `json:"vector,omitempty"`
+	Text       string    `json:"text,omitempty"` // used when Vector is empty; see ErrTextQueryUnsupported
+	TopK       int       `json:"top_k,omitempty"`
+	MinScore   float32   `json:"min_score,omitempty"`
+
+	// Filter keeps only points whose payload equals every key/value pair.
+	Filter Payload `json:"filter,omitempty"`
+
+	// Where keeps only points matching every condition, in addition to Filter.
+	Where []Condition `json:"where,omitempty"`
+
+	// Hybrid blends keyword (BM25) relevance with vector similarity. Vector,
+	// if set, is used for the vector half; otherwise the backend vectorizes
+	// Hybrid.Query. See ErrHybridUnsupported.
+	Hybrid *Hybrid `json:"hybrid,omitempty"`
+
+	// Fields are the payload fields to return; some backends return the
+	// whole payload regardless.
+	Fields      []string `json:"fields,omitempty"`
+	WithPayload bool     `json:"with_payload,omitempty"`
+	WithVector  bool     `json:"with_vector,omitempty"`
+}
+
+type Match struct {
+	ID      string    `json:"id"`
+	Score   float32   `json:"score"`
+	Payload Payload   `json:"payload,omitempty"`
+	Vector  []float32 `json:"vector,omitempty"`
+}
+
+// Condition is a metadata where-clause such as {"year", OpGreaterThan, 2020}.
+type Condition struct {
+	Field    string `json:"field"`
+	Operator string `json:"operator"`
+	Value    any    `json:"value"`
+}
+
+type Hybrid struct {
+	Query string `json:"query"`
+
+	// Alpha weights the two scores: 0 is pure keyword search, 1 pure vector
+	// search. Nil uses the backend default (0.75 for Weaviate).
+	Alpha *float32 `json:"alpha,omitempty"`
+
+	// Properties limits keyword search to these payload fields; empty searches
+	// all text fields.
+	Properties []string `json:"properties,omitempty"`
+}
+
+// Values returns the list operand of an OpIn condition.
+func (c Condition) Values() ([]any, bool) {
+	switch v := c.Value.(type) {
+	case []any:
+		return v, len(v) > 0
+	case []string:
+		out := make([]any, len(v))
+		for i, s := range v {
+			out[i] = s
+		}
+		return out, len(out) > 0
+	case []int:
+		out := make([]any, len(v))
+		for i, n := range v {
+			out[i] = n
+		}
+		return out, len(out) > 0
+	case []float64:
+		out := make([]any, len(v))
+		for i, f := range v {
+			out[i] = f
+		}
+		return out, len(out) > 0
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,110 @@
+package qdrant
+
+import "encoding/json"
+
+// HNSWConfig tunes the HNSW index of new collections. Zero fields use the
+// server defaults (m=16, ef_construct=100).
+type HNSWConfig struct {
+	M                 int  `json:"m,omitempty"`
+	EfConstruct       int  `json:"ef_construct,omitempty"`
+	FullScanThreshold int  `json:"full_scan_threshold,omitempty"`
+	OnDisk            bool `json:"on_disk,omitempty"`
+}
+
+type vectorParams struct {
+	Size     int    `json:"size"`
+	Distance string `json:"distance"` // Cosine, Euclid, Dot
+}
+
+type createCollectionRequest struct {
+	Vectors    vectorParams `json:"vectors"`
+	HNSWConfig *HNSWConfig  `json:"hnsw_config,omitempty"`
+}
+
+type point struct {
+	ID      string         `json:"id"`
+	Vector  []float32      `json:"vector"`
+	Payload map[string]any `json:"payload,omitempty"`
+}
+
+type upsertRequest struct {
+	Points []point `json:"points"`
+}
+
+type deleteRequest struct {
+	Points []string `json:"points"`
+}
+
+// filter is a Qdrant payload filter; every condition in Must has to match
+// and none in MustNot.
+type filter struct {
+	Must    []condition `json:"must,omitempty"`
+	MustNot []condition `json:"must_not,omitempty"`
+}
+
+type condition struct {
+	Key   string    `json:"key"`
+	Match *match    `json:"match,omitempty"`
+	Range *rangeCnd `json:"range,omitempty"`
+}
+
+type match struct {
+	Value any    `json:"value,omitempty"`
+	Any   []any  `json:"any,omitempty"`
+	Text  string `json:"text,omitempty"`
+}
+
+type rangeCnd struct {
+	GT  any `json:"gt,omitempty"`
+	GTE any `json:"gte,omitempty"`
+	LT  any `json:"lt,omitempty"`
+	LTE any `json:"lte,omitempty"`
+}
+
+type searchParams struct {
+	HNSWEf int `json:"hnsw_ef,omitempty"`
+}
+
+type searchRequest struct {
+	Vector         []float32     `json:"vector"`
+	Limit          int           `json:"limit"`
+	ScoreThreshold *float32      `json:"score_threshold,omitempty"`
+	Filter         *filter       `json:"filter,omitempty"`
+	Params         *searchParams `json:"params,omitempty"`
+	WithPayload    any           `json:"with_payload"` // bool or list of fields
+	WithVector     bool          `json:"with_vector"`
+}
+
+type scoredPoint struct {
+	ID      json.RawMessage `json:"id"` // UUID string or unsigned integer
+	Score   float32         `json:"score"`
+	Payload map[string]any  `json:"payload,omitempty"`
+	Vector  []float32       `json:"vector,omitempty"`
+}
+
+type collectionsResult struct {
+	Collections []struct {
+		Name string `json:"name"`
+	} `json:"collections"`
+}
+
+type collectionInfo struct {
+	Config struct {
+		Params struct {
+			Vectors vectorParams `json:"vectors"`
+		} `json:"params"`
+	} `json:"config"`
+}
+
+// response is the envelope of every Qdrant REST response.
+type response[T any] struct {
+	Result T       `json:"result"`
+	Status any     `json:"status"` // "ok", or {"error": "..."} on failure
+	Time   float64 `json:"time"`
+}
+
+type errorStatus struct {
+	Status struct {
+		Error string `json:"error"`
+	} `json:"status"`
+}
@@ -0,0 +1,155 @@
+package qdrant
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultURL     = "http://localhost:6333"
+	defaultTimeout = 30 * time.Second
+	defaultLimit   = 10
+)
+
+var errCollectionRequired = errors.New("collection name is required")
+
+type QdrantConfig struct {
+	URL     string        // e.g. "http://localhost:6333" (REST port)
+	APIKey  string        // sent as the api-key header
+	Timeout time.Duration // per request (default: 30s)
+
+	// HNSW is applied to collections created through the store.
+	HNSW *HNSWConfig
+
+	// SearchEf is the HNSW ef used at query time; 0 uses the server default.
+	SearchEf int
+
+	// Wait makes writes return only once they are applied.
+	Wait bool
+}
+
+type Client struct {
+	url        string
+	apiKey     string
+	hnsw       *HNSWConfig
+	searchEf   int
+	wait       bool
+	httpClient *http.Client
+	logger     *zap.Logger
+}
+
+func NewQdrantClient(cfg QdrantConfig, logger *zap.Logger) (*Client, error) {
+	url := strings.TrimRight(strings.TrimSpace(cfg.URL), "/")
+	if url == "" {
+		url = defaultURL
+	}
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return nil, fmt.Errorf("unsupported qdrant url: %s", cfg.URL)
+	}
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	return &Client{
+		url:        url,
+		apiKey:     cfg.APIKey,
+		hnsw:       cfg.HNSW,
+		searchEf:   cfg.SearchEf,
+		wait:       cfg.Wait,
+		httpClient: &http.Client{Timeout: timeout},
+		logger:     logger,
+	}, nil
+}
+
+// Health reports whether Qdrant is ready to serve requests.
+func (c *Client) Health(ctx context.Context) error {
+	req, err := c.newRequest(ctx, http.MethodGet, "/readyz", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("check qdrant readiness: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("qdrant is not ready (status %d)", resp.StatusCode)
+	}
+	return nil
+}
+
+// do sends a JSON request and decodes the result field of the response into out.
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		jsonData, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("marshal qdrant request: %w", err)
+		}
+		reader = bytes.NewReader(jsonData)
+	}
+
+	req, err := c.newRequest(ctx, method, path, reader)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("qdrant %s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read qdrant response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var e errorStatus
+		if json.Unmarshal(raw, &e) == nil && e.Status.Error != "" {
+			return fmt.Errorf("qdrant API error (status %d): %s", resp.StatusCode, e.Status.Error)
+		}
+		return fmt.Errorf("qdrant API error (status %d): %s", resp.StatusCode, string(raw))
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return fmt.Errorf("unmarshal qdrant response: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) newRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.url+path, body)
+	if err != nil {
+		return nil, fmt.Errorf("create qdrant request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.apiKey != "" {
+		req.Header.Set("api-key", c.apiKey)
+	}
+	return req, nil
+}
+
+func (c *Client) waitQuery() string {
+	if c.wait {
+		return "?wait=true"
+	}
+	return ""
+}
@@ -0,0 +1,245 @@
+package qdrant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/db/vector"
+	"go.uber.org/zap"
+)
+
+type qdrantStore struct {
+	client *Client
+}
+
+// NewStore exposes a Qdrant client as a vector.Store. Point IDs must be
+// UUIDs or unsigned integers, as required by Qdrant.
+func NewStore(client *Client) vector.Store {
+	return &qdrantStore{client: client}
+}
+
+func (s *qdrantStore) Upsert(ctx context.Context, collection string, points []vector.Point) error {
+	if strings.TrimSpace(collection) == "" {
+		return errCollectionRequired
+	}
+	if len(points) == 0 {
+		return errors.New("at least one point is required")
+	}
+
+	req := upsertRequest{Points: make([]point, len(points))}
+	for i, p := range points {
+		if strings.TrimSpace(p.ID) == "" {
+			return errors.New("point ID is required")
+		}
+		req.Points[i] = point{ID: p.ID, Vector: p.Vector, Payload: p.Payload}
+	}
+
+	path := "/collections/" + url.PathEscape(collection) + "/points" + s.client.waitQuery()
+	if err := s.client.do(ctx, http.MethodPut, path, req, nil); err != nil {
+		return fmt.Errorf("upsert points to %q: %w", collection, err)
+	}
+	s.client.logger.Debug("upserted points", zap.String("collection", collection), zap.Int("count", len(points)))
+	return nil
+}
+
+func (s *qdrantStore) Query(ctx context.Context, req *vector.QueryRequest) ([]vector.Match, error) {
+	if req == nil {
+		return nil, errors.New("QueryRequest is required")
+	}
+	if strings.TrimSpace(req.Collection) == "" {
+		return nil, errCollectionRequired
+	}
+	if req.Hybrid != nil {
+		return nil, vector.ErrHybridUnsupported
+	}
+	if len(req.Vector) == 0 {
+		if req.Text != "" {
+			return nil, vector.ErrTextQueryUnsupported
+		}
+		return nil, errors.New("search vector is required")
+	}
+
+	limit := req.TopK
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+
+	f, err := buildFilter(req.Filter, req.Where)
+	if err != nil {
+		return nil, err
+	}
+
+	searchReq := searchRequest{
+		Vector:      req.Vector,
+		Limit:       limit,
+		Filter:      f,
+		WithPayload: req.WithPayload,
+		WithVector:  req.WithVector,
+	}
+	if req.WithPayload && len(req.Fields) > 0 {
+		searchReq.WithPayload = req.Fields
+	}
+	if req.MinScore > 0 {
+		searchReq.ScoreThreshold = &req.MinScore
+	}
+	if s.client.searchEf > 0 {
+		searchReq.Params = &searchParams{HNSWEf: s.client.searchEf}
+	}
+
+	var resp response[[]scoredPoint]
+	path := "/collections/" + url.PathEscape(req.Collection) + "/points/search"
+	if err := s.client.do(ctx, http.MethodPost, path, searchReq, &resp); err != nil {
+		return nil, fmt.Errorf("search %q: %w", req.Collection, err)
+	}
+
+	matches := make([]vector.Match, len(resp.Result))
+	for i, p := range resp.Result {
+		matches[i] = vector.Match{
+			ID:      strings.Trim(string(p.ID), `"`),
+			Score:   p.Score,
+			Payload: vector.Payload(p.Payload),
+			Vector:  p.Vector,
+		}
+	}
+	return matches, nil
+}
+
+func (s *qdrantStore) Delete(ctx context.Context, collection string, ids []string) error {
+	if strings.TrimSpace(collection) == "" {
+		return errCollectionRequired
+	}
+	if len(ids) == 0 {
+		return errors.New("at least one point id is required")
+	}
+
+	path := "/collections/" + url.PathEscape(collection) + "/points/delete" + s.client.waitQuery()
+	if err := s.client.do(ctx, http.MethodPost, path, deleteRequest{Points: ids}, nil); err != nil {
+		return fmt.Errorf("delete points from %q: %w", collection, err)
+	}
+	s.client.logger.Debug("deleted points", zap.String("collection", collection), zap.Int("count", len(ids)))
+	return nil
+}
+
+func (s *qdrantStore) Collections(ctx context.Context) ([]vector.Collection, error) {
+	var list response[collectionsResult]
+	if err := s.client.do(ctx, http.MethodGet, "/collections", nil, &list); err != nil {
+		return nil, fmt.Errorf("list collections: %w", err)
+	}
+
+	collections := make([]vector.Collection, 0, len(list.Result.Collections))
+	for _, c := range list.Result.Collections {
+		var info response[collectionInfo]
+		if err := s.client.do(ctx, http.MethodGet, "/collections/"+url.PathEscape(c.Name), nil, &info); err != nil {
+			return nil, fmt.Errorf("get collection %q: %w", c.Name, err)
+		}
+		params := info.Result.Config.Params.Vectors
+		collections = append(collections, vector.Collection{
+			Name:      c.Name,
+			Dimension: params.Size,
+			Distance:  storeDistance(params.Distance),
+		})
+	}
+	return collections, nil
+}
+
+func (s *qdrantStore) CreateCollection(ctx context.Context, collection vector.Collection) error {
+	if strings.TrimSpace(collection.Name) == "" {
+		return errCollectionRequired
+	}
+	if collection.Dimension <= 0 {
+		return errors.New("vector size is required")
+	}
+
+	req := createCollectionRequest{
+		Vectors: vectorParams{
+			Size:     collection.Dimension,
+			Distance: normalizeDistance(collection.Distance),
+		},
+		HNSWConfig: s.client.hnsw,
+	}
+	if err := s.client.do(ctx, http.MethodPut, "/collections/"+url.PathEscape(collection.Name), req, nil); err != nil {
+		return fmt.Errorf("create collection %q: %w", collection.Name, err)
+	}
+	s.client.logger.Info("created collection", zap.String("collection", collection.Name))
+	return nil
+}
+
+// buildFilter combines equality filters and where-clauses into one filter,
+// or returns nil if there are none.
+func buildFilter(payload vector.Payload, conditions []vector.Condition) (*filter, error) {
+	keys := make([]string, 0, len(payload))
+	for k := range payload {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	f := &filter{}
+	for _, k := range keys {
+		f.Must = append(f.Must, condition{Key: k, Match: &match{Value: payload[k]}})
+	}
+
+	for _, c := range conditions {
+		switch c.Operator {
+		case vector.OpEqual:
+			f.Must = append(f.Must, condition{Key: c.Field, Match: &match{Value: c.Value}})
+		case vector.OpNotEqual:
+			f.MustNot = append(f.MustNot, condition{Key: c.Field, Match: &match{Value: c.Value}})
+		case vector.OpGreaterThan:
+			f.Must = append(f.Must, condition{Key: c.Field, Range: &rangeCnd{GT: c.Value}})
+		case vector.OpGreaterThanEqual:
+			f.Must = append(f.Must, condition{Key: c.Field, Range: &rangeCnd{GTE: c.Value}})
+		case vector.OpLessThan:
+			f.Must = append(f.Must, condition{Key: c.Field, Range: &rangeCnd{LT: c.Value}})
+		case vector.OpLessThanEqual:
+			f.Must = append(f.Must, condition{Key: c.Field, Range: &rangeCnd{LTE: c.Value}})
+		case vector.OpIn:
+			values, ok := c.Values()
+			if !ok {
+				return nil, fmt.Errorf("%q condition on %q needs a non-empty list", c.Operator, c.Field)
+			}
+			f.Must = append(f.Must, condition{Key: c.Field, Match: &match{Any: values}})
+		case vector.OpLike:
+			text, ok := c.Value.(string)
+			if !ok {
+				return nil, fmt.Errorf("%q condition on %q needs a string", c.Operator, c.Field)
+			}
+			// Requires a full-text payload index on the field.
+			f.Must = append(f.Must, condition{Key: c.Field, Match: &match{Text: text}})
+		default:
+			return nil, fmt.Errorf("unsupported condition operator %q", c.Operator)
+		}
+	}
+
+	if len(f.Must) == 0 && len(f.MustNot) == 0 {
+		return nil, nil
+	}
+	return f, nil
+}
+
+func normalizeDistance(d string) string {
+	switch strings.ToLower(strings.TrimSpace(d)) {
+	case "euclid", "l2":
+		return "Euclid"
+	case "dot":
+		return "Dot"
+	default:
+		return "Cosine"
+	}
+}
+
+// storeDistance maps a Qdrant distance name onto the vector package's names.
+func storeDistance(d string) string {
+	switch d {
+	case "Euclid":
+		return vector.DistanceEuclid
+	case "Dot":
+		return vector.DistanceDot
+	default:
+		return vector.DistanceCosine
+	}
+}
@@ -5,6 +5,7 @@ import "context"
 // Service defines the high-level vector store operations for RAG:
 // create collection, upsert points, similarity search, delete, and get by IDs.
 type Service interface {
+	Health(ctx context.Context) error
 	CreateCollection(ctx context.Context, req *CreateCollectionRequest) error
 	UpsertPoints(ctx context.Context, req *UpsertPointsRequest) error
 	Search(ctx context.Context, req *SearchRequest) (*SearchResponse, error)
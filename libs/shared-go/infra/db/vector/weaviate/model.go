@@ -1,5 +1,7 @@
 package weaviate
 
+import "github.com/Joepolymath/DaVinci/libs/shared-go/infra/db/vector"
+
 type Vector []float32
 
 type Payload map[string]interface{}
@@ -11,9 +13,19 @@ type Point struct {
 }
 
 type CreateCollectionRequest struct {
-	CollectionName string `json:"collection_name" validate:"required"`
-	VectorSize     uint64 `json:"vector_size" validate:"required,min=1"`
-	Distance       string `json:"distance,omitempty"` // Cosine, Euclid, Dot
+	CollectionName string     `json:"collection_name" validate:"required"`
+	VectorSize     uint64     `json:"vector_size" validate:"required,min=1"`
+	Distance       string     `json:"distance,omitempty"`   // Cosine, Euclid, Dot
+	Vectorizer     string     `json:"vectorizer,omitempty"` // e.g. "text2vec-openai"; default "none" (vectors supplied by caller)
+	Properties     []Property `json:"properties,omitempty"`
+}
+
+// Property declares a class property. DataType is a Weaviate data type such
+// as "text", "int", "number", "boolean", "date" or "text[]".
+type Property struct {
+	Name        string `json:"name" validate:"required"`
+	DataType    string `json:"data_type" validate:"required"`
+	Description string `json:"description,omitempty"`
 }
 
 type UpsertPointsRequest struct {
@@ -22,14 +34,21 @@ type UpsertPointsRequest struct {
 	Wait           bool    `json:"wait,omitempty"` // Wait for indexing to complete
 }
 
+// SearchRequest runs a near-vector search, or a near-text search on Query when
+// Vector is empty (the collection needs a vectorizer module for near-text).
 type SearchRequest struct {
 	CollectionName string   `json:"collection_name" validate:"required"`
-	Vector         Vector   `json:"vector" validate:"required"`
+	Vector         Vector   `json:"vector,omitempty"`
+	Query          string   `json:"query,omitempty"`
 	Limit          uint64   `json:"limit,omitempty"`           // Number of results
 	ScoreThreshold float32  `json:"score_threshold,omitempty"` // Minimum similarity score
 	Filter         *Payload `json:"filter,omitempty"`          // Optional metadata filter
 	WithPayload    bool     `json:"with_payload,omitempty"`    // Include payload in results
 	WithVector     bool     `json:"with_vector,omitempty"`     // Include vector in results
+	Properties     []string `json:"properties,omitempty"`      // Payload properties to return with WithPayload
+
+	Where  []vector.Condition `json:"where,omitempty"`  // Optional where-clauses, combined with Filter
+	Hybrid *vector.Hybrid     `json:"hybrid,omitempty"` // Hybrid BM25 + vector search; ScoreThreshold is ignored
 }
 
 type SearchResult struct {
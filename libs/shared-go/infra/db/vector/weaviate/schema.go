@@ -0,0 +1,191 @@
+package weaviate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	weavLib "github.com/weaviate/weaviate-go-client/v5/weaviate"
+	"github.com/weaviate/weaviate/entities/models"
+	"go.uber.org/zap"
+)
+
+// ClassSchema declares a class the application expects to exist.
+type ClassSchema struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	Vectorizer  string     `json:"vectorizer,omitempty"` // default "none"
+	Distance    string     `json:"distance,omitempty"`   // Cosine, Euclid, Dot
+	Properties  []Property `json:"properties,omitempty"`
+}
+
+// MigrationPlan is the difference between the declared and the live schema.
+// Only additive changes are applied; Conflicts lists differences that need a
+// manual migration (e.g. a property whose data type changed).
+type MigrationPlan struct {
+	CreateClasses []ClassSchema         `json:"create_classes,omitempty"`
+	AddProperties map[string][]Property `json:"add_properties,omitempty"`
+	Conflicts     []string              `json:"conflicts,omitempty"`
+}
+
+// Empty reports whether the plan has no changes to apply.
+func (p *MigrationPlan) Empty() bool {
+	return len(p.CreateClasses) == 0 && len(p.AddProperties) == 0
+}
+
+// Migrator keeps the live Weaviate schema in line with declared classes.
+type Migrator struct {
+	client *weavLib.Client
+	logger *zap.Logger
+	dryRun bool
+}
+
+// NewMigrator creates a migrator. In dry-run mode Migrate logs the plan
+// without changing the schema.
+func NewMigrator(client *weavLib.Client, logger *zap.Logger, dryRun bool) *Migrator {
+	return &Migrator{client: client, logger: logger, dryRun: dryRun}
+}
+
+// Plan diffs the declared classes against the live schema.
+func (m *Migrator) Plan(ctx context.Context, classes []ClassSchema) (*MigrationPlan, error) {
+	live, err := m.client.Schema().Getter().Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get weaviate schema: %w", err)
+	}
+	return diffSchema(classes, &live.Schema), nil
+}
+
+// Migrate plans and, unless in dry-run mode, applies the additive changes.
+// Conflicts are logged but do not stop the migration.
+func (m *Migrator) Migrate(ctx context.Context, classes []ClassSchema) (*MigrationPlan, error) {
+	for _, c := range classes {
+		if strings.TrimSpace(c.Name) == "" {
+			return nil, errors.New("class name is required")
+		}
+	}
+
+	plan, err := m.Plan(ctx, classes)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, conflict := range plan.Conflicts {
+		m.logger.Warn("schema conflict requires manual migration", zap.String("conflict", conflict))
+	}
+	if plan.Empty() {
+		m.logger.Info("schema is up to date", zap.Int("classes", len(classes)))
+		return plan, nil
+	}
+
+	for _, c := range plan.CreateClasses {
+		m.logger.Info("create class", zap.String("class", c.Name), zap.Bool("dry_run", m.dryRun))
+		if m.dryRun {
+			continue
+		}
+		if err := m.client.Schema().ClassCreator().WithClass(toModelClass(c)).Do(ctx); err != nil {
+			return plan, fmt.Errorf("create class %q: %w", c.Name, err)
+		}
+	}
+
+	for class, props := range plan.AddProperties {
+		for _, p := range props {
+			m.logger.Info("add property",
+				zap.String("class", class),
+				zap.String("property", p.Name),
+				zap.String("data_type", p.DataType),
+				zap.Bool("dry_run", m.dryRun))
+			if m.dryRun {
+				continue
+			}
+			err := m.client.Schema().PropertyCreator().
+				WithClassName(class).
+				WithProperty(toModelProperty(p)).
+				Do(ctx)
+			if err != nil {
+				return plan, fmt.Errorf("add property %q to %q: %w", p.Name, class, err)
+			}
+		}
+	}
+
+	return plan, nil
+}
+
+func diffSchema(classes []ClassSchema, live *models.Schema) *MigrationPlan {
+	plan := &MigrationPlan{AddProperties: make(map[string][]Property)}
+
+	for _, want := range classes {
+		have := findClass(live, want.Name)
+		if have == nil {
+			plan.CreateClasses = append(plan.CreateClasses, want)
+			continue
+		}
+
+		for _, p := range want.Properties {
+			existing := findProperty(have, p.Name)
+			switch {
+			case existing == nil:
+				plan.AddProperties[have.Class] = append(plan.AddProperties[have.Class], p)
+			case !strings.EqualFold(strings.Join(existing.DataType, ","), p.DataType):
+				plan.Conflicts = append(plan.Conflicts, fmt.Sprintf("%s.%s: data type is %v, declared %s",
+					have.Class, p.Name, existing.DataType, p.DataType))
+			}
+		}
+
+		if want.Vectorizer != "" && have.Vectorizer != want.Vectorizer {
+			plan.Conflicts = append(plan.Conflicts, fmt.Sprintf("%s: vectorizer is %q, declared %q",
+				have.Class, have.Vectorizer, want.Vectorizer))
+		}
+	}
+
+	if len(plan.AddProperties) == 0 {
+		plan.AddProperties = nil
+	}
+	return plan
+}
+
+// findClass looks a class up by name. Weaviate capitalises class names, so
+// the comparison ignores case.
+func findClass(schema *models.Schema, name string) *models.Class {
+	if schema == nil {
+		return nil
+	}
+	for _, c := range schema.Classes {
+		if strings.EqualFold(c.Class, name) {
+			return c
+		}
+	}
+	return nil
+}
+
+func findProperty(class *models.Class, name string) *models.Property {
+	for _, p := range class.Properties {
+		if strings.EqualFold(p.Name, name) {
+			return p
+		}
+	}
+	return nil
+}
+
+func toModelClass(c ClassSchema) *models.Class {
+	vectorizer := c.Vectorizer
+	if vectorizer == "" {
+		vectorizer = "none"
+	}
+	properties := make([]*models.Property, len(c.Properties))
+	for i, p := range c.Properties {
+		properties[i] = toModelProperty(p)
+	}
+	return &models.Class{
+		Class:             c.Name,
+		Description:       c.Description,
+		Vectorizer:        vectorizer,
+		VectorIndexType:   "hnsw",
+		VectorIndexConfig: map[string]interface{}{"distance": normalizeDistance(c.Distance)},
+		Properties:        properties,
+	}
+}
+
+func toModelProperty(p Property) *models.Property {
+	return &models.Property{Name: p.Name, DataType: []string{p.DataType}, Description: p.Description}
+}
@@ -4,8 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/db/vector"
 	"github.com/go-openapi/strfmt"
 	weavLib "github.com/weaviate/weaviate-go-client/v5/weaviate"
 	"github.com/weaviate/weaviate-go-client/v5/weaviate/filters"
@@ -26,6 +30,18 @@ func NewService(client *weavLib.Client, logger *zap.Logger) Service {
 	}
 }
 
+// Health reports whether Weaviate is ready to serve requests.
+func (s *weaviateService) Health(ctx context.Context) error {
+	ready, err := s.client.Misc().ReadyChecker().Do(ctx)
+	if err != nil {
+		return fmt.Errorf("check weaviate readiness: %w", err)
+	}
+	if !ready {
+		return errors.New("weaviate is not ready")
+	}
+	return nil
+}
+
 func (s *weaviateService) CreateCollection(ctx context.Context, req *CreateCollectionRequest) error {
 	if req == nil {
 		return errors.New("CreateCollectionRequest is required")
@@ -37,13 +53,26 @@ func (s *weaviateService) CreateCollection(ctx context.Context, req *CreateColle
 		return errors.New("vector size is required")
 	}
 
+	vectorizer := req.Vectorizer
+	if vectorizer == "" {
+		vectorizer = "none"
+	}
+
+	properties := make([]*models.Property, 0, len(req.Properties))
+	for _, p := range req.Properties {
+		if strings.TrimSpace(p.Name) == "" || strings.TrimSpace(p.DataType) == "" {
+			return errors.New("property name and data type are required")
+		}
+		properties = append(properties, &models.Property{Name: p.Name, DataType: []string{p.DataType}})
+	}
+
 	distance := normalizeDistance(req.Distance)
 	class := &models.Class{
 		Class:             req.CollectionName,
-		Vectorizer:        "none",
+		Vectorizer:        vectorizer,
 		VectorIndexType:   "hnsw",
 		VectorIndexConfig: map[string]interface{}{"distance": distance},
-		Properties:        []*models.Property{},
+		Properties:        properties,
 	}
 
 	err := s.client.Schema().ClassCreator().WithClass(class).Do(ctx)
@@ -98,8 +127,8 @@ func (s *weaviateService) Search(ctx context.Context, req *SearchRequest) (*Sear
 	if strings.TrimSpace(req.CollectionName) == "" {
 		return nil, errors.New("collection name is required")
 	}
-	if len(req.Vector) == 0 {
-		return nil, errors.New("search vector is required")
+	if len(req.Vector) == 0 && strings.TrimSpace(req.Query) == "" && req.Hybrid == nil {
+		return nil, errors.New("search vector or query is required")
 	}
 
 	limit := int(req.Limit)
@@ -107,23 +136,52 @@ func (s *weaviateService) Search(ctx context.Context, req *SearchRequest) (*Sear
 		limit = 10
 	}
 
-	nearVector := s.client.GraphQL().NearVectorArgBuilder().
-		WithVector(req.Vector)
-	if req.ScoreThreshold > 0 {
-		nearVector = nearVector.WithCertainty(req.ScoreThreshold)
-	}
-
-	additionalFields := "_additional { id certainty"
-	if req.WithVector {
-		additionalFields += " vector"
-	}
-	additionalFields += " }"
-
 	builder := s.client.GraphQL().Get().
 		WithClassName(req.CollectionName).
 		WithLimit(limit).
-		WithNearVector(nearVector).
-		WithFields(graphql.Field{Name: additionalFields})
+		WithFields(searchFields(req)...)
+
+	var filter Payload
+	if req.Filter != nil {
+		filter = *req.Filter
+	}
+	where, err := buildWhere(filter, req.Where)
+	if err != nil {
+		return nil, err
+	}
+	if where != nil {
+		builder = builder.WithWhere(where)
+	}
+
+	switch {
+	case req.Hybrid != nil:
+		hybrid := s.client.GraphQL().HybridArgumentBuilder().
+			WithQuery(req.Hybrid.Query)
+		if req.Hybrid.Alpha != nil {
+			hybrid = hybrid.WithAlpha(*req.Hybrid.Alpha)
+		}
+		if len(req.Hybrid.Properties) > 0 {
+			hybrid = hybrid.WithProperties(req.Hybrid.Properties)
+		}
+		if len(req.Vector) > 0 {
+			hybrid = hybrid.WithVector([]float32(req.Vector))
+		}
+		builder = builder.WithHybrid(hybrid)
+	case len(req.Vector) > 0:
+		nearVector := s.client.GraphQL().NearVectorArgBuilder().
+			WithVector(req.Vector)
+		if req.ScoreThreshold > 0 {
+			nearVector = nearVector.WithCertainty(req.ScoreThreshold)
+		}
+		builder = builder.WithNearVector(nearVector)
+	default:
+		nearText := s.client.GraphQL().NearTextArgBuilder().
+			WithConcepts([]string{req.Query})
+		if req.ScoreThreshold > 0 {
+			nearText = nearText.WithCertainty(req.ScoreThreshold)
+		}
+		builder = builder.WithNearText(nearText)
+	}
 
 	resp, err := builder.Do(ctx)
 	if err != nil {
@@ -215,6 +273,126 @@ func (s *weaviateService) GetPointsByIDs(ctx context.Context, req *GetPointsByID
 	return &GetPointsByIDsResponse{Points: points}, nil
 }
 
+// searchFields returns the requested payload properties plus the _additional
+// id, certainty and (optionally) vector fields.
+func searchFields(req *SearchRequest) []graphql.Field {
+	additionalFields := "_additional { id certainty"
+	if req.Hybrid != nil {
+		additionalFields = "_additional { id score"
+	}
+	if req.WithVector {
+		additionalFields += " vector"
+	}
+	additionalFields += " }"
+
+	fields := make([]graphql.Field, 0, len(req.Properties)+1)
+	if req.WithPayload {
+		for _, p := range req.Properties {
+			fields = append(fields, graphql.Field{Name: p})
+		}
+	}
+	return append(fields, graphql.Field{Name: additionalFields})
+}
+
+// buildWhere combines equality filters and where-clauses into one filter,
+// or returns nil if there are none.
+func buildWhere(filter Payload, conditions []vector.Condition) (*filters.WhereBuilder, error) {
+	keys := make([]string, 0, len(filter))
+	for k := range filter {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	all := make([]vector.Condition, 0, len(keys)+len(conditions))
+	for _, k := range keys {
+		all = append(all, vector.Condition{Field: k, Operator: vector.OpEqual, Value: filter[k]})
+	}
+	all = append(all, conditions...)
+
+	operands := make([]*filters.WhereBuilder, 0, len(all))
+	for _, c := range all {
+		w, err := whereFromCondition(c)
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, w)
+	}
+
+	switch len(operands) {
+	case 0:
+		return nil, nil
+	case 1:
+		return operands[0], nil
+	default:
+		return filters.Where().
+			WithOperator(filters.And).
+			WithOperands(operands), nil
+	}
+}
+
+func whereFromCondition(c vector.Condition) (*filters.WhereBuilder, error) {
+	switch c.Operator {
+	case vector.OpIn:
+		values, ok := c.Values()
+		if !ok {
+			return nil, fmt.Errorf("%q condition on %q needs a non-empty list", c.Operator, c.Field)
+		}
+		operands := make([]*filters.WhereBuilder, len(values))
+		for i, v := range values {
+			w, err := whereFromCondition(vector.Condition{Field: c.Field, Operator: vector.OpEqual, Value: v})
+			if err != nil {
+				return nil, err
+			}
+			operands[i] = w
+		}
+		return filters.Where().
+			WithOperator(filters.Or).
+			WithOperands(operands), nil
+	case vector.OpLike:
+		s, ok := c.Value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%q condition on %q needs a string", c.Operator, c.Field)
+		}
+		return filters.Where().
+			WithPath([]string{c.Field}).
+			WithOperator(filters.Like).
+			WithValueText("*" + s + "*"), nil
+	}
+
+	operator, ok := whereOperators[c.Operator]
+	if !ok {
+		return nil, fmt.Errorf("unsupported condition operator %q", c.Operator)
+	}
+	w := filters.Where().
+		WithPath([]string{c.Field}).
+		WithOperator(operator)
+	switch v := c.Value.(type) {
+	case string:
+		return w.WithValueText(v), nil
+	case bool:
+		return w.WithValueBoolean(v), nil
+	case int:
+		return w.WithValueInt(int64(v)), nil
+	case int64:
+		return w.WithValueInt(v), nil
+	case float64:
+		return w.WithValueNumber(v), nil
+	case time.Time:
+		return w.WithValueDate(v), nil
+	default:
+		return nil, fmt.Errorf("unsupported filter value for %q: %T", c.Field, v)
+	}
+}
+
+var whereOperators = map[string]filters.WhereOperator{
+	vector.OpEqual:            filters.Equal,
+	vector.OpNotEqual:         filters.NotEqual,
+	vector.OpGreaterThan:      filters.GreaterThan,
+	vector.OpGreaterThanEqual: filters.GreaterThanEqual,
+	vector.OpLessThan:         filters.LessThan,
+	vector.OpLessThanEqual:    filters.LessThanEqual,
+}
+
 func normalizeDistance(d string) string {
 	switch strings.ToLower(strings.TrimSpace(d)) {
 	case "cosine", "":
@@ -254,6 +432,11 @@ func parseSearchResults(resp *models.GraphQLResponse, className string, withPayl
 			if c, ok := id["certainty"].(float64); ok {
 				sr.Score = float32(c)
 			}
+			if score, ok := id["score"].(string); ok {
+				if f, err := strconv.ParseFloat(score, 32); err == nil {
+					sr.Score = float32(f)
+				}
+			}
 			if withVector && id["vector"] != nil {
 				if vec, ok := id["vector"].([]interface{}); ok {
 					v := make(Vector, 0, len(vec))
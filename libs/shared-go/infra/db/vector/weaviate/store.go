@@ -0,0 +1,110 @@
+package weaviate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Joepolymath/DaVinci/libs/shared-go/infra/db/vector"
+	weavLib "github.com/weaviate/weaviate-go-client/v5/weaviate"
+)
+
+type weaviateStore struct {
+	client  *weavLib.Client
+	service Service
+}
+
+// NewStore exposes a Weaviate service as a vector.Store.
+func NewStore(client *weavLib.Client, service Service) vector.Store {
+	return &weaviateStore{client: client, service: service}
+}
+
+func (s *weaviateStore) Upsert(ctx context.Context, collection string, points []vector.Point) error {
+	req := &UpsertPointsRequest{
+		CollectionName: collection,
+		Points:         make([]Point, len(points)),
+	}
+	for i, p := range points {
+		req.Points[i] = Point{ID: p.ID, Vector: p.Vector, Payload: Payload(p.Payload)}
+	}
+	return s.service.UpsertPoints(ctx, req)
+}
+
+func (s *weaviateStore) Query(ctx context.Context, req *vector.QueryRequest) ([]vector.Match, error) {
+	if req == nil {
+		return nil, fmt.Errorf("QueryRequest is required")
+	}
+
+	searchReq := &SearchRequest{
+		CollectionName: req.Collection,
+		Vector:         req.Vector,
+		Query:          req.Text,
+		Limit:          uint64(max(req.TopK, 0)),
+		ScoreThreshold: req.MinScore,
+		WithPayload:    req.WithPayload,
+		WithVector:     req.WithVector,
+		Properties:     req.Fields,
+		Where:          req.Where,
+		Hybrid:         req.Hybrid,
+	}
+	if len(req.Filter) > 0 {
+		filter := Payload(req.Filter)
+		searchReq.Filter = &filter
+	}
+
+	resp, err := s.service.Search(ctx, searchReq)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]vector.Match, len(resp.Results))
+	for i, r := range resp.Results {
+		matches[i] = vector.Match{ID: r.ID, Score: r.Score, Payload: vector.Payload(r.Payload)}
+		if r.Vector != nil {
+			matches[i].Vector = *r.Vector
+		}
+	}
+	return matches, nil
+}
+
+func (s *weaviateStore) Delete(ctx context.Context, collection string, ids []string) error {
+	return s.service.DeletePoints(ctx, &DeletePointsRequest{CollectionName: collection, PointIDs: ids})
+}
+
+func (s *weaviateStore) Collections(ctx context.Context) ([]vector.Collection, error) {
+	schema, err := s.client.Schema().Getter().Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get weaviate schema: %w", err)
+	}
+
+	collections := make([]vector.Collection, 0, len(schema.Classes))
+	for _, class := range schema.Classes {
+		c := vector.Collection{Name: class.Class}
+		if cfg, ok := class.VectorIndexConfig.(map[string]interface{}); ok {
+			if d, ok := cfg["distance"].(string); ok {
+				c.Distance = storeDistance(d)
+			}
+		}
+		collections = append(collections, c)
+	}
+	return collections, nil
+}
+
+func (s *weaviateStore) CreateCollection(ctx context.Context, collection vector.Collection) error {
+	return s.service.CreateCollection(ctx, &CreateCollectionRequest{
+		CollectionName: collection.Name,
+		VectorSize:     uint64(max(collection.Dimension, 0)),
+		Distance:       collection.Distance,
+	})
+}
+
+// storeDistance maps a Weaviate distance name onto the vector package's names.
+func storeDistance(d string) string {
+	switch d {
+	case "l2", "l2-squared":
+		return vector.DistanceEuclid
+	case "dot":
+		return vector.DistanceDot
+	default:
+		return vector.DistanceCosine
+	}
+}
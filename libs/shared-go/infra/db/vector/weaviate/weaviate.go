@@ -24,6 +24,7 @@ type WeaviateConfig struct {
 	StartupTimeout time.Duration
 	Timeout        time.Duration
 	GrpcConfig     *grpc.Config
+	GrpcHost       string // used to build GrpcConfig when it is nil
 }
 
 func NewWeaviateClient(cfg WeaviateConfig) (*weaviate.Client, error) {
@@ -55,6 +56,9 @@ func (cfg WeaviateConfig) withDefaults() WeaviateConfig {
 	if cfg.StartupTimeout == 0 {
 		cfg.StartupTimeout = defaultStartupTimeout
 	}
+	if cfg.GrpcConfig == nil && strings.TrimSpace(cfg.GrpcHost) != "" {
+		cfg.GrpcConfig = &grpc.Config{Host: cfg.GrpcHost, Secured: cfg.Scheme == "https"}
+	}
 	return cfg
 }
 
@@ -0,0 +1,124 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// Get returns the value of key, or ErrNil when it does not exist.
+func (c *Client) Get(ctx context.Context, key string) (string, error) {
+	reply, err := c.Do(ctx, "GET", key)
+	if err != nil {
+		return "", err
+	}
+	if reply == nil {
+		return "", ErrNil
+	}
+	return asString(reply)
+}
+
+// Set stores value under key. A positive ttl expires the key after that
+// long; zero keeps it until it is deleted.
+func (c *Client) Set(ctx context.Context, key, value string, ttl time.Duration) error {
+	args := []any{"SET", key, value}
+	if ttl > 0 {
+		args = append(args, "PX", ttl)
+	}
+	_, err := c.Do(ctx, args...)
+	return err
+}
+
+// Del removes keys and returns how many existed.
+func (c *Client) Del(ctx context.Context, keys ...string) (int64, error) {
+	if len(keys) == 0 {
+		return 0, nil
+	}
+	args := make([]any, 0, len(keys)+1)
+	args = append(args, "DEL")
+	for _, key := range keys {
+		args = append(args, key)
+	}
+	return asInt(c.Do(ctx, args...))
+}
+
+// Exists reports whether key exists.
+func (c *Client) Exists(ctx context.Context, key string) (bool, error) {
+	n, err := asInt(c.Do(ctx, "EXISTS", key))
+	return n > 0, err
+}
+
+// Incr adds one to the integer stored under key, starting from zero, and
+// returns the new value.
+func (c *Client) Incr(ctx context.Context, key string) (int64, error) {
+	return asInt(c.Do(ctx, "INCR", key))
+}
+
+// Decr subtracts one from the integer stored under key and returns the new
+// value.
+func (c *Client) Decr(ctx context.Context, key string) (int64, error) {
+	return asInt(c.Do(ctx, "DECR", key))
+}
+
+// Expire sets key to expire after ttl and reports whether the key exists.
+func (c *Client) Expire(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	n, err := asInt(c.Do(ctx, "PEXPIRE", key, ttl))
+	return n > 0, err
+}
+
+// LRange returns the elements of the list under key between start and
+// stop, inclusive; negative indexes count from the end. A missing key is
+// an empty list.
+func (c *Client) LRange(ctx context.Context, key string, start, stop int64) ([]string, error) {
+	return asStrings(c.Do(ctx, "LRANGE", key, start, stop))
+}
+
+// Eval runs a Lua script with the given keys and arguments and returns its
+// reply, nil for a false or nil one. A cluster needs every key on one
+// slot, so keys of one script share a {hash tag}.
+func (c *Client) Eval(ctx context.Context, script string, keys []string, args ...any) (any, error) {
+	reply, err := c.client.Eval(ctx, script, keys, millis(args)...).Result()
+	if errors.Is(err, goredis.Nil) {
+		return nil, nil
+	}
+	return reply, err
+}
+
+func asString(reply any) (string, error) {
+	s, ok := reply.(string)
+	if !ok {
+		return "", fmt.Errorf("redis: unexpected reply %T, want string", reply)
+	}
+	return s, nil
+}
+
+func asInt(reply any, err error) (int64, error) {
+	if err != nil {
+		return 0, err
+	}
+	n, ok := reply.(int64)
+	if !ok {
+		return 0, fmt.Errorf("redis: unexpected reply %T, want integer", reply)
+	}
+	return n, nil
+}
+
+func asStrings(reply any, err error) ([]string, error) {
+	if err != nil {
+		return nil, err
+	}
+	items, ok := reply.([]any)
+	if !ok {
+		return nil, fmt.Errorf("redis: unexpected reply %T, want array", reply)
+	}
+	values := make([]string, len(items))
+	for i, item := range items {
+		if values[i], err = asString(item); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,138 @@
+// Package redis is the Redis client shared by the services, a thin layer
+// over go-redis. It connects to a single server, a Redis Cluster or a
+// Sentinel-managed primary, with optional auth, database selection and TLS
+// (rediss:// URLs).
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultMaxConns = 10
+	defaultTimeout  = 5 * time.Second
+)
+
+// Deployment modes.
+const (
+	ModeSingle   = ""
+	ModeCluster  = "cluster"
+	ModeSentinel = "sentinel"
+)
+
+// ErrNil is returned for commands that find no value, e.g. GET on a
+// missing key.
+var ErrNil = goredis.Nil
+
+type RedisConfig struct {
+	// URL is e.g. "redis://:password@localhost:6379/0"; rediss:// uses
+	// TLS. In cluster and sentinel mode, further nodes or sentinels are
+	// added as addr query parameters, and sentinel mode names the primary
+	// with master_name.
+	URL      string
+	Mode     string        // ModeSingle, ModeCluster or ModeSentinel
+	MaxConns int           // connections open at most, per node (default: 10)
+	Timeout  time.Duration // per command (default: 5s)
+}
+
+type Client struct {
+	client goredis.UniversalClient
+	logger *zap.Logger
+}
+
+// NewRedisClient parses cfg.URL and checks that the server answers.
+func NewRedisClient(ctx context.Context, cfg RedisConfig, logger *zap.Logger) (*Client, error) {
+	maxConns := cfg.MaxConns
+	if maxConns <= 0 {
+		maxConns = defaultMaxConns
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	var client goredis.UniversalClient
+	switch cfg.Mode {
+	case ModeSingle:
+		opts, err := goredis.ParseURL(cfg.URL)
+		if err != nil {
+			return nil, fmt.Errorf("unsupported redis url %s: %w", redact(cfg.URL), err)
+		}
+		opts.PoolSize, opts.DialTimeout, opts.ReadTimeout, opts.WriteTimeout = maxConns, timeout, timeout, timeout
+		client = goredis.NewClient(opts)
+	case ModeCluster:
+		opts, err := goredis.ParseClusterURL(cfg.URL)
+		if err != nil {
+			return nil, fmt.Errorf("unsupported redis cluster url %s: %w", redact(cfg.URL), err)
+		}
+		opts.PoolSize, opts.DialTimeout, opts.ReadTimeout, opts.WriteTimeout = maxConns, timeout, timeout, timeout
+		client = goredis.NewClusterClient(opts)
+	case ModeSentinel:
+		opts, err := goredis.ParseFailoverURL(cfg.URL)
+		if err != nil {
+			return nil, fmt.Errorf("unsupported redis sentinel url %s: %w", redact(cfg.URL), err)
+		}
+		opts.PoolSize, opts.DialTimeout, opts.ReadTimeout, opts.WriteTimeout = maxConns, timeout, timeout, timeout
+		client = goredis.NewFailoverClient(opts)
+	default:
+		return nil, fmt.Errorf("unsupported redis mode: %q (supported: %q, %q)", cfg.Mode, ModeCluster, ModeSentinel)
+	}
+
+	c := &Client{client: client, logger: logger}
+	if err := c.Health(ctx); err != nil {
+		client.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// Health reports whether the server answers a PING.
+func (c *Client) Health(ctx context.Context) error {
+	if err := c.client.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("ping redis: %w", err)
+	}
+	return nil
+}
+
+// Do runs a command and returns its reply: a string, an int64, nil, or a
+// []any of replies. Durations among the arguments are sent in
+// milliseconds. Error replies are returned as errors.
+func (c *Client) Do(ctx context.Context, args ...any) (any, error) {
+	reply, err := c.client.Do(ctx, millis(args)...).Result()
+	if errors.Is(err, goredis.Nil) {
+		return nil, nil
+	}
+	return reply, err
+}
+
+// Close closes the client's connections.
+func (c *Client) Close() error {
+	return c.client.Close()
+}
+
+// millis replaces durations in args with their milliseconds, which go-redis
+// would send in nanoseconds.
+func millis(args []any) []any {
+	for i, arg := range args {
+		if d, ok := arg.(time.Duration); ok {
+			args[i] = d.Milliseconds()
+		}
+	}
+	return args
+}
+
+// redact hides the password in a URL for error messages.
+func redact(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "(invalid)"
+	}
+	return u.Redacted()
+}
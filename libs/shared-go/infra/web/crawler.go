@@ -0,0 +1,426 @@
+// Package web fetches web pages for ingestion, following robots.txt and
+// optionally crawling links within the same site. URLs come from users, so
+// the crawler only connects to public addresses: it refuses loopback,
+// private, link-local (such as the 169.254.169.254 metadata service) and
+// other non-public addresses, whatever name resolves to them, and local
+// names such as localhost and *.internal.
+package web
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+const (
+	DefaultUserAgent = "DaVinciBot/1.0"
+
+	defaultTimeout  = 30 * time.Second
+	defaultMaxBytes = 10 << 20
+	defaultMaxPages = 20
+	maxCrawlDelay   = 10 * time.Second
+	maxRobotsBytes  = 512 << 10
+)
+
+var (
+	ErrInvalidURL  = errors.New("url must be absolute http or https")
+	ErrDisallowed  = errors.New("url is disallowed by robots.txt")
+	ErrFetchFailed = errors.New("failed to fetch url")
+	// ErrForbiddenHost is returned for a URL whose host is local, resolves
+	// to a non-public address or, with AllowedHosts, is not listed.
+	ErrForbiddenHost = errors.New("url host is not allowed")
+	errNoIndex       = errors.New("page asks not to be indexed")
+)
+
+// StatusError reports a response other than 200 OK.
+type StatusError struct {
+	URL        string
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s returned status %d", e.URL, e.StatusCode)
+}
+
+type CrawlerConfig struct {
+	UserAgent string        // sent with every request and matched against robots.txt (default: DaVinciBot/1.0)
+	Timeout   time.Duration // per request (default: 30s)
+	MaxBytes  int64         // largest body read per page (default: 10MB)
+	MaxPages  int           // upper bound on pages per crawl (default: 20)
+	// AllowedHosts, when set, limits fetching to these hosts and their
+	// subdomains, e.g. "example.com" for docs.example.com too. Their
+	// addresses must still be public.
+	AllowedHosts []string
+}
+
+// Page is a fetched document.
+type Page struct {
+	URL       string // final URL after redirects
+	Depth     int    // link distance from the start URL
+	MediaType string
+	Body      []byte
+}
+
+// CrawlRequest describes a crawl starting at URL. With MaxDepth 0 only URL
+// itself is fetched; each extra level follows links on the same host.
+type CrawlRequest struct {
+	URL      string
+	MaxDepth int
+	MaxPages int // 0 uses the crawler's MaxPages; larger values are capped to it
+}
+
+type Crawler struct {
+	userAgent  string
+	agentToken string
+	maxBytes   int64
+	maxPages   int
+	allowed    []string // lower-case hosts; empty allows every host
+	httpClient *http.Client
+	logger     *zap.Logger
+
+	mu     sync.Mutex
+	robots map[string]*robots // by scheme://host
+}
+
+func NewCrawler(cfg CrawlerConfig, logger *zap.Logger) *Crawler {
+	userAgent := strings.TrimSpace(cfg.UserAgent)
+	if userAgent == "" {
+		userAgent = DefaultUserAgent
+	}
+	token, _, _ := strings.Cut(userAgent, "/")
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	maxBytes := cfg.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = defaultMaxBytes
+	}
+	maxPages := cfg.MaxPages
+	if maxPages <= 0 {
+		maxPages = defaultMaxPages
+	}
+
+	var allowed []string
+	for _, host := range cfg.AllowedHosts {
+		if host = strings.ToLower(strings.TrimSpace(host)); host != "" {
+			allowed = append(allowed, host)
+		}
+	}
+
+	c := &Crawler{
+		userAgent:  userAgent,
+		agentToken: strings.ToLower(strings.TrimSpace(token)),
+		maxBytes:   maxBytes,
+		maxPages:   maxPages,
+		allowed:    allowed,
+		logger:     logger,
+		robots:     make(map[string]*robots),
+	}
+	// Connections are checked after name resolution, so a public name
+	// that resolves to a private address is refused too. Requests go
+	// direct, without HTTP_PROXY, since the check applies to the address
+	// connected to.
+	dialer := &net.Dialer{Timeout: timeout, Control: dialControl}
+	c.httpClient = &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 10 * time.Second,
+			MaxIdleConnsPerHost: 4,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return fmt.Errorf("%w: %q", ErrInvalidURL, req.URL)
+			}
+			return c.checkHost(req.URL)
+		},
+	}
+	return c
+}
+
+// checkHost refuses u unless its host may be fetched: not a local name
+// and, with an allowlist, listed. Its addresses are checked on connect.
+func (c *Crawler) checkHost(u *url.URL) error {
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") ||
+		strings.HasSuffix(host, ".local") || strings.HasSuffix(host, ".internal") {
+		return fmt.Errorf("%w: %s", ErrForbiddenHost, host)
+	}
+	if len(c.allowed) > 0 && !slices.ContainsFunc(c.allowed, func(a string) bool {
+		return host == a || strings.HasSuffix(host, "."+a)
+	}) {
+		return fmt.Errorf("%w: %s", ErrForbiddenHost, host)
+	}
+	return nil
+}
+
+// cgnat is the shared address space of RFC 6598, used inside carrier and
+// cloud networks.
+var cgnat = netip.MustParsePrefix("100.64.0.0/10")
+
+// dialControl refuses connections to addresses that are not public.
+func dialControl(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrForbiddenHost, address)
+	}
+	addr := addrPort.Addr().Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() || cgnat.Contains(addr) {
+		return fmt.Errorf("%w: %s is not a public address", ErrForbiddenHost, addr)
+	}
+	return nil
+}
+
+// Crawl fetches req.URL and, breadth first, same-host pages linked from it
+// up to req.MaxDepth, calling visit for each page. Failures on the start URL
+// are returned; failures on linked pages are logged and skipped. Returning an
+// error from visit stops the crawl.
+func (c *Crawler) Crawl(ctx context.Context, req CrawlRequest, visit func(*Page) error) error {
+	start, err := parseURL(req.URL)
+	if err != nil {
+		return err
+	}
+
+	maxPages := c.maxPages
+	if req.MaxPages > 0 && req.MaxPages < maxPages {
+		maxPages = req.MaxPages
+	}
+
+	type queued struct {
+		url   *url.URL
+		depth int
+	}
+	queue := []queued{{url: start}}
+	seen := map[string]bool{start.String(): true}
+	fetched := 0
+
+	for len(queue) > 0 && fetched < maxPages {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		next := queue[0]
+		queue = queue[1:]
+
+		page, links, err := c.fetch(ctx, next.url)
+		if err != nil {
+			if next.depth == 0 {
+				return err
+			}
+			c.logger.Warn("Skipping page", zap.String("url", next.url.String()), zap.Error(err))
+			continue
+		}
+		fetched++
+		page.Depth = next.depth
+
+		if err := visit(page); err != nil {
+			return err
+		}
+
+		if next.depth >= req.MaxDepth {
+			continue
+		}
+		for _, link := range links {
+			if link.Host != start.Host || seen[link.String()] {
+				continue
+			}
+			seen[link.String()] = true
+			queue = append(queue, queued{url: link, depth: next.depth + 1})
+		}
+	}
+	return nil
+}
+
+// Fetch fetches a single page, honouring robots.txt.
+func (c *Crawler) Fetch(ctx context.Context, rawURL string) (*Page, error) {
+	u, err := parseURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	page, _, err := c.fetch(ctx, u)
+	return page, err
+}
+
+// fetch downloads u and returns the page and, for HTML, its followable links.
+func (c *Crawler) fetch(ctx context.Context, u *url.URL) (*Page, []*url.URL, error) {
+	if err := c.checkHost(u); err != nil {
+		return nil, nil, err
+	}
+	rules, err := c.robotsFor(ctx, u)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !rules.allowed(u.RequestURI()) {
+		return nil, nil, fmt.Errorf("%w: %s", ErrDisallowed, u)
+	}
+	if rules.crawlDelay > 0 {
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case <-time.After(min(rules.crawlDelay, maxCrawlDelay)):
+		}
+	}
+
+	body, mediaType, final, err := c.get(ctx, u, c.maxBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	page := &Page{URL: final.String(), MediaType: mediaType, Body: body}
+	if mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return page, nil, nil
+	}
+
+	links, noindex := parseLinks(body, final)
+	if noindex {
+		return nil, nil, fmt.Errorf("%s: %w", u, errNoIndex)
+	}
+	return page, links, nil
+}
+
+func (c *Crawler) get(ctx context.Context, u *url.URL, limit int64) ([]byte, string, *url.URL, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,text/plain;q=0.9,*/*;q=0.5")
+
+	resp, err := c.httpClient.Do(req)
+	if errors.Is(err, ErrForbiddenHost) {
+		return nil, "", nil, err
+	}
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("%w: %w", ErrFetchFailed, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", nil, fmt.Errorf("%w: %w", ErrFetchFailed, &StatusError{URL: u.String(), StatusCode: resp.StatusCode})
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit))
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("%w: read body: %w", ErrFetchFailed, err)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType == "" {
+		mediaType, _, _ = mime.ParseMediaType(http.DetectContentType(body))
+	}
+	return body, mediaType, resp.Request.URL, nil
+}
+
+// robotsFor returns the cached robots.txt rules for u's host, fetching them
+// on first use. A missing robots.txt allows everything; a server error
+// disallows everything, as recommended by RFC 9309.
+func (c *Crawler) robotsFor(ctx context.Context, u *url.URL) (*robots, error) {
+	key := u.Scheme + "://" + u.Host
+
+	c.mu.Lock()
+	rules, ok := c.robots[key]
+	c.mu.Unlock()
+	if ok {
+		return rules, nil
+	}
+
+	robotsURL := &url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/robots.txt"}
+	body, _, _, err := c.get(ctx, robotsURL, maxRobotsBytes)
+	var status *StatusError
+	switch {
+	case errors.Is(err, ErrForbiddenHost):
+		return nil, err
+	case err == nil:
+		rules = parseRobots(body, c.agentToken)
+	case errors.As(err, &status) && status.StatusCode >= 400 && status.StatusCode < 500:
+		rules = &robots{}
+	default:
+		c.logger.Warn("Failed to fetch robots.txt", zap.String("url", robotsURL.String()), zap.Error(err))
+		rules = &robots{disallowed: true}
+	}
+
+	c.mu.Lock()
+	c.robots[key] = rules
+	c.mu.Unlock()
+	return rules, nil
+}
+
+func parseURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidURL, raw)
+	}
+	u.Fragment = ""
+	return u, nil
+}
+
+// parseLinks returns the absolute http(s) links of an HTML page, honouring
+// <base href>, rel="nofollow" and robots meta tags. noindex reports a
+// robots meta tag asking not to index the page.
+func parseLinks(body []byte, base *url.URL) (links []*url.URL, noindex bool) {
+	z := html.NewTokenizer(bytes.NewReader(body))
+	nofollow := false
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+		if tt != html.StartTagToken && tt != html.SelfClosingTagToken {
+			continue
+		}
+
+		tok := z.Token()
+		attrs := make(map[string]string, len(tok.Attr))
+		for _, a := range tok.Attr {
+			attrs[strings.ToLower(a.Key)] = a.Val
+		}
+
+		switch tok.DataAtom {
+		case atom.Base:
+			if href, err := base.Parse(attrs["href"]); err == nil && attrs["href"] != "" {
+				base = href
+			}
+		case atom.Meta:
+			if strings.EqualFold(attrs["name"], "robots") {
+				content := strings.ToLower(attrs["content"])
+				noindex = noindex || strings.Contains(content, "noindex") || strings.Contains(content, "none")
+				nofollow = nofollow || strings.Contains(content, "nofollow") || strings.Contains(content, "none")
+			}
+		case atom.A:
+			href, ok := attrs["href"]
+			if !ok || strings.Contains(strings.ToLower(attrs["rel"]), "nofollow") {
+				continue
+			}
+			link, err := base.Parse(strings.TrimSpace(href))
+			if err != nil || (link.Scheme != "http" && link.Scheme != "https") {
+				continue
+			}
+			link.Fragment = ""
+			links = append(links, link)
+		}
+	}
+	if nofollow {
+		links = nil
+	}
+	return links, noindex
+}
@@ -0,0 +1,135 @@
+package web
+
+import (
+	"bufio"
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// robots holds the rules of a robots.txt that apply to our user agent.
+type robots struct {
+	rules      []robotsRule
+	crawlDelay time.Duration
+	disallowed bool // robots.txt could not be fetched; treat everything as disallowed
+}
+
+type robotsRule struct {
+	allow   bool
+	pattern string
+}
+
+// parseRobots parses a robots.txt body, keeping the group for agent (the
+// lower-cased product token of our user agent) or, failing that, "*".
+func parseRobots(data []byte, agent string) *robots {
+	type group struct {
+		agents []string
+		rules  []robotsRule
+		delay  time.Duration
+	}
+
+	var (
+		groups  []*group
+		current *group
+		inRules bool
+	)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			if current == nil || inRules {
+				current = &group{}
+				groups = append(groups, current)
+				inRules = false
+			}
+			current.agents = append(current.agents, strings.ToLower(value))
+		case "allow", "disallow":
+			if current == nil {
+				continue
+			}
+			inRules = true
+			if value != "" {
+				current.rules = append(current.rules, robotsRule{allow: key == "allow", pattern: value})
+			}
+		case "crawl-delay":
+			if current == nil {
+				continue
+			}
+			inRules = true
+			if secs, err := strconv.ParseFloat(value, 64); err == nil && secs > 0 {
+				current.delay = time.Duration(secs * float64(time.Second))
+			}
+		}
+	}
+
+	var wildcard *group
+	for _, g := range groups {
+		for _, a := range g.agents {
+			if a == agent {
+				return &robots{rules: g.rules, crawlDelay: g.delay}
+			}
+			if a == "*" && wildcard == nil {
+				wildcard = g
+			}
+		}
+	}
+	if wildcard != nil {
+		return &robots{rules: wildcard.rules, crawlDelay: wildcard.delay}
+	}
+	return &robots{}
+}
+
+// allowed reports whether path (including any query) may be fetched. The
+// longest matching rule wins; on a tie, allow wins.
+func (r *robots) allowed(path string) bool {
+	if r.disallowed {
+		return false
+	}
+
+	allow, best := true, -1
+	for _, rule := range r.rules {
+		if !matchRobots(rule.pattern, path) {
+			continue
+		}
+		if n := len(rule.pattern); n > best || (n == best && rule.allow) {
+			allow, best = rule.allow, n
+		}
+	}
+	return allow
+}
+
+// matchRobots matches a robots.txt path pattern, which may contain "*"
+// wildcards and a trailing "$" anchor.
+func matchRobots(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	parts := strings.Split(strings.TrimSuffix(pattern, "$"), "*")
+
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	if len(parts) == 1 {
+		return !anchored || path == parts[0]
+	}
+
+	pos := len(parts[0])
+	last := parts[len(parts)-1]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(path[pos:], part)
+		if i < 0 {
+			return false
+		}
+		pos += i + len(part)
+	}
+	if anchored {
+		return len(path)-len(last) >= pos && strings.HasSuffix(path, last)
+	}
+	return strings.Contains(path[pos:], last)
+}
@@ -0,0 +1,158 @@
+// Package pagination pages through listings with opaque cursors. A cursor
+// records the sort key of the last item returned, so pages stay consistent
+// when items are added or removed between requests.
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Joepolymath/DaVinci/libs/shared-go/validate"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+var (
+	ErrInvalidCursor = errors.New("invalid cursor")
+	ErrInvalidLimit  = fmt.Errorf("limit must be between 1 and %d", MaxLimit)
+)
+
+// Request asks for up to Limit items (default: 20) following Cursor, the
+// NextCursor of the previous page. An empty Cursor asks for the first page.
+type Request struct {
+	Limit  int
+	Cursor string
+}
+
+// Validate checks the "limit" and "cursor" parameters.
+func (r Request) Validate() error {
+	var errs validate.Errors
+	if r.Limit < 0 || r.Limit > MaxLimit {
+		errs.Add("limit", ErrInvalidLimit)
+	}
+	if _, err := decode(r.Cursor); err != nil {
+		errs.Add("cursor", err)
+	}
+	return errs.Err()
+}
+
+// Size is the number of items a page holds: Limit, or DefaultLimit.
+func (r Request) Size() int {
+	if r.Limit == 0 {
+		return DefaultLimit
+	}
+	return r.Limit
+}
+
+// After is the key of the last item of the previous page, or nil for the
+// first page. It is also nil for an invalid cursor, which Validate
+// reports.
+func (r Request) After() *Key {
+	k, _ := decode(r.Cursor)
+	return k
+}
+
+// Key is an item's place in a listing: items are ordered by Time, and
+// items with equal times by ID, in the same direction. A descending
+// listing is thus ORDER BY time DESC, id DESC, and the items after a key
+// are those with (time, id) < (key.Time, key.ID).
+type Key struct {
+	Time time.Time `json:"t"`
+	ID   string    `json:"id"`
+}
+
+// Order is the direction of a listing by time.
+type Order int
+
+const (
+	Ascending Order = iota
+	Descending
+)
+
+// Compare returns a negative number when a comes before b in o, a
+// positive number when it comes after, and zero when they are equal.
+func (o Order) Compare(a, b Key) int {
+	if c := a.Time.Compare(b.Time); c != 0 {
+		if o == Descending {
+			return -c
+		}
+		return c
+	}
+	if o == Descending {
+		return strings.Compare(b.ID, a.ID)
+	}
+	return strings.Compare(a.ID, b.ID)
+}
+
+// Sort sorts items in o by their key.
+func Sort[T any](items []T, o Order, key func(T) Key) {
+	slices.SortFunc(items, func(a, b T) int {
+		return o.Compare(key(a), key(b))
+	})
+}
+
+// Paginate returns the page of items that req asks for and the cursor of
+// the next page, or "" on the last page. items must be sorted in o.
+func Paginate[T any](items []T, req Request, o Order, key func(T) Key) ([]T, string, error) {
+	if err := req.Validate(); err != nil {
+		return nil, "", err
+	}
+	limit := req.Size()
+
+	start := 0
+	if after := req.After(); after != nil {
+		start, _ = slices.BinarySearchFunc(items, *after, func(item T, k Key) int {
+			return o.Compare(key(item), k)
+		})
+		if start < len(items) && o.Compare(key(items[start]), *after) == 0 {
+			start++
+		}
+	}
+
+	end := min(start+limit, len(items))
+	page := items[start:end]
+	if end == len(items) {
+		return page, "", nil
+	}
+	return page, encode(key(items[end-1])), nil
+}
+
+// Next returns the page of items, which a store read from after req's
+// cursor with a limit of one more than req.Size(), and the cursor of the
+// next page, or "" on the last page. The extra item only shows that there
+// is a next page.
+func Next[T any](items []T, req Request, key func(T) Key) ([]T, string) {
+	limit := req.Size()
+	if len(items) <= limit {
+		return items, ""
+	}
+	return items[:limit], encode(key(items[limit-1]))
+}
+
+func encode(k Key) string {
+	data, _ := json.Marshal(k)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decode(cursor string) (*Key, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var k Key
+	if err := json.Unmarshal(data, &k); err != nil || k.ID == "" {
+		return nil, ErrInvalidCursor
+	}
+	return &k, nil
+}
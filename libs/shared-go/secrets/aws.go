@@ -0,0 +1,217 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	awsService = "secretsmanager"
+	// ecsCredentialsHost serves the task role's credentials to ECS tasks.
+	ecsCredentialsHost = "http://169.254.170.2"
+	// credentialsMargin renews temporary credentials this long before
+	// they expire.
+	credentialsMargin = 5 * time.Minute
+)
+
+type AWSConfig struct {
+	Region   string
+	SecretID string // the secret's name or ARN
+	// Endpoint overrides https://secretsmanager.<region>.amazonaws.com,
+	// e.g. for a VPC endpoint.
+	Endpoint string
+}
+
+type awsCredentials struct {
+	AccessKeyID     string    `json:"AccessKeyId"`
+	SecretAccessKey string    `json:"SecretAccessKey"`
+	SessionToken    string    `json:"Token"`
+	Expiration      time.Time `json:"Expiration"` // zero for static keys
+}
+
+type awsSource struct {
+	config     AWSConfig
+	endpoint   string
+	httpClient *http.Client
+
+	mu          sync.Mutex
+	credentials *awsCredentials
+}
+
+// NewAWSSource reads a secret from AWS Secrets Manager. The secret's
+// string must be a JSON object of string values. Credentials come from
+// AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN, or else
+// from the ECS task role.
+func NewAWSSource(cfg AWSConfig) (Source, error) {
+	if cfg.Region == "" || cfg.SecretID == "" {
+		return nil, errors.New("aws region and secret id are required")
+	}
+	endpoint := strings.TrimRight(cfg.Endpoint, "/")
+	if endpoint == "" {
+		endpoint = "https://" + awsService + "." + cfg.Region + ".amazonaws.com"
+	}
+	return &awsSource{
+		config:     cfg,
+		endpoint:   endpoint,
+		httpClient: &http.Client{Timeout: fetchTimeout},
+	}, nil
+}
+
+func (s *awsSource) Fetch(ctx context.Context) (map[string]string, error) {
+	creds, err := s.awsCredentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(map[string]string{"SecretId": s.config.SecretID})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint+"/", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create secrets manager request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	signV4(req, body, creds, s.config.Region, time.Now().UTC())
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read aws secret: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var out struct {
+		SecretString string `json:"SecretString"`
+		Type         string `json:"__type"`
+		Message      string `json:"message"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxSecretBytes)).Decode(&out); err != nil && resp.StatusCode == http.StatusOK {
+		return nil, fmt.Errorf("failed to parse aws secret: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to read aws secret %s: %s %s %s", s.config.SecretID, resp.Status, out.Type, out.Message)
+	}
+
+	var values map[string]string
+	if err := json.Unmarshal([]byte(out.SecretString), &values); err != nil {
+		return nil, fmt.Errorf("aws secret %s is not a JSON object of strings: %w", s.config.SecretID, err)
+	}
+	return values, nil
+}
+
+// awsCredentials returns the static credentials from the environment, or
+// the task role's, fetched again shortly before they expire.
+func (s *awsSource) awsCredentials(ctx context.Context) (*awsCredentials, error) {
+	if id := os.Getenv("AWS_ACCESS_KEY_ID"); id != "" {
+		return &awsCredentials{
+			AccessKeyID:     id,
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		}, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if c := s.credentials; c != nil && time.Until(c.Expiration) > credentialsMargin {
+		return c, nil
+	}
+
+	url := os.Getenv("AWS_CONTAINER_CREDENTIALS_FULL_URI")
+	if uri := os.Getenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI"); uri != "" {
+		url = ecsCredentialsHost + uri
+	}
+	if url == "" {
+		return nil, errors.New("no aws credentials: set AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY or run with an ECS task role")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create aws credentials request: %w", err)
+	}
+	if token := os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN"); token != "" {
+		req.Header.Set("Authorization", token)
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch aws credentials: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch aws credentials: %s", resp.Status)
+	}
+	var c awsCredentials
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxSecretBytes)).Decode(&c); err != nil {
+		return nil, fmt.Errorf("failed to parse aws credentials: %w", err)
+	}
+	s.credentials = &c
+	return &c, nil
+}
+
+// signV4 signs req with AWS Signature Version 4, covering its
+// Content-Type and X-Amz-* headers and body.
+func signV4(req *http.Request, body []byte, creds *awsCredentials, region string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	if creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+
+	names := []string{"content-type", "host", "x-amz-date"}
+	if creds.SessionToken != "" {
+		names = append(names, "x-amz-security-token")
+	}
+	names = append(names, "x-amz-target")
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		value := req.Header.Get(name)
+		if name == "host" {
+			value = req.URL.Host
+		}
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(value) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		hexSHA256(body),
+	}, "\n")
+	scope := date + "/" + region + "/" + awsService + "/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hexSHA256([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+creds.SecretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, awsService)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+creds.AccessKeyID+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func hexSHA256(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
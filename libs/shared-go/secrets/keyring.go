@@ -0,0 +1,150 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	sealedVersion = "v1"
+	dataKeySize   = 32
+)
+
+var (
+	ErrInvalidKeyring = errors.New("invalid encryption keys")
+	ErrUnknownKey     = errors.New("value was sealed with an unknown encryption key")
+	ErrSealedValue    = errors.New("sealed value is malformed or was tampered with")
+)
+
+// Keyring encrypts values at rest with envelope encryption: each value is
+// sealed with its own random data key, and the data key is wrapped with
+// the current key-encryption key. Older keys are kept to open values
+// sealed before a rotation; Sealed values name the key that wrapped them.
+type Keyring struct {
+	current string
+	keys    map[string]cipher.AEAD
+}
+
+// ParseKeyring parses key-encryption keys in the form "id:base64,...",
+// as used in environment variables. Keys are 32 random bytes, e.g. from
+// `openssl rand -base64 32`; the first is used to seal new values.
+func ParseKeyring(s string) (*Keyring, error) {
+	k := &Keyring{keys: make(map[string]cipher.AEAD)}
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		id, encoded, ok := strings.Cut(entry, ":")
+		if !ok || id == "" || strings.Contains(id, ".") {
+			return nil, fmt.Errorf("%w: want id:base64key", ErrInvalidKeyring)
+		}
+		if _, dup := k.keys[id]; dup {
+			return nil, fmt.Errorf("%w: key %q is listed twice", ErrInvalidKeyring, id)
+		}
+		raw, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(raw) != dataKeySize {
+			return nil, fmt.Errorf("%w: key %q is not 32 base64-encoded bytes", ErrInvalidKeyring, id)
+		}
+		aead, err := newAEAD(raw)
+		if err != nil {
+			return nil, err
+		}
+		k.keys[id] = aead
+		if k.current == "" {
+			k.current = id
+		}
+	}
+	if k.current == "" {
+		return nil, fmt.Errorf("%w: no keys", ErrInvalidKeyring)
+	}
+	return k, nil
+}
+
+// Seal encrypts plaintext. associatedData, such as the ID of the record
+// the value belongs to, is not stored but must be given to Open, so a
+// sealed value cannot be moved to another record.
+func (k *Keyring) Seal(plaintext, associatedData []byte) (string, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", err
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+	wrapped := seal(k.keys[k.current], dataKey, []byte(k.current))
+	ciphertext := seal(aead, plaintext, associatedData)
+
+	enc := base64.RawURLEncoding
+	return sealedVersion + "." + k.current + "." + enc.EncodeToString(wrapped) + "." + enc.EncodeToString(ciphertext), nil
+}
+
+// Open decrypts a value returned by Seal with the same associatedData.
+func (k *Keyring) Open(sealed string, associatedData []byte) ([]byte, error) {
+	parts := strings.Split(sealed, ".")
+	if len(parts) != 4 || parts[0] != sealedVersion {
+		return nil, ErrSealedValue
+	}
+	kek, ok := k.keys[parts[1]]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKey, parts[1])
+	}
+	enc := base64.RawURLEncoding
+	wrapped, err := enc.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrSealedValue
+	}
+	ciphertext, err := enc.DecodeString(parts[3])
+	if err != nil {
+		return nil, ErrSealedValue
+	}
+
+	dataKey, err := open(kek, wrapped, []byte(parts[1]))
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, ErrSealedValue
+	}
+	return open(aead, ciphertext, associatedData)
+}
+
+// Current reports whether sealed was wrapped with the current key, so
+// values sealed before a rotation can be sealed again.
+func (k *Keyring) Current(sealed string) bool {
+	parts := strings.SplitN(sealed, ".", 3)
+	return len(parts) == 3 && parts[1] == k.current
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal returns a random nonce followed by the ciphertext.
+func seal(aead cipher.AEAD, plaintext, associatedData []byte) []byte {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	rand.Read(nonce)
+	return aead.Seal(nonce, nonce, plaintext, associatedData)
+}
+
+func open(aead cipher.AEAD, data, associatedData []byte) ([]byte, error) {
+	if len(data) < aead.NonceSize() {
+		return nil, ErrSealedValue
+	}
+	plaintext, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], associatedData)
+	if err != nil {
+		return nil, ErrSealedValue
+	}
+	return plaintext, nil
+}
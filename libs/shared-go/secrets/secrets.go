@@ -0,0 +1,86 @@
+// Package secrets loads credentials from a secrets manager, HashiCorp
+// Vault or AWS Secrets Manager, instead of .env files. A secret holds
+// key/value pairs named like the environment variables they replace, e.g.
+// {"OPENAI_API_KEY": "sk-..."}; Apply sets the allowed ones in the process
+// environment, where configuration reads them, and Refresh keeps them
+// current so rotated keys are picked up without a restart.
+package secrets
+
+import (
+	"context"
+	"errors"
+	"os"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	fetchTimeout   = 10 * time.Second
+	maxSecretBytes = 1 << 20
+)
+
+var ErrEmptySecret = errors.New("secret has no values")
+
+// Source reads the current values of a secret.
+type Source interface {
+	Fetch(ctx context.Context) (map[string]string, error)
+}
+
+// Apply fetches the secret and sets its values named in allowed as
+// environment variables, overriding any already set. Other values are
+// ignored, so that whoever can write the secret cannot also change
+// settings such as PATH or the secrets provider itself. It returns the
+// names of the variables whose value changed.
+func Apply(ctx context.Context, source Source, allowed []string) ([]string, error) {
+	values, err := source.Fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, ErrEmptySecret
+	}
+
+	var changed []string
+	for name, value := range values {
+		if !slices.Contains(allowed, name) {
+			continue
+		}
+		if current, ok := os.LookupEnv(name); ok && current == value {
+			continue
+		}
+		if err := os.Setenv(name, value); err != nil {
+			return changed, err
+		}
+		changed = append(changed, name)
+	}
+	slices.Sort(changed)
+	return changed, nil
+}
+
+// Refresh applies the allowed values of the secret every interval until
+// ctx is done. A failed
+// fetch keeps the values already applied. Only the names of changed
+// values are logged, never the values.
+func Refresh(ctx context.Context, source Source, allowed []string, interval time.Duration, logger *zap.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		changed, err := Apply(ctx, source, allowed)
+		if err != nil {
+			logger.Warn("Failed to refresh secrets", zap.Error(err))
+			continue
+		}
+		if len(changed) > 0 {
+			logger.Info("Secrets rotated", zap.Strings("names", changed))
+		}
+	}
+}
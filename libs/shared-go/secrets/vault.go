@@ -0,0 +1,86 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+type VaultConfig struct {
+	Address   string // e.g. https://vault.example.com:8200
+	Token     string
+	Namespace string // Vault Enterprise namespace, if any
+	// Path is the secret's API path under /v1, e.g. "secret/data/scribequery"
+	// for a KV version 2 mount named "secret".
+	Path string
+}
+
+type vaultSource struct {
+	url        string
+	config     VaultConfig
+	httpClient *http.Client
+}
+
+// NewVaultSource reads a secret from a Vault KV engine (version 1 or 2)
+// with a token.
+func NewVaultSource(cfg VaultConfig) (Source, error) {
+	if cfg.Address == "" || cfg.Path == "" {
+		return nil, errors.New("vault address and secret path are required")
+	}
+	if cfg.Token == "" {
+		return nil, errors.New("vault token is required")
+	}
+	return &vaultSource{
+		url:        strings.TrimRight(cfg.Address, "/") + "/v1/" + strings.Trim(cfg.Path, "/"),
+		config:     cfg,
+		httpClient: &http.Client{Timeout: fetchTimeout},
+	}, nil
+}
+
+func (s *vaultSource) Fetch(ctx context.Context) (map[string]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create vault request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", s.config.Token)
+	if s.config.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", s.config.Namespace)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vault secret: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to read vault secret %s: %s", s.config.Path, resp.Status)
+	}
+
+	var body struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxSecretBytes)).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to parse vault secret: %w", err)
+	}
+
+	// KV version 2 nests the values under data.data, next to metadata.
+	data := body.Data
+	if inner, ok := data["data"].(map[string]any); ok {
+		if _, ok := data["metadata"]; ok {
+			data = inner
+		}
+	}
+	values := make(map[string]string, len(data))
+	for k, v := range data {
+		if str, ok := v.(string); ok {
+			values[k] = str
+		} else {
+			values[k] = fmt.Sprint(v)
+		}
+	}
+	return values, nil
+}
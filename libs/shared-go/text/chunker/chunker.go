@@ -0,0 +1,134 @@
+// Package chunker splits documents into overlapping chunks for embedding.
+// Sizes and overlaps are measured in characters (runes).
+package chunker
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Strategies accepted by New.
+const (
+	StrategyFixed     = "fixed"
+	StrategySentence  = "sentence"
+	StrategyMarkdown  = "markdown"
+	StrategyRecursive = "recursive"
+)
+
+const (
+	DefaultSize    = 1000
+	DefaultOverlap = 200
+)
+
+var (
+	ErrInvalidConfig       = errors.New("chunk overlap must be smaller than chunk size")
+	ErrUnsupportedStrategy = errors.New("unsupported chunking strategy")
+)
+
+// Chunk is one piece of a document.
+type Chunk struct {
+	Text  string `json:"text"`
+	Index int    `json:"index"`
+
+	// Heading is the markdown heading path the chunk belongs to, e.g.
+	// "Install > Linux". Only set by the markdown chunker.
+	Heading string `json:"heading,omitempty"`
+}
+
+type Chunker interface {
+	Split(text string) []Chunk
+}
+
+// Config sets the maximum chunk size and how much consecutive chunks overlap.
+// A zero Size uses DefaultSize and DefaultOverlap.
+type Config struct {
+	Size    int `json:"size,omitempty"`
+	Overlap int `json:"overlap,omitempty"`
+}
+
+func (c Config) withDefaults() (Config, error) {
+	if c.Size <= 0 {
+		c.Size = DefaultSize
+		if c.Overlap <= 0 {
+			c.Overlap = DefaultOverlap
+		}
+	}
+	if c.Overlap < 0 || c.Overlap >= c.Size {
+		return c, ErrInvalidConfig
+	}
+	return c, nil
+}
+
+// New returns the chunker for strategy; an empty strategy is recursive.
+func New(strategy string, cfg Config) (Chunker, error) {
+	cfg, err := cfg.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+
+	switch strategy {
+	case StrategyFixed:
+		return &fixedChunker{cfg: cfg}, nil
+	case StrategySentence:
+		return &sentenceChunker{cfg: cfg}, nil
+	case StrategyMarkdown:
+		return &markdownChunker{inner: newRecursive(cfg)}, nil
+	case "", StrategyRecursive:
+		return newRecursive(cfg), nil
+	default:
+		return nil, fmt.Errorf("%w: %q (supported: %q, %q, %q, %q)",
+			ErrUnsupportedStrategy, strategy, StrategyFixed, StrategySentence, StrategyMarkdown, StrategyRecursive)
+	}
+}
+
+// toChunks numbers non-empty texts as chunks.
+func toChunks(texts []string, heading string, first int) []Chunk {
+	chunks := make([]Chunk, 0, len(texts))
+	for _, t := range texts {
+		if t = strings.TrimSpace(t); t != "" {
+			chunks = append(chunks, Chunk{Text: t, Index: first + len(chunks), Heading: heading})
+		}
+	}
+	return chunks
+}
+
+// splitFixed cuts text into windows of at most size runes, each starting
+// overlap runes before the end of the previous one. Cuts prefer the last
+// whitespace in the window so words are not split.
+func splitFixed(text string, size, overlap int) []string {
+	runes := []rune(text)
+
+	var out []string
+	for start := 0; start < len(runes); {
+		end := min(start+size, len(runes))
+		if end < len(runes) {
+			for i := end; i > start+size/2; i-- {
+				if unicode.IsSpace(runes[i-1]) {
+					end = i
+					break
+				}
+			}
+		}
+
+		out = append(out, string(runes[start:end]))
+		if end == len(runes) {
+			break
+		}
+		start = max(end-overlap, start+1)
+	}
+	return out
+}
+
+type fixedChunker struct {
+	cfg Config
+}
+
+func (c *fixedChunker) Split(text string) []Chunk {
+	return toChunks(splitFixed(strings.TrimSpace(text), c.cfg.Size, c.cfg.Overlap), "", 0)
+}
+
+func runeLen(s string) int {
+	return len([]rune(s))
+}
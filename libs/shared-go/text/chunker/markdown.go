@@ -0,0 +1,80 @@
+package chunker
+
+import "strings"
+
+// markdownChunker splits a document into sections at ATX headings ("#" to
+// "######") and chunks each section separately, so no chunk spans two
+// sections. Each chunk records its heading path.
+type markdownChunker struct {
+	inner *recursiveChunker
+}
+
+type section struct {
+	heading string
+	body    strings.Builder
+}
+
+func (c *markdownChunker) Split(text string) []Chunk {
+	var (
+		chunks   []Chunk
+		headings []string // current heading per level
+		current  = &section{}
+		inFence  bool
+	)
+
+	flush := func() {
+		for _, t := range c.inner.split(strings.TrimSpace(current.body.String())) {
+			chunks = append(chunks, toChunks([]string{t}, current.heading, len(chunks))...)
+		}
+	}
+
+	for _, line := range strings.SplitAfter(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+		}
+
+		level, title := headingLevel(trimmed)
+		if inFence || level == 0 {
+			current.body.WriteString(line)
+			continue
+		}
+
+		flush()
+		if len(headings) >= level {
+			headings = headings[:level-1]
+		}
+		for len(headings) < level-1 {
+			headings = append(headings, "")
+		}
+		headings = append(headings, title)
+
+		current = &section{heading: joinHeadings(headings)}
+		current.body.WriteString(line)
+	}
+	flush()
+
+	return chunks
+}
+
+// headingLevel returns the level and title of an ATX heading line, or 0.
+func headingLevel(line string) (int, string) {
+	level := 0
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 || (level < len(line) && line[level] != ' ' && line[level] != '\t') {
+		return 0, ""
+	}
+	return level, strings.TrimSpace(strings.TrimRight(line[level:], "#"))
+}
+
+func joinHeadings(headings []string) string {
+	parts := make([]string, 0, len(headings))
+	for _, h := range headings {
+		if h != "" {
+			parts = append(parts, h)
+		}
+	}
+	return strings.Join(parts, " > ")
+}
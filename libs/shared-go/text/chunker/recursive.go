@@ -0,0 +1,75 @@
+package chunker
+
+import "strings"
+
+// defaultSeparators are tried in order: paragraphs, lines, sentences, words.
+var defaultSeparators = []string{"\n\n", "\n", ". ", " "}
+
+// recursiveChunker splits on the coarsest separator that yields pieces small
+// enough, recursing into oversized pieces with finer separators, then merges
+// neighbouring pieces back up to the chunk size.
+type recursiveChunker struct {
+	cfg        Config
+	separators []string
+}
+
+func newRecursive(cfg Config) *recursiveChunker {
+	return &recursiveChunker{cfg: cfg, separators: defaultSeparators}
+}
+
+func (c *recursiveChunker) Split(text string) []Chunk {
+	return toChunks(c.split(strings.TrimSpace(text)), "", 0)
+}
+
+func (c *recursiveChunker) split(text string) []string {
+	return merge(c.pieces(text, c.separators), c.cfg.Size, c.cfg.Overlap)
+}
+
+// pieces breaks text into parts no longer than the chunk size. Separators
+// stay attached to the preceding piece so merging restores the original text.
+func (c *recursiveChunker) pieces(text string, separators []string) []string {
+	if runeLen(text) <= c.cfg.Size {
+		return []string{text}
+	}
+	if len(separators) == 0 {
+		return splitFixed(text, c.cfg.Size, 0)
+	}
+
+	var out []string
+	for _, part := range strings.SplitAfter(text, separators[0]) {
+		if part == "" {
+			continue
+		}
+		out = append(out, c.pieces(part, separators[1:])...)
+	}
+	return out
+}
+
+// merge joins consecutive pieces into chunks of at most size runes. Each new
+// chunk starts with the trailing pieces of the previous one, up to overlap runes.
+func merge(pieces []string, size, overlap int) []string {
+	var (
+		chunks  []string
+		current []string
+		length  int
+	)
+	for _, p := range pieces {
+		n := runeLen(p)
+		if length+n > size && len(current) > 0 {
+			chunks = append(chunks, strings.Join(current, ""))
+
+			// Keep a tail of the chunk as overlap, dropping pieces from the
+			// front until it fits alongside p.
+			for len(current) > 0 && (length > overlap || length+n > size) {
+				length -= runeLen(current[0])
+				current = current[1:]
+			}
+		}
+		current = append(current, p)
+		length += n
+	}
+	if len(current) > 0 {
+		chunks = append(chunks, strings.Join(current, ""))
+	}
+	return chunks
+}
@@ -0,0 +1,64 @@
+package chunker
+
+import (
+	"strings"
+	"unicode"
+)
+
+// sentenceChunker packs whole sentences into chunks, overlapping by trailing
+// sentences. Sentences longer than the chunk size are split on words.
+type sentenceChunker struct {
+	cfg Config
+}
+
+func (c *sentenceChunker) Split(text string) []Chunk {
+	var pieces []string
+	for _, s := range sentences(strings.TrimSpace(text)) {
+		if runeLen(s) > c.cfg.Size {
+			pieces = append(pieces, splitFixed(s, c.cfg.Size, 0)...)
+			continue
+		}
+		pieces = append(pieces, s)
+	}
+	return toChunks(merge(pieces, c.cfg.Size, c.cfg.Overlap), "", 0)
+}
+
+// sentences splits text after '.', '!' or '?' (plus closing quotes or
+// brackets) when followed by whitespace, and at blank lines. Trailing
+// whitespace stays with each sentence.
+func sentences(text string) []string {
+	runes := []rune(text)
+
+	var (
+		out   []string
+		start int
+	)
+	for i := 0; i < len(runes); i++ {
+		end := -1
+		switch {
+		case runes[i] == '.' || runes[i] == '!' || runes[i] == '?':
+			j := i + 1
+			for j < len(runes) && strings.ContainsRune(`"')]`, runes[j]) {
+				j++
+			}
+			if j == len(runes) || unicode.IsSpace(runes[j]) {
+				end = j
+			}
+		case runes[i] == '\n' && i+1 < len(runes) && runes[i+1] == '\n':
+			end = i + 1
+		}
+		if end < 0 {
+			continue
+		}
+
+		for end < len(runes) && unicode.IsSpace(runes[end]) {
+			end++
+		}
+		out = append(out, string(runes[start:end]))
+		start, i = end, end-1
+	}
+	if start < len(runes) {
+		out = append(out, string(runes[start:]))
+	}
+	return out
+}
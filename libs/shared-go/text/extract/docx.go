@@ -0,0 +1,166 @@
+package extract
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// DOCX extracts the paragraphs of a Word document. Paragraphs styled as
+// headings start new sections; the document title comes from its core
+// properties or a "Title" paragraph.
+func DOCX(data []byte) (*Document, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDOCX, err)
+	}
+
+	body, err := readZipFile(zr, "word/document.xml")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDOCX, err)
+	}
+
+	doc := &Document{}
+	if core, err := readZipFile(zr, "docProps/core.xml"); err == nil {
+		var props struct {
+			Title   string `xml:"title"`
+			Creator string `xml:"creator"`
+		}
+		if xml.Unmarshal(core, &props) == nil {
+			doc.Title = strings.TrimSpace(props.Title)
+			if author := strings.TrimSpace(props.Creator); author != "" {
+				doc.Metadata = map[string]any{"author": author}
+			}
+		}
+	}
+
+	paragraphs, err := docxParagraphs(body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDOCX, err)
+	}
+
+	var (
+		path    headingPath
+		current Section
+		text    strings.Builder
+	)
+	for _, p := range paragraphs {
+		if p.style == "title" {
+			if doc.Title == "" {
+				doc.Title = p.text
+			}
+			continue
+		}
+		if level := headingLevel(p.style); level > 0 && p.text != "" {
+			current.Text = text.String()
+			doc.Sections = append(doc.Sections, current)
+			text.Reset()
+			current = Section{Heading: path.push(level, p.text)}
+		}
+		text.WriteString(p.text)
+		text.WriteString("\n")
+	}
+	current.Text = text.String()
+	doc.Sections = append(doc.Sections, current)
+
+	joinSections(doc)
+	return doc, nil
+}
+
+func readZipFile(zr *zip.Reader, name string) ([]byte, error) {
+	f, err := zr.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
+
+type docxParagraph struct {
+	style string // lower-cased style ID, e.g. "heading1"
+	text  string
+}
+
+// docxParagraphs streams word/document.xml, collecting the text runs of
+// each <w:p>. Tabs and breaks become whitespace.
+func docxParagraphs(data []byte) ([]docxParagraph, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+
+	var (
+		out    []docxParagraph
+		cur    *docxParagraph
+		text   strings.Builder
+		inText bool
+	)
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return out, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "p":
+				cur = &docxParagraph{}
+				text.Reset()
+			case "pStyle":
+				if cur != nil {
+					cur.style = strings.ToLower(attr(t, "val"))
+				}
+			case "t":
+				inText = true
+			case "tab":
+				text.WriteByte('\t')
+			case "br", "cr":
+				text.WriteByte('\n')
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				if cur != nil {
+					cur.text = strings.TrimSpace(text.String())
+					out = append(out, *cur)
+					cur = nil
+				}
+			}
+		case xml.CharData:
+			if inText {
+				text.Write(t)
+			}
+		}
+	}
+}
+
+func attr(el xml.StartElement, local string) string {
+	for _, a := range el.Attr {
+		if a.Name.Local == local {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// headingLevel maps Word's built-in heading style IDs ("Heading1" ...) to
+// a level, or 0.
+func headingLevel(style string) int {
+	n, ok := strings.CutPrefix(style, "heading")
+	if !ok {
+		return 0
+	}
+	level, err := strconv.Atoi(n)
+	if err != nil || level < 1 || level > 6 {
+		return 0
+	}
+	return level
+}
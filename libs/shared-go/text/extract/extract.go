@@ -0,0 +1,189 @@
+// Package extract pulls plain text out of uploaded documents so it can be
+// chunked and embedded.
+package extract
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	ErrUnsupportedType = errors.New("unsupported document type")
+	ErrInvalidPDF      = errors.New("invalid or unsupported pdf")
+	ErrEncryptedPDF    = errors.New("encrypted pdfs are not supported")
+	ErrInvalidDOCX     = errors.New("invalid docx file")
+)
+
+// Media types with built-in extractors.
+const (
+	MIMEText     = "text/plain"
+	MIMEMarkdown = "text/markdown"
+	MIMEHTML     = "text/html"
+	MIMEXHTML    = "application/xhtml+xml"
+	MIMEPDF      = "application/pdf"
+	MIMEDOCX     = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+)
+
+// Document is the text extracted from a file.
+type Document struct {
+	Title string `json:"title,omitempty"`
+	Text  string `json:"text"`
+
+	// Pages is set for paginated formats such as PDF. Text is the pages
+	// joined by blank lines.
+	Pages []Page `json:"pages,omitempty"`
+
+	// Sections is set for formats with headings (Markdown, HTML, DOCX).
+	// Text is the sections joined by blank lines.
+	Sections []Section `json:"sections,omitempty"`
+
+	Metadata map[string]any `json:"metadata,omitempty"`
+}
+
+type Page struct {
+	Number int    `json:"number"` // 1-based
+	Text   string `json:"text"`
+}
+
+// Section is the text under a heading. Heading is the heading path, e.g.
+// "Install > Linux"; it is empty for text before the first heading.
+type Section struct {
+	Heading string `json:"heading,omitempty"`
+	Text    string `json:"text"`
+}
+
+type Extractor interface {
+	Extract(data []byte) (*Document, error)
+}
+
+// ExtractorFunc adapts a function to Extractor.
+type ExtractorFunc func(data []byte) (*Document, error)
+
+func (f ExtractorFunc) Extract(data []byte) (*Document, error) {
+	return f(data)
+}
+
+// Registry maps media types to extractors.
+type Registry struct {
+	extractors map[string]Extractor
+}
+
+// NewRegistry returns a registry with the built-in extractors registered.
+func NewRegistry() *Registry {
+	r := &Registry{extractors: make(map[string]Extractor)}
+	r.Register(MIMEText, ExtractorFunc(Text))
+	r.Register(MIMEMarkdown, ExtractorFunc(Markdown))
+	r.Register("text/x-markdown", ExtractorFunc(Markdown))
+	r.Register(MIMEHTML, ExtractorFunc(HTML))
+	r.Register(MIMEXHTML, ExtractorFunc(HTML))
+	r.Register(MIMEPDF, ExtractorFunc(PDF))
+	r.Register(MIMEDOCX, ExtractorFunc(DOCX))
+	return r
+}
+
+// Register sets the extractor for a media type, replacing any existing one.
+func (r *Registry) Register(mediaType string, extractor Extractor) {
+	r.extractors[strings.ToLower(mediaType)] = extractor
+}
+
+// Lookup returns the extractor for mediaType. Unregistered text/* types
+// fall back to plain text.
+func (r *Registry) Lookup(mediaType string) (Extractor, bool) {
+	mediaType = strings.ToLower(mediaType)
+	if e, ok := r.extractors[mediaType]; ok {
+		return e, true
+	}
+	if strings.HasPrefix(mediaType, "text/") {
+		return r.extractors[MIMEText], r.extractors[MIMEText] != nil
+	}
+	return nil, false
+}
+
+func (r *Registry) Extract(mediaType string, data []byte) (*Document, error) {
+	e, ok := r.Lookup(mediaType)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, mediaType)
+	}
+	return e.Extract(data)
+}
+
+// extensions covers types that mime.TypeByExtension often lacks.
+var extensions = map[string]string{
+	".txt":      MIMEText,
+	".md":       MIMEMarkdown,
+	".markdown": MIMEMarkdown,
+	".htm":      MIMEHTML,
+	".html":     MIMEHTML,
+	".xhtml":    MIMEXHTML,
+	".pdf":      MIMEPDF,
+	".docx":     MIMEDOCX,
+}
+
+// MediaType returns the media type of an upload from its declared
+// Content-Type, falling back to the file extension when the declared type is
+// missing or generic.
+func MediaType(declared, filename string) string {
+	mediaType, _, _ := mime.ParseMediaType(declared)
+	if mediaType != "" && mediaType != "application/octet-stream" {
+		return mediaType
+	}
+
+	ext := strings.ToLower(filepath.Ext(filename))
+	if t, ok := extensions[ext]; ok {
+		return t
+	}
+	if t, _, err := mime.ParseMediaType(mime.TypeByExtension(ext)); err == nil {
+		return t
+	}
+	return mediaType
+}
+
+// Text extracts a plain text file. Invalid UTF-8 is rejected.
+func Text(data []byte) (*Document, error) {
+	if !utf8.Valid(data) {
+		return nil, fmt.Errorf("%w: text is not valid utf-8", ErrUnsupportedType)
+	}
+	return &Document{Text: string(data)}, nil
+}
+
+// joinSections fills Text from the sections of doc, dropping empty ones.
+func joinSections(doc *Document) {
+	sections := doc.Sections[:0]
+	texts := make([]string, 0, len(doc.Sections))
+	for _, s := range doc.Sections {
+		if s.Text = strings.TrimSpace(s.Text); s.Text == "" {
+			continue
+		}
+		sections = append(sections, s)
+		texts = append(texts, s.Text)
+	}
+	doc.Sections = sections
+	doc.Text = strings.Join(texts, "\n\n")
+}
+
+// headingPath tracks the current heading at each level.
+type headingPath []string
+
+// push sets the heading at level (1-6) and returns the resulting path.
+func (p *headingPath) push(level int, title string) string {
+	h := *p
+	if len(h) >= level {
+		h = h[:level-1]
+	}
+	for len(h) < level-1 {
+		h = append(h, "")
+	}
+	*p = append(h, title)
+
+	parts := make([]string, 0, len(*p))
+	for _, t := range *p {
+		if t != "" {
+			parts = append(parts, t)
+		}
+	}
+	return strings.Join(parts, " > ")
+}
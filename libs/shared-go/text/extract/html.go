@@ -0,0 +1,171 @@
+package extract
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// skippedElements never contain readable content.
+var skippedElements = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Noscript: true, atom.Template: true,
+	atom.Iframe: true, atom.Svg: true, atom.Canvas: true, atom.Form: true,
+	atom.Button: true, atom.Select: true, atom.Nav: true, atom.Header: true,
+	atom.Footer: true, atom.Aside: true,
+}
+
+// boilerplate matches class and id values of navigation, ads and similar
+// page furniture.
+var boilerplate = regexp.MustCompile(`(?i)(^|[\s_-])(nav|navbar|menu|sidebar|footer|header|banner|cookie|consent|advert|ads?|promo|share|social|comments?|related|breadcrumbs?|popup|modal|newsletter|subscribe)($|[\s_-])`)
+
+// blockElements end the current line.
+var blockElements = map[atom.Atom]bool{
+	atom.P: true, atom.Div: true, atom.Section: true, atom.Article: true,
+	atom.Main: true, atom.Li: true, atom.Ul: true, atom.Ol: true, atom.Dl: true,
+	atom.Dt: true, atom.Dd: true, atom.Table: true, atom.Tr: true,
+	atom.Blockquote: true, atom.Pre: true, atom.Br: true, atom.Hr: true,
+	atom.Figure: true, atom.Figcaption: true,
+}
+
+var headingLevels = map[atom.Atom]int{
+	atom.H1: 1, atom.H2: 2, atom.H3: 3, atom.H4: 4, atom.H5: 5, atom.H6: 6,
+}
+
+// HTML extracts the main content of a web page. Scripts, navigation, headers,
+// footers and elements whose class or id looks like boilerplate are dropped;
+// when the page has a <main> or <article>, only that is used. Headings start
+// new sections.
+func HTML(data []byte) (*Document, error) {
+	root, err := html.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &Document{}
+	if title := findElement(root, atom.Title); title != nil {
+		doc.Title = collapseSpace(textContent(title))
+	}
+
+	content := findElement(root, atom.Main)
+	if content == nil {
+		content = findElement(root, atom.Article)
+	}
+	if content == nil {
+		if content = findElement(root, atom.Body); content == nil {
+			content = root
+		}
+	}
+
+	w := &htmlWriter{doc: doc}
+	w.walk(content)
+	w.flush()
+
+	if doc.Title == "" {
+		if h1 := findElement(content, atom.H1); h1 != nil {
+			doc.Title = collapseSpace(textContent(h1))
+		}
+	}
+
+	joinSections(doc)
+	return doc, nil
+}
+
+type htmlWriter struct {
+	doc     *Document
+	path    headingPath
+	heading string
+	buf     strings.Builder
+}
+
+func (w *htmlWriter) walk(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		w.buf.WriteString(n.Data)
+		return
+	case html.ElementNode:
+		if skippedElements[n.DataAtom] || isBoilerplate(n) {
+			return
+		}
+		if level := headingLevels[n.DataAtom]; level > 0 {
+			w.flush()
+			title := collapseSpace(textContent(n))
+			w.heading = w.path.push(level, title)
+			w.buf.WriteString(title + "\n")
+			return
+		}
+		if n.DataAtom == atom.Pre {
+			w.buf.WriteString("\n" + textContent(n) + "\n")
+			return
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		w.walk(c)
+	}
+
+	if n.Type == html.ElementNode && blockElements[n.DataAtom] {
+		w.buf.WriteString("\n")
+	}
+}
+
+// flush ends the current section, collapsing whitespace within lines.
+func (w *htmlWriter) flush() {
+	var lines []string
+	for _, line := range strings.Split(w.buf.String(), "\n") {
+		if line = collapseSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	w.doc.Sections = append(w.doc.Sections, Section{Heading: w.heading, Text: strings.Join(lines, "\n")})
+	w.buf.Reset()
+}
+
+func isBoilerplate(n *html.Node) bool {
+	for _, a := range n.Attr {
+		switch a.Key {
+		case "class", "id":
+			if boilerplate.MatchString(a.Val) {
+				return true
+			}
+		case "role":
+			if a.Val == "navigation" || a.Val == "banner" || a.Val == "contentinfo" {
+				return true
+			}
+		case "hidden", "aria-hidden":
+			if a.Key == "hidden" || a.Val == "true" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func findElement(n *html.Node, a atom.Atom) *html.Node {
+	if n.Type == html.ElementNode && n.DataAtom == a {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := findElement(c, a); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var sb strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		sb.WriteString(textContent(c))
+	}
+	return sb.String()
+}
+
+func collapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,58 @@
+package extract
+
+import "strings"
+
+// Markdown splits a Markdown document into sections at ATX headings. The
+// first level-one heading becomes the title.
+func Markdown(data []byte) (*Document, error) {
+	doc, err := Text(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		path    headingPath
+		current = Section{}
+		body    strings.Builder
+		inFence bool
+	)
+	for _, line := range strings.SplitAfter(doc.Text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+		}
+
+		level, title := atxHeading(trimmed)
+		if inFence || level == 0 {
+			body.WriteString(line)
+			continue
+		}
+
+		current.Text = body.String()
+		doc.Sections = append(doc.Sections, current)
+		body.Reset()
+
+		if level == 1 && doc.Title == "" {
+			doc.Title = title
+		}
+		current = Section{Heading: path.push(level, title)}
+		body.WriteString(line)
+	}
+	current.Text = body.String()
+	doc.Sections = append(doc.Sections, current)
+
+	joinSections(doc)
+	return doc, nil
+}
+
+// atxHeading returns the level and title of a "# Title" line, or 0.
+func atxHeading(line string) (int, string) {
+	level := 0
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 || (level < len(line) && line[level] != ' ' && line[level] != '\t') {
+		return 0, ""
+	}
+	return level, strings.TrimSpace(strings.TrimRight(line[level:], "#"))
+}
@@ -0,0 +1,348 @@
+package extract
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf16"
+)
+
+// PDF extracts the text layer of a PDF page by page. Scanned PDFs without a
+// text layer yield empty pages; OCR is out of scope.
+func PDF(data []byte) (*Document, error) {
+	f, err := parsePDF(data)
+	if err != nil {
+		return nil, err
+	}
+
+	pages := f.pages()
+	if len(pages) == 0 {
+		return nil, fmt.Errorf("%w: no pages found", ErrInvalidPDF)
+	}
+
+	doc := &Document{Metadata: map[string]any{"page_count": len(pages)}}
+	texts := make([]string, 0, len(pages))
+	for i, page := range pages {
+		text := strings.TrimSpace(f.pageText(page))
+		doc.Pages = append(doc.Pages, Page{Number: i + 1, Text: text})
+		if text != "" {
+			texts = append(texts, text)
+		}
+	}
+	doc.Text = strings.Join(texts, "\n\n")
+
+	if info := f.dict(f.trailer[name("Info")]); info != nil {
+		for key, field := range map[name]string{"Title": "title", "Author": "author", "Subject": "subject"} {
+			if s, ok := f.resolve(info[key]).(pdfString); ok {
+				if v := strings.TrimSpace(decodeTextString(s)); v != "" {
+					doc.Metadata[field] = v
+				}
+			}
+		}
+		doc.Title, _ = doc.Metadata["title"].(string)
+	}
+
+	return doc, nil
+}
+
+// pdfPage is a page dictionary with its inherited resources.
+type pdfPage struct {
+	dict      dict
+	resources dict
+}
+
+// pages walks the page tree from the catalog, falling back to every /Page
+// object in file order when the tree is missing or broken.
+func (f *pdfFile) pages() []pdfPage {
+	var pages []pdfPage
+	if root := f.dict(f.trailer[name("Root")]); root != nil {
+		f.walkPages(root[name("Pages")], nil, map[any]bool{}, &pages)
+	}
+	if len(pages) > 0 {
+		return pages
+	}
+
+	for num := range f.maxObject() + 1 {
+		if d := f.dict(f.objects[num]); d != nil && d[name("Type")] == name("Page") {
+			pages = append(pages, pdfPage{dict: d, resources: f.dict(d[name("Resources")])})
+		}
+	}
+	return pages
+}
+
+func (f *pdfFile) maxObject() int64 {
+	var n int64
+	for num := range f.objects {
+		n = max(n, num)
+	}
+	return n
+}
+
+func (f *pdfFile) walkPages(node any, resources dict, seen map[any]bool, pages *[]pdfPage) {
+	if r, ok := node.(ref); ok {
+		if seen[r] {
+			return
+		}
+		seen[r] = true
+	}
+	d := f.dict(node)
+	if d == nil {
+		return
+	}
+	if res := f.dict(d[name("Resources")]); res != nil {
+		resources = res
+	}
+
+	kids, ok := f.resolve(d[name("Kids")]).(array)
+	if !ok {
+		if d[name("Type")] != name("Pages") {
+			*pages = append(*pages, pdfPage{dict: d, resources: resources})
+		}
+		return
+	}
+	for _, kid := range kids {
+		f.walkPages(kid, resources, seen, pages)
+	}
+}
+
+// pageText concatenates and interprets the page's content streams.
+func (f *pdfFile) pageText(page pdfPage) string {
+	var streams []any
+	switch v := f.resolve(page.dict[name("Contents")]).(type) {
+	case array:
+		streams = v
+	case *stream:
+		streams = []any{v}
+	}
+
+	var content []byte
+	for _, obj := range streams {
+		s, ok := f.resolve(obj).(*stream)
+		if !ok {
+			continue
+		}
+		data, err := f.decode(s)
+		if err != nil {
+			continue
+		}
+		content = append(content, data...)
+		content = append(content, '\n')
+	}
+
+	r := &textReader{file: f, resources: page.resources, fonts: f.fonts(page.resources)}
+	r.run(content, 0)
+	return r.text()
+}
+
+// font decodes strings shown with a PDF font.
+type font struct {
+	cmap     *cmap // from /ToUnicode, if any
+	twoBytes bool  // composite (Type0) font without a ToUnicode map
+}
+
+func (f *pdfFile) fonts(resources dict) map[name]*font {
+	fonts := make(map[name]*font)
+	for key, obj := range f.dict(resources[name("Font")]) {
+		d := f.dict(obj)
+		if d == nil {
+			continue
+		}
+		ft := &font{twoBytes: d[name("Subtype")] == name("Type0")}
+		if s, ok := f.resolve(d[name("ToUnicode")]).(*stream); ok {
+			if data, err := f.decode(s); err == nil {
+				ft.cmap = parseCMap(data)
+			}
+		}
+		fonts[key] = ft
+	}
+	return fonts
+}
+
+func (ft *font) decode(s pdfString) string {
+	if ft != nil && ft.cmap != nil {
+		return ft.cmap.decode([]byte(s))
+	}
+	if ft != nil && ft.twoBytes {
+		return "" // glyph IDs with no Unicode mapping
+	}
+	return decodeLatin1([]byte(s))
+}
+
+// textReader interprets the text operators of a content stream.
+type textReader struct {
+	file      *pdfFile
+	resources dict
+	fonts     map[name]*font
+	font      *font
+	buf       strings.Builder
+	lineY     float64
+}
+
+func (r *textReader) run(content []byte, depth int) {
+	l := &lexer{data: content}
+	var operands []any
+	for {
+		obj, err := l.next()
+		if err != nil {
+			return
+		}
+		op, ok := obj.(keyword)
+		if !ok {
+			operands = append(operands, obj)
+			continue
+		}
+
+		switch op {
+		case "Tf":
+			if len(operands) >= 2 {
+				if n, ok := operands[len(operands)-2].(name); ok {
+					r.font = r.fonts[n]
+				}
+			}
+		case "Tj":
+			r.show(last(operands))
+		case "'", "\"":
+			r.newline()
+			r.show(last(operands))
+		case "TJ":
+			if arr, ok := last(operands).(array); ok {
+				for _, item := range arr {
+					if kern, ok := number(item); ok && kern < -200 {
+						r.space()
+						continue
+					}
+					r.show(item)
+				}
+			}
+		case "Td", "TD":
+			if len(operands) >= 2 {
+				tx, _ := number(operands[len(operands)-2])
+				ty, _ := number(operands[len(operands)-1])
+				if ty != 0 {
+					r.newline()
+				} else if tx != 0 {
+					r.space()
+				}
+			}
+		case "Tm":
+			if len(operands) >= 6 {
+				if y, ok := number(operands[len(operands)-1]); ok && y != r.lineY {
+					r.lineY = y
+					r.newline()
+				}
+			}
+		case "T*":
+			r.newline()
+		case "ET":
+			r.space()
+		case "Do":
+			if n, ok := last(operands).(name); ok && depth < 4 {
+				r.form(n, depth)
+			}
+		case "BI":
+			skipInlineImage(l)
+		}
+		operands = operands[:0]
+	}
+}
+
+// form runs the content stream of a form XObject, which may carry its own
+// fonts.
+func (r *textReader) form(n name, depth int) {
+	s, ok := r.file.resolve(r.file.dict(r.resources[name("XObject")])[n]).(*stream)
+	if !ok || s.dict[name("Subtype")] != name("Form") {
+		return
+	}
+	data, err := r.file.decode(s)
+	if err != nil {
+		return
+	}
+
+	outer := *r
+	if res := r.file.dict(s.dict[name("Resources")]); res != nil {
+		r.resources, r.fonts = res, r.file.fonts(res)
+	}
+	r.run(data, depth+1)
+	r.resources, r.fonts, r.font = outer.resources, outer.fonts, outer.font
+}
+
+func (r *textReader) show(obj any) {
+	if s, ok := obj.(pdfString); ok {
+		r.buf.WriteString(r.font.decode(s))
+	}
+}
+
+func (r *textReader) space() {
+	if s := r.buf.String(); s != "" && !strings.HasSuffix(s, " ") && !strings.HasSuffix(s, "\n") {
+		r.buf.WriteByte(' ')
+	}
+}
+
+func (r *textReader) newline() {
+	if s := r.buf.String(); s != "" && !strings.HasSuffix(s, "\n") {
+		r.buf.WriteByte('\n')
+	}
+}
+
+// text returns the collected text with runs of spaces collapsed.
+func (r *textReader) text() string {
+	lines := strings.Split(r.buf.String(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.Join(strings.Fields(line), " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// skipInlineImage moves past inline image data, which ends at "EI".
+func skipInlineImage(l *lexer) {
+	for !l.eof() {
+		if l.pos+2 < len(l.data) && l.data[l.pos] == 'E' && l.data[l.pos+1] == 'I' &&
+			isSpace(l.data[l.pos+2]) && l.pos > 0 && isSpace(l.data[l.pos-1]) {
+			l.pos += 2
+			return
+		}
+		l.pos++
+	}
+}
+
+func last(operands []any) any {
+	if len(operands) == 0 {
+		return nil
+	}
+	return operands[len(operands)-1]
+}
+
+func number(obj any) (float64, bool) {
+	switch v := obj.(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// decodeTextString decodes a PDF text string (UTF-16BE with BOM, or
+// PDFDocEncoding, approximated as Latin-1).
+func decodeTextString(s pdfString) string {
+	b := []byte(s)
+	if len(b) >= 2 && b[0] == 0xFE && b[1] == 0xFF {
+		return decodeUTF16(b[2:])
+	}
+	return decodeLatin1(b)
+}
+
+func decodeUTF16(b []byte) string {
+	u := make([]uint16, 0, len(b)/2)
+	for i := 0; i+1 < len(b); i += 2 {
+		u = append(u, uint16(b[i])<<8|uint16(b[i+1]))
+	}
+	return string(utf16.Decode(u))
+}
+
+func decodeLatin1(b []byte) string {
+	runes := make([]rune, len(b))
+	for i, c := range b {
+		runes[i] = rune(c)
+	}
+	return string(runes)
+}
@@ -0,0 +1,135 @@
+package extract
+
+import "strings"
+
+// cmap is a parsed /ToUnicode map from character codes to text.
+type cmap struct {
+	codeLen int // bytes per code
+	chars   map[uint32]string
+	ranges  []cmapRange
+}
+
+type cmapRange struct {
+	lo, hi uint32
+	base   []byte   // UTF-16BE of lo; incremented for later codes
+	names  []string // explicit destinations, when given as an array
+}
+
+func parseCMap(data []byte) *cmap {
+	m := &cmap{chars: make(map[uint32]string)}
+	l := &lexer{data: data}
+
+	var operands []any
+	for {
+		obj, err := l.next()
+		if err != nil {
+			break
+		}
+		op, ok := obj.(keyword)
+		if !ok {
+			operands = append(operands, obj)
+			continue
+		}
+
+		switch op {
+		case "endcodespacerange":
+			if len(operands) >= 1 {
+				if s, ok := operands[0].(pdfString); ok {
+					m.codeLen = len(s)
+				}
+			}
+		case "endbfchar":
+			for i := 0; i+1 < len(operands); i += 2 {
+				src, ok1 := operands[i].(pdfString)
+				dst, ok2 := operands[i+1].(pdfString)
+				if ok1 && ok2 {
+					m.setCodeLen(len(src))
+					m.chars[code(src)] = decodeUTF16([]byte(dst))
+				}
+			}
+		case "endbfrange":
+			for i := 0; i+2 < len(operands); i += 3 {
+				lo, ok1 := operands[i].(pdfString)
+				hi, ok2 := operands[i+1].(pdfString)
+				if !ok1 || !ok2 {
+					continue
+				}
+				m.setCodeLen(len(lo))
+				r := cmapRange{lo: code(lo), hi: code(hi)}
+				switch dst := operands[i+2].(type) {
+				case pdfString:
+					r.base = []byte(dst)
+				case array:
+					for _, item := range dst {
+						s, _ := item.(pdfString)
+						r.names = append(r.names, decodeUTF16([]byte(s)))
+					}
+				}
+				m.ranges = append(m.ranges, r)
+			}
+		}
+		operands = operands[:0]
+	}
+
+	if m.codeLen == 0 {
+		m.codeLen = 1
+	}
+	return m
+}
+
+func (m *cmap) setCodeLen(n int) {
+	if m.codeLen == 0 && n > 0 {
+		m.codeLen = n
+	}
+}
+
+func code(s pdfString) uint32 {
+	var c uint32
+	for _, b := range []byte(s) {
+		c = c<<8 | uint32(b)
+	}
+	return c
+}
+
+func (m *cmap) decode(b []byte) string {
+	var sb strings.Builder
+	for i := 0; i+m.codeLen <= len(b); i += m.codeLen {
+		c := code(pdfString(b[i : i+m.codeLen]))
+		if s, ok := m.lookup(c); ok {
+			sb.WriteString(s)
+		} else if m.codeLen == 1 {
+			sb.WriteRune(rune(c))
+		}
+	}
+	return sb.String()
+}
+
+func (m *cmap) lookup(c uint32) (string, bool) {
+	if s, ok := m.chars[c]; ok {
+		return s, true
+	}
+	for _, r := range m.ranges {
+		if c < r.lo || c > r.hi {
+			continue
+		}
+		offset := c - r.lo
+		if r.names != nil {
+			if int(offset) < len(r.names) {
+				return r.names[offset], true
+			}
+			return "", false
+		}
+		if len(r.base) == 0 {
+			return "", false
+		}
+		// Increment the last byte pair of the destination.
+		dst := append([]byte(nil), r.base...)
+		last := uint32(dst[len(dst)-1]) + offset
+		dst[len(dst)-1] = byte(last)
+		if len(dst) >= 2 {
+			dst[len(dst)-2] += byte(last >> 8)
+		}
+		return decodeUTF16(dst), true
+	}
+	return "", false
+}
@@ -0,0 +1,498 @@
+package extract
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+)
+
+// PDF object model. Numbers are int64 or float64, strings are pdfString and
+// operators in content streams are keyword.
+type (
+	name      string
+	pdfString string
+	keyword   string
+	array     []any
+	dict      map[name]any
+	ref       struct{ num, gen int64 }
+	stream    struct {
+		dict dict
+		data []byte
+	}
+)
+
+// lexer reads PDF objects from a byte slice.
+type lexer struct {
+	data []byte
+	pos  int
+}
+
+func isSpace(c byte) bool {
+	switch c {
+	case 0, '\t', '\n', '\f', '\r', ' ':
+		return true
+	}
+	return false
+}
+
+func isDelimiter(c byte) bool {
+	switch c {
+	case '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+	return false
+}
+
+func (l *lexer) eof() bool {
+	return l.pos >= len(l.data)
+}
+
+func (l *lexer) skipSpace() {
+	for !l.eof() {
+		switch c := l.data[l.pos]; {
+		case isSpace(c):
+			l.pos++
+		case c == '%':
+			for !l.eof() && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// next returns the next object, or io.EOF. Closing delimiters are returned
+// as keywords so callers can detect the end of arrays and dictionaries.
+func (l *lexer) next() (any, error) {
+	l.skipSpace()
+	if l.eof() {
+		return nil, io.EOF
+	}
+
+	switch c := l.data[l.pos]; {
+	case c == '/':
+		l.pos++
+		return l.name(), nil
+	case c == '(':
+		l.pos++
+		return l.literalString(), nil
+	case c == '<':
+		if l.pos+1 < len(l.data) && l.data[l.pos+1] == '<' {
+			l.pos += 2
+			return l.dict()
+		}
+		l.pos++
+		return l.hexString(), nil
+	case c == '>' && l.pos+1 < len(l.data) && l.data[l.pos+1] == '>':
+		l.pos += 2
+		return keyword(">>"), nil
+	case c == '[':
+		l.pos++
+		return l.array()
+	case c == ']' || c == '{' || c == '}' || c == ')' || c == '>':
+		l.pos++
+		return keyword([]byte{c}), nil
+	case c == '+' || c == '-' || c == '.' || (c >= '0' && c <= '9'):
+		return l.number(), nil
+	}
+
+	word := l.regular()
+	switch word {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	return keyword(word), nil
+}
+
+func (l *lexer) regular() string {
+	start := l.pos
+	for !l.eof() && !isSpace(l.data[l.pos]) && !isDelimiter(l.data[l.pos]) {
+		l.pos++
+	}
+	if l.pos == start {
+		l.pos++ // never stall on an unexpected byte
+	}
+	return string(l.data[start:l.pos])
+}
+
+func (l *lexer) name() name {
+	raw := l.regular()
+	if !bytes.ContainsRune([]byte(raw), '#') {
+		return name(raw)
+	}
+	var b []byte
+	for i := 0; i < len(raw); i++ {
+		if raw[i] == '#' && i+2 < len(raw) {
+			if v, err := strconv.ParseUint(raw[i+1:i+3], 16, 8); err == nil {
+				b = append(b, byte(v))
+				i += 2
+				continue
+			}
+		}
+		b = append(b, raw[i])
+	}
+	return name(b)
+}
+
+// number reads a number, folding "num gen R" into a ref.
+func (l *lexer) number() any {
+	start := l.pos
+	l.pos++
+	for !l.eof() && (l.data[l.pos] == '.' || (l.data[l.pos] >= '0' && l.data[l.pos] <= '9')) {
+		l.pos++
+	}
+	raw := string(l.data[start:l.pos])
+
+	n, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		f, _ := strconv.ParseFloat(raw, 64)
+		return f
+	}
+
+	// Look ahead for "gen R".
+	save := l.pos
+	l.skipSpace()
+	genStart := l.pos
+	for !l.eof() && l.data[l.pos] >= '0' && l.data[l.pos] <= '9' {
+		l.pos++
+	}
+	if l.pos > genStart {
+		gen, _ := strconv.ParseInt(string(l.data[genStart:l.pos]), 10, 64)
+		l.skipSpace()
+		if !l.eof() && l.data[l.pos] == 'R' && (l.pos+1 == len(l.data) || isSpace(l.data[l.pos+1]) || isDelimiter(l.data[l.pos+1])) {
+			l.pos++
+			return ref{num: n, gen: gen}
+		}
+	}
+	l.pos = save
+	return n
+}
+
+func (l *lexer) literalString() pdfString {
+	var b []byte
+	depth := 1
+	for !l.eof() {
+		c := l.data[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth == 0 {
+				return pdfString(b)
+			}
+		case '\\':
+			if l.eof() {
+				continue
+			}
+			e := l.data[l.pos]
+			l.pos++
+			switch e {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r':
+				if !l.eof() && l.data[l.pos] == '\n' {
+					l.pos++
+				}
+				continue
+			case '\n':
+				continue
+			default:
+				if e >= '0' && e <= '7' {
+					v := int(e - '0')
+					for i := 0; i < 2 && !l.eof() && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; i++ {
+						v = v*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+					c = byte(v)
+				} else {
+					c = e
+				}
+			}
+		}
+		b = append(b, c)
+	}
+	return pdfString(b)
+}
+
+func (l *lexer) hexString() pdfString {
+	var digits []byte
+	for !l.eof() && l.data[l.pos] != '>' {
+		if c := l.data[l.pos]; !isSpace(c) {
+			digits = append(digits, c)
+		}
+		l.pos++
+	}
+	l.pos++
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	b := make([]byte, len(digits)/2)
+	n, _ := hex.Decode(b, digits)
+	return pdfString(b[:n])
+}
+
+func (l *lexer) array() (array, error) {
+	var arr array
+	for {
+		obj, err := l.next()
+		if err != nil {
+			return arr, err
+		}
+		if obj == keyword("]") {
+			return arr, nil
+		}
+		arr = append(arr, obj)
+	}
+}
+
+func (l *lexer) dict() (dict, error) {
+	d := make(dict)
+	for {
+		key, err := l.next()
+		if err != nil {
+			return d, err
+		}
+		if key == keyword(">>") {
+			return d, nil
+		}
+		k, ok := key.(name)
+		if !ok {
+			continue
+		}
+		value, err := l.next()
+		if err != nil {
+			return d, err
+		}
+		if value == keyword(">>") {
+			return d, nil
+		}
+		d[k] = value
+	}
+}
+
+// pdfFile holds every object of a PDF, found by scanning for "n g obj"
+// rather than trusting the xref table, which is frequently broken.
+type pdfFile struct {
+	objects map[int64]any
+	trailer dict
+}
+
+var objectHeader = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+
+func parsePDF(data []byte) (*pdfFile, error) {
+	if !bytes.HasPrefix(bytes.TrimLeft(data, " \t\r\n"), []byte("%PDF-")) {
+		return nil, fmt.Errorf("%w: missing %%PDF header", ErrInvalidPDF)
+	}
+
+	f := &pdfFile{objects: make(map[int64]any), trailer: make(dict)}
+
+	end := 0
+	for _, m := range objectHeader.FindAllSubmatchIndex(data, -1) {
+		if m[0] < end {
+			continue // inside the previous object's stream
+		}
+		num, _ := strconv.ParseInt(string(data[m[2]:m[3]]), 10, 64)
+
+		l := &lexer{data: data, pos: m[1]}
+		obj, err := l.next()
+		if err != nil {
+			continue
+		}
+		if d, ok := obj.(dict); ok {
+			if s, next, ok := readStream(data, l.pos, d); ok {
+				obj, l.pos = s, next
+			}
+		}
+		f.objects[num] = obj
+		end = l.pos
+
+		// Cross-reference streams double as the trailer in PDF 1.5+.
+		if s, ok := obj.(*stream); ok && s.dict[name("Type")] == name("XRef") {
+			f.mergeTrailer(s.dict)
+		}
+	}
+
+	for _, idx := range allIndexes(data, []byte("trailer")) {
+		l := &lexer{data: data, pos: idx + len("trailer")}
+		if obj, err := l.next(); err == nil {
+			if d, ok := obj.(dict); ok {
+				f.mergeTrailer(d)
+			}
+		}
+	}
+
+	if _, ok := f.trailer[name("Encrypt")]; ok {
+		return nil, ErrEncryptedPDF
+	}
+
+	f.expandObjectStreams()
+	return f, nil
+}
+
+func (f *pdfFile) mergeTrailer(d dict) {
+	for _, key := range []name{"Root", "Info", "Encrypt"} {
+		if v, ok := d[key]; ok {
+			f.trailer[key] = v
+		}
+	}
+}
+
+// readStream reads the stream following a dictionary ending at pos. It uses
+// /Length when it is direct and consistent, otherwise the next "endstream".
+func readStream(data []byte, pos int, d dict) (*stream, int, bool) {
+	l := &lexer{data: data, pos: pos}
+	l.skipSpace()
+	if !bytes.HasPrefix(data[l.pos:], []byte("stream")) {
+		return nil, pos, false
+	}
+	start := l.pos + len("stream")
+	if start < len(data) && data[start] == '\r' {
+		start++
+	}
+	if start < len(data) && data[start] == '\n' {
+		start++
+	}
+
+	if length, ok := d[name("Length")].(int64); ok && length >= 0 && start+int(length) <= len(data) {
+		end := start + int(length)
+		rest := bytes.TrimLeft(data[end:min(end+32, len(data))], " \t\r\n")
+		if bytes.HasPrefix(rest, []byte("endstream")) {
+			return &stream{dict: d, data: data[start:end]}, end, true
+		}
+	}
+
+	idx := bytes.Index(data[start:], []byte("endstream"))
+	if idx < 0 {
+		return &stream{dict: d, data: data[start:]}, len(data), true
+	}
+	end := start + idx
+	body := bytes.TrimSuffix(bytes.TrimSuffix(data[start:end], []byte("\n")), []byte("\r"))
+	return &stream{dict: d, data: body}, end + len("endstream"), true
+}
+
+func allIndexes(data, sep []byte) []int {
+	var out []int
+	for off := 0; ; {
+		i := bytes.Index(data[off:], sep)
+		if i < 0 {
+			return out
+		}
+		out = append(out, off+i)
+		off += i + len(sep)
+	}
+}
+
+// expandObjectStreams adds the objects packed inside /ObjStm streams.
+// Objects defined directly in the file take precedence.
+func (f *pdfFile) expandObjectStreams() {
+	for _, obj := range f.objects {
+		s, ok := obj.(*stream)
+		if !ok || s.dict[name("Type")] != name("ObjStm") {
+			continue
+		}
+		data, err := f.decode(s)
+		if err != nil {
+			continue
+		}
+		n, _ := s.dict[name("N")].(int64)
+		first, _ := s.dict[name("First")].(int64)
+		if first <= 0 || int(first) > len(data) {
+			continue
+		}
+
+		header := &lexer{data: data[:first]}
+		for i := int64(0); i < n; i++ {
+			num, err1 := header.next()
+			off, err2 := header.next()
+			objNum, ok1 := num.(int64)
+			objOff, ok2 := off.(int64)
+			if err1 != nil || err2 != nil || !ok1 || !ok2 {
+				break
+			}
+			if _, exists := f.objects[objNum]; exists {
+				continue
+			}
+			l := &lexer{data: data, pos: int(first + objOff)}
+			if l.pos >= len(data) {
+				continue
+			}
+			if v, err := l.next(); err == nil {
+				f.objects[objNum] = v
+			}
+		}
+	}
+}
+
+// resolve follows indirect references.
+func (f *pdfFile) resolve(obj any) any {
+	for range 32 {
+		r, ok := obj.(ref)
+		if !ok {
+			return obj
+		}
+		obj = f.objects[r.num]
+	}
+	return nil
+}
+
+func (f *pdfFile) dict(obj any) dict {
+	switch v := f.resolve(obj).(type) {
+	case dict:
+		return v
+	case *stream:
+		return v.dict
+	}
+	return nil
+}
+
+// decode returns the decoded stream data. Only FlateDecode and
+// ASCIIHexDecode are supported; they cover nearly all text content.
+func (f *pdfFile) decode(s *stream) ([]byte, error) {
+	var filters []any
+	switch v := f.resolve(s.dict[name("Filter")]).(type) {
+	case name:
+		filters = []any{v}
+	case array:
+		filters = v
+	}
+
+	data := s.data
+	for _, filter := range filters {
+		switch f.resolve(filter) {
+		case name("FlateDecode"), name("Fl"):
+			zr, err := zlib.NewReader(bytes.NewReader(data))
+			if err != nil {
+				return nil, fmt.Errorf("inflate stream: %w", err)
+			}
+			out, err := io.ReadAll(zr)
+			if err != nil && len(out) == 0 {
+				return nil, fmt.Errorf("inflate stream: %w", err)
+			}
+			data = out
+		case name("ASCIIHexDecode"), name("AHx"):
+			l := &lexer{data: data}
+			data = []byte(l.hexString())
+		default:
+			return nil, fmt.Errorf("unsupported stream filter: %v", filter)
+		}
+	}
+	return data, nil
+}
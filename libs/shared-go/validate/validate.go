@@ -0,0 +1,81 @@
+// Package validate collects field-level validation errors, so that a
+// request reports every invalid field at once instead of the first one.
+package validate
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// FieldError is a failed check on one field. Field is the JSON path of the
+// field, e.g. "messages[2].content", or empty for the request as a whole;
+// Err is the domain error, kept so callers can still match it with
+// errors.Is.
+type FieldError struct {
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+	Err     error  `json:"-"`
+}
+
+func (e *FieldError) Error() string {
+	if e.Field == "" {
+		return e.Message
+	}
+	return e.Field + ": " + e.Message
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// Errors lists the failed fields of a request, in the order they were
+// checked.
+type Errors []*FieldError
+
+// Add records that field failed with err.
+func (e *Errors) Add(field string, err error) {
+	*e = append(*e, &FieldError{Field: field, Message: err.Error(), Err: err})
+}
+
+// Err returns e as an error, or nil when no field failed.
+func (e Errors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, f := range e {
+		messages[i] = f.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e Errors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, f := range e {
+		errs[i] = f
+	}
+	return errs
+}
+
+// Fields returns the field errors in err, or nil if it has none.
+func Fields(err error) []*FieldError {
+	var errs Errors
+	if errors.As(err, &errs) {
+		return errs
+	}
+	var field *FieldError
+	if errors.As(err, &field) {
+		return []*FieldError{field}
+	}
+	return nil
+}
+
+// Index returns the path of element i of the list field.
+func Index(field string, i int) string {
+	return field + "[" + strconv.Itoa(i) + "]"
+}